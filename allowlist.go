@@ -0,0 +1,33 @@
+package regret
+
+import (
+	"sync"
+	"time"
+)
+
+// allowedUnsafeMessage is the message of issues reported for patterns in
+// Options.AllowUnsafePatterns.
+const allowedUnsafeMessage = "pattern is on the unsafe-but-allowed list — please fix before next major release"
+
+// allowedSince records when each allowed unsafe pattern was first
+// validated in this process, as a time.Time.
+var allowedSince sync.Map
+
+// allowedUnsafe returns the Info issue reported instead of analysis for a
+// pattern in Options.AllowUnsafePatterns. Details["allowed_since"] is the
+// time the pattern was first validated in this process, so repeated
+// validations report the same time.
+func allowedUnsafe(pattern string) []Issue {
+	since, _ := allowedSince.LoadOrStore(pattern, time.Now())
+
+	return []Issue{{
+		Type:       ContextuallyDangerous,
+		Severity:   Info,
+		Position:   Position{Start: 0, End: len(pattern)},
+		Pattern:    pattern,
+		Message:    allowedUnsafeMessage,
+		Suggestion: "Fix the pattern and remove it from AllowUnsafePatterns",
+		Details:    map[string]interface{}{"allowed_since": since},
+		References: References(ContextuallyDangerous),
+	}}
+}
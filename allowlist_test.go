@@ -0,0 +1,61 @@
+package regret
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidate_AllowUnsafePatterns(t *testing.T) {
+	opts := DefaultOptions()
+	opts.AllowUnsafePatterns = []string{`(a+)+`}
+
+	issues, err := ValidateWithOptions(`(a+)+`, opts)
+	if err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v", err)
+	}
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 issue, got %v", issues)
+	}
+	if issues[0].Type != ContextuallyDangerous || issues[0].Severity != Info {
+		t.Errorf("got %v/%v, want ContextuallyDangerous/Info", issues[0].Type, issues[0].Severity)
+	}
+	if issues[0].Message != allowedUnsafeMessage {
+		t.Errorf("Message = %q", issues[0].Message)
+	}
+	since, ok := issues[0].Details["allowed_since"].(time.Time)
+	if !ok || since.IsZero() {
+		t.Fatalf("Details[allowed_since] = %v, want a time", issues[0].Details["allowed_since"])
+	}
+
+	// The time of the first validation is kept
+	issues, err = ValidateWithOptions(`(a+)+`, opts)
+	if err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v", err)
+	}
+	if got := issues[0].Details["allowed_since"]; got != since {
+		t.Errorf("second Details[allowed_since] = %v, want %v", got, since)
+	}
+
+	// Other patterns are analyzed as usual
+	issues, err = ValidateWithOptions(`(b+)+`, opts)
+	if err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v", err)
+	}
+	if hasMessage(issues, allowedUnsafeMessage) || len(issues) == 0 {
+		t.Errorf("expected analysis issues for pattern not on the list, got %v", issues)
+	}
+}
+
+func TestValidate_AllowUnsafePatternsDenied(t *testing.T) {
+	opts := DefaultOptions()
+	opts.DenyList = []string{`(a+)+`}
+	opts.AllowUnsafePatterns = []string{`(a+)+`}
+
+	issues, err := ValidateWithOptions(`(a+)+`, opts)
+	if err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v", err)
+	}
+	if !hasMessage(issues, denyListMessage) {
+		t.Errorf("expected deny list issue to take precedence, got %v", issues)
+	}
+}
@@ -0,0 +1,99 @@
+package regret
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"strings"
+)
+
+// Annotate returns Go source with a comment inserted above each regexp call
+// site that has issues:
+//
+//	// regret: critical nested_quantifiers: Nested quantifiers detected: (a+)+
+//	var re = regexp.MustCompile(`(a+)+`)
+//
+// Call sites are found like ScanReader finds them for "go". An issue
+// belongs to a call site if its Pattern is found at its Position in the
+// call site's pattern, so issues from validating each pattern found in the
+// source can be passed together. Comments are indented like the line they
+// annotate, and issues that match no call site are ignored.
+func Annotate(source string, issues []Issue) string {
+	findings, _ := scanGo(strings.NewReader(source))
+
+	comments := make(map[int][]string) // Line -> comments to insert above it
+	for _, finding := range findings {
+		for _, issue := range issues {
+			if !issueAt(finding.Pattern, issue) {
+				continue
+			}
+			comment := fmt.Sprintf("// regret: %s %s: %s", issue.Severity, issue.Type, issue.Message)
+			if !containsString(comments[finding.Line], comment) {
+				comments[finding.Line] = append(comments[finding.Line], comment)
+			}
+		}
+	}
+	if len(comments) == 0 {
+		return source
+	}
+
+	var b strings.Builder
+	for i, line := range strings.SplitAfter(source, "\n") {
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		for _, comment := range comments[i+1] {
+			b.WriteString(indent + comment + "\n")
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+// issueAt reports whether issue was found at its Position in pattern. The
+// Pattern of an issue may be printed in normalized form, [0-9]+ for \d+, so
+// the two are also compared after parsing. Issues whose sub-pattern could
+// not be located span the whole pattern, and match a pattern of the same
+// length that contains their normalized Pattern. Issues without a Position
+// match only a pattern equal to their Pattern.
+func issueAt(pattern string, issue Issue) bool {
+	start, end := issue.Position.Start, issue.Position.End
+	switch {
+	case issue.Pattern == "":
+		return false
+	case start == 0 && end == 0:
+		return issue.Pattern == pattern
+	case start < 0 || start >= end || end > len(pattern):
+		return false
+	case pattern[start:end] == issue.Pattern:
+		return true
+	}
+
+	want, ok := normalizePattern(issue.Pattern)
+	if !ok {
+		return false
+	}
+	if got, ok := normalizePattern(pattern[start:end]); ok && got == want {
+		return true
+	}
+	if start == 0 && end == len(pattern) {
+		whole, ok := normalizePattern(pattern)
+		return ok && strings.Contains(whole, want)
+	}
+	return false
+}
+
+// normalizePattern returns pattern as printed by regexp/syntax.
+func normalizePattern(pattern string) (string, bool) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	return re.Simplify().String(), true
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package regret
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnnotate(t *testing.T) {
+	source := "package main\n" +
+		"\n" +
+		"import \"regexp\"\n" +
+		"\n" +
+		"func main() {\n" +
+		"\tbad := regexp.MustCompile(`(a+)+`)\n" +
+		"\tgood := regexp.MustCompile(`^[a-z]+$`)\n" +
+		"\t_, _ = bad, good\n" +
+		"}\n"
+
+	findings, err := ScanReader(strings.NewReader(source), "go", nil)
+	if err != nil {
+		t.Fatalf("ScanReader() error = %v", err)
+	}
+	var issues []Issue
+	for _, f := range findings {
+		issues = append(issues, f.Issues...)
+	}
+
+	got := Annotate(source, issues)
+
+	lines := strings.Split(got, "\n")
+	var annotated []string
+	for i, line := range lines {
+		if !strings.Contains(line, "// regret: ") {
+			continue
+		}
+		if !strings.HasPrefix(line, "\t// regret: ") {
+			t.Errorf("comment %q is not indented like the call", line)
+		}
+		if !strings.Contains(lines[i+1], "// regret: ") {
+			annotated = append(annotated, lines[i+1])
+		}
+	}
+	if len(annotated) == 0 {
+		t.Fatalf("Annotate() added no comments:\n%s", got)
+	}
+	for _, line := range annotated {
+		if !strings.Contains(line, "(a+)+") {
+			t.Errorf("comment placed above %q, want above the (a+)+ call", line)
+		}
+	}
+
+	// Removing the comments gives back the source
+	var stripped []string
+	for _, line := range lines {
+		if !strings.Contains(line, "// regret: ") {
+			stripped = append(stripped, line)
+		}
+	}
+	if strings.Join(stripped, "\n") != source {
+		t.Errorf("Annotate() changed the source beyond adding comments:\n%s", got)
+	}
+}
+
+func TestAnnotate_NoIssues(t *testing.T) {
+	source := "package main\n\nvar re = regexp.MustCompile(`^a+$`)\n"
+	if got := Annotate(source, nil); got != source {
+		t.Errorf("Annotate() without issues = %q, want the source unchanged", got)
+	}
+	unrelated := []Issue{{Type: NestedQuantifiers, Severity: Critical, Pattern: "(b+)+", Message: "nested"}}
+	if got := Annotate(source, unrelated); got != source {
+		t.Errorf("Annotate() with unrelated issues = %q, want the source unchanged", got)
+	}
+}
+
+func TestAnnotate_MatchesPosition(t *testing.T) {
+	source := "package main\n" +
+		"\n" +
+		"var (\n" +
+		"\tbare    = regexp.MustCompile(`(a+)+`)\n" +
+		"\twrapped = regexp.MustCompile(`x(a+)+y`)\n" +
+		"\tdigits  = regexp.MustCompile(`^(\\d+)+$`)\n" +
+		")\n"
+
+	// Only the issues of the bare and digits patterns; the wrapped pattern
+	// contains (a+)+ but at a different position
+	var issues []Issue
+	for _, pattern := range []string{`(a+)+`, `^(\d+)+$`} {
+		found, err := Validate(pattern)
+		if err != nil {
+			t.Fatalf("Validate(%q) error = %v", pattern, err)
+		}
+		issues = append(issues, found...)
+	}
+
+	lines := strings.Split(Annotate(source, issues), "\n")
+	annotated := make(map[string]bool)
+	for i, line := range lines {
+		if strings.Contains(line, "// regret: ") && !strings.Contains(lines[i+1], "// regret: ") {
+			annotated[strings.Fields(lines[i+1])[0]] = true
+		}
+	}
+	if !annotated["bare"] || !annotated["digits"] || annotated["wrapped"] {
+		t.Errorf("annotated call sites = %v, want bare and digits only", annotated)
+	}
+}
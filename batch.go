@@ -0,0 +1,159 @@
+package regret
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// IssueSet is the list of issues found in a single pattern.
+type IssueSet []Issue
+
+// Worst returns the most severe issue in the set. If several issues share
+// the highest severity, the first one is returned. ok is false if the set
+// is empty.
+func (s IssueSet) Worst() (worst Issue, ok bool) {
+	if len(s) == 0 {
+		return Issue{}, false
+	}
+	worst = s[0]
+	for _, issue := range s[1:] {
+		if issue.Severity < worst.Severity {
+			worst = issue
+		}
+	}
+	return worst, true
+}
+
+// ValidationResult is the outcome of validating one pattern in a batch.
+type ValidationResult struct {
+	// Pattern is the validated pattern.
+	Pattern string
+
+	// Issues contains the detected issues (empty if the pattern is safe).
+	Issues IssueSet
+
+	// Err is set if the pattern could not be validated.
+	Err error
+}
+
+// ValidateMany validates patterns concurrently and returns one result per
+// pattern, in the same order as patterns. At most opts.Concurrency patterns
+// are validated at once.
+//
+// Example:
+//
+//	for _, result := range regret.ValidateMany(patterns, nil) {
+//	    if result.Err != nil || len(result.Issues) > 0 {
+//	        fmt.Println("rejected:", result.Pattern)
+//	    }
+//	}
+func ValidateMany(patterns []string, opts *Options) []ValidationResult {
+	v := NewValidator(opts)
+	results := make([]ValidationResult, len(patterns))
+	parallel(len(patterns), concurrency(v.opts), func(i int) {
+		issues, err := v.Validate(patterns[i])
+		results[i] = ValidationResult{Pattern: patterns[i], Issues: issues, Err: err}
+	})
+	return results
+}
+
+// ValidateManyStream validates patterns read from a channel and sends the
+// results on the returned channel as they complete, so large batches can be
+// processed without holding every result in memory. At most
+// opts.Concurrency patterns are validated at once, and results may arrive
+// in a different order than their patterns.
+//
+// The returned channel is closed once patterns is closed and every pattern
+// read from it has been validated, or as soon as ctx is done. After ctx is
+// done no more patterns are read, so producers should select on ctx.Done()
+// too rather than block sending to patterns.
+//
+// Example:
+//
+//	patterns := make(chan string)
+//	go func() {
+//	    defer close(patterns)
+//	    for rows.Next() {
+//	        var p string
+//	        rows.Scan(&p)
+//	        select {
+//	        case patterns <- p:
+//	        case <-ctx.Done():
+//	            return
+//	        }
+//	    }
+//	}()
+//	for result := range regret.ValidateManyStream(ctx, patterns, nil) {
+//	    auditLog.Record(result.Pattern, result.Issues, result.Err)
+//	}
+func ValidateManyStream(ctx context.Context, patterns <-chan string, opts *Options) <-chan ValidationResult {
+	v := NewValidator(opts)
+	results := make(chan ValidationResult)
+
+	workers := concurrency(v.opts)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case pattern, ok := <-patterns:
+					if !ok {
+						return
+					}
+					issues, err := v.Validate(pattern)
+					select {
+					case results <- ValidationResult{Pattern: pattern, Issues: issues, Err: err}:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// parallel calls fn for every index below n, on at most workers goroutines
+// at once, and returns when every call has returned.
+func parallel(n, workers int, fn func(i int)) {
+	if workers > n {
+		workers = n
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// concurrency returns the number of workers batch validation may use.
+func concurrency(opts *Options) int {
+	if opts.Concurrency > 0 {
+		return opts.Concurrency
+	}
+	return runtime.GOMAXPROCS(0)
+}
@@ -0,0 +1,246 @@
+package regret
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+)
+
+// maxMostDangerous is the number of patterns BatchSummary.MostDangerous
+// lists at most.
+const maxMostDangerous = 5
+
+// PatternResult is the report of one pattern in a batch.
+type PatternResult struct {
+	// Pattern is the reported pattern.
+	Pattern string
+
+	// Report is the validation report of the pattern, or nil if Err is set.
+	Report *ValidationReport
+
+	// Err is set if the pattern could not be reported, usually because it
+	// is invalid.
+	Err error
+}
+
+// BatchSummary holds aggregate statistics about a batch of patterns.
+type BatchSummary struct {
+	// Total is the number of patterns in the batch, including those that
+	// could not be reported.
+	Total int
+
+	// Safe and Unsafe count the reported patterns without and with issues.
+	Safe   int
+	Unsafe int
+
+	// Errors counts the patterns that could not be reported.
+	Errors int
+
+	// Critical, High, Medium, Low and Info count the unsafe patterns by
+	// the severity of their most severe issue, so they add up to Unsafe.
+	Critical int
+	High     int
+	Medium   int
+	Low      int
+	Info     int
+
+	// AverageScore is the mean overall complexity score of the reported
+	// patterns.
+	AverageScore float64
+
+	// MostDangerous lists up to five unsafe patterns, most severe first,
+	// with ties broken by the higher complexity score.
+	MostDangerous []PatternResult
+}
+
+// BatchReport is the result of reporting on many patterns at once, such as
+// every pattern found when auditing a codebase.
+type BatchReport struct {
+	// Patterns holds one result per pattern, in input order.
+	Patterns []PatternResult
+
+	// Summary aggregates the results.
+	Summary BatchSummary
+}
+
+// ReportMany reports on patterns concurrently, like ValidateMany, and
+// aggregates the results into summary statistics. At most
+// opts.Concurrency patterns are reported at once. If opts is nil,
+// DefaultOptions() is used.
+//
+// Patterns that cannot be reported are recorded in PatternResult.Err. The
+// returned error is only set for failures that affect every pattern, such
+// as an unreadable Options.DenyListFile.
+//
+// Example:
+//
+//	report, err := regret.ReportMany(patterns, nil)
+//	if err != nil {
+//	    return err
+//	}
+//	report.WriteTo(os.Stdout)
+func ReportMany(patterns []string, opts *Options) (*BatchReport, error) {
+	v := NewValidator(opts)
+	if _, err := v.denyList(); err != nil {
+		return nil, err
+	}
+
+	results := make([]PatternResult, len(patterns))
+	parallel(len(patterns), concurrency(v.opts), func(i int) {
+		report, err := v.Report(patterns[i])
+		results[i] = PatternResult{Pattern: patterns[i], Report: report, Err: err}
+	})
+
+	return &BatchReport{Patterns: results, Summary: summarizeBatch(results)}, nil
+}
+
+// ValidateAll reports on named patterns concurrently, like ReportMany,
+// and returns the results keyed by name, so that callers validating a
+// config file can say which named pattern is unsafe. At most
+// opts.Concurrency patterns are reported at once.
+//
+// Example:
+//
+//	results := v.ValidateAll(map[string]string{
+//	    "email":    `^[a-z]+@[a-z]+\.[a-z]+$`,
+//	    "username": `^([a-z0-9]+)+$`,
+//	})
+//	for name, result := range results {
+//	    if result.Err != nil || len(result.Report.Issues) > 0 {
+//	        fmt.Printf("the %q pattern is unsafe\n", name)
+//	    }
+//	}
+func (v *Validator) ValidateAll(patterns map[string]string) map[string]PatternResult {
+	return validateAll(patterns, v.opts, v.Report)
+}
+
+// validateAll is ValidateAll with report reporting on each pattern.
+func validateAll(patterns map[string]string, opts *Options, report func(string) (*ValidationReport, error)) map[string]PatternResult {
+	names := make([]string, 0, len(patterns))
+	for name := range patterns {
+		names = append(names, name)
+	}
+
+	results := make([]PatternResult, len(names))
+	parallel(len(names), concurrency(opts), func(i int) {
+		pattern := patterns[names[i]]
+		result, err := report(pattern)
+		results[i] = PatternResult{Pattern: pattern, Report: result, Err: err}
+	})
+
+	byName := make(map[string]PatternResult, len(names))
+	for i, name := range names {
+		byName[name] = results[i]
+	}
+	return byName
+}
+
+// summarizeBatch computes the statistics of a batch.
+func summarizeBatch(results []PatternResult) BatchSummary {
+	s := BatchSummary{Total: len(results)}
+
+	var unsafe []PatternResult
+	totalScore := 0
+	for _, result := range results {
+		if result.Err != nil {
+			s.Errors++
+			continue
+		}
+		totalScore += result.Report.Complexity.Overall
+
+		worst, ok := result.Report.Issues.Worst()
+		if !ok {
+			s.Safe++
+			continue
+		}
+		s.Unsafe++
+		unsafe = append(unsafe, result)
+		switch worst.Severity {
+		case Critical:
+			s.Critical++
+		case High:
+			s.High++
+		case Medium:
+			s.Medium++
+		case Low:
+			s.Low++
+		default:
+			s.Info++
+		}
+	}
+	if reported := s.Safe + s.Unsafe; reported > 0 {
+		s.AverageScore = float64(totalScore) / float64(reported)
+	}
+
+	sort.SliceStable(unsafe, func(i, j int) bool {
+		wi, _ := unsafe[i].Report.Issues.Worst()
+		wj, _ := unsafe[j].Report.Issues.Worst()
+		if wi.Severity != wj.Severity {
+			return wi.Severity < wj.Severity
+		}
+		return unsafe[i].Report.Complexity.Overall > unsafe[j].Report.Complexity.Overall
+	})
+	if len(unsafe) > maxMostDangerous {
+		unsafe = unsafe[:maxMostDangerous]
+	}
+	s.MostDangerous = unsafe
+
+	return s
+}
+
+// WriteTo writes the summary of the report to w as aligned tables: the
+// pattern counts, followed by the most dangerous patterns with their worst
+// issue, complexity score and time complexity. It implements io.WriterTo.
+func (r *BatchReport) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	tw := tabwriter.NewWriter(cw, 0, 0, 2, ' ', 0)
+	s := r.Summary
+
+	fmt.Fprintf(tw, "Patterns:\t%d\n", s.Total)
+	fmt.Fprintf(tw, "Safe:\t%d\n", s.Safe)
+	fmt.Fprintf(tw, "Unsafe:\t%d\n", s.Unsafe)
+	fmt.Fprintf(tw, "  Critical:\t%d\n", s.Critical)
+	fmt.Fprintf(tw, "  High:\t%d\n", s.High)
+	fmt.Fprintf(tw, "  Medium:\t%d\n", s.Medium)
+	fmt.Fprintf(tw, "  Low:\t%d\n", s.Low)
+	fmt.Fprintf(tw, "  Info:\t%d\n", s.Info)
+	fmt.Fprintf(tw, "Errors:\t%d\n", s.Errors)
+	fmt.Fprintf(tw, "Average score:\t%.1f/100\n", s.AverageScore)
+	if err := tw.Flush(); err != nil {
+		return cw.n, err
+	}
+
+	if len(s.MostDangerous) > 0 {
+		fmt.Fprintf(cw, "\nMost dangerous:\n")
+		tw = tabwriter.NewWriter(cw, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "PATTERN\tSEVERITY\tISSUE\tSCORE\tTIME\n")
+		for _, result := range s.MostDangerous {
+			worst, _ := result.Report.Issues.Worst()
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n", result.Pattern, worst.Severity, worst.Type,
+				result.Report.Complexity.Overall, result.Report.Complexity.TimeComplexity)
+		}
+		if err := tw.Flush(); err != nil {
+			return cw.n, err
+		}
+	}
+
+	return cw.n, cw.err
+}
+
+// countingWriter counts the bytes written to w and keeps the first error.
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
@@ -0,0 +1,120 @@
+package regret
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReportMany(t *testing.T) {
+	patterns := []string{`^\d+$`, `(a+)+$`, `(abc`, `\w*\w*x`, `^[a-z]+$`}
+
+	report, err := ReportMany(patterns, nil)
+	if err != nil {
+		t.Fatalf("ReportMany() error = %v", err)
+	}
+
+	if len(report.Patterns) != len(patterns) {
+		t.Fatalf("len(Patterns) = %d, want %d", len(report.Patterns), len(patterns))
+	}
+	for i, result := range report.Patterns {
+		if result.Pattern != patterns[i] {
+			t.Errorf("Patterns[%d].Pattern = %q, want %q", i, result.Pattern, patterns[i])
+		}
+		if (result.Err != nil) == (result.Report != nil) {
+			t.Errorf("Patterns[%d] has Report %v and Err %v, want exactly one", i, result.Report, result.Err)
+		}
+	}
+
+	s := report.Summary
+	if s.Total != 5 || s.Safe != 2 || s.Unsafe != 2 || s.Errors != 1 {
+		t.Errorf("Summary counts = %d total, %d safe, %d unsafe, %d errors, want 5, 2, 2, 1",
+			s.Total, s.Safe, s.Unsafe, s.Errors)
+	}
+	if s.Critical+s.High+s.Medium+s.Low+s.Info != s.Unsafe || s.Critical != 1 {
+		t.Errorf("Summary severities = %+v, want one critical and a total of Unsafe", s)
+	}
+	if s.AverageScore <= 0 {
+		t.Errorf("AverageScore = %v, want > 0", s.AverageScore)
+	}
+	if len(s.MostDangerous) != 2 || s.MostDangerous[0].Pattern != `(a+)+$` {
+		t.Errorf("MostDangerous = %+v, want (a+)+$ first", s.MostDangerous)
+	}
+}
+
+func TestReportMany_MostDangerousLimit(t *testing.T) {
+	patterns := []string{`(a+)+`, `(b+)+`, `(c+)+`, `(d+)+`, `(e+)+`, `(f+)+`, `(g+)+`}
+
+	report, err := ReportMany(patterns, nil)
+	if err != nil {
+		t.Fatalf("ReportMany() error = %v", err)
+	}
+	if got := len(report.Summary.MostDangerous); got != 5 {
+		t.Errorf("len(MostDangerous) = %d, want 5", got)
+	}
+}
+
+func TestReportMany_DenyListFileError(t *testing.T) {
+	opts := DefaultOptions()
+	opts.DenyListFile = filepath.Join(t.TempDir(), "missing.txt")
+
+	if _, err := ReportMany([]string{"abc"}, opts); err == nil {
+		t.Error("ReportMany() error = nil, want an error for a missing deny list file")
+	}
+}
+
+func TestBatchReport_WriteTo(t *testing.T) {
+	report, err := ReportMany([]string{`(a+)+$`, `^\d+$`}, nil)
+	if err != nil {
+		t.Fatalf("ReportMany() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	n, err := report.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo() = %d, want %d bytes written", n, buf.Len())
+	}
+
+	out := buf.String()
+	for _, want := range []string{"Patterns:", "Unsafe:", "Critical:", "Average score:", "Most dangerous:", "(a+)+$", "nested_quantifiers"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("WriteTo() output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `^\d+$`) {
+		t.Errorf("WriteTo() lists a safe pattern:\n%s", out)
+	}
+}
+
+func TestValidator_ValidateAll(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Concurrency = 2
+	v := NewValidator(opts)
+
+	results := v.ValidateAll(map[string]string{
+		"email":    `^[a-z]+@[a-z]+\.[a-z]+$`,
+		"username": `^([a-z0-9]+)+$`,
+		"broken":   `(abc`,
+	})
+
+	if len(results) != 3 {
+		t.Fatalf("ValidateAll() returned %d results, want 3", len(results))
+	}
+	if r := results["email"]; r.Err != nil || len(r.Report.Issues) != 0 {
+		t.Errorf("results[email] = %+v, want a safe report", r)
+	}
+	if r := results["username"]; r.Err != nil || len(r.Report.Issues) == 0 || r.Pattern != `^([a-z0-9]+)+$` {
+		t.Errorf("results[username] = %+v, want issues for ^([a-z0-9]+)+$", r)
+	}
+	if r := results["broken"]; r.Err == nil || r.Report != nil {
+		t.Errorf("results[broken] = %+v, want an error", r)
+	}
+
+	if results := v.ValidateAll(nil); len(results) != 0 {
+		t.Errorf("ValidateAll(nil) = %v, want empty", results)
+	}
+}
@@ -0,0 +1,105 @@
+package regret
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestIssueSet_Worst(t *testing.T) {
+	if _, ok := IssueSet(nil).Worst(); ok {
+		t.Error("Worst() ok = true for empty set")
+	}
+
+	set := IssueSet{
+		{Severity: Medium, Message: "medium"},
+		{Severity: Critical, Message: "first critical"},
+		{Severity: Critical, Message: "second critical"},
+	}
+	worst, ok := set.Worst()
+	if !ok || worst.Message != "first critical" {
+		t.Errorf("Worst() = %q, %v, want first critical", worst.Message, ok)
+	}
+}
+
+func TestValidateMany(t *testing.T) {
+	patterns := []string{"^[a-z]+$", "(a+)+", "(a+", "^\\d{3}$", "(x*)*"}
+
+	opts := DefaultOptions()
+	opts.Concurrency = 2
+	results := ValidateMany(patterns, opts)
+
+	if len(results) != len(patterns) {
+		t.Fatalf("ValidateMany() returned %d results, want %d", len(results), len(patterns))
+	}
+	for i, result := range results {
+		if result.Pattern != patterns[i] {
+			t.Errorf("results[%d].Pattern = %q, want %q", i, result.Pattern, patterns[i])
+		}
+	}
+	if len(results[0].Issues) != 0 || len(results[3].Issues) != 0 {
+		t.Error("ValidateMany() reported issues for safe patterns")
+	}
+	if len(results[1].Issues) == 0 || len(results[4].Issues) == 0 {
+		t.Error("ValidateMany() missed issues in unsafe patterns")
+	}
+	if results[2].Err == nil {
+		t.Error("ValidateMany() expected error for invalid pattern")
+	}
+
+	if results := ValidateMany(nil, nil); len(results) != 0 {
+		t.Errorf("ValidateMany(nil) = %v, want empty", results)
+	}
+}
+
+func TestValidateManyStream(t *testing.T) {
+	patterns := make(chan string)
+	go func() {
+		defer close(patterns)
+		for _, p := range []string{"(a+)+", "^ok$", "(x+x+)+y", "abc"} {
+			patterns <- p
+		}
+	}()
+
+	unsafe := make(map[string]bool)
+	for result := range ValidateManyStream(context.Background(), patterns, nil) {
+		if result.Err != nil {
+			t.Errorf("Validate(%q) error = %v", result.Pattern, result.Err)
+		}
+		unsafe[result.Pattern] = len(result.Issues) > 0
+	}
+
+	want := map[string]bool{"(a+)+": true, "^ok$": false, "(x+x+)+y": true, "abc": false}
+	if len(unsafe) != len(want) {
+		t.Fatalf("ValidateManyStream() returned %d results, want %d", len(unsafe), len(want))
+	}
+	for pattern, wantUnsafe := range want {
+		if unsafe[pattern] != wantUnsafe {
+			t.Errorf("%q unsafe = %v, want %v", pattern, unsafe[pattern], wantUnsafe)
+		}
+	}
+}
+
+func TestValidateManyStream_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// The input is never closed and the results are never read
+	patterns := make(chan string, 1)
+	patterns <- "(a+)+"
+	results := ValidateManyStream(ctx, patterns, nil)
+
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range results {
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("results channel not closed after cancellation")
+	}
+}
@@ -0,0 +1,193 @@
+package regret
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+// PatternBuilder builds a regex pattern piece by piece. Quantifiers take a
+// function that fills in a sub-builder, so the grouping is always explicit:
+//
+//	re, err := regret.NewPattern().
+//	    Start().
+//	    Literal("id-").
+//	    OneOrMore(func(b *regret.PatternBuilder) { b.CharClass("[0-9]") }).
+//	    End().
+//	    Build()
+//
+// Mistakes are reported as soon as they are made: repeating a sub-pattern
+// that already repeats, such as ZeroOrMore around a ZeroOrMore, records an
+// error that Build returns, and later calls do nothing. Build validates the
+// finished pattern like Compile.
+type PatternBuilder struct {
+	parts   []string
+	repeats bool // The pattern contains an unbounded quantifier
+	err     error
+}
+
+// NewPattern returns an empty PatternBuilder.
+func NewPattern() *PatternBuilder {
+	return &PatternBuilder{}
+}
+
+// Start anchors the pattern at the start of the input (^).
+func (b *PatternBuilder) Start() *PatternBuilder {
+	return b.add("^")
+}
+
+// End anchors the pattern at the end of the input ($).
+func (b *PatternBuilder) End() *PatternBuilder {
+	return b.add("$")
+}
+
+// Literal matches s exactly; regex metacharacters in s are escaped.
+func (b *PatternBuilder) Literal(s string) *PatternBuilder {
+	return b.add(regexp.QuoteMeta(s))
+}
+
+// CharClass matches one character of class, which must be a single
+// character class such as "[a-z]", `\d` or `[^\s]`.
+func (b *PatternBuilder) CharClass(class string) *PatternBuilder {
+	if b.err != nil {
+		return b
+	}
+	re, err := syntax.Parse(class, syntax.Perl)
+	if err != nil {
+		return b.fail(fmt.Errorf("%w: %v", ErrInvalidPattern, err))
+	}
+	if re.Op != syntax.OpCharClass {
+		return b.fail(fmt.Errorf("%w: %q is not a character class", ErrInvalidPattern, class))
+	}
+	return b.add(class)
+}
+
+// Any matches any character except newline (.).
+func (b *PatternBuilder) Any() *PatternBuilder {
+	return b.add(".")
+}
+
+// Group adds the sub-pattern built by fn as a non-capturing group.
+func (b *PatternBuilder) Group(fn func(*PatternBuilder)) *PatternBuilder {
+	return b.sub(fn, "(?:", ")", false)
+}
+
+// Capture adds the sub-pattern built by fn as a capturing group.
+func (b *PatternBuilder) Capture(fn func(*PatternBuilder)) *PatternBuilder {
+	return b.sub(fn, "(", ")", false)
+}
+
+// AnyOf matches one of the sub-patterns built by fns.
+func (b *PatternBuilder) AnyOf(fns ...func(*PatternBuilder)) *PatternBuilder {
+	if b.err != nil {
+		return b
+	}
+	branches := make([]string, len(fns))
+	for i, fn := range fns {
+		child := b.build(fn)
+		if child.err != nil {
+			return b.fail(child.err)
+		}
+		branches[i] = child.String()
+		b.repeats = b.repeats || child.repeats
+	}
+	return b.add("(?:" + strings.Join(branches, "|") + ")")
+}
+
+// Optional matches the sub-pattern built by fn zero or one time (?).
+func (b *PatternBuilder) Optional(fn func(*PatternBuilder)) *PatternBuilder {
+	return b.sub(fn, "(?:", ")?", false)
+}
+
+// ZeroOrMore matches the sub-pattern built by fn any number of times (*).
+// The sub-pattern must not repeat itself.
+func (b *PatternBuilder) ZeroOrMore(fn func(*PatternBuilder)) *PatternBuilder {
+	return b.sub(fn, "(?:", ")*", true)
+}
+
+// OneOrMore matches the sub-pattern built by fn one or more times (+).
+// The sub-pattern must not repeat itself.
+func (b *PatternBuilder) OneOrMore(fn func(*PatternBuilder)) *PatternBuilder {
+	return b.sub(fn, "(?:", ")+", true)
+}
+
+// Repeat matches the sub-pattern built by fn between min and max times.
+// A negative max means no upper bound, in which case the sub-pattern must
+// not repeat itself.
+func (b *PatternBuilder) Repeat(min, max int, fn func(*PatternBuilder)) *PatternBuilder {
+	if b.err != nil {
+		return b
+	}
+	switch {
+	case min < 0 || (max >= 0 && max < min):
+		return b.fail(fmt.Errorf("%w: invalid repeat count {%d,%d}", ErrInvalidPattern, min, max))
+	case max < 0:
+		return b.sub(fn, "(?:", fmt.Sprintf("){%d,}", min), true)
+	case min == max:
+		return b.sub(fn, "(?:", fmt.Sprintf("){%d}", min), false)
+	default:
+		return b.sub(fn, "(?:", fmt.Sprintf("){%d,%d}", min, max), false)
+	}
+}
+
+// Err returns the first mistake recorded by the builder, if any.
+func (b *PatternBuilder) Err() error {
+	return b.err
+}
+
+// String returns the pattern built so far.
+func (b *PatternBuilder) String() string {
+	return strings.Join(b.parts, "")
+}
+
+// Build compiles the pattern with Compile. It returns the first mistake
+// recorded while building, or an error if the pattern is unsafe.
+func (b *PatternBuilder) Build() (*SafeRegexp, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return Compile(b.String())
+}
+
+// add appends a piece of pattern unless an error was recorded.
+func (b *PatternBuilder) add(piece string) *PatternBuilder {
+	if b.err == nil {
+		b.parts = append(b.parts, piece)
+	}
+	return b
+}
+
+// fail records err unless an error was already recorded.
+func (b *PatternBuilder) fail(err error) *PatternBuilder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
+// build runs fn on a new sub-builder.
+func (b *PatternBuilder) build(fn func(*PatternBuilder)) *PatternBuilder {
+	child := NewPattern()
+	fn(child)
+	return child
+}
+
+// sub adds the sub-pattern built by fn between open and close. If unbounded
+// is set, close holds an unbounded quantifier, which must not be applied to
+// a sub-pattern that already repeats.
+func (b *PatternBuilder) sub(fn func(*PatternBuilder), open, close string, unbounded bool) *PatternBuilder {
+	if b.err != nil {
+		return b
+	}
+	child := b.build(fn)
+	if child.err != nil {
+		return b.fail(child.err)
+	}
+	if unbounded && child.repeats {
+		return b.fail(fmt.Errorf("pattern builder: repeating %q, which already repeats, nests quantifiers", child.String()))
+	}
+
+	b.repeats = b.repeats || child.repeats || unbounded
+	return b.add(open + child.String() + close)
+}
@@ -0,0 +1,108 @@
+package regret
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPatternBuilder(t *testing.T) {
+	b := NewPattern().
+		Start().
+		Literal("id.").
+		OneOrMore(func(b *PatternBuilder) { b.CharClass("[0-9]") }).
+		Literal("-").
+		AnyOf(
+			func(b *PatternBuilder) { b.Literal("draft") },
+			func(b *PatternBuilder) { b.Literal("final") },
+		).
+		End()
+
+	if got, want := b.String(), `^id\.(?:[0-9])+-(?:draft|final)$`; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	re, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	for input, want := range map[string]bool{"id.42-draft": true, "id.7-final": true, "id.x-final": false, "idx42-draft": false} {
+		if re.MatchString(input) != want {
+			t.Errorf("MatchString(%q) = %v, want %v", input, !want, want)
+		}
+	}
+}
+
+func TestPatternBuilder_Groups(t *testing.T) {
+	b := NewPattern().
+		Capture(func(b *PatternBuilder) { b.Any() }).
+		Optional(func(b *PatternBuilder) { b.Literal("x") }).
+		Repeat(2, 2, func(b *PatternBuilder) { b.Literal("y") }).
+		Repeat(1, -1, func(b *PatternBuilder) { b.Literal("z") })
+
+	if got, want := b.String(), `(.)(?:x)?(?:y){2}(?:z){1,}`; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestPatternBuilder_NestedQuantifiers(t *testing.T) {
+	b := NewPattern().ZeroOrMore(func(b *PatternBuilder) {
+		b.ZeroOrMore(func(b *PatternBuilder) { b.Literal("a") })
+	})
+	if b.Err() == nil {
+		t.Fatal("ZeroOrMore over ZeroOrMore recorded no error")
+	}
+
+	// Later calls do nothing and Build reports the first mistake
+	b.Literal("b")
+	if b.String() != "" {
+		t.Errorf("String() = %q after error, want empty", b.String())
+	}
+	if _, err := b.Build(); err == nil || !strings.Contains(err.Error(), "nests quantifiers") {
+		t.Errorf("Build() error = %v, want nested quantifier error", err)
+	}
+
+	// Bounded repetition of a repeating sub-pattern is allowed
+	ok := NewPattern().Repeat(1, 3, func(b *PatternBuilder) {
+		b.OneOrMore(func(b *PatternBuilder) { b.CharClass(`\d`) }).Literal(",")
+	})
+	if ok.Err() != nil {
+		t.Errorf("Repeat(1, 3) over OneOrMore error = %v", ok.Err())
+	}
+}
+
+func TestPatternBuilder_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func() *PatternBuilder
+	}{
+		{"not a class", func() *PatternBuilder { return NewPattern().CharClass("abc") }},
+		{"invalid class", func() *PatternBuilder { return NewPattern().CharClass("[a-") }},
+		{"bad repeat", func() *PatternBuilder {
+			return NewPattern().Repeat(3, 1, func(b *PatternBuilder) { b.Literal("a") })
+		}},
+		{"error in sub-builder", func() *PatternBuilder {
+			return NewPattern().Group(func(b *PatternBuilder) { b.CharClass("x+") })
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.build().Build(); !errors.Is(err, ErrInvalidPattern) {
+				t.Errorf("Build() error = %v, want %v", err, ErrInvalidPattern)
+			}
+		})
+	}
+}
+
+func TestPatternBuilder_BuildValidates(t *testing.T) {
+	// Overlapping repetitions are not caught while building but are unsafe
+	_, err := NewPattern().
+		OneOrMore(func(b *PatternBuilder) { b.CharClass(`\w`) }).
+		OneOrMore(func(b *PatternBuilder) { b.CharClass(`\w`) }).
+		Literal("!").
+		Build()
+	if err == nil {
+		t.Error("Build() accepted an unsafe pattern")
+	}
+}
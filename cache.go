@@ -0,0 +1,92 @@
+package regret
+
+import (
+	"container/list"
+	"sync"
+)
+
+// resultCache is a least-recently-used cache of the issues Validator.Validate
+// found, keyed by the decoded pattern. It is safe for concurrent use.
+type resultCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // of *cacheEntry, most recently used first
+	entries map[string]*list.Element
+}
+
+type cacheEntry struct {
+	pattern string
+	issues  []Issue
+}
+
+// newResultCache creates a cache that holds up to size results.
+func newResultCache(size int) *resultCache {
+	return &resultCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// get returns a copy of the issues cached for pattern, or false if there
+// are none.
+func (c *resultCache) get(pattern string) ([]Issue, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[pattern]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return copyIssues(e.Value.(*cacheEntry).issues), true
+}
+
+// add caches a copy of the issues found in pattern, evicting the least
+// recently used result if the cache is full.
+func (c *resultCache) add(pattern string, issues []Issue) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[pattern]; ok {
+		c.order.MoveToFront(e)
+		return
+	}
+	if c.order.Len() >= c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).pattern)
+	}
+	c.entries[pattern] = c.order.PushFront(&cacheEntry{pattern: pattern, issues: copyIssues(issues)})
+}
+
+// len returns the number of cached results.
+func (c *resultCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// copyIssues returns a copy of issues that shares no maps or slices with
+// them, so that callers may modify the issues they get from the cache.
+func copyIssues(issues []Issue) []Issue {
+	if issues == nil {
+		return nil
+	}
+	copied := make([]Issue, len(issues))
+	for i, issue := range issues {
+		if issue.Details != nil {
+			details := make(map[string]interface{}, len(issue.Details))
+			for k, v := range issue.Details {
+				details[k] = v
+			}
+			issue.Details = details
+		}
+		if issue.References != nil {
+			issue.References = append([]string{}, issue.References...)
+		}
+		if issue.RelatedPatterns != nil {
+			issue.RelatedPatterns = append([]string{}, issue.RelatedPatterns...)
+		}
+		copied[i] = issue
+	}
+	return copied
+}
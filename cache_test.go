@@ -0,0 +1,53 @@
+package regret
+
+import "testing"
+
+func TestValidator_Cache(t *testing.T) {
+	opts := DefaultOptions()
+	opts.CacheSize = 2
+	opts.Timeout = 0
+	v := NewValidator(opts)
+
+	first, err := v.Validate(`(a+)+`)
+	if err != nil || len(first) == 0 {
+		t.Fatalf("Validate() = %v, %v, want issues", first, err)
+	}
+	if got := v.CacheLen(); got != 1 {
+		t.Errorf("CacheLen() = %d, want 1", got)
+	}
+
+	// Cached issues are copies that callers may modify
+	first[0].Details["changed"] = true
+	second, err := v.Validate(`(a+)+`)
+	if err != nil || len(second) != len(first) {
+		t.Fatalf("Validate() from cache = %v, %v, want %d issues", second, err, len(first))
+	}
+	if _, ok := second[0].Details["changed"]; ok {
+		t.Error("Validate() returned an issue modified by the caller")
+	}
+
+	// The least recently used result is evicted
+	for _, pattern := range []string{`^a$`, `^b$`} {
+		if _, err := v.Validate(pattern); err != nil {
+			t.Fatalf("Validate(%q) error = %v", pattern, err)
+		}
+	}
+	if got := v.CacheLen(); got != 2 {
+		t.Errorf("CacheLen() = %d, want 2", got)
+	}
+	if _, ok := v.cache.get(`(a+)+`); ok {
+		t.Error("(a+)+ still cached, want it evicted")
+	}
+
+	// Errors are not cached
+	if _, err := v.Validate(`(a+`); err == nil {
+		t.Error("Validate() expected error for invalid pattern")
+	}
+	if _, ok := v.cache.get(`(a+`); ok {
+		t.Error("invalid pattern cached")
+	}
+
+	if got := NewValidator(nil).CacheLen(); got != 0 {
+		t.Errorf("CacheLen() without cache = %d, want 0", got)
+	}
+}
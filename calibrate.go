@@ -0,0 +1,63 @@
+package regret
+
+import (
+	"math"
+	"regexp"
+	"time"
+
+	"github.com/theakshaypant/regret/internal/pump"
+)
+
+// CalibrateForEngine measures how fast Go's regexp engine processes input
+// on this machine, in nanoseconds per character, for use with
+// PumpPattern.EstimateMatchTime. It matches re against inputs of 1, 10, 100
+// and 1000 characters and fits a line through the times; the slope is
+// returned. re should be a safe pattern that reads its whole input. If re
+// is nil, ^.{n}$ is compiled for each input length n.
+//
+// Example:
+//
+//	nsPerChar, err := regret.CalibrateForEngine(nil)
+//	if err != nil {
+//	    return err
+//	}
+//	p := &regret.PumpPattern{Pumps: score.PumpPattern, Suffix: "!"}
+//	fmt.Println(p.EstimateMatchTime(30, nsPerChar, score.TimeComplexity))
+func CalibrateForEngine(re *regexp.Regexp) (float64, error) {
+	return pump.NewGenerator(nil).CalibrateForEngine(re)
+}
+
+// EstimateMatchTime estimates how long a backtracking engine takes to
+// reject Generate(size) for a pattern of the given time complexity, from
+// nsPerChar as measured by CalibrateForEngine. The engine steps through the
+// input once for every way of matching the pumps it tries: 2^size ways for
+// Exponential patterns, size^(k-1) for polynomial ones of degree k, taken
+// as 4 for Polynomial, and one for Linear and Constant patterns. Unknown
+// complexity is treated as Linear. Estimates that do not fit in a
+// time.Duration are capped at its maximum.
+//
+// The estimate is an order of magnitude: engines that memoize or do not
+// backtrack, such as Go's own regexp package, match these inputs in linear
+// time.
+func (p *PumpPattern) EstimateMatchTime(size int, nsPerChar float64, complexity Complexity) time.Duration {
+	n := float64(max(size, 0))
+	var paths float64
+	switch complexity {
+	case Exponential:
+		paths = math.Pow(2, n)
+	case Polynomial:
+		paths = math.Pow(n, 3)
+	case Cubic:
+		paths = n * n
+	case Quadratic:
+		paths = n
+	default:
+		paths = 1
+	}
+
+	ns := paths * float64(len(p.Generate(size))) * nsPerChar
+	if ns >= math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(ns)
+}
@@ -0,0 +1,46 @@
+package regret
+
+import (
+	"math"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestCalibrateForEngine(t *testing.T) {
+	nsPerChar, err := CalibrateForEngine(regexp.MustCompile(`^[a-z]*$`))
+	if err != nil {
+		t.Fatalf("CalibrateForEngine() error = %v", err)
+	}
+	if nsPerChar <= 0 {
+		t.Errorf("CalibrateForEngine() = %v, want > 0", nsPerChar)
+	}
+}
+
+func TestPumpPattern_EstimateMatchTime(t *testing.T) {
+	p := &PumpPattern{Pumps: []string{"a"}, Suffix: "!"} // 11 characters for size 10
+
+	tests := []struct {
+		complexity Complexity
+		want       time.Duration
+	}{
+		{Linear, 11},
+		{Unknown, 11},
+		{Quadratic, 10 * 11},
+		{Cubic, 100 * 11},
+		{Polynomial, 1000 * 11},
+		{Exponential, 1024 * 11},
+	}
+	for _, tt := range tests {
+		if got := p.EstimateMatchTime(10, 1, tt.complexity); got != tt.want {
+			t.Errorf("EstimateMatchTime(10, 1, %v) = %v, want %v", tt.complexity, got, tt.want)
+		}
+	}
+
+	if got := p.EstimateMatchTime(10, 2.5, Quadratic); got != 275 {
+		t.Errorf("EstimateMatchTime(10, 2.5, Quadratic) = %v, want 275ns", got)
+	}
+	if got := p.EstimateMatchTime(1000, 1, Exponential); got != time.Duration(math.MaxInt64) {
+		t.Errorf("EstimateMatchTime(1000, 1, Exponential) = %v, want the maximum duration", got)
+	}
+}
@@ -0,0 +1,50 @@
+package regret
+
+import "time"
+
+// CheckOption changes one setting of the Options used by
+// ValidateWithCheckOptions. Options not set keep their DefaultOptions values.
+type CheckOption func(*Options)
+
+// ValidateWithCheckOptions is like ValidateWithOptions but starts from
+// DefaultOptions() and applies opts in order, so only the settings that
+// differ from the defaults need to be given.
+//
+// Example:
+//
+//	issues, err := regret.ValidateWithCheckOptions(pattern,
+//	    regret.WithMode(regret.Fast),
+//	    regret.WithStrictMode(true),
+//	)
+func ValidateWithCheckOptions(pattern string, opts ...CheckOption) ([]Issue, error) {
+	options := DefaultOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+	return ValidateWithOptions(pattern, options)
+}
+
+// WithMode sets Options.Mode.
+func WithMode(m ValidationMode) CheckOption {
+	return func(o *Options) { o.Mode = m }
+}
+
+// WithTimeout sets Options.Timeout.
+func WithTimeout(d time.Duration) CheckOption {
+	return func(o *Options) { o.Timeout = d }
+}
+
+// WithMaxComplexityScore sets Options.MaxComplexityScore.
+func WithMaxComplexityScore(n int) CheckOption {
+	return func(o *Options) { o.MaxComplexityScore = n }
+}
+
+// WithStrictMode sets Options.StrictMode.
+func WithStrictMode(strict bool) CheckOption {
+	return func(o *Options) { o.StrictMode = strict }
+}
+
+// WithChecks sets Options.Checks.
+func WithChecks(flags CheckFlags) CheckOption {
+	return func(o *Options) { o.Checks = flags }
+}
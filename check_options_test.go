@@ -0,0 +1,47 @@
+package regret
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckOptions(t *testing.T) {
+	opts := DefaultOptions()
+	for _, opt := range []CheckOption{
+		WithMode(Fast),
+		WithTimeout(time.Second),
+		WithMaxComplexityScore(40),
+		WithStrictMode(true),
+		WithChecks(CheckNestedQuantifiers),
+	} {
+		opt(opts)
+	}
+
+	if opts.Mode != Fast || opts.Timeout != time.Second || opts.MaxComplexityScore != 40 ||
+		!opts.StrictMode || opts.Checks != CheckNestedQuantifiers {
+		t.Errorf("options not applied: %+v", opts)
+	}
+
+	// Settings without an option keep their defaults
+	if opts.MaxPatternLength != DefaultOptions().MaxPatternLength {
+		t.Errorf("MaxPatternLength = %d, want default", opts.MaxPatternLength)
+	}
+}
+
+func TestValidateWithCheckOptions(t *testing.T) {
+	issues, err := ValidateWithCheckOptions("(a+)+", WithMode(Fast), WithStrictMode(true))
+	if err != nil {
+		t.Fatalf("ValidateWithCheckOptions() error = %v", err)
+	}
+	if len(issues) == 0 {
+		t.Error("ValidateWithCheckOptions() found no issues in (a+)+")
+	}
+
+	issues, err = ValidateWithCheckOptions("^[a-z]+$")
+	if err != nil {
+		t.Fatalf("ValidateWithCheckOptions() error = %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("ValidateWithCheckOptions() issues = %v, want none", issues)
+	}
+}
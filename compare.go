@@ -0,0 +1,292 @@
+package regret
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"unicode"
+
+	"github.com/theakshaypant/regret/internal/parser"
+	"github.com/theakshaypant/regret/internal/pump"
+	"github.com/theakshaypant/regret/internal/rewrite"
+)
+
+// maxCompareSamples bounds the number of strings enumerated by ComparePatterns.
+const maxCompareSamples = 20000
+
+// Sampling parameters of IsEquivalent.
+const (
+	equivalenceSamples   = 1000 // Random strings
+	equivalenceMatches   = 100  // Strings accepted by each pattern
+	equivalenceMaxLength = 50
+)
+
+// PatternComparison describes how the strings matched by two patterns relate.
+// Fields describe the second pattern relative to the first.
+type PatternComparison struct {
+	// MorePermissive is true if the second pattern matches a string
+	// that the first pattern rejects.
+	MorePermissive bool
+
+	// MoreRestrictive is true if the second pattern rejects a string
+	// that the first pattern matches.
+	MoreRestrictive bool
+
+	// Equivalent is true if no difference was found between the patterns.
+	Equivalent bool
+
+	// ExampleDifference is a string matched by exactly one of the patterns.
+	// Empty if the patterns are equivalent.
+	ExampleDifference string
+}
+
+// ComparePatterns checks whether two patterns match the same strings.
+// It is meant for confirming that a rewrite of a dangerous pattern (usually
+// the second argument) still accepts the same input as the original.
+//
+// Patterns are compared as whole-string matches. If one pattern is a known
+// safe rewrite of the other (see Suggest), they are reported as equivalent
+// without further checks. Otherwise both patterns are run against a bounded
+// set of strings: all short strings over the characters the patterns mention,
+// and pumped inputs up to MaxPatternLength/2 characters. Equivalence of
+// arbitrary regexes cannot be decided this way, so Equivalent means no
+// difference was found, while a reported difference is always real.
+//
+// Example:
+//
+//	cmp, err := regret.ComparePatterns("(a+)+", "a+")
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Println(cmp.Equivalent) // true
+func ComparePatterns(a, b string) (*PatternComparison, error) {
+	opts := DefaultOptions()
+	for _, pattern := range []string{a, b} {
+		if opts.MaxPatternLength > 0 && len(pattern) > opts.MaxPatternLength {
+			return nil, fmt.Errorf("%w: %d > %d", ErrPatternTooLong, len(pattern), opts.MaxPatternLength)
+		}
+	}
+
+	p := parser.NewParser()
+	reA, err := p.Parse(a)
+	if err != nil {
+		return nil, err
+	}
+	reB, err := p.Parse(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if isKnownRewrite(reA, reB) {
+		return &PatternComparison{Equivalent: true}, nil
+	}
+
+	matchA, err := regexp.Compile(`\A(?:` + a + `)\z`)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPattern, err)
+	}
+	matchB, err := regexp.Compile(`\A(?:` + b + `)\z`)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPattern, err)
+	}
+
+	result := &PatternComparison{}
+	for _, input := range comparisonInputs(reA, reB, opts.MaxPatternLength/2) {
+		inA, inB := matchA.MatchString(input), matchB.MatchString(input)
+		if inA == inB {
+			continue
+		}
+		if inB {
+			result.MorePermissive = true
+		} else {
+			result.MoreRestrictive = true
+		}
+		if result.ExampleDifference == "" {
+			result.ExampleDifference = input
+		}
+		if result.MorePermissive && result.MoreRestrictive {
+			break
+		}
+	}
+
+	result.Equivalent = !result.MorePermissive && !result.MoreRestrictive
+	return result, nil
+}
+
+// IsEquivalent checks whether two patterns match the same strings by
+// sampling: both patterns are compiled with Go's regexp package and matched,
+// as whole strings, against 1000 random strings of up to 50 characters
+// drawn from the characters the patterns mention, and against short
+// strings each pattern accepts. If opts is nil, DefaultOptions() is used;
+// patterns longer than opts.MaxPatternLength are rejected with
+// ErrPatternTooLong.
+//
+// A true result is not a proof: the patterns agreed on every sample, but
+// may differ on strings that were not tried. A false result is always
+// real, and the error wraps ErrNotEquivalent and quotes the counterexample.
+// The samples are the same on every call, so results are reproducible.
+//
+// Example:
+//
+//	ok, err := regret.IsEquivalent(`(a+)+b`, `a+b`, nil)
+//	if !ok {
+//	    return err // patterns are not equivalent: "..." is matched by ...
+//	}
+func IsEquivalent(patternA, patternB string, opts *Options) (bool, error) {
+	if opts == nil {
+		opts = DefaultOptions()
+	}
+	for _, pattern := range []string{patternA, patternB} {
+		if opts.MaxPatternLength > 0 && len(pattern) > opts.MaxPatternLength {
+			return false, fmt.Errorf("%w: %d > %d", ErrPatternTooLong, len(pattern), opts.MaxPatternLength)
+		}
+	}
+
+	matchA, err := regexp.Compile(`\A(?:` + patternA + `)\z`)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidPattern, err)
+	}
+	matchB, err := regexp.Compile(`\A(?:` + patternB + `)\z`)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidPattern, err)
+	}
+
+	p := parser.NewParser()
+	reA, err := p.Parse(patternA)
+	if err != nil {
+		return false, err
+	}
+	reB, err := p.Parse(patternB)
+	if err != nil {
+		return false, err
+	}
+
+	for _, input := range sampleInputs(reA, reB, opts.MaxNFAStates) {
+		inA, inB := matchA.MatchString(input), matchB.MatchString(input)
+		switch {
+		case inA && !inB:
+			return false, fmt.Errorf("%w: %q is matched by %q but not by %q", ErrNotEquivalent, input, patternA, patternB)
+		case inB && !inA:
+			return false, fmt.Errorf("%w: %q is matched by %q but not by %q", ErrNotEquivalent, input, patternB, patternA)
+		}
+	}
+	return true, nil
+}
+
+// sampleInputs returns the strings IsEquivalent matches both patterns
+// against: short strings each pattern accepts, so that the patterns are
+// compared on matches and not only on rejections, then random strings over
+// the comparison alphabet of both patterns, from a fixed seed.
+func sampleInputs(a, b *syntax.Regexp, maxNFAStates int) []string {
+	var samples []string
+	for _, re := range []*syntax.Regexp{a, b} {
+		if nfa, err := parser.BuildNFAWithLimit(re, maxNFAStates); err == nil {
+			samples = append(samples, nfa.AcceptedInputs(equivalenceMatches, equivalenceMaxLength)...)
+		}
+	}
+
+	alphabet := comparisonAlphabet(a, b)
+	rng := rand.New(rand.NewPCG(1, 2))
+	for i := 0; i < equivalenceSamples; i++ {
+		input := make([]rune, rng.IntN(equivalenceMaxLength+1))
+		for j := range input {
+			input[j] = alphabet[rng.IntN(len(alphabet))]
+		}
+		samples = append(samples, string(input))
+	}
+
+	return samples
+}
+
+// isKnownRewrite reports whether the rewrite rules turn one pattern into the other.
+func isKnownRewrite(a, b *syntax.Regexp) bool {
+	if a.Equal(b) {
+		return true
+	}
+	rewrittenA := rewrite.Rewrite(a).Regexp
+	rewrittenB := rewrite.Rewrite(b).Regexp
+	return rewrittenA.Equal(b) || rewrittenB.Equal(a) || rewrittenA.Equal(rewrittenB)
+}
+
+// comparisonInputs builds the strings both patterns are tested against.
+func comparisonInputs(a, b *syntax.Regexp, maxLen int) []string {
+	alphabet := comparisonAlphabet(a, b)
+	inputs := []string{""}
+
+	// All strings over the alphabet, as long as the budget allows
+	words := []string{""}
+	for length := 1; length <= maxLen && len(inputs)+len(words)*len(alphabet) <= maxCompareSamples; length++ {
+		var next []string
+		for _, w := range words {
+			for _, r := range alphabet {
+				next = append(next, w+string(r))
+			}
+		}
+		inputs = append(inputs, next...)
+		words = next
+	}
+
+	// Longer inputs built from each pattern's pump components
+	gen := pump.NewGenerator(nil)
+	for _, re := range []*syntax.Regexp{a, b} {
+		pumps, err := gen.Generate(re, re.String())
+		if err != nil {
+			continue
+		}
+		for _, p := range pumps {
+			if p.PumpComponent == "" {
+				continue
+			}
+			for size := 1; len(p.BaseString)+size*len(p.PumpComponent) <= maxLen; size *= 2 {
+				input := p.GenerateInput(size)
+				inputs = append(inputs, input[:len(input)-len(p.FailSuffix)], input)
+			}
+		}
+	}
+
+	return inputs
+}
+
+// comparisonAlphabet collects the characters that can tell two patterns apart:
+// literals, class boundaries and the characters just outside each class.
+func comparisonAlphabet(patterns ...*syntax.Regexp) []rune {
+	set := map[rune]bool{'a': true, '0': true, ' ': true, '\n': true}
+
+	add := func(r rune) {
+		if r >= 0 && r <= unicode.MaxRune {
+			set[r] = true
+		}
+	}
+
+	for _, re := range patterns {
+		parser.Walk(re, func(node *syntax.Regexp) (bool, error) {
+			switch node.Op {
+			case syntax.OpLiteral:
+				for _, r := range node.Rune {
+					add(r)
+					if node.Flags&syntax.FoldCase != 0 {
+						add(unicode.SimpleFold(r))
+					}
+				}
+			case syntax.OpCharClass:
+				for i := 0; i+1 < len(node.Rune); i += 2 {
+					lo, hi := node.Rune[i], node.Rune[i+1]
+					add(lo)
+					add(hi)
+					add(lo - 1)
+					add(hi + 1)
+				}
+			}
+			return true, nil
+		})
+	}
+
+	alphabet := make([]rune, 0, len(set))
+	for r := range set {
+		alphabet = append(alphabet, r)
+	}
+	sort.Slice(alphabet, func(i, j int) bool { return alphabet[i] < alphabet[j] })
+	return alphabet
+}
@@ -0,0 +1,118 @@
+package regret
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+)
+
+func TestComparePatterns(t *testing.T) {
+	tests := []struct {
+		name            string
+		a, b            string
+		equivalent      bool
+		morePermissive  bool
+		moreRestrictive bool
+	}{
+		{"known rewrite", "(a+)+", "a+", true, false, false},
+		{"merged quantifiers", "a*a+", "a+", true, false, false},
+		{"identical", "^[a-z]+$", "^[a-z]+$", true, false, false},
+		{"reordered alternation", "cat|dog", "dog|cat", true, false, false},
+		{"narrower class", "[a-z]+", "[a-y]+", false, false, true},
+		{"accepts empty", "a+", "a*", false, true, false},
+		{"disjoint", "a+", "b+", false, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ComparePatterns(tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("ComparePatterns(%q, %q) error = %v", tt.a, tt.b, err)
+			}
+			if got.Equivalent != tt.equivalent ||
+				got.MorePermissive != tt.morePermissive ||
+				got.MoreRestrictive != tt.moreRestrictive {
+				t.Errorf("ComparePatterns(%q, %q) = %+v", tt.a, tt.b, got)
+			}
+			if tt.equivalent {
+				if got.ExampleDifference != "" {
+					t.Errorf("ExampleDifference = %q for equivalent patterns", got.ExampleDifference)
+				}
+				return
+			}
+
+			// The example must actually tell the patterns apart
+			inA := regexp.MustCompile(`\A(?:` + tt.a + `)\z`).MatchString(got.ExampleDifference)
+			inB := regexp.MustCompile(`\A(?:` + tt.b + `)\z`).MatchString(got.ExampleDifference)
+			if inA == inB {
+				t.Errorf("ExampleDifference %q is matched by both or neither pattern", got.ExampleDifference)
+			}
+		})
+	}
+}
+
+func TestComparePatterns_InvalidPattern(t *testing.T) {
+	if _, err := ComparePatterns("(a+", "a+"); err == nil {
+		t.Error("ComparePatterns() expected error for invalid pattern")
+	}
+	if _, err := ComparePatterns("a+", "(a+"); err == nil {
+		t.Error("ComparePatterns() expected error for invalid pattern")
+	}
+}
+
+func TestComparePatterns_TooLong(t *testing.T) {
+	long := make([]byte, DefaultOptions().MaxPatternLength+1)
+	for i := range long {
+		long[i] = 'a'
+	}
+	if _, err := ComparePatterns(string(long), "a+"); !errors.Is(err, ErrPatternTooLong) {
+		t.Errorf("ComparePatterns() error = %v, want ErrPatternTooLong", err)
+	}
+}
+
+func TestIsEquivalent(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		// Nested quantifiers change the running time, not the strings matched
+		{"a+", "(a+)+", true},
+		{`(a+)+b`, `a+b`, true},
+		{`^(\d+)*$`, `^\d*$`, true},
+		{"cat|dog", "dog|cat", true},
+		{"a+", "a*", false},
+		{"[a-z]+", "[a-y]+", false},
+		{`\w+@\w+\.com`, `\w+@\w+\.org`, false},
+		{`[a-z]{3,10}`, `[a-z]{3,}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			got, err := IsEquivalent(tt.a, tt.b, nil)
+			if got != tt.want {
+				t.Fatalf("IsEquivalent(%q, %q) = %v, %v, want %v", tt.a, tt.b, got, err, tt.want)
+			}
+			if tt.want {
+				if err != nil {
+					t.Errorf("IsEquivalent(%q, %q) error = %v, want nil", tt.a, tt.b, err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrNotEquivalent) {
+				t.Errorf("IsEquivalent(%q, %q) error = %v, want ErrNotEquivalent", tt.a, tt.b, err)
+			}
+		})
+	}
+}
+
+func TestIsEquivalent_Errors(t *testing.T) {
+	if _, err := IsEquivalent("(a+", "a+", nil); !errors.Is(err, ErrInvalidPattern) {
+		t.Errorf("IsEquivalent() error = %v, want ErrInvalidPattern", err)
+	}
+
+	opts := DefaultOptions()
+	opts.MaxPatternLength = 3
+	if _, err := IsEquivalent("abcd", "a+", opts); !errors.Is(err, ErrPatternTooLong) {
+		t.Errorf("IsEquivalent() error = %v, want ErrPatternTooLong", err)
+	}
+}
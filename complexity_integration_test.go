@@ -1,7 +1,11 @@
 package regret
 
 import (
+	"errors"
+	"math"
+	"strings"
 	"testing"
+	"time"
 )
 
 // TestComplexityAnalysis tests the full complexity analysis API.
@@ -142,3 +146,223 @@ func BenchmarkAnalyzeComplexity(b *testing.B) {
 		_, _ = AnalyzeComplexity(pattern)
 	}
 }
+
+func TestAnalyzeComplexity_Breakdown(t *testing.T) {
+	score, err := AnalyzeComplexity("(a+)+")
+	if err != nil {
+		t.Fatalf("AnalyzeComplexity() error = %v", err)
+	}
+
+	var nesting *SubScore
+	for i := range score.Breakdown {
+		if score.Breakdown[i].Name == "nesting" {
+			nesting = &score.Breakdown[i]
+		}
+	}
+	if nesting == nil {
+		t.Fatalf("Breakdown has no nesting entry: %+v", score.Breakdown)
+	}
+	if nesting.Score == 0 || nesting.Description == "" {
+		t.Errorf("nesting entry = %+v, want a non-zero score with a description", *nesting)
+	}
+}
+
+func TestAnalyzeComplexity_Partial(t *testing.T) {
+	score, err := AnalyzeComplexity("^[a-z]+$")
+	if err != nil {
+		t.Fatalf("AnalyzeComplexity() error = %v", err)
+	}
+	if score.Partial || score.Completeness != 1 || !score.Safe {
+		t.Errorf("complete analysis: Partial = %v, Completeness = %v, Safe = %v", score.Partial, score.Completeness, score.Safe)
+	}
+
+	opts := DefaultOptions()
+	opts.Timeout = time.Nanosecond
+	score, err = AnalyzeComplexityWithOptions("^[a-z]+$", opts)
+	if err != nil {
+		t.Fatalf("AnalyzeComplexityWithOptions() error = %v", err)
+	}
+	if !score.Partial || score.Completeness != 0 {
+		t.Fatalf("timed out analysis: Partial = %v, Completeness = %v", score.Partial, score.Completeness)
+	}
+	if score.Safe || score.Overall != opts.MaxComplexityScore {
+		t.Errorf("timed out analysis: Overall = %d, Safe = %v, want %d and unsafe", score.Overall, score.Safe, opts.MaxComplexityScore)
+	}
+}
+
+func TestAnalyzeComplexity_MinimizedStateCount(t *testing.T) {
+	score, err := AnalyzeComplexity("[ab]*a[ab]{4}")
+	if err != nil {
+		t.Fatalf("AnalyzeComplexity() error = %v", err)
+	}
+	if score.Metrics.MinimizedStateCount != 32 {
+		t.Errorf("MinimizedStateCount = %d, want 32", score.Metrics.MinimizedStateCount)
+	}
+
+	// Only computed with every check enabled
+	score, err = NewValidator(DefaultOptions()).AnalyzeComplexity("[ab]*a[ab]{4}")
+	if err != nil {
+		t.Fatalf("AnalyzeComplexity() error = %v", err)
+	}
+	if score.Metrics.MinimizedStateCount != 0 {
+		t.Errorf("MinimizedStateCount with default checks = %d, want 0", score.Metrics.MinimizedStateCount)
+	}
+}
+
+func TestAnalyzeComplexityWithOptions_MaxPatternLength(t *testing.T) {
+	opts := DefaultOptions()
+	opts.MaxPatternLength = 10
+
+	if _, err := AnalyzeComplexityWithOptions(strings.Repeat("a", 11), opts); !errors.Is(err, ErrPatternTooLong) {
+		t.Errorf("AnalyzeComplexityWithOptions() error = %v, want ErrPatternTooLong", err)
+	}
+	if _, err := AnalyzeComplexityWithOptions(strings.Repeat("a", 10), opts); err != nil {
+		t.Errorf("AnalyzeComplexityWithOptions() at the limit error = %v", err)
+	}
+	if _, err := AnalyzeComplexity(strings.Repeat("a", ThoroughOptions().MaxPatternLength+1)); !errors.Is(err, ErrPatternTooLong) {
+		t.Errorf("AnalyzeComplexity() error = %v, want ErrPatternTooLong", err)
+	}
+}
+
+func TestAnalyzeComplexity_Safe(t *testing.T) {
+	linear := `^.*$`
+
+	score, err := NewValidator(DefaultOptions()).AnalyzeComplexity(linear)
+	if err != nil {
+		t.Fatalf("AnalyzeComplexity() error = %v", err)
+	}
+	if !score.Safe {
+		t.Errorf("Safe = false for %s with default options (score %d)", linear, score.Overall)
+	}
+
+	// A linear pattern is unsafe once its score reaches the threshold.
+	// Linear patterns score low, so the threshold has to be very low.
+	opts := DefaultOptions()
+	opts.MaxComplexityScore = 5
+	score, err = NewValidator(opts).AnalyzeComplexity(linear)
+	if err != nil {
+		t.Fatalf("AnalyzeComplexity() error = %v", err)
+	}
+	if score.HasEDA || score.HasIDA || score.Overall < 5 {
+		t.Fatalf("score = %+v, want linear pattern scoring at least 5", score)
+	}
+	if score.Safe {
+		t.Errorf("Safe = true with score %d and MaxComplexityScore 5", score.Overall)
+	}
+
+	// A safety margin lowers the threshold below the score: 8 * (1 - 0.5) = 4
+	opts = DefaultOptions()
+	opts.MaxComplexityScore = 8
+	score, err = NewValidator(opts).AnalyzeComplexity(linear)
+	if err != nil {
+		t.Fatalf("AnalyzeComplexity() error = %v", err)
+	}
+	if !score.Safe {
+		t.Fatalf("Safe = false with score %d and MaxComplexityScore 8", score.Overall)
+	}
+	opts.EnableSafetyMargin = 0.5
+	score, err = NewValidator(opts).AnalyzeComplexity(linear)
+	if err != nil {
+		t.Fatalf("AnalyzeComplexity() error = %v", err)
+	}
+	if score.Safe {
+		t.Errorf("Safe = true with score %d, MaxComplexityScore 8 and EnableSafetyMargin 0.5", score.Overall)
+	}
+	if got := effectiveThreshold(70, 0.2); got != 56 {
+		t.Errorf("effectiveThreshold(70, 0.2) = %d, want 56", got)
+	}
+
+	// EDA is never safe, whatever the threshold
+	opts = DefaultOptions()
+	opts.MaxComplexityScore = 100
+	score, err = NewValidator(opts).AnalyzeComplexity("(a+)+")
+	if err != nil {
+		t.Fatalf("AnalyzeComplexity() error = %v", err)
+	}
+	if !score.HasEDA || score.Safe {
+		t.Errorf("(a+)+ HasEDA = %v Safe = %v, want EDA and unsafe", score.HasEDA, score.Safe)
+	}
+}
+
+func TestAnalyzeComplexity_ExploitabilityScore(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		min, max float64
+	}{
+		{"(a+)+", 1, 1},
+		{"([a-z]+)+", 0.30, 0.31}, // 1/ln(26)
+		{"(.+)+", 0.01, 0.1},
+		{"abc", 0, 0},
+	}
+
+	for _, tt := range tests {
+		score, err := AnalyzeComplexity(tt.pattern)
+		if err != nil {
+			t.Fatalf("AnalyzeComplexity(%q) error = %v", tt.pattern, err)
+		}
+		if score.ExploitabilityScore < tt.min || score.ExploitabilityScore > tt.max {
+			t.Errorf("AnalyzeComplexity(%q).ExploitabilityScore = %v, want %v..%v", tt.pattern, score.ExploitabilityScore, tt.min, tt.max)
+		}
+	}
+
+	// Adversarial input uses characters the quantified class accepts
+	score, err := AnalyzeComplexity("([0-9]+)+$")
+	if err != nil {
+		t.Fatalf("AnalyzeComplexity() error = %v", err)
+	}
+	if !strings.HasPrefix(score.WorstCaseInput, "00000") {
+		t.Errorf("WorstCaseInput = %q, want pumped digits", score.WorstCaseInput)
+	}
+}
+
+func TestAnalyzeComplexity_NormalizedScore(t *testing.T) {
+	short, err := AnalyzeComplexityWithOptions("(a+)+", nil)
+	if err != nil {
+		t.Fatalf("AnalyzeComplexityWithOptions() error = %v", err)
+	}
+	if want := float64(short.Overall) / math.Log(6); math.Abs(short.NormalizedScore-want) > 1e-9 {
+		t.Errorf("NormalizedScore = %v, want %v", short.NormalizedScore, want)
+	}
+
+	// The same danger in a longer pattern is less concentrated
+	long, err := AnalyzeComplexityWithOptions("^[0-9]{4}-[0-9]{2}-[0-9]{2}T(a+)+$", nil)
+	if err != nil {
+		t.Fatalf("AnalyzeComplexityWithOptions() error = %v", err)
+	}
+	if long.Overall < short.Overall || long.NormalizedScore >= short.NormalizedScore {
+		t.Errorf("long pattern scores %d (normalized %v), short %d (normalized %v), want the short one normalized higher",
+			long.Overall, long.NormalizedScore, short.Overall, short.NormalizedScore)
+	}
+
+	empty, err := AnalyzeComplexityWithOptions("", nil)
+	if err != nil {
+		t.Fatalf("AnalyzeComplexityWithOptions() error = %v", err)
+	}
+	if empty.NormalizedScore != 0 {
+		t.Errorf("NormalizedScore of empty pattern = %v, want 0", empty.NormalizedScore)
+	}
+}
+
+func TestAnalyzeComplexity_Issues(t *testing.T) {
+	for _, pattern := range []string{"(a+)+", "^[a-z]+$", `(\d+)*x`} {
+		t.Run(pattern, func(t *testing.T) {
+			score, err := AnalyzeComplexityWithOptions(pattern, ThoroughOptions())
+			if err != nil {
+				t.Fatalf("AnalyzeComplexityWithOptions() error = %v", err)
+			}
+			want, err := ValidateWithOptions(pattern, ThoroughOptions())
+			if err != nil {
+				t.Fatalf("ValidateWithOptions() error = %v", err)
+			}
+			if len(score.Issues) != len(want) {
+				t.Fatalf("Issues = %v, want %v", score.Issues, want)
+			}
+			for i := range want {
+				if score.Issues[i].Type != want[i].Type || score.Issues[i].Severity != want[i].Severity {
+					t.Errorf("Issues[%d] = %v/%v, want %v/%v", i,
+						score.Issues[i].Type, score.Issues[i].Severity, want[i].Type, want[i].Severity)
+				}
+			}
+		})
+	}
+}
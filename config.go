@@ -0,0 +1,136 @@
+package regret
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// ParseOptions reads Options from a config file. The format is picked from
+// the file extension: .yaml or .yml, .toml, or .json. Keys are the snake_case
+// names of the Options fields, as in RuleSet files:
+//
+//	mode: thorough
+//	timeout: 500ms
+//	max_complexity_score: 60
+//	deny_list:
+//	  - "(a+)+"
+//
+// Fields missing from the file keep their DefaultOptions values. Unknown
+// keys and out-of-range values are rejected.
+//
+// Example:
+//
+//	opts, err := regret.ParseOptions("regret.yaml")
+//	if err != nil {
+//	    return err
+//	}
+//	v := regret.NewValidator(opts)
+func ParseOptions(configFile string) (*Options, error) {
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var v optionsJSON
+	switch ext := strings.ToLower(filepath.Ext(configFile)); ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		// An empty file decodes to io.EOF and leaves the defaults
+		if err := dec.Decode(&v); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%s: %w", configFile, err)
+		}
+	case ".toml":
+		md, err := toml.Decode(string(data), &v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", configFile, err)
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			return nil, fmt.Errorf("%s: unknown field %q", configFile, undecoded[0].String())
+		}
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&v); err != nil {
+			return nil, fmt.Errorf("%s: %w", configFile, err)
+		}
+	default:
+		return nil, fmt.Errorf("%s: unsupported config format %q (expected .yaml, .yml, .toml or .json)", configFile, ext)
+	}
+
+	opts := DefaultOptions()
+	if err := v.apply(opts); err != nil {
+		return nil, fmt.Errorf("%s: %w", configFile, err)
+	}
+	if err := validateOptions(opts); err != nil {
+		return nil, fmt.Errorf("%s: %w", configFile, err)
+	}
+	return opts, nil
+}
+
+// ToYAML encodes the options as YAML in the format read by ParseOptions.
+// Every field is written, which makes the output a useful starter config.
+func (o *Options) ToYAML() ([]byte, error) {
+	return yaml.Marshal(newOptionsJSON(o))
+}
+
+// ToJSON encodes the options as indented JSON in the format read by
+// ParseOptions. Every field is written.
+func (o *Options) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(newOptionsJSON(o), "", "  ")
+}
+
+// validateOptions checks that the numeric options are in range.
+func validateOptions(opts *Options) error {
+	if opts.Mode < Fast || opts.Mode > Thorough {
+		return fmt.Errorf("invalid mode %d", opts.Mode)
+	}
+	if opts.Dialect < DialectPCRE || opts.Dialect > DialectRuby {
+		return fmt.Errorf("invalid dialect %d", opts.Dialect)
+	}
+	if opts.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative: %v", opts.Timeout)
+	}
+	if opts.MaxComplexityScore < 0 || opts.MaxComplexityScore > 100 {
+		return fmt.Errorf("max_complexity_score must be between 0 and 100: %d", opts.MaxComplexityScore)
+	}
+	switch opts.PatternEncoding {
+	case "", PatternEncodingRaw, PatternEncodingBase64, PatternEncodingURL:
+	default:
+		return fmt.Errorf("pattern_encoding must be raw, base64 or url: %q", opts.PatternEncoding)
+	}
+	if opts.EnableSafetyMargin < 0 || opts.EnableSafetyMargin > 1 {
+		return fmt.Errorf("enable_safety_margin must be between 0 and 1: %v", opts.EnableSafetyMargin)
+	}
+
+	for _, f := range []struct {
+		name  string
+		value int
+	}{
+		{"max_pattern_length", opts.MaxPatternLength},
+		{"max_nesting_depth", opts.MaxNestingDepth},
+		{"max_quantifiers", opts.MaxQuantifiers},
+		{"max_quantifier_range", opts.MaxQuantifierRange},
+		{"max_repetition_count", opts.MaxRepetitionCount},
+		{"max_alternation_branches", opts.MaxAlternationBranches},
+		{"max_nfa_states", opts.MaxNFAStates},
+		{"max_dfa_states", opts.MaxDFAStates},
+		{"max_issues", opts.MaxIssues},
+		{"concurrency", opts.Concurrency},
+		{"cache_size", opts.CacheSize},
+	} {
+		if f.value < 0 {
+			return fmt.Errorf("%s must not be negative: %d", f.name, f.value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package regret
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp/syntax"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{"yaml", "regret.yaml", "mode: thorough\ntimeout: 250ms\nmax_complexity_score: 60\ndeny_list:\n  - \"(a+)+\"\n"},
+		{"yml", "regret.yml", "mode: thorough\ntimeout: 250ms\nmax_complexity_score: 60\ndeny_list: [\"(a+)+\"]\n"},
+		{"toml", "regret.toml", "mode = \"thorough\"\ntimeout = \"250ms\"\nmax_complexity_score = 60\ndeny_list = [\"(a+)+\"]\n"},
+		{"json", "regret.json", `{"mode": "thorough", "timeout": "250ms", "max_complexity_score": 60, "deny_list": ["(a+)+"]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := ParseOptions(writeConfig(t, tt.file, tt.content))
+			if err != nil {
+				t.Fatalf("ParseOptions() error = %v", err)
+			}
+			if opts.Mode != Thorough || opts.Timeout != 250*time.Millisecond || opts.MaxComplexityScore != 60 ||
+				len(opts.DenyList) != 1 || opts.DenyList[0] != "(a+)+" {
+				t.Errorf("ParseOptions() = %+v", opts)
+			}
+			// Missing fields keep their defaults
+			if opts.MaxPatternLength != DefaultOptions().MaxPatternLength {
+				t.Errorf("MaxPatternLength = %d, want default", opts.MaxPatternLength)
+			}
+		})
+	}
+}
+
+func TestParseOptions_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    string
+	}{
+		{"unknown yaml key", "c.yaml", "max_depth: 2\n", "max_depth"},
+		{"unknown toml key", "c.toml", "max_depth = 2\n", "max_depth"},
+		{"unknown json key", "c.json", `{"max_depth": 2}`, "max_depth"},
+		{"bad mode", "c.yaml", "mode: paranoid\n", "invalid mode"},
+		{"bad dialect", "c.yaml", "dialect: perl6\n", "invalid dialect"},
+		{"bad override type", "c.yaml", "severity_override:\n  slow_regex: critical\n", "unknown issue type"},
+		{"bad override severity", "c.json", `{"severity_override": {"polynomial_backtracking": "urgent"}}`, "unknown severity"},
+		{"score out of range", "c.json", `{"max_complexity_score": 150}`, "max_complexity_score"},
+		{"negative limit", "c.toml", "max_nfa_states = -1\n", "max_nfa_states"},
+		{"unknown format", "c.ini", "mode=fast\n", "unsupported config format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseOptions(writeConfig(t, tt.file, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseOptions() error = %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParseOptions(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("ParseOptions() expected error for missing file")
+	}
+}
+
+func TestOptions_ToYAMLRoundTrip(t *testing.T) {
+	in := ThoroughOptions()
+	in.DenyList = []string{"(a+)+"}
+	in.AllowUnsafePatterns = []string{"(b+)+"}
+	in.Dialect = DialectJava
+	in.SeverityOverride = map[IssueType]Severity{PolynomialBacktracking: Critical}
+	in.TreatWarningsAsErrors = true
+	in.MaxIssues = 10
+	in.EnableSafetyMargin = 0.2
+	in.PatternEncoding = PatternEncodingBase64
+	in.CacheSize = 100
+	in.ParseFlags = syntax.Perl | syntax.FoldCase
+
+	data, err := in.ToYAML()
+	if err != nil {
+		t.Fatalf("ToYAML() error = %v", err)
+	}
+	if !strings.Contains(string(data), "mode: thorough\n") || !strings.Contains(string(data), "timeout: 1s\n") ||
+		!strings.Contains(string(data), "dialect: java\n") {
+		t.Errorf("ToYAML() = %s, want readable mode, timeout and dialect", data)
+	}
+
+	out, err := ParseOptions(writeConfig(t, "regret.yaml", string(data)))
+	if err != nil {
+		t.Fatalf("ParseOptions() error = %v", err)
+	}
+	if out.Mode != in.Mode || out.Timeout != in.Timeout || out.TimeoutBehavior != in.TimeoutBehavior ||
+		out.Checks != in.Checks || out.StrictMode != in.StrictMode ||
+		out.TreatWarningsAsErrors != in.TreatWarningsAsErrors || out.MaxIssues != in.MaxIssues || out.ParseFlags != in.ParseFlags || out.Dialect != in.Dialect || len(out.DenyList) != 1 ||
+		len(out.AllowUnsafePatterns) != 1 || out.EnableSafetyMargin != in.EnableSafetyMargin || out.PatternEncoding != in.PatternEncoding || out.CacheSize != in.CacheSize || out.SeverityOverride[PolynomialBacktracking] != Critical {
+		t.Errorf("round trip = %+v, want %+v", out, in)
+	}
+}
+
+func TestOptions_ToJSON(t *testing.T) {
+	data, err := DefaultOptions().ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON() error = %v", err)
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("ToJSON() produced invalid JSON: %v", err)
+	}
+	if fields["mode"] != "balanced" || fields["max_nfa_states"] != float64(10000) {
+		t.Errorf("ToJSON() = %s", data)
+	}
+
+	out, err := ParseOptions(writeConfig(t, "regret.json", string(data)))
+	if err != nil {
+		t.Fatalf("ParseOptions() error = %v", err)
+	}
+	if out.Mode != Balanced || out.MaxNFAStates != 10000 {
+		t.Errorf("round trip = %+v", out)
+	}
+}
@@ -0,0 +1,144 @@
+package regret
+
+import "sync/atomic"
+
+// ValidatorStats is a snapshot of the counters of a CountingValidator.
+type ValidatorStats struct {
+	// TotalCalls counts every validation, including those that failed.
+	TotalCalls uint64
+
+	// SafeCount and UnsafeCount count the validations without and with
+	// issues, and ErrorCount those that returned an error.
+	SafeCount   uint64
+	UnsafeCount uint64
+	ErrorCount  uint64
+
+	// ScoreHistogram[i] counts the validated patterns with a complexity
+	// score in [i*10, (i+1)*10). The last bucket also counts scores of 100.
+	// Patterns whose score could not be computed are not counted, so the
+	// buckets may add up to less than SafeCount + UnsafeCount.
+	ScoreHistogram [10]uint64
+}
+
+// CountingValidator wraps a Validator and counts its validations and their
+// outcomes, a lightweight alternative to the Prometheus metrics package for
+// programs that do not want that dependency. Every method that validates a
+// pattern is counted. It is safe for concurrent use.
+type CountingValidator struct {
+	validator *Validator
+
+	total, safe, unsafe, errors atomic.Uint64
+	histogram                   [10]atomic.Uint64
+}
+
+// NewCountingValidator creates a counting validator with the given options.
+// A nil opts uses DefaultOptions. It returns an error wrapping
+// ErrInvalidOptions if the options are invalid, as reported by
+// Options.Validate.
+//
+// Example:
+//
+//	v, err := regret.NewCountingValidator(nil)
+//	if err != nil {
+//	    return err
+//	}
+//	issues, err := v.Validate(userPattern)
+//	// ...
+//	stats := v.Stats()
+//	log.Printf("%d of %d patterns unsafe", stats.UnsafeCount, stats.TotalCalls)
+func NewCountingValidator(opts *Options) (*CountingValidator, error) {
+	if opts != nil {
+		if err := opts.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return &CountingValidator{validator: NewValidator(opts)}, nil
+}
+
+// Options returns the options the validator was created with.
+func (v *CountingValidator) Options() *Options {
+	return v.validator.Options()
+}
+
+// Validate validates a pattern like Validator.Validate and counts the
+// outcome and complexity score. The issues and the score come from a
+// single Report, so the pattern is only analyzed once.
+func (v *CountingValidator) Validate(pattern string) ([]Issue, error) {
+	report, err := v.Report(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return report.Issues, nil
+}
+
+// IsSafe reports whether pattern is valid and has no issues, counting the
+// validation like Validate.
+func (v *CountingValidator) IsSafe(pattern string) bool {
+	issues, err := v.Validate(pattern)
+	return err == nil && len(issues) == 0
+}
+
+// Report reports on a pattern like Validator.Report and counts the outcome
+// and complexity score.
+func (v *CountingValidator) Report(pattern string) (*ValidationReport, error) {
+	report, err := v.validator.Report(pattern)
+	if err != nil {
+		v.record(nil, err)
+		return report, err
+	}
+
+	v.record(report.Issues, nil)
+	v.observe(report.Complexity.Overall)
+	return report, nil
+}
+
+// AnalyzeComplexity analyzes a pattern like Validator.AnalyzeComplexity and
+// counts it like Report.
+func (v *CountingValidator) AnalyzeComplexity(pattern string) (*ComplexityScore, error) {
+	report, err := v.Report(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return report.Complexity, nil
+}
+
+// ValidateAll reports on named patterns like Validator.ValidateAll and
+// counts each of them like Report.
+func (v *CountingValidator) ValidateAll(patterns map[string]string) map[string]PatternResult {
+	return validateAll(patterns, v.validator.opts, v.Report)
+}
+
+// Stats returns the counters. They are read one at a time, so a snapshot
+// taken during validations may count a call in TotalCalls but not yet in
+// its outcome.
+func (v *CountingValidator) Stats() ValidatorStats {
+	stats := ValidatorStats{
+		TotalCalls:  v.total.Load(),
+		SafeCount:   v.safe.Load(),
+		UnsafeCount: v.unsafe.Load(),
+		ErrorCount:  v.errors.Load(),
+	}
+	for i := range v.histogram {
+		stats.ScoreHistogram[i] = v.histogram[i].Load()
+	}
+	return stats
+}
+
+// record counts a validation with the given outcome.
+func (v *CountingValidator) record(issues []Issue, err error) {
+	v.total.Add(1)
+	switch {
+	case err != nil:
+		v.errors.Add(1)
+	case len(issues) == 0:
+		v.safe.Add(1)
+	default:
+		v.unsafe.Add(1)
+	}
+}
+
+// observe counts a complexity score in the histogram.
+func (v *CountingValidator) observe(score int) {
+	bucket := min(max(score/10, 0), len(v.histogram)-1)
+	v.histogram[bucket].Add(1)
+}
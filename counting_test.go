@@ -0,0 +1,104 @@
+package regret
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestCountingValidator(t *testing.T) {
+	v, err := NewCountingValidator(nil)
+	if err != nil {
+		t.Fatalf("NewCountingValidator() error = %v", err)
+	}
+
+	if _, err := v.Validate(`^[a-z]+$`); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if v.IsSafe(`(a+)+`) {
+		t.Error("IsSafe((a+)+) = true, want false")
+	}
+	if _, err := v.Validate(`(a+`); err == nil {
+		t.Fatal("Validate() expected error for invalid pattern")
+	}
+	report, err := v.Report(`(a+)+`)
+	if err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+
+	stats := v.Stats()
+	if stats.TotalCalls != 4 || stats.SafeCount != 1 || stats.UnsafeCount != 2 || stats.ErrorCount != 1 {
+		t.Errorf("Stats() = %+v, want 4 calls: 1 safe, 2 unsafe, 1 error", stats)
+	}
+	var scored uint64
+	for _, n := range stats.ScoreHistogram {
+		scored += n
+	}
+	if scored != 3 {
+		t.Errorf("ScoreHistogram = %v, want 3 scores", stats.ScoreHistogram)
+	}
+	if bucket := min(report.Complexity.Overall/10, 9); stats.ScoreHistogram[bucket] < 2 {
+		t.Errorf("ScoreHistogram[%d] = %d, want the two (a+)+ scores", bucket, stats.ScoreHistogram[bucket])
+	}
+}
+
+func TestCountingValidator_CountsEveryMethod(t *testing.T) {
+	v, err := NewCountingValidator(nil)
+	if err != nil {
+		t.Fatalf("NewCountingValidator() error = %v", err)
+	}
+
+	if _, err := v.AnalyzeComplexity(`(a+)+`); err != nil {
+		t.Fatalf("AnalyzeComplexity() error = %v", err)
+	}
+	results := v.ValidateAll(map[string]string{"digits": `^\d+$`, "nested": `(a+)+`, "invalid": `(a+`})
+	if len(results) != 3 {
+		t.Fatalf("ValidateAll() = %d results, want 3", len(results))
+	}
+
+	stats := v.Stats()
+	if stats.TotalCalls != 4 || stats.SafeCount != 1 || stats.UnsafeCount != 2 || stats.ErrorCount != 1 {
+		t.Errorf("Stats() = %+v, want 4 calls: 1 safe, 2 unsafe, 1 error", stats)
+	}
+}
+
+func TestCountingValidator_Concurrent(t *testing.T) {
+	v, err := NewCountingValidator(FastOptions())
+	if err != nil {
+		t.Fatalf("NewCountingValidator() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v.IsSafe(`a+b`)
+		}()
+	}
+	wg.Wait()
+
+	if stats := v.Stats(); stats.TotalCalls != 50 || stats.SafeCount != 50 {
+		t.Errorf("Stats() = %+v, want 50 safe calls", stats)
+	}
+}
+
+func TestCountingValidator_Histogram(t *testing.T) {
+	v := &CountingValidator{}
+	for _, score := range []int{0, 9, 10, 55, 99, 100} {
+		v.observe(score)
+	}
+	want := [10]uint64{2, 1, 0, 0, 0, 1, 0, 0, 0, 2}
+	if got := v.Stats().ScoreHistogram; got != want {
+		t.Errorf("ScoreHistogram = %v, want %v", got, want)
+	}
+}
+
+func TestNewCountingValidator_InvalidOptions(t *testing.T) {
+	opts := DefaultOptions()
+	opts.AllowUnsafe = true
+	opts.StrictMode = true
+	if _, err := NewCountingValidator(opts); !errors.Is(err, ErrInvalidOptions) {
+		t.Errorf("NewCountingValidator() error = %v, want ErrInvalidOptions", err)
+	}
+}
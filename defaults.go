@@ -0,0 +1,76 @@
+package regret
+
+import "sync"
+
+var (
+	defaultsMu     sync.RWMutex
+	customDefaults *Options // Set by SetDefaultOptions, nil for factory defaults
+)
+
+// SetDefaultOptions replaces the package-level defaults used by IsSafe and
+// Validate, so that a service can apply its policy, such as a stricter
+// MaxComplexityScore, without changing every call site. A copy of opts is
+// stored, so later changes to opts have no effect. A nil opts restores the
+// factory defaults, like ResetDefaultOptions.
+//
+// IsSafe uses FastOptions() until defaults are set; it always sets
+// TreatWarningsAsErrors. Functions that take options, such as ValidateWithOptions, are
+// not affected.
+//
+// Example:
+//
+//	opts := regret.DefaultOptions()
+//	opts.MaxComplexityScore = 50
+//	regret.SetDefaultOptions(opts)
+func SetDefaultOptions(opts *Options) {
+	if opts != nil {
+		opts = opts.clone()
+	}
+
+	defaultsMu.Lock()
+	defer defaultsMu.Unlock()
+	customDefaults = opts
+}
+
+// GetDefaultOptions returns a copy of the options Validate uses: those set
+// with SetDefaultOptions, or DefaultOptions().
+func GetDefaultOptions() *Options {
+	if opts, ok := customDefaultOptions(); ok {
+		return opts
+	}
+	return DefaultOptions()
+}
+
+// ResetDefaultOptions restores the factory defaults of IsSafe and Validate.
+func ResetDefaultOptions() {
+	SetDefaultOptions(nil)
+}
+
+// customDefaultOptions returns a copy of the options set with
+// SetDefaultOptions, or false if none are set.
+func customDefaultOptions() (*Options, bool) {
+	defaultsMu.RLock()
+	defer defaultsMu.RUnlock()
+	if customDefaults == nil {
+		return nil, false
+	}
+	return customDefaults.clone(), true
+}
+
+// clone returns a copy of o that shares no maps or slices with it.
+func (o *Options) clone() *Options {
+	c := *o
+	if o.SeverityOverride != nil {
+		c.SeverityOverride = make(map[IssueType]Severity, len(o.SeverityOverride))
+		for t, s := range o.SeverityOverride {
+			c.SeverityOverride[t] = s
+		}
+	}
+	if o.DenyList != nil {
+		c.DenyList = append([]string(nil), o.DenyList...)
+	}
+	if o.AllowUnsafePatterns != nil {
+		c.AllowUnsafePatterns = append([]string(nil), o.AllowUnsafePatterns...)
+	}
+	return &c
+}
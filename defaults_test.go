@@ -0,0 +1,49 @@
+package regret
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSetDefaultOptions(t *testing.T) {
+	defer ResetDefaultOptions()
+
+	pattern := "^[a-z]+$"
+	if !IsSafe(pattern) {
+		t.Fatalf("IsSafe(%q) = false with factory defaults", pattern)
+	}
+
+	opts := DefaultOptions()
+	opts.MaxPatternLength = 4
+	opts.SeverityOverride = map[IssueType]Severity{NestedQuantifiers: Low}
+	SetDefaultOptions(opts)
+
+	if IsSafe(pattern) {
+		t.Errorf("IsSafe(%q) = true, want false with MaxPatternLength 4", pattern)
+	}
+	if _, err := Validate(pattern); !errors.Is(err, ErrPatternTooLong) {
+		t.Errorf("Validate(%q) error = %v, want ErrPatternTooLong", pattern, err)
+	}
+
+	// Later changes to opts do not affect the defaults
+	opts.MaxPatternLength = 100
+	opts.SeverityOverride[NestedQuantifiers] = Critical
+	got := GetDefaultOptions()
+	if got.MaxPatternLength != 4 || got.SeverityOverride[NestedQuantifiers] != Low {
+		t.Errorf("GetDefaultOptions() = %+v, want the options passed to SetDefaultOptions", got)
+	}
+
+	// GetDefaultOptions returns a copy
+	got.MaxPatternLength = 100
+	if GetDefaultOptions().MaxPatternLength != 4 {
+		t.Error("modifying GetDefaultOptions() changed the defaults")
+	}
+
+	ResetDefaultOptions()
+	if !IsSafe(pattern) {
+		t.Errorf("IsSafe(%q) = false after ResetDefaultOptions", pattern)
+	}
+	if got := GetDefaultOptions(); got.MaxPatternLength != DefaultOptions().MaxPatternLength {
+		t.Errorf("MaxPatternLength after reset = %d, want %d", got.MaxPatternLength, DefaultOptions().MaxPatternLength)
+	}
+}
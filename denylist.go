@@ -0,0 +1,83 @@
+package regret
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// denyListMessage is the message of issues reported for denied patterns.
+const denyListMessage = "pattern is on the deny list"
+
+// denyList is the set of patterns rejected before analysis.
+type denyList map[string]struct{}
+
+// loadDenyList combines Options.DenyList with the patterns in Options.DenyListFile.
+func loadDenyList(opts *Options) (denyList, error) {
+	if len(opts.DenyList) == 0 && opts.DenyListFile == "" {
+		return nil, nil
+	}
+
+	denied := make(denyList, len(opts.DenyList))
+	for _, pattern := range opts.DenyList {
+		denied[pattern] = struct{}{}
+	}
+
+	if opts.DenyListFile != "" {
+		data, err := os.ReadFile(opts.DenyListFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load deny list: %w", err)
+		}
+		for _, line := range readPatternLines(data) {
+			denied[line.pattern] = struct{}{}
+		}
+	}
+
+	return denied, nil
+}
+
+// check returns a critical issue if the pattern is denied.
+func (d denyList) check(pattern string) []Issue {
+	if _, ok := d[pattern]; !ok {
+		return nil
+	}
+
+	return []Issue{{
+		Type:       ContextuallyDangerous,
+		Severity:   Critical,
+		Position:   Position{Start: 0, End: len(pattern)},
+		Pattern:    pattern,
+		Message:    denyListMessage,
+		Suggestion: "Use a pattern permitted by your organization's regex policy",
+		Details:    make(map[string]interface{}),
+		References: References(ContextuallyDangerous),
+	}}
+}
+
+// patternLine is a pattern read from a pattern file.
+type patternLine struct {
+	line    int // 1-based
+	pattern string
+}
+
+// readPatternLines parses newline-separated patterns.
+// Surrounding whitespace is trimmed; blank lines and lines starting with # are skipped.
+func readPatternLines(data []byte) []patternLine {
+	var lines []patternLine
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	line := 0
+	for scanner.Scan() {
+		line++
+		pattern := strings.TrimSpace(scanner.Text())
+		if pattern == "" || strings.HasPrefix(pattern, "#") {
+			continue
+		}
+		lines = append(lines, patternLine{line: line, pattern: pattern})
+	}
+
+	return lines
+}
@@ -0,0 +1,88 @@
+package regret
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidate_DenyList(t *testing.T) {
+	opts := DefaultOptions()
+	opts.DenyList = []string{`^[a-z]+$`}
+
+	issues, err := ValidateWithOptions(`^[a-z]+$`, opts)
+	if err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v", err)
+	}
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 issue, got %v", issues)
+	}
+	if issues[0].Type != ContextuallyDangerous || issues[0].Severity != Critical {
+		t.Errorf("got %v/%v, want ContextuallyDangerous/Critical", issues[0].Type, issues[0].Severity)
+	}
+	if issues[0].Message != "pattern is on the deny list" {
+		t.Errorf("Message = %q", issues[0].Message)
+	}
+
+	// Only exact matches are denied
+	issues, err = ValidateWithOptions(`^[a-z]*$`, opts)
+	if err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("expected no issues for pattern not on the deny list, got %v", issues)
+	}
+}
+
+func TestValidate_DenyListBeforeAnalysis(t *testing.T) {
+	opts := DefaultOptions()
+	opts.DenyList = []string{`(a+)+`}
+	opts.AllowUnsafe = true
+
+	issues, err := ValidateWithOptions(`(a+)+`, opts)
+	if err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v", err)
+	}
+	if !hasMessage(issues, "pattern is on the deny list") {
+		t.Errorf("expected deny list issue even with AllowUnsafe, got %v", issues)
+	}
+}
+
+func TestValidate_DenyListFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deny.txt")
+	content := "# banned by policy\n\n.*secret.*\n  ^admin$  \n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := DefaultOptions()
+	opts.DenyListFile = path
+	v := NewValidator(opts)
+
+	for _, pattern := range []string{`.*secret.*`, `^admin$`} {
+		issues, err := v.Validate(pattern)
+		if err != nil {
+			t.Fatalf("Validate(%q) error = %v", pattern, err)
+		}
+		if !hasMessage(issues, "pattern is on the deny list") {
+			t.Errorf("Validate(%q) expected deny list issue, got %v", pattern, issues)
+		}
+	}
+
+	issues, err := v.Validate(`# banned by policy`)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if hasMessage(issues, "pattern is on the deny list") {
+		t.Error("comment line was added to the deny list")
+	}
+}
+
+func TestValidate_DenyListFileMissing(t *testing.T) {
+	opts := DefaultOptions()
+	opts.DenyListFile = filepath.Join(t.TempDir(), "missing.txt")
+
+	if _, err := ValidateWithOptions(`^[a-z]+$`, opts); err == nil {
+		t.Error("expected error for missing deny list file")
+	}
+}
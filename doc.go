@@ -66,11 +66,11 @@ The library detects multiple types of dangerous patterns:
 Customize validation behavior with Options:
 
 	opts := &regret.Options{
-	    Mode:               regret.Balanced,
-	    Timeout:            100 * time.Millisecond,
-	    MaxComplexityScore: 70,
-	    Checks:             regret.CheckDefault,
-	    StrictMode:         true,
+	    Mode:                  regret.Balanced,
+	    Timeout:               100 * time.Millisecond,
+	    MaxComplexityScore:    70,
+	    Checks:                regret.CheckDefault,
+	    TreatWarningsAsErrors: true,
 	}
 	issues, err := regret.ValidateWithOptions(pattern, opts)
 
@@ -78,7 +78,7 @@ Customize validation behavior with Options:
 
 Get detailed complexity metrics:
 
-	score, err := regret.AnalyzeComplexity(pattern)
+	score, err := regret.AnalyzeComplexityWithOptions(pattern, regret.ThoroughOptions())
 	if err != nil {
 	    return err
 	}
@@ -97,7 +97,7 @@ Get detailed complexity metrics:
 
 Adversarial inputs are automatically generated during complexity analysis:
 
-	score, err := regret.AnalyzeComplexity("(a+)+")
+	score, err := regret.AnalyzeComplexityWithOptions("(a+)+", regret.ThoroughOptions())
 	if err != nil {
 	    return err
 	}
@@ -196,9 +196,18 @@ The library returns meaningful errors:
 
   - ErrInvalidPattern: Syntactically invalid regex
   - ErrPatternTooLong: Pattern exceeds MaxPatternLength
-  - ErrTimeout: Analysis exceeded configured timeout
+  - ErrTimeout: Analysis exceeded configured timeout (with TimeoutError behavior)
   - ErrUnsupportedFeature: Pattern uses unsupported features
 
+# References
+
+The EDA and IDA analysis follows Weideman et al., "Analyzing Matching Time
+Behavior of Backtracking Regular Expression Matchers by Using Ambiguity of
+NFA" (CIAA 2016), and Wüstholz et al., "Static Detection of DoS
+Vulnerabilities in Programs that use Regular Expressions" (TACAS 2017).
+Issue.References links each issue to these papers and to real-world ReDoS
+vulnerabilities; see References.
+
 # Version Information
 
 	fmt.Println(regret.FullVersion())
@@ -0,0 +1,65 @@
+package regret
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+)
+
+// Values of Options.PatternEncoding.
+const (
+	// PatternEncodingRaw means patterns are used as they are.
+	PatternEncodingRaw = "raw"
+
+	// PatternEncodingBase64 means patterns are base64-encoded, with the
+	// standard or URL-safe alphabet, with or without padding.
+	PatternEncodingBase64 = "base64"
+
+	// PatternEncodingURL means patterns are percent-encoded. A + is kept
+	// as it is rather than read as a space, since it is common in patterns.
+	PatternEncodingURL = "url"
+)
+
+// base64Encodings are the encodings a base64 pattern is decoded with, in
+// order.
+var base64Encodings = []*base64.Encoding{
+	base64.StdEncoding,
+	base64.RawStdEncoding,
+	base64.URLEncoding,
+	base64.RawURLEncoding,
+}
+
+// decodePattern decodes pattern according to opts.PatternEncoding. Patterns
+// that cannot be decoded return an error wrapping ErrInvalidPattern.
+func decodePattern(pattern string, opts *Options) (string, error) {
+	switch opts.PatternEncoding {
+	case "", PatternEncodingRaw:
+		return pattern, nil
+	case PatternEncodingBase64:
+		var err error
+		for _, enc := range base64Encodings {
+			var decoded []byte
+			if decoded, err = enc.DecodeString(pattern); err == nil {
+				return string(decoded), nil
+			}
+		}
+		return "", fmt.Errorf("%w: cannot decode base64 pattern %s: %v", ErrInvalidPattern, quote(pattern), err)
+	case PatternEncodingURL:
+		decoded, err := url.PathUnescape(pattern)
+		if err != nil {
+			return "", fmt.Errorf("%w: cannot decode url pattern %s: %v", ErrInvalidPattern, quote(pattern), err)
+		}
+		return decoded, nil
+	default:
+		return "", fmt.Errorf("%w: unknown pattern encoding %q", ErrInvalidOptions, opts.PatternEncoding)
+	}
+}
+
+// encodedError adds the encoded form of a pattern to an error about its
+// decoded form, so that the error can be traced back to the input.
+func encodedError(err error, encoded, decoded string) error {
+	if err == nil || encoded == decoded {
+		return err
+	}
+	return fmt.Errorf("%w (encoded pattern %s)", err, quote(encoded))
+}
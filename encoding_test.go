@@ -0,0 +1,102 @@
+package regret
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestValidateWithOptions_PatternEncoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		encoding string
+		pattern  string
+	}{
+		{"base64", PatternEncodingBase64, base64.StdEncoding.EncodeToString([]byte("(a+)+"))},
+		{"unpadded url-safe base64", PatternEncodingBase64, base64.RawURLEncoding.EncodeToString([]byte("(a+)+"))},
+		{"url", PatternEncodingURL, url.PathEscape("(a+)+")},
+		{"url keeps plus", PatternEncodingURL, "%28a+%29+"},
+		{"raw", PatternEncodingRaw, "(a+)+"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.PatternEncoding = tt.encoding
+			issues, err := ValidateWithOptions(tt.pattern, opts)
+			if err != nil {
+				t.Fatalf("ValidateWithOptions(%q) error = %v", tt.pattern, err)
+			}
+			if len(issues) == 0 {
+				t.Fatalf("ValidateWithOptions(%q) found no issues in the decoded (a+)+", tt.pattern)
+			}
+			if issues[0].Pattern != "(a+)+" {
+				t.Errorf("Issue.Pattern = %q, want the decoded pattern", issues[0].Pattern)
+			}
+		})
+	}
+}
+
+func TestValidateWithOptions_PatternEncodingErrors(t *testing.T) {
+	opts := DefaultOptions()
+	opts.PatternEncoding = PatternEncodingBase64
+	if _, err := ValidateWithOptions("not base64!", opts); !errors.Is(err, ErrInvalidPattern) {
+		t.Errorf("ValidateWithOptions() error = %v, want ErrInvalidPattern for undecodable base64", err)
+	}
+
+	opts.PatternEncoding = PatternEncodingURL
+	if _, err := ValidateWithOptions("%zz", opts); !errors.Is(err, ErrInvalidPattern) {
+		t.Errorf("ValidateWithOptions() error = %v, want ErrInvalidPattern for undecodable url", err)
+	}
+
+	// Errors about the decoded pattern quote the encoded form
+	encoded := url.PathEscape("(a+")
+	_, err := ValidateWithOptions(encoded, opts)
+	if err == nil || !strings.Contains(err.Error(), encoded) {
+		t.Errorf("ValidateWithOptions(%q) error = %v, want it to quote the encoded pattern", encoded, err)
+	}
+
+	opts.PatternEncoding = "rot13"
+	if _, err := ValidateWithOptions("(a+)+", opts); !errors.Is(err, ErrInvalidOptions) {
+		t.Errorf("ValidateWithOptions() error = %v, want ErrInvalidOptions for an unknown encoding", err)
+	}
+}
+
+func TestReport_PatternEncoding(t *testing.T) {
+	opts := DefaultOptions()
+	opts.PatternEncoding = PatternEncodingBase64
+	report, err := Report(base64.StdEncoding.EncodeToString([]byte("(a+)+")), opts)
+	if err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+	if report.Pattern != "(a+)+" || len(report.Issues) == 0 || !report.Complexity.HasEDA {
+		t.Errorf("Report() = %+v, want the analysis of the decoded (a+)+", report)
+	}
+}
+
+func TestCompile_IgnoresPatternEncoding(t *testing.T) {
+	defer ResetDefaultOptions()
+	opts := DefaultOptions()
+	opts.PatternEncoding = PatternEncodingBase64
+	SetDefaultOptions(opts)
+
+	re, err := Compile(`^[a-z]+$`)
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	if !re.MatchString("abc") {
+		t.Errorf("Compile(%q) does not match abc", re.String())
+	}
+	if _, err := Compile(base64.StdEncoding.EncodeToString([]byte(`(a+)+`))); err != nil {
+		t.Errorf("Compile() of the base64 text error = %v, want it compiled as is", err)
+	}
+
+	if _, err := SafeCompile(`^[a-z]+$`); err != nil {
+		t.Errorf("SafeCompile() error = %v", err)
+	}
+	if IsSafe(`(a+)+`) {
+		t.Error("IsSafe((a+)+) = true, want false")
+	}
+}
@@ -11,7 +11,7 @@ import (
 // ExamplePumpIntegration_Basic demonstrates automatic pump pattern generation.
 func ExamplePumpIntegration_Basic() {
 	// Analyze a dangerous pattern
-	score, err := regret.AnalyzeComplexity("(a+)+")
+	score, err := regret.AnalyzeComplexityWithOptions("(a+)+", regret.ThoroughOptions())
 	if err != nil {
 		panic(err)
 	}
@@ -46,7 +46,7 @@ func ExamplePumpIntegration_SecurityTesting() {
 	}
 
 	for _, pattern := range patterns {
-		score, err := regret.AnalyzeComplexity(pattern)
+		score, err := regret.AnalyzeComplexityWithOptions(pattern, regret.ThoroughOptions())
 		if err != nil {
 			fmt.Printf("Pattern %q: ERROR - %v\n", pattern, err)
 			continue
@@ -73,7 +73,7 @@ func ExamplePumpIntegration_SecurityTesting() {
 func ExamplePumpIntegration_Benchmarking() {
 	pattern := "(a+)+"
 
-	score, err := regret.AnalyzeComplexity(pattern)
+	score, err := regret.AnalyzeComplexityWithOptions(pattern, regret.ThoroughOptions())
 	if err != nil {
 		panic(err)
 	}
@@ -123,7 +123,7 @@ func ExamplePumpIntegration_MultiPatternAnalysis() {
 	fmt.Println("=== Pattern Analysis Report ===")
 
 	for _, test := range tests {
-		score, err := regret.AnalyzeComplexity(test.Pattern)
+		score, err := regret.AnalyzeComplexityWithOptions(test.Pattern, regret.ThoroughOptions())
 		if err != nil {
 			fmt.Printf("%s: ERROR - %v\n\n", test.Name, err)
 			continue
@@ -149,7 +149,7 @@ func ExamplePumpIntegration_MultiPatternAnalysis() {
 func ExamplePumpIntegration_ConditionalTesting() {
 	pattern := "(a+)+"
 
-	score, err := regret.AnalyzeComplexity(pattern)
+	score, err := regret.AnalyzeComplexityWithOptions(pattern, regret.ThoroughOptions())
 	if err != nil {
 		panic(err)
 	}
@@ -196,7 +196,7 @@ func ExamplePumpIntegration_BatchAnalysis() {
 
 	// Analyze all patterns
 	for _, pattern := range patterns {
-		score, err := regret.AnalyzeComplexity(pattern)
+		score, err := regret.AnalyzeComplexityWithOptions(pattern, regret.ThoroughOptions())
 		if err != nil {
 			continue
 		}
@@ -47,7 +47,7 @@ func AuditPattern(pattern string) (*SecurityAudit, error) {
 	audit.Issues = issues
 
 	// Step 3: Analyze complexity
-	complexity, err := regret.AnalyzeComplexity(pattern)
+	complexity, err := regret.AnalyzeComplexityWithOptions(pattern, regret.ThoroughOptions())
 	if err != nil {
 		return nil, fmt.Errorf("complexity analysis failed: %w", err)
 	}
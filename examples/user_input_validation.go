@@ -25,7 +25,6 @@ func ValidateUserRegexDetailed(pattern string) (bool, []string, error) {
 	issues, err := regret.ValidateWithOptions(pattern, &regret.Options{
 		Mode:               regret.Balanced,
 		MaxComplexityScore: 60,
-		StrictMode:         false,
 	})
 	if err != nil {
 		return false, nil, err
@@ -88,7 +87,7 @@ type SearchFilterValidator struct {
 // NewSearchFilterValidator creates a new validator with custom options.
 func NewSearchFilterValidator(strict bool) *SearchFilterValidator {
 	opts := regret.DefaultOptions()
-	opts.StrictMode = strict
+	opts.TreatWarningsAsErrors = strict
 	if strict {
 		opts.MaxComplexityScore = 40 // Very conservative
 	}
@@ -131,7 +130,7 @@ func (v *SearchFilterValidator) ValidateWithComplexity(pattern string) (bool, *r
 	}
 
 	// Analyze complexity
-	score, err := regret.AnalyzeComplexity(pattern)
+	score, err := regret.AnalyzeComplexityWithOptions(pattern, regret.ThoroughOptions())
 	if err != nil {
 		return false, nil, err
 	}
@@ -0,0 +1,284 @@
+package regret
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"sort"
+	"strings"
+
+	"github.com/theakshaypant/regret/internal/parser"
+)
+
+// Explain returns a plain-English paragraph describing why a pattern is or
+// is not safe. It is meant for developers who are not familiar with ReDoS:
+// each detected problem is described in terms of the sub-pattern that causes
+// it and the kind of input that triggers it.
+//
+// Explain uses default options, so backreferences like \1 are accepted as
+// Validate accepts them. Invalid patterns produce an explanation of the
+// syntax error rather than an error value.
+//
+// Example:
+//
+//	fmt.Println(regret.Explain("(a+)+"))
+//	// This pattern contains a quantifier `+` applied to the group `(a+)`,
+//	// which itself contains the quantifier `+`. When the input consists of
+//	// `a` repeated many times followed by a character that doesn't match, ...
+func Explain(pattern string) string {
+	opts := GetDefaultOptions()
+	re, err := parser.NewParserWithFlags(parseFlags(opts)).Parse(expandBackreferences(pattern, opts))
+	if err != nil {
+		return fmt.Sprintf("The pattern `%s` is not a valid regular expression: %v.", pattern, err)
+	}
+
+	issues, err := Validate(pattern)
+	if err != nil {
+		return fmt.Sprintf("The pattern `%s` could not be analyzed: %v.", pattern, err)
+	}
+
+	if len(issues) == 0 {
+		return fmt.Sprintf("No backtracking risks were found in `%s`. It has no nested quantifiers, "+
+			"no quantifiers competing for the same characters and no ambiguous alternations, "+
+			"so matching time grows linearly with the input length.", pattern)
+	}
+
+	// Most severe problems first
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Severity < issues[j].Severity
+	})
+
+	// Several checks may report the same problem: explain each one once,
+	// and skip generic descriptions of problems already explained precisely
+	var explanations []explanation
+	seen := make(map[string]bool)
+	precise := make(map[IssueType]bool)
+	for _, issue := range issues {
+		e := explainIssue(issue, re, pattern)
+		if seen[e.key] {
+			continue
+		}
+		seen[e.key] = true
+		if e.precise {
+			precise[issue.Type] = true
+		}
+		explanations = append(explanations, e)
+	}
+
+	var sentences []string
+	for _, e := range explanations {
+		if !e.precise && precise[e.issueType] {
+			continue
+		}
+		sentences = append(sentences, e.text)
+	}
+
+	if suggestion := issues[0].Suggestion; suggestion != "" {
+		sentences = append(sentences, fmt.Sprintf("Suggested fix: %s.", strings.TrimSuffix(suggestion, ".")))
+	}
+
+	return strings.Join(sentences, " ")
+}
+
+// explanation is the description of a single issue.
+type explanation struct {
+	key       string // Identifies the underlying problem across checks
+	issueType IssueType
+	text      string
+	precise   bool // Quotes the exact sub-pattern responsible
+}
+
+// explainIssue describes a single issue, quoting the sub-pattern it refers to.
+func explainIssue(issue Issue, re *syntax.Regexp, pattern string) explanation {
+	// Narrow the analysis to the sub-pattern the issue points at, if any
+	quoted := issueSubpattern(issue, pattern)
+	target := re
+	if quoted != pattern {
+		if sub, err := parser.NewParser().Parse(quoted); err == nil {
+			target = sub
+		}
+	}
+
+	e := explanation{key: issue.Type.String() + ":" + quoted, issueType: issue.Type}
+
+	switch issue.Type {
+	case NestedQuantifiers, ExponentialBacktracking:
+		var inners []*syntax.Regexp
+		outer := findNestedQuantifier(target)
+		if outer != nil {
+			inners = parser.FindQuantifiers(outer.Sub[0])
+		}
+		if len(inners) > 0 {
+			inner := inners[0]
+			e.key = "nested:" + outer.String()
+			e.precise = true
+			e.text = fmt.Sprintf("This pattern contains a quantifier `%s` applied to the group `%s`, "+
+				"which itself contains the quantifier `%s`. When the input consists of `%s` repeated "+
+				"many times followed by a character that doesn't match, the regex engine must try "+
+				"every way to split the repetitions between the outer and inner quantifiers, "+
+				"which grows exponentially with the input length.",
+				quantifierSymbol(outer), sourceOf(pattern, quoted, outer.Sub[0]), quantifierSymbol(inner), sampleInput(inner))
+			return e
+		}
+		e.text = fmt.Sprintf("The pattern `%s` can match the same text along more than one path through "+
+			"a repetition. When a match fails, the regex engine tries every path, and the number of "+
+			"paths grows exponentially with the input length.", quoted)
+
+	case PolynomialBacktracking:
+		if first, second := findAdjacentQuantifiers(target); first != nil {
+			e.key = "adjacent:" + first.String() + second.String()
+			e.precise = true
+			e.text = fmt.Sprintf("The quantifiers in `%s` can match the same characters one after another. "+
+				"When the input consists of `%s` repeated many times followed by a character that doesn't "+
+				"match, the regex engine tries every way to divide the characters between them, "+
+				"so matching time grows polynomially with the input length.",
+				sourceOf(pattern, quoted, first, second), sampleInput(first))
+			return e
+		}
+		e.text = fmt.Sprintf("The sub-pattern `%s` lets several quantifiers compete for the same characters. "+
+			"When a match fails, the regex engine tries every way to divide the input between them, "+
+			"so matching time grows polynomially with the input length.", quoted)
+
+	case OverlappingAlternation:
+		e.precise = quoted != pattern
+		e.text = fmt.Sprintf("The alternation `%s` has branches that can match the same text. "+
+			"Inside a repetition, each repeated section can be matched by more than one branch, "+
+			"so a failing match may try exponentially many combinations.", quoted)
+
+	default:
+		e.text = fmt.Sprintf("%s (in `%s`).", strings.TrimSuffix(issue.Message, "."), quoted)
+	}
+
+	return e
+}
+
+// issueSubpattern returns the part of the pattern an issue refers to.
+// Falls back to the whole pattern when the issue does not narrow it down.
+func issueSubpattern(issue Issue, pattern string) string {
+	pos := issue.Position
+	if pos.Start >= 0 && pos.End <= len(pattern) && pos.Start < pos.End &&
+		(pos.Start > 0 || pos.End < len(pattern)) {
+		return pattern[pos.Start:pos.End]
+	}
+	// Detectors may report a normalized sub-pattern that does not appear verbatim
+	if issue.Pattern != "" && strings.Contains(pattern, issue.Pattern) {
+		return issue.Pattern
+	}
+	return pattern
+}
+
+// sourceOf returns the source text of consecutive AST nodes. The syntax
+// package prints nodes in normalized form (\d becomes [0-9]), so capture
+// groups are looked up in the original pattern, and the fallback is used
+// when the normalized form does not appear verbatim.
+func sourceOf(pattern, fallback string, nodes ...*syntax.Regexp) string {
+	if len(nodes) == 1 && nodes[0].Op == syntax.OpCapture {
+		if start, end, ok := parser.CaptureGroupSpan(pattern, nodes[0].Cap); ok {
+			return pattern[start:end]
+		}
+	}
+
+	var b strings.Builder
+	for _, node := range nodes {
+		b.WriteString(node.String())
+	}
+	if strings.Contains(pattern, b.String()) {
+		return b.String()
+	}
+	return fallback
+}
+
+// findNestedQuantifier returns the first quantifier whose body contains another quantifier.
+func findNestedQuantifier(re *syntax.Regexp) *syntax.Regexp {
+	var found *syntax.Regexp
+	parser.Walk(re, func(node *syntax.Regexp) (bool, error) {
+		if found != nil {
+			return false, nil
+		}
+		if parser.IsQuantifier(node) && len(node.Sub) > 0 && parser.HasQuantifier(node.Sub[0]) {
+			found = node
+			return false, nil
+		}
+		return true, nil
+	})
+	return found
+}
+
+// findAdjacentQuantifiers returns the first pair of unbounded quantifiers that
+// follow each other directly in a concatenation.
+func findAdjacentQuantifiers(re *syntax.Regexp) (*syntax.Regexp, *syntax.Regexp) {
+	var first, second *syntax.Regexp
+	parser.Walk(re, func(node *syntax.Regexp) (bool, error) {
+		if first != nil {
+			return false, nil
+		}
+		if node.Op != syntax.OpConcat {
+			return true, nil
+		}
+		for i := 1; i < len(node.Sub); i++ {
+			if isUnbounded(node.Sub[i-1]) && isUnbounded(node.Sub[i]) {
+				first, second = node.Sub[i-1], node.Sub[i]
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	return first, second
+}
+
+func isUnbounded(re *syntax.Regexp) bool {
+	return re.Op == syntax.OpStar || re.Op == syntax.OpPlus ||
+		(re.Op == syntax.OpRepeat && re.Max == -1)
+}
+
+// quantifierSymbol returns the source form of a quantifier node's operator.
+func quantifierSymbol(re *syntax.Regexp) string {
+	switch re.Op {
+	case syntax.OpStar:
+		return "*"
+	case syntax.OpPlus:
+		return "+"
+	case syntax.OpQuest:
+		return "?"
+	case syntax.OpRepeat:
+		if re.Max == -1 {
+			return fmt.Sprintf("{%d,}", re.Min)
+		}
+		if re.Min == re.Max {
+			return fmt.Sprintf("{%d}", re.Min)
+		}
+		return fmt.Sprintf("{%d,%d}", re.Min, re.Max)
+	}
+	return re.Op.String()
+}
+
+// sampleInput returns a short string matched by one iteration of a quantifier.
+func sampleInput(re *syntax.Regexp) string {
+	if sample := sampleString(re); sample != "" {
+		return sample
+	}
+	return "a"
+}
+
+func sampleString(re *syntax.Regexp) string {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return string(re.Rune)
+	case syntax.OpCharClass:
+		if len(re.Rune) > 0 {
+			return string(re.Rune[0])
+		}
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return "a"
+	case syntax.OpConcat:
+		var b strings.Builder
+		for _, sub := range re.Sub {
+			b.WriteString(sampleString(sub))
+		}
+		return b.String()
+	case syntax.OpCapture, syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat, syntax.OpAlternate:
+		if len(re.Sub) > 0 {
+			return sampleString(re.Sub[0])
+		}
+	}
+	return ""
+}
@@ -0,0 +1,64 @@
+package regret
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		contains []string
+	}{
+		{
+			name:     "nested quantifiers",
+			pattern:  "(a+)+",
+			contains: []string{"quantifier `+` applied to the group `(a+)`", "`a` repeated", "exponentially"},
+		},
+		{
+			name:     "nested quantifiers with escapes",
+			pattern:  `^(\w+\s?)*$`,
+			contains: []string{"the group `(\\w+\\s?)`", "exponentially"},
+		},
+		{
+			name:     "overlapping quantifiers",
+			pattern:  `\d*\d+`,
+			contains: []string{"`\\d*\\d+`", "polynomially"},
+		},
+		{
+			name:     "backreference",
+			pattern:  `(a+)+\1`,
+			contains: []string{"quantifier `+` applied to the group `(a+)`", "Backreference \\1 refers to group (a+)"},
+		},
+		{
+			name:     "safe pattern",
+			pattern:  "^[a-z]+$",
+			contains: []string{"No backtracking risks were found in `^[a-z]+$`"},
+		},
+		{
+			name:     "invalid pattern",
+			pattern:  "(a+",
+			contains: []string{"not a valid regular expression"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Explain(tt.pattern)
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("Explain(%q) = %q, want it to contain %q", tt.pattern, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestExplain_ExplainsEachProblemOnce(t *testing.T) {
+	// (a+)+ is reported by both the nested quantifier and the NFA checks
+	got := Explain("(a+)+")
+	if n := strings.Count(got, "This pattern contains a quantifier"); n != 1 {
+		t.Errorf("Explain() described the nested quantifier %d times: %q", n, got)
+	}
+}
@@ -0,0 +1,50 @@
+package regret
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp/syntax"
+)
+
+// Fingerprint returns a stable identifier for the regex a pattern denotes.
+// Patterns that differ only in spelling, such as (a+)+ and (a+){1,}, have
+// the same fingerprint, which makes it useful as a deduplication or cache key.
+//
+// The fingerprint is the SHA-256 hex digest of the canonical form of the
+// pattern: its simplified syntax tree printed back as a string. Patterns
+// with the same fingerprint match the same strings, but equivalent patterns
+// written with a different structure, such as a|b and [ab], are not
+// guaranteed to share a fingerprint.
+//
+// Example:
+//
+//	fp, err := regret.Fingerprint("(a+){1,}")
+//	if err != nil {
+//	    return err
+//	}
+//	seen[fp] = true
+func Fingerprint(pattern string) (string, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidPattern, err)
+	}
+
+	sum := sha256.Sum256([]byte(re.Simplify().String()))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// FingerprintMany groups patterns by their Fingerprint. Each fingerprint
+// maps to the patterns that share it, in input order. It fails on the first
+// invalid pattern.
+func FingerprintMany(patterns []string) (map[string][]string, error) {
+	groups := make(map[string][]string)
+	for _, pattern := range patterns {
+		fp, err := Fingerprint(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("pattern %q: %w", pattern, err)
+		}
+		groups[fp] = append(groups[fp], pattern)
+	}
+	return groups, nil
+}
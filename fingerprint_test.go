@@ -0,0 +1,59 @@
+package regret
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	tests := []struct {
+		a, b string
+		same bool
+	}{
+		{"(a+)+", "(a+){1,}", true},
+		{"a{2,}", "aa+", true},
+		{"(?:ab)", "ab", true},
+		{"(a+)+", "(a*)*", false},
+		{"(a+)+", "(?:a+)+", false}, // capturing groups are kept
+	}
+
+	for _, tt := range tests {
+		fa, err := Fingerprint(tt.a)
+		if err != nil {
+			t.Fatalf("Fingerprint(%q) error = %v", tt.a, err)
+		}
+		fb, err := Fingerprint(tt.b)
+		if err != nil {
+			t.Fatalf("Fingerprint(%q) error = %v", tt.b, err)
+		}
+		if len(fa) != 64 {
+			t.Errorf("Fingerprint(%q) = %q, want a SHA-256 hex digest", tt.a, fa)
+		}
+		if (fa == fb) != tt.same {
+			t.Errorf("Fingerprint(%q) == Fingerprint(%q) is %v, want %v", tt.a, tt.b, fa == fb, tt.same)
+		}
+	}
+
+	if _, err := Fingerprint("(a+"); !errors.Is(err, ErrInvalidPattern) {
+		t.Errorf("Fingerprint() error = %v, want %v", err, ErrInvalidPattern)
+	}
+}
+
+func TestFingerprintMany(t *testing.T) {
+	groups, err := FingerprintMany([]string{"(a+)+", "x*", "(a+){1,}", "x{0,}"})
+	if err != nil {
+		t.Fatalf("FingerprintMany() error = %v", err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("FingerprintMany() = %v, want 2 groups", groups)
+	}
+
+	fp, _ := Fingerprint("(a+)+")
+	if got := groups[fp]; len(got) != 2 || got[0] != "(a+)+" || got[1] != "(a+){1,}" {
+		t.Errorf("group for (a+)+ = %q, want [(a+)+ (a+){1,}]", got)
+	}
+
+	if _, err := FingerprintMany([]string{"x", "(a+"}); !errors.Is(err, ErrInvalidPattern) {
+		t.Errorf("FingerprintMany() error = %v, want %v", err, ErrInvalidPattern)
+	}
+}
@@ -0,0 +1,441 @@
+// Package ambiguity builds concrete witnesses for ambiguity in regex NFAs.
+package ambiguity
+
+import (
+	"context"
+	"regexp/syntax"
+	"sort"
+	"unicode"
+
+	"github.com/theakshaypant/regret/internal/parser"
+)
+
+// maxProductStates bounds the number of product states explored by
+// PolynomialWitness, so that large NFAs give up instead of stalling.
+const maxProductStates = 100000
+
+// failCandidates are tried in order as the suffix that makes a match fail.
+var failCandidates = []rune{'!', '#', '~', 'x', '\n', 0}
+
+// move is a consuming step from a state, after following epsilon transitions.
+type move struct {
+	ranges    []parser.RuneRange
+	to        *parser.State
+	ambiguous bool // The step is reachable along more than one epsilon path
+}
+
+// triple is a state of the product NFA × NFA × NFA.
+type triple struct {
+	a, b, c *parser.State
+}
+
+// PolynomialWitness finds an input that makes the NFA polynomially ambiguous:
+// states p ≠ q and a string w such that p loops back to itself on w, p
+// reaches q on w, and q loops back to itself on w. It returns a prefix that
+// leads from the start state to p, the pump read by the loop at p, the pump
+// read by the loop at q, and a suffix that makes the overall match fail.
+// Every split of prefix + pump1^i + pump2^j between the two loops is a
+// separate matching path, so a backtracking engine tries O(i*j) of them
+// before the suffix rejects the input.
+//
+// The search runs on the product NFA × NFA × NFA, so both pumps are read by
+// the same transitions and are equal; they are returned separately to match
+// the shape of the pumping argument. degree is the ambiguity degree being
+// witnessed; any degree of 2 or more has such a pair of loops, and ok is
+// false for smaller degrees or when no witness is found.
+func PolynomialWitness(nfa *parser.NFA, degree int) (prefix, pump1, pump2, suffix string, ok bool) {
+	return PolynomialWitnessContext(context.Background(), nfa, degree)
+}
+
+// PolynomialWitnessContext is like PolynomialWitness but gives up, with ok
+// false, once ctx is done. It looks at ctx for every NFA state and before
+// each pair of loops.
+func PolynomialWitnessContext(ctx context.Context, nfa *parser.NFA, degree int) (prefix, pump1, pump2, suffix string, ok bool) {
+	if nfa == nil || nfa.Start == nil || degree < 2 {
+		return "", "", "", "", false
+	}
+
+	moves := buildMoves(ctx, nfa)
+	if ctx.Err() != nil {
+		return "", "", "", "", false
+	}
+	loops := loopStates(nfa, moves)
+
+	budget := maxProductStates
+	for _, p := range loops {
+		for _, q := range loops {
+			if p == q {
+				continue
+			}
+			if ctx.Err() != nil {
+				return "", "", "", "", false
+			}
+			w, found := findCommonPath(moves, triple{p, p, q}, triple{p, q, q}, &budget)
+			if budget <= 0 {
+				return "", "", "", "", false
+			}
+			if !found {
+				continue
+			}
+			prefix, reachable := shortestPath(moves, nfa.Start, p)
+			if !reachable {
+				continue
+			}
+			return prefix, w, w, failSuffix(moves), true
+		}
+	}
+
+	return "", "", "", "", false
+}
+
+// ExponentialWitness finds an input that makes the NFA exponentially
+// ambiguous: a state p and a string w such that p loops back to itself on w
+// along two different paths. It returns a prefix that leads from the start
+// state to p, the pump w, and a suffix that makes the overall match fail.
+// Every one of the 2^n ways of reading pump^n is a separate matching path.
+//
+// The search runs on the product NFA × NFA from (p, p) back to (p, p),
+// tracking whether the two copies have taken different transitions. ok is
+// false when no witness is found within the search budget.
+func ExponentialWitness(nfa *parser.NFA) (prefix, pump, suffix string, ok bool) {
+	if nfa == nil || nfa.Start == nil {
+		return "", "", "", false
+	}
+
+	moves := buildMoves(context.Background(), nfa)
+	budget := maxProductStates
+	for _, p := range loopStates(nfa, moves) {
+		w, found := findDivergentLoop(moves, p, &budget)
+		if budget <= 0 {
+			return "", "", "", false
+		}
+		if !found {
+			continue
+		}
+		prefix, reachable := shortestPath(moves, nfa.Start, p)
+		if !reachable {
+			continue
+		}
+		return prefix, w, failSuffix(moves), true
+	}
+
+	return "", "", "", false
+}
+
+// findDivergentLoop searches NFA × NFA for a non-empty string that leads
+// from (p, p) back to (p, p) along two different paths, decrementing budget
+// for every product state visited.
+func findDivergentLoop(moves map[*parser.State][]move, p *parser.State, budget *int) (string, bool) {
+	type pair struct {
+		a, b     *parser.State
+		diverged bool
+	}
+	type step struct {
+		prev pair
+		r    rune
+	}
+	from := pair{a: p, b: p}
+	to := pair{a: p, b: p, diverged: true}
+	parent := make(map[pair]step)
+	queue := []pair{from}
+	visited := map[pair]bool{from: true}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		for i, ma := range moves[cur.a] {
+			for j, mb := range moves[cur.b] {
+				ab := intersect(ma.ranges, mb.ranges)
+				if len(ab) == 0 {
+					continue
+				}
+				// The copies diverge when they take different transitions
+				// out of the same state, or the same transition along
+				// different epsilon paths
+				next := pair{a: ma.to, b: mb.to, diverged: cur.diverged || (cur.a == cur.b && (i != j || ma.ambiguous))}
+				if visited[next] {
+					continue
+				}
+				visited[next] = true
+				parent[next] = step{prev: cur, r: pick(ab)}
+
+				if next == to {
+					var runes []rune
+					for t := next; t != from; t = parent[t].prev {
+						runes = append(runes, parent[t].r)
+					}
+					for l, r := 0, len(runes)-1; l < r; l, r = l+1, r-1 {
+						runes[l], runes[r] = runes[r], runes[l]
+					}
+					return string(runes), true
+				}
+
+				*budget--
+				if *budget <= 0 {
+					return "", false
+				}
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return "", false
+}
+
+// buildMoves computes the consuming steps available from each state.
+// Anchors are zero-width and are followed like epsilon transitions. If
+// ctx is done first, the steps of the remaining states are left out.
+func buildMoves(ctx context.Context, nfa *parser.NFA) map[*parser.State][]move {
+	moves := make(map[*parser.State][]move, len(nfa.States))
+	for _, state := range nfa.States {
+		if ctx.Err() != nil {
+			break
+		}
+		for s, paths := range closure(state) {
+			for _, trans := range s.Transitions {
+				if trans.IsEpsilon || trans.Label.Type == parser.TransitionAnchor {
+					continue
+				}
+				moves[state] = append(moves[state], move{ranges: labelRanges(trans.Label), to: trans.To, ambiguous: paths > 1})
+			}
+		}
+	}
+	return moves
+}
+
+// closure returns the states reachable from state by epsilon transitions,
+// with the number of distinct epsilon paths to each, capped at 2. Paths that
+// go around an epsilon cycle are not counted.
+func closure(state *parser.State) map[*parser.State]int {
+	paths := make(map[*parser.State]int)
+	onPath := make(map[*parser.State]bool)
+
+	var visit func(s *parser.State)
+	visit = func(s *parser.State) {
+		if onPath[s] || paths[s] >= 2 {
+			return
+		}
+		paths[s]++
+		onPath[s] = true
+		for _, trans := range s.Transitions {
+			if trans.IsEpsilon || trans.Label.Type == parser.TransitionAnchor {
+				visit(trans.To)
+			}
+		}
+		onPath[s] = false
+	}
+	visit(state)
+
+	return paths
+}
+
+// loopStates returns the states reached by consuming input that lie on a
+// cycle, in ascending ID order.
+func loopStates(nfa *parser.NFA, moves map[*parser.State][]move) []*parser.State {
+	inCycle := make(map[int]bool)
+	for _, cycle := range nfa.FindCycles() {
+		for _, id := range cycle {
+			inCycle[id] = true
+		}
+	}
+
+	seen := make(map[*parser.State]bool)
+	var loops []*parser.State
+	for _, state := range nfa.States {
+		for _, m := range moves[state] {
+			if inCycle[m.to.ID] && !seen[m.to] {
+				seen[m.to] = true
+				loops = append(loops, m.to)
+			}
+		}
+	}
+
+	sort.Slice(loops, func(i, j int) bool { return loops[i].ID < loops[j].ID })
+	return loops
+}
+
+// findCommonPath searches the product automaton for a non-empty string that
+// leads from one product state to another, decrementing budget for every
+// product state visited.
+func findCommonPath(moves map[*parser.State][]move, from, to triple, budget *int) (string, bool) {
+	type step struct {
+		prev triple
+		r    rune
+	}
+	parent := make(map[triple]step)
+	queue := []triple{from}
+	visited := map[triple]bool{}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		for _, ma := range moves[cur.a] {
+			for _, mb := range moves[cur.b] {
+				ab := intersect(ma.ranges, mb.ranges)
+				if len(ab) == 0 {
+					continue
+				}
+				for _, mc := range moves[cur.c] {
+					abc := intersect(ab, mc.ranges)
+					if len(abc) == 0 {
+						continue
+					}
+					next := triple{ma.to, mb.to, mc.to}
+					if visited[next] {
+						continue
+					}
+					visited[next] = true
+					parent[next] = step{prev: cur, r: pick(abc)}
+
+					if next == to {
+						var runes []rune
+						for t := next; ; {
+							s := parent[t]
+							runes = append(runes, s.r)
+							if s.prev == from {
+								break
+							}
+							t = s.prev
+						}
+						for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+							runes[i], runes[j] = runes[j], runes[i]
+						}
+						return string(runes), true
+					}
+
+					*budget--
+					if *budget <= 0 {
+						return "", false
+					}
+					queue = append(queue, next)
+				}
+			}
+		}
+	}
+
+	return "", false
+}
+
+// shortestPath returns the shortest string leading from one state to another.
+func shortestPath(moves map[*parser.State][]move, from, to *parser.State) (string, bool) {
+	if from == to {
+		return "", true
+	}
+
+	type step struct {
+		prev *parser.State
+		r    rune
+	}
+	parent := map[*parser.State]step{from: {}}
+	queue := []*parser.State{from}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, m := range moves[cur] {
+			if _, seen := parent[m.to]; seen {
+				continue
+			}
+			parent[m.to] = step{prev: cur, r: pick(m.ranges)}
+			if m.to == to {
+				var runes []rune
+				for s := to; s != from; s = parent[s].prev {
+					runes = append(runes, parent[s].r)
+				}
+				for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+					runes[i], runes[j] = runes[j], runes[i]
+				}
+				return string(runes), true
+			}
+			queue = append(queue, m.to)
+		}
+	}
+
+	return "", false
+}
+
+// failSuffix returns a character that no transition of the NFA accepts,
+// or an empty string if every candidate is accepted somewhere.
+func failSuffix(moves map[*parser.State][]move) string {
+	for _, r := range failCandidates {
+		accepted := false
+		for _, ms := range moves {
+			for _, m := range ms {
+				if contains(m.ranges, r) {
+					accepted = true
+					break
+				}
+			}
+			if accepted {
+				break
+			}
+		}
+		if !accepted {
+			return string(r)
+		}
+	}
+	return ""
+}
+
+// labelRanges returns the runes accepted by a consuming transition label.
+func labelRanges(label parser.TransitionLabel) []parser.RuneRange {
+	switch label.Type {
+	case parser.TransitionAny:
+		if label.Op == syntax.OpAnyCharNotNL {
+			return []parser.RuneRange{{Lo: 0, Hi: '\n' - 1}, {Lo: '\n' + 1, Hi: unicode.MaxRune}}
+		}
+		return []parser.RuneRange{{Lo: 0, Hi: unicode.MaxRune}}
+	case parser.TransitionClass:
+		if label.Class != nil {
+			return label.Class.Ranges
+		}
+		return nil
+	default:
+		ranges := make([]parser.RuneRange, len(label.Runes))
+		for i, r := range label.Runes {
+			ranges[i] = parser.RuneRange{Lo: r, Hi: r}
+		}
+		return ranges
+	}
+}
+
+// intersect returns the runes accepted by both range lists.
+func intersect(x, y []parser.RuneRange) []parser.RuneRange {
+	var out []parser.RuneRange
+	for _, rx := range x {
+		for _, ry := range y {
+			lo, hi := max(rx.Lo, ry.Lo), min(rx.Hi, ry.Hi)
+			if lo <= hi {
+				out = append(out, parser.RuneRange{Lo: lo, Hi: hi})
+			}
+		}
+	}
+	return out
+}
+
+func contains(ranges []parser.RuneRange, r rune) bool {
+	for _, rr := range ranges {
+		if rr.Lo <= r && r <= rr.Hi {
+			return true
+		}
+	}
+	return false
+}
+
+// pick chooses a readable representative rune from a non-empty range list,
+// preferring lowercase letters, then digits, uppercase letters and other
+// printable ASCII.
+func pick(ranges []parser.RuneRange) rune {
+	for _, preferred := range []parser.RuneRange{{Lo: 'a', Hi: 'z'}, {Lo: '0', Hi: '9'}, {Lo: 'A', Hi: 'Z'}, {Lo: ' ', Hi: '~'}} {
+		if in := intersect(ranges, []parser.RuneRange{preferred}); len(in) > 0 {
+			lowest := in[0].Lo
+			for _, rr := range in[1:] {
+				lowest = min(lowest, rr.Lo)
+			}
+			return lowest
+		}
+	}
+	return ranges[0].Lo
+}
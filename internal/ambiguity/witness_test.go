@@ -0,0 +1,119 @@
+package ambiguity
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/theakshaypant/regret/internal/parser"
+)
+
+func TestPolynomialWitness(t *testing.T) {
+	tests := []struct {
+		pattern    string
+		wantOK     bool
+		wantPrefix string
+		wantPump   string
+	}{
+		{`^a*a*$`, true, "a", "a"},
+		{`^id:\d+\d+$`, true, "id:0", "0"},
+		{`^\s*\w*\s*$`, true, " ", " "},
+		{`^a*b*$`, false, "", ""},
+		{`^[a-z]+@[a-z]+$`, false, "", ""},
+		{`^abc$`, false, "", ""},
+	}
+
+	p := parser.NewParser()
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			nfa, err := parser.BuildNFA(p.MustParse(tt.pattern))
+			if err != nil {
+				t.Fatalf("BuildNFA() error = %v", err)
+			}
+
+			prefix, pump1, pump2, suffix, ok := PolynomialWitness(nfa, 2)
+			if ok != tt.wantOK {
+				t.Fatalf("PolynomialWitness(%q) ok = %v, want %v", tt.pattern, ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if prefix != tt.wantPrefix || pump1 != tt.wantPump || pump2 != tt.wantPump {
+				t.Errorf("PolynomialWitness(%q) = (%q, %q, %q), want (%q, %q, %q)",
+					tt.pattern, prefix, pump1, pump2, tt.wantPrefix, tt.wantPump, tt.wantPump)
+			}
+
+			// The pumped input matches, and the suffix makes it fail
+			re := regexp.MustCompile(tt.pattern)
+			input := prefix + strings.Repeat(pump1, 5) + strings.Repeat(pump2, 5)
+			if !re.MatchString(input) {
+				t.Errorf("pumped input %q does not match %q", input, tt.pattern)
+			}
+			if re.MatchString(input + suffix) {
+				t.Errorf("input with suffix %q still matches %q", input+suffix, tt.pattern)
+			}
+		})
+	}
+}
+
+func TestPolynomialWitness_Degree(t *testing.T) {
+	nfa, err := parser.BuildNFA(parser.NewParser().MustParse(`^a*a*$`))
+	if err != nil {
+		t.Fatalf("BuildNFA() error = %v", err)
+	}
+
+	if _, _, _, _, ok := PolynomialWitness(nfa, 1); ok {
+		t.Error("PolynomialWitness() with degree 1 should not find a witness")
+	}
+	if _, _, _, _, ok := PolynomialWitness(nil, 2); ok {
+		t.Error("PolynomialWitness(nil) should not find a witness")
+	}
+}
+
+func TestExponentialWitness(t *testing.T) {
+	tests := []struct {
+		pattern string
+		wantOK  bool
+	}{
+		{`^(a+)+$`, true},
+		{`^(?:a|aa)+$`, true},
+		{`^(\w+\d+)+$`, true},
+		{`^a*a*$`, false},
+		{`^(ab)+$`, false},
+		{`^[a-z]+@[a-z]+$`, false},
+	}
+
+	p := parser.NewParser()
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			nfa, err := parser.BuildNFA(p.MustParse(tt.pattern))
+			if err != nil {
+				t.Fatalf("BuildNFA() error = %v", err)
+			}
+
+			prefix, pump, suffix, ok := ExponentialWitness(nfa)
+			if ok != tt.wantOK {
+				t.Fatalf("ExponentialWitness(%q) ok = %v, want %v (pump %q)", tt.pattern, ok, tt.wantOK, pump)
+			}
+			if !ok {
+				return
+			}
+			if pump == "" {
+				t.Fatalf("ExponentialWitness(%q) returned an empty pump", tt.pattern)
+			}
+
+			// The suffix makes the pumped input fail
+			re := regexp.MustCompile(tt.pattern)
+			input := prefix + strings.Repeat(pump, 5)
+			if re.MatchString(input + suffix) {
+				t.Errorf("input with suffix %q still matches %q", input+suffix, tt.pattern)
+			}
+		})
+	}
+
+	if _, _, _, ok := ExponentialWitness(nil); ok {
+		t.Error("ExponentialWitness(nil) should not find a witness")
+	}
+}
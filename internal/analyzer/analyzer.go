@@ -2,14 +2,22 @@
 package analyzer
 
 import (
+	"context"
+	"fmt"
+	"math"
 	"regexp/syntax"
+	"strings"
 	"time"
+
+	"github.com/theakshaypant/regret/internal/parser"
 )
 
 // Options contains configuration for analysis.
 type Options struct {
 	Timeout            time.Duration
 	MaxComplexityScore int
+	MaxNFAStates       int  // 0 means no limit
+	MinimizeDFA        bool // Record the minimized DFA size, which can be costly
 }
 
 // ComplexityScore contains complexity analysis results (internal format).
@@ -21,8 +29,31 @@ type ComplexityScore struct {
 	Degree      int                    // For polynomial: degree (2=quadratic, 3=cubic)
 	Issues      []string               // List of contributing issues
 	Metrics     map[string]interface{} // Detailed metrics
+	Breakdown   []SubScore             // Points contributed by each analysis step
+
+	// Partial is set if analysis stopped before every step completed.
+	// Completeness is the fraction of steps that completed, from 0 to 1.
+	// The score of a partial analysis is raised toward MaxComplexityScore
+	// in proportion to the steps that did not complete.
+	Partial      bool
+	Completeness float64
+}
+
+// SubScore records how many points one analysis step added to the score.
+type SubScore struct {
+	Name        string // nesting, quantifiers, alternations, pattern, time_complexity, partial_analysis
+	Score       int
+	Description string
 }
 
+// budgetFirstPhase is how long AnalyzeWithBudget spends at most on the
+// cheap nesting and quantifier steps.
+const budgetFirstPhase = 10 * time.Millisecond
+
+// step is one analysis step, returning the points it adds to the score.
+// It stops early once ctx is done.
+type step func(context.Context, *syntax.Regexp, *ComplexityScore) SubScore
+
 // Analyzer performs complexity analysis on regex patterns.
 type Analyzer struct {
 	opts *Options
@@ -41,7 +72,62 @@ func NewAnalyzer(opts *Options) *Analyzer {
 
 // Analyze performs comprehensive complexity analysis on a regex pattern.
 func (a *Analyzer) Analyze(re *syntax.Regexp, pattern string) (*ComplexityScore, error) {
-	score := &ComplexityScore{
+	return a.AnalyzeContext(context.Background(), re, pattern)
+}
+
+// AnalyzeContext is like Analyze but stops when ctx is done. Analysis steps
+// look at ctx after every AST node they visit. On cancellation it returns the
+// score accumulated so far together with ctx.Err().
+func (a *Analyzer) AnalyzeContext(ctx context.Context, re *syntax.Regexp, pattern string) (*ComplexityScore, error) {
+	score := newComplexityScore()
+
+	// Analyze different aspects
+	steps := []step{
+		a.analyzeNesting,
+		a.analyzeQuantifiers,
+		a.analyzeAlternations,
+		a.analyzePattern,
+	}
+	completed := a.runSteps(ctx, re, score, steps)
+	a.finish(score, completed, len(steps))
+
+	return score, ctx.Err()
+}
+
+// AnalyzeWithBudget is like Analyze but deepens the analysis only while
+// budget lasts, for interactive use such as editor integrations. Nesting
+// and quantifier analysis run first, for at most 10ms; alternation
+// analysis and then the NFA-based analysis run only if budget remains.
+// If the budget runs out first, the score is marked Partial rather than
+// an error returned, and Completeness tells how much of the analysis ran.
+func (a *Analyzer) AnalyzeWithBudget(re *syntax.Regexp, pattern string, budget time.Duration) (*ComplexityScore, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), budget)
+	defer cancel()
+	first, cancelFirst := context.WithTimeout(ctx, budgetFirstPhase)
+	defer cancelFirst()
+
+	score := newComplexityScore()
+	phases := []struct {
+		ctx   context.Context
+		steps []step
+	}{
+		{first, []step{a.analyzeNesting, a.analyzeQuantifiers}},
+		{ctx, []step{a.analyzeAlternations}},
+		{ctx, []step{a.analyzePattern}},
+	}
+	completed, total := 0, 0
+	for _, phase := range phases {
+		completed += a.runSteps(phase.ctx, re, score, phase.steps)
+		total += len(phase.steps)
+	}
+	a.finish(score, completed, total)
+
+	return score, nil
+}
+
+// newComplexityScore returns the score of a pattern before analysis.
+func newComplexityScore() *ComplexityScore {
+	return &ComplexityScore{
 		Score:       0,
 		Complexity:  "O(n)",
 		Description: "Linear time complexity",
@@ -50,22 +136,72 @@ func (a *Analyzer) Analyze(re *syntax.Regexp, pattern string) (*ComplexityScore,
 		Issues:      make([]string, 0),
 		Metrics:     make(map[string]interface{}),
 	}
+}
 
-	// Analyze different aspects
-	a.analyzeNesting(re, score)
-	a.analyzeQuantifiers(re, score)
-	a.analyzeAlternations(re, score)
-	a.analyzePattern(re, score)
+// runSteps runs steps in order until ctx is done, adding their points to
+// score, and returns how many completed before ctx was done.
+func (a *Analyzer) runSteps(ctx context.Context, re *syntax.Regexp, score *ComplexityScore, steps []step) int {
+	completed := 0
+	for _, step := range steps {
+		if ctx.Err() != nil {
+			break
+		}
+		sub := step(ctx, re, score)
+		score.Score += sub.Score
+		score.Breakdown = append(score.Breakdown, sub)
+		if ctx.Err() == nil {
+			completed++
+		}
+	}
+	return completed
+}
 
-	// Determine final complexity class
+// finish completes a score once completed of total steps have run.
+func (a *Analyzer) finish(score *ComplexityScore, completed, total int) {
+	score.Completeness = float64(completed) / float64(total)
+	score.Partial = completed < total
+
+	// Determine final complexity class, which may raise the score to the
+	// minimum for its class
+	before := score.Score
 	a.determineComplexity(score)
+	if raised := score.Score - before; raised > 0 {
+		score.Breakdown = append(score.Breakdown, SubScore{
+			Name:        "time_complexity",
+			Score:       raised,
+			Description: "minimum score for " + score.Complexity + " time complexity",
+		})
+	}
+
+	// Steps that did not complete might have found more, so treat the
+	// part of the analysis that is missing as if it found the worst
+	if score.Partial {
+		if missing := 1 - score.Completeness; score.Score < a.opts.MaxComplexityScore {
+			raised := int(math.Ceil(missing * float64(a.opts.MaxComplexityScore-score.Score)))
+			score.Score += raised
+			score.Breakdown = append(score.Breakdown, SubScore{
+				Name:        "partial_analysis",
+				Score:       raised,
+				Description: fmt.Sprintf("%.0f%% of the analysis did not complete", missing*100),
+			})
+		}
+		score.Description += " (partial analysis)"
+	}
 
 	// Cap score at max
 	if score.Score > a.opts.MaxComplexityScore {
 		score.Score = a.opts.MaxComplexityScore
 	}
+}
 
-	return score, nil
+// walk is walkRegexp but stops visiting nodes once ctx is done.
+func (a *Analyzer) walk(ctx context.Context, re *syntax.Regexp, visitor func(*syntax.Regexp) bool) {
+	walkRegexp(re, func(node *syntax.Regexp) bool {
+		if ctx.Err() != nil {
+			return false
+		}
+		return visitor(node)
+	})
 }
 
 // EstimateComplexity provides a quick complexity estimate.
@@ -96,11 +232,11 @@ func (a *Analyzer) EstimateComplexity(re *syntax.Regexp) string {
 
 // Analysis methods
 
-func (a *Analyzer) analyzeNesting(re *syntax.Regexp, score *ComplexityScore) {
+func (a *Analyzer) analyzeNesting(ctx context.Context, re *syntax.Regexp, score *ComplexityScore) SubScore {
 	maxDepth := 0
 	nestedCount := 0
 
-	walkRegexp(re, func(node *syntax.Regexp) bool {
+	a.walk(ctx, re, func(node *syntax.Regexp) bool {
 		if isQuantifier(node) {
 			depth := getQuantifierDepth(node)
 			if depth > maxDepth {
@@ -116,31 +252,56 @@ func (a *Analyzer) analyzeNesting(re *syntax.Regexp, score *ComplexityScore) {
 		return true
 	})
 
+	return scoreNesting(score, maxDepth, nestedCount)
+}
+
+// scoreNesting records the nesting metrics in score and returns the points
+// they add.
+func scoreNesting(score *ComplexityScore, maxDepth, nestedCount int) SubScore {
+	sub := SubScore{Name: "nesting", Description: "no nested quantifiers"}
 	score.Metrics["nesting_depth"] = maxDepth
 	score.Metrics["nested_quantifiers"] = nestedCount
 
 	if nestedCount > 0 {
-		score.Score += 40 + (nestedCount * 10)
+		sub.Score = 40 + (nestedCount * 10)
+		sub.Description = fmt.Sprintf("%d nested quantifier(s)", nestedCount)
 		score.Issues = append(score.Issues, "nested quantifiers (exponential risk)")
 		score.TimeClass = "exponential"
 		score.Degree = nestedCount + 1
 	} else if maxDepth > 3 {
-		score.Score += 15 + (maxDepth * 5)
+		sub.Score = 15 + (maxDepth * 5)
+		sub.Description = fmt.Sprintf("nesting depth %d", maxDepth)
 		score.Issues = append(score.Issues, "deep nesting")
 	}
+
+	return sub
 }
 
-func (a *Analyzer) analyzeQuantifiers(re *syntax.Regexp, score *ComplexityScore) {
-	quantifierCount := countQuantifiers(re)
-	overlappingSeqs := findOverlappingQuantifiers(re)
+func (a *Analyzer) analyzeQuantifiers(ctx context.Context, re *syntax.Regexp, score *ComplexityScore) SubScore {
+	quantifierCount, overlappingSeqs := 0, 0
+	a.walk(ctx, re, func(node *syntax.Regexp) bool {
+		if isQuantifier(node) {
+			quantifierCount++
+		}
+		if hasConsecutiveQuantifiers(node) {
+			overlappingSeqs++
+		}
+		return true
+	})
+
+	return scoreQuantifiers(score, quantifierCount, overlappingSeqs)
+}
 
+// scoreQuantifiers records the quantifier metrics in score and returns the
+// points they add.
+func scoreQuantifiers(score *ComplexityScore, quantifierCount, overlappingSeqs int) SubScore {
+	sub := SubScore{Name: "quantifiers"}
 	score.Metrics["quantifier_count"] = quantifierCount
-	score.Metrics["overlapping_sequences"] = len(overlappingSeqs)
+	score.Metrics["overlapping_sequences"] = overlappingSeqs
 
-	if len(overlappingSeqs) > 0 {
-		degree := len(overlappingSeqs) + 1
-		baseScore := 25 + (degree * 10)
-		score.Score += baseScore
+	if overlappingSeqs > 0 {
+		degree := overlappingSeqs + 1
+		sub.Score += 25 + (degree * 10)
 
 		if degree == 2 {
 			score.Issues = append(score.Issues, "overlapping quantifiers (quadratic)")
@@ -157,16 +318,17 @@ func (a *Analyzer) analyzeQuantifiers(re *syntax.Regexp, score *ComplexityScore)
 	}
 
 	if quantifierCount > 15 {
-		score.Score += 10 + (quantifierCount - 15)
+		sub.Score += 10 + (quantifierCount - 15)
 		score.Issues = append(score.Issues, "excessive quantifiers")
 	}
-}
 
-func (a *Analyzer) analyzeAlternations(re *syntax.Regexp, score *ComplexityScore) {
-	alternationCount := 0
-	overlappingAlts := 0
+	sub.Description = fmt.Sprintf("%d quantifier(s), %d overlapping sequence(s)", quantifierCount, overlappingSeqs)
+	return sub
+}
 
-	walkRegexp(re, func(node *syntax.Regexp) bool {
+func (a *Analyzer) analyzeAlternations(ctx context.Context, re *syntax.Regexp, score *ComplexityScore) SubScore {
+	alternationCount, overlappingAlts := 0, 0
+	a.walk(ctx, re, func(node *syntax.Regexp) bool {
 		if node.Op == syntax.OpAlternate {
 			alternationCount++
 			if hasOverlappingBranches(node) {
@@ -176,28 +338,66 @@ func (a *Analyzer) analyzeAlternations(re *syntax.Regexp, score *ComplexityScore
 		return true
 	})
 
+	return scoreAlternations(score, alternationCount, overlappingAlts)
+}
+
+// scoreAlternations records the alternation metrics in score and returns
+// the points they add.
+func scoreAlternations(score *ComplexityScore, alternationCount, overlappingAlts int) SubScore {
+	sub := SubScore{Name: "alternations"}
 	score.Metrics["alternations"] = alternationCount
 	score.Metrics["overlapping_alternations"] = overlappingAlts
 
 	if overlappingAlts > 0 {
-		score.Score += 20 + (overlappingAlts * 5)
+		sub.Score = 20 + (overlappingAlts * 5)
 		score.Issues = append(score.Issues, "overlapping alternation branches")
 	}
+
+	sub.Description = fmt.Sprintf("%d alternation(s), %d with overlapping branches", alternationCount, overlappingAlts)
+	return sub
 }
 
-func (a *Analyzer) analyzePattern(re *syntax.Regexp, score *ComplexityScore) {
+func (a *Analyzer) analyzePattern(ctx context.Context, re *syntax.Regexp, score *ComplexityScore) SubScore {
+	sub := SubScore{Name: "pattern", Description: "no costly constructs"}
+	var found []string
 	patternLen := len(re.String())
 	score.Metrics["pattern_length"] = patternLen
 
 	if patternLen > 500 {
-		score.Score += 10
+		sub.Score += 10
 		score.Issues = append(score.Issues, "very long pattern")
+		found = append(found, fmt.Sprintf("long pattern (%d characters)", patternLen))
 	}
 
-	if hasDotStar(re) {
-		score.Score += 5
+	if hasDotStar(ctx, re) {
+		sub.Score += 5
 		score.Metrics["has_dotstar"] = true
+		found = append(found, "contains .*")
 	}
+
+	if len(found) > 0 {
+		sub.Description = strings.Join(found, ", ")
+	}
+
+	// NFA shape: the longest acyclic path is the baseline per-character cost,
+	// and every choice point is a place the matcher may have to backtrack to
+	if ctx.Err() != nil {
+		return sub
+	}
+	nfa, err := parser.BuildNFAWithLimit(re, a.opts.MaxNFAStates)
+	if err != nil || ctx.Err() != nil {
+		return sub
+	}
+	score.Metrics["max_path_length"] = longestAcyclicPath(nfa)
+	score.Metrics["branch_count"] = countBranches(nfa)
+	score.Metrics["max_backtrack_depth"] = nfa.EstimateMaxBacktrackDepth()
+	if a.opts.MinimizeDFA {
+		if dfa := nfa.MinimizeContext(ctx); dfa != nil {
+			score.Metrics["minimized_state_count"] = dfa.StateCount - 1 // minus the accept state
+		}
+	}
+
+	return sub
 }
 
 func (a *Analyzer) determineComplexity(score *ComplexityScore) {
@@ -360,19 +560,8 @@ func findOverlappingQuantifiers(re *syntax.Regexp) []string {
 	var sequences []string
 
 	walkRegexp(re, func(node *syntax.Regexp) bool {
-		if node.Op == syntax.OpConcat {
-			consecutive := 0
-			for _, sub := range node.Sub {
-				if isQuantifier(sub) {
-					consecutive++
-					if consecutive >= 2 {
-						sequences = append(sequences, node.String())
-						break
-					}
-				} else {
-					consecutive = 0
-				}
-			}
+		if hasConsecutiveQuantifiers(node) {
+			sequences = append(sequences, node.String())
 		}
 		return true
 	})
@@ -380,6 +569,25 @@ func findOverlappingQuantifiers(re *syntax.Regexp) []string {
 	return sequences
 }
 
+// hasConsecutiveQuantifiers reports whether re is a concatenation with two
+// adjacent quantifiers.
+func hasConsecutiveQuantifiers(re *syntax.Regexp) bool {
+	if re.Op != syntax.OpConcat {
+		return false
+	}
+	consecutive := 0
+	for _, sub := range re.Sub {
+		if !isQuantifier(sub) {
+			consecutive = 0
+			continue
+		}
+		if consecutive++; consecutive >= 2 {
+			return true
+		}
+	}
+	return false
+}
+
 func hasOverlappingBranches(re *syntax.Regexp) bool {
 	if re.Op != syntax.OpAlternate || len(re.Sub) < 2 {
 		return false
@@ -395,9 +603,13 @@ func hasOverlappingBranches(re *syntax.Regexp) bool {
 	return false
 }
 
-func hasDotStar(re *syntax.Regexp) bool {
+// hasDotStar reports whether re contains .*, giving up once ctx is done.
+func hasDotStar(ctx context.Context, re *syntax.Regexp) bool {
 	result := false
 	walkRegexp(re, func(node *syntax.Regexp) bool {
+		if ctx.Err() != nil {
+			return false
+		}
 		if node.Op == syntax.OpStar && len(node.Sub) > 0 {
 			if node.Sub[0].Op == syntax.OpAnyChar || node.Sub[0].Op == syntax.OpAnyCharNotNL {
 				result = true
@@ -408,3 +620,29 @@ func hasDotStar(re *syntax.Regexp) bool {
 	})
 	return result
 }
+
+// longestAcyclicPath estimates the number of consuming transitions on the
+// longest path from the start to the accept state. Transitions back into a
+// state already on the path are ignored, so each loop body is counted once.
+// The search is memoized, so on patterns with loops the result depends on
+// the order of transitions, as parser.NFA.EstimateLongestPath describes.
+func longestAcyclicPath(nfa *parser.NFA) int {
+	return nfa.EstimateLongestPath(func(trans *parser.Transition) int {
+		if trans.IsEpsilon || trans.Label.Type == parser.TransitionAnchor {
+			return 0
+		}
+		return 1
+	})
+}
+
+// countBranches returns the number of NFA states with more than one epsilon
+// transition, i.e. the choice points introduced by alternations and quantifiers.
+func countBranches(nfa *parser.NFA) int {
+	count := 0
+	for _, state := range nfa.States {
+		if len(state.EpsilonTo) > 1 {
+			count++
+		}
+	}
+	return count
+}
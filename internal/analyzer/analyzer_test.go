@@ -1,9 +1,15 @@
 package analyzer
 
 import (
+	"context"
+	"errors"
+	"reflect"
 	"regexp/syntax"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/theakshaypant/regret/internal/parser"
 )
 
 func TestNewAnalyzer(t *testing.T) {
@@ -232,6 +238,26 @@ func TestComplexityScoring(t *testing.T) {
 				"overlapping_sequences": 1,
 			},
 		},
+		{
+			name:         "nfa path length and branches",
+			pattern:      "ab(c|d)*e",
+			checkMetrics: true,
+			expectedMetrics: map[string]int{
+				"max_path_length":     4, // a, b, one pass through the loop, e
+				"branch_count":        2, // star entry and loop end
+				"max_backtrack_depth": 2,
+			},
+		},
+		{
+			name:         "no branches in literal",
+			pattern:      "abc",
+			checkMetrics: true,
+			expectedMetrics: map[string]int{
+				"max_path_length":     3,
+				"branch_count":        0,
+				"max_backtrack_depth": 0,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -308,6 +334,40 @@ func TestMaxComplexityScore(t *testing.T) {
 	}
 }
 
+func TestAnalyze_Breakdown(t *testing.T) {
+	analyzer := NewAnalyzer(nil)
+
+	for _, pattern := range []string{"hello", "(a+)+", "a*a*", "(a|ab)*c", "^.*foo$"} {
+		t.Run(pattern, func(t *testing.T) {
+			re, err := syntax.Parse(pattern, syntax.Perl)
+			if err != nil {
+				t.Fatalf("Failed to parse pattern: %v", err)
+			}
+			re = re.Simplify()
+
+			result, err := analyzer.Analyze(re, pattern)
+			if err != nil {
+				t.Fatalf("Analyze() error = %v", err)
+			}
+
+			sum := 0
+			names := make(map[string]bool)
+			for _, sub := range result.Breakdown {
+				sum += sub.Score
+				names[sub.Name] = true
+			}
+			if sum != result.Score {
+				t.Errorf("Breakdown sums to %d, Score = %d (%+v)", sum, result.Score, result.Breakdown)
+			}
+			for _, name := range []string{"nesting", "quantifiers", "alternations", "pattern"} {
+				if !names[name] {
+					t.Errorf("Breakdown missing %q step", name)
+				}
+			}
+		})
+	}
+}
+
 func TestHelperFunctions(t *testing.T) {
 	t.Run("isQuantifier", func(t *testing.T) {
 		tests := []struct {
@@ -410,3 +470,208 @@ func BenchmarkEstimateComplexity(b *testing.B) {
 		_ = analyzer.EstimateComplexity(re)
 	}
 }
+
+func TestAnalyzeContext_Deadline(t *testing.T) {
+	re, err := syntax.Parse("(a+)+", syntax.Perl)
+	if err != nil {
+		t.Fatalf("Failed to parse pattern: %v", err)
+	}
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	result, err := NewAnalyzer(nil).AnalyzeContext(ctx, re.Simplify(), "(a+)+")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("AnalyzeContext() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if result == nil {
+		t.Fatal("AnalyzeContext() returned no partial result")
+	}
+	if len(result.Breakdown) != 1 || result.Breakdown[0].Name != "partial_analysis" {
+		t.Errorf("expected no analysis steps to run, got %+v", result.Breakdown)
+	}
+	if !result.Partial || result.Completeness != 0 {
+		t.Errorf("Partial = %v, Completeness = %v, want a partial result", result.Partial, result.Completeness)
+	}
+	if result.Score != 100 {
+		t.Errorf("Score = %d, want 100 when no analysis step completed", result.Score)
+	}
+}
+
+func TestAnalyzeWithBudget(t *testing.T) {
+	re, err := syntax.Parse("^(a|ab)*(x+)+$", syntax.Perl)
+	if err != nil {
+		t.Fatalf("Failed to parse pattern: %v", err)
+	}
+	re = re.Simplify()
+	a := NewAnalyzer(nil)
+
+	full, err := a.Analyze(re, "^(a|ab)*(x+)+$")
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	result, err := a.AnalyzeWithBudget(re, "^(a|ab)*(x+)+$", time.Second)
+	if err != nil {
+		t.Fatalf("AnalyzeWithBudget() error = %v", err)
+	}
+	if result.Partial || result.Completeness != 1 {
+		t.Errorf("Partial = %v, Completeness = %v, want a complete analysis", result.Partial, result.Completeness)
+	}
+	if result.Score != full.Score || !reflect.DeepEqual(result.Breakdown, full.Breakdown) {
+		t.Errorf("AnalyzeWithBudget() = %d %+v, want %d %+v like Analyze", result.Score, result.Breakdown, full.Score, full.Breakdown)
+	}
+
+	// Without budget no step runs, and the result says so instead of failing
+	result, err = a.AnalyzeWithBudget(re, "^(a|ab)*(x+)+$", 0)
+	if err != nil {
+		t.Fatalf("AnalyzeWithBudget(0) error = %v", err)
+	}
+	if !result.Partial || result.Completeness != 0 || len(result.Breakdown) != 1 || result.Breakdown[0].Name != "partial_analysis" {
+		t.Errorf("AnalyzeWithBudget(0) = %+v, want a partial result with no steps", result)
+	}
+	if result.Score != 100 {
+		t.Errorf("AnalyzeWithBudget(0) Score = %d, want 100 when no analysis step completed", result.Score)
+	}
+	if !strings.Contains(result.Description, "partial") {
+		t.Errorf("Description = %q, want it to mention the partial analysis", result.Description)
+	}
+}
+
+func TestAnalyzeWithProof(t *testing.T) {
+	defer func(d time.Duration) { verifyTime = d }(verifyTime)
+	verifyTime = 50 * time.Millisecond
+
+	tests := []struct {
+		pattern   string
+		wantType  string // "" means no proof
+		wantPumps int
+	}{
+		{"(a+)+$", "EDA", 1},
+		{"^(?:a|aa)+$", "EDA", 1},
+		{`\d+\d+x`, "IDA", 2},
+		{"^[a-z]+$", "", 0},
+		{"abc", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re, err := syntax.Parse(tt.pattern, syntax.Perl)
+			if err != nil {
+				t.Fatalf("Failed to parse pattern: %v", err)
+			}
+			score, proof, err := NewAnalyzer(nil).AnalyzeWithProof(re.Simplify(), tt.pattern)
+			if err != nil {
+				t.Fatalf("AnalyzeWithProof() error = %v", err)
+			}
+			if score == nil {
+				t.Fatal("AnalyzeWithProof() returned no score")
+			}
+			if tt.wantType == "" {
+				if proof != nil {
+					t.Errorf("AnalyzeWithProof() proof = %+v, want nil", proof)
+				}
+				return
+			}
+			if proof == nil {
+				t.Fatal("AnalyzeWithProof() returned no proof")
+			}
+			if proof.Type != tt.wantType {
+				t.Errorf("Type = %q, want %q", proof.Type, tt.wantType)
+			}
+			if len(proof.PumpStrings) != tt.wantPumps {
+				t.Errorf("PumpStrings = %q, want %d pumps", proof.PumpStrings, tt.wantPumps)
+			}
+			if tt.wantType == "IDA" && proof.Degree < 2 {
+				t.Errorf("Degree = %d, want at least 2", proof.Degree)
+			}
+			if !proof.Verification {
+				t.Errorf("Verification = false for witness %q", proof.Input(5))
+			}
+		})
+	}
+}
+
+func TestRunsLongerThan_Linear(t *testing.T) {
+	re, _ := syntax.Parse("^[a-z]+$", syntax.Perl)
+	nfa, err := parser.BuildNFA(re.Simplify())
+	if err != nil {
+		t.Fatalf("BuildNFA() error = %v", err)
+	}
+	input := strings.Repeat("a", 10000) + "!"
+	if runsLongerThan(nfa, input, 100*time.Millisecond) {
+		t.Error("runsLongerThan() = true for a linear pattern")
+	}
+}
+
+func TestAnalyzeIncremental(t *testing.T) {
+	tests := []struct {
+		before, after string
+	}{
+		{`(ab)c|d`, `(ab)+c|d`},
+		{`(a|b)c`, `(a|b)+c`},
+		{`a+xb+`, `a+b+`},
+		{`a+b`, `a+b+`},
+		{`foo|bar`, `foo|ba*r`},
+		{`x|yz`, `x|y`},
+		{`(a+)+b`, `a+b`},
+		{`abc`, `abd`},
+		{`a|b|c`, `ab|a|c`},
+		{`^\d+$`, `^\d+\d+$`},
+	}
+
+	a := NewAnalyzer(nil)
+	for _, tt := range tests {
+		t.Run(tt.before+" to "+tt.after, func(t *testing.T) {
+			before, err := syntax.Parse(tt.before, syntax.Perl)
+			if err != nil {
+				t.Fatalf("Failed to parse pattern: %v", err)
+			}
+			after, err := syntax.Parse(tt.after, syntax.Perl)
+			if err != nil {
+				t.Fatalf("Failed to parse pattern: %v", err)
+			}
+
+			prev, err := a.Analyze(before, tt.before)
+			if err != nil {
+				t.Fatalf("Analyze() error = %v", err)
+			}
+			got, err := a.AnalyzeIncremental(after, tt.after, prev, parser.ASTDiff(before, after))
+			if err != nil {
+				t.Fatalf("AnalyzeIncremental() error = %v", err)
+			}
+			want, err := a.Analyze(after, tt.after)
+			if err != nil {
+				t.Fatalf("Analyze() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("AnalyzeIncremental() = %+v, want %+v like Analyze", got, want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeIncremental_ReusesUnaffectedSteps(t *testing.T) {
+	before, _ := syntax.Parse(`(ab)c|d`, syntax.Perl)
+	after, _ := syntax.Parse(`(ab)+c|d`, syntax.Perl)
+	delta := parser.ASTDiff(before, after)
+	prev, err := NewAnalyzer(nil).Analyze(before, `(ab)c|d`)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	// Adding a quantifier to a group inside a branch affects nesting and
+	// quantifiers, but not the alternation
+	if !changesQuantifiers(delta) {
+		t.Error("changesQuantifiers() = false, want true")
+	}
+	if changesAlternations(after, prev, delta) {
+		t.Error("changesAlternations() = true, want false")
+	}
+
+	// Changing the operator of a branch can change its overlap
+	before, _ = syntax.Parse(`foo|bar`, syntax.Perl)
+	after, _ = syntax.Parse(`foo|b+`, syntax.Perl)
+	if !changesAlternations(after, prev, parser.ASTDiff(before, after)) {
+		t.Error("changesAlternations() = false for a changed branch, want true")
+	}
+}
@@ -0,0 +1,174 @@
+package analyzer
+
+import (
+	"context"
+	"regexp/syntax"
+
+	"github.com/theakshaypant/regret/internal/parser"
+)
+
+// AnalyzeIncremental is like Analyze but reuses the metrics of prev, the
+// score of the previous version of the pattern, for the analysis steps that
+// the changes in delta cannot affect, for editor integrations that analyze
+// a pattern on every keystroke. delta must be parser.ASTDiff of the previous
+// AST and re, and prev must come from an analyzer with the same options.
+// The result is the same as Analyze(re, pattern).
+//
+// Nesting analysis re-runs only if a changed subtree contains a quantifier,
+// quantifier analysis also if a node was added or removed, since that can
+// make two quantifiers adjacent, and alternation analysis only if a branch
+// of an alternation changed or the changed subtrees differ in alternations.
+// The pattern step always re-runs. If prev is nil or partial, the whole
+// pattern is analyzed.
+//
+// Example:
+//
+//	score, _ := a.Analyze(before, patternBefore)
+//	// the user types a '+'
+//	score, _ = a.AnalyzeIncremental(after, patternAfter, score, parser.ASTDiff(before, after))
+func (a *Analyzer) AnalyzeIncremental(re *syntax.Regexp, pattern string, prev *ComplexityScore, delta []parser.ASTChange) (*ComplexityScore, error) {
+	if prev == nil || prev.Partial {
+		return a.Analyze(re, pattern)
+	}
+
+	score := newComplexityScore()
+	steps := []step{
+		a.analyzeNesting,
+		a.analyzeQuantifiers,
+		a.analyzeAlternations,
+		a.analyzePattern,
+	}
+	if !changesQuantifiers(delta) {
+		steps[0] = reuse(prev, steps[0], scoreNesting, "nesting_depth", "nested_quantifiers")
+	}
+	if !changesQuantifiers(delta) && !changesAdjacency(delta) {
+		steps[1] = reuse(prev, steps[1], scoreQuantifiers, "quantifier_count", "overlapping_sequences")
+	}
+	if !changesAlternations(re, prev, delta) {
+		steps[2] = reuse(prev, steps[2], scoreAlternations, "alternations", "overlapping_alternations")
+	}
+	completed := a.runSteps(context.Background(), re, score, steps)
+	a.finish(score, completed, len(steps))
+
+	return score, nil
+}
+
+// reuse returns a step that scores the two metrics of prev named by keys
+// instead of measuring them again, or runs full if prev lacks them.
+func reuse(prev *ComplexityScore, full step, scoreFn func(*ComplexityScore, int, int) SubScore, keys ...string) step {
+	return func(ctx context.Context, re *syntax.Regexp, score *ComplexityScore) SubScore {
+		x, okX := prev.Metrics[keys[0]].(int)
+		y, okY := prev.Metrics[keys[1]].(int)
+		if !okX || !okY {
+			return full(ctx, re, score)
+		}
+		return scoreFn(score, x, y)
+	}
+}
+
+// changesQuantifiers reports whether a subtree changed by delta contains a
+// quantifier.
+func changesQuantifiers(delta []parser.ASTChange) bool {
+	for _, change := range delta {
+		if (change.Before != nil && hasQuantifier(change.Before)) ||
+			(change.After != nil && hasQuantifier(change.After)) {
+			return true
+		}
+	}
+	return false
+}
+
+// changesAdjacency reports whether delta adds or removes a node, which can
+// change which quantifiers of a concatenation are adjacent.
+func changesAdjacency(delta []parser.ASTChange) bool {
+	for _, change := range delta {
+		if change.Op != parser.ASTModified {
+			return true
+		}
+	}
+	return false
+}
+
+// changesAlternations reports whether delta can change the alternation
+// metrics of prev: the changed subtrees differ in their alternations, or a
+// change is a branch of an alternation, whose overlap depends on the
+// operators of its branches.
+func changesAlternations(re *syntax.Regexp, prev *ComplexityScore, delta []parser.ASTChange) bool {
+	var parents map[*syntax.Regexp]*syntax.Regexp
+	for _, change := range delta {
+		beforeCount, beforeOverlapping := alternationStats(change.Before)
+		afterCount, afterOverlapping := alternationStats(change.After)
+		if beforeCount != afterCount || beforeOverlapping != afterOverlapping {
+			return true
+		}
+
+		var parent *syntax.Regexp
+		switch change.Op {
+		case parser.ASTAdded:
+			if parent = nodeAt(re, change.Path[:max(len(change.Path)-1, 0)]); parent == nil {
+				return true
+			}
+		case parser.ASTModified:
+			if len(change.Path) == 0 {
+				continue
+			}
+			if parents == nil {
+				parents = parentMap(re)
+			}
+			var ok bool
+			if parent, ok = parents[change.After]; !ok {
+				return true
+			}
+		default:
+			// The parent of a removed node is only in the previous AST
+			if alternations, _ := prev.Metrics["alternations"].(int); alternations > 0 {
+				return true
+			}
+		}
+		if parent != nil && parent.Op == syntax.OpAlternate {
+			return true
+		}
+	}
+	return false
+}
+
+// alternationStats counts the alternations in re and those of them with
+// overlapping branches. re may be nil.
+func alternationStats(re *syntax.Regexp) (count, overlapping int) {
+	if re == nil {
+		return 0, 0
+	}
+	walkRegexp(re, func(node *syntax.Regexp) bool {
+		if node.Op == syntax.OpAlternate {
+			count++
+			if hasOverlappingBranches(node) {
+				overlapping++
+			}
+		}
+		return true
+	})
+	return count, overlapping
+}
+
+// nodeAt returns the node of re at path, or nil if there is none.
+func nodeAt(re *syntax.Regexp, path []int) *syntax.Regexp {
+	for _, i := range path {
+		if i >= len(re.Sub) {
+			return nil
+		}
+		re = re.Sub[i]
+	}
+	return re
+}
+
+// parentMap maps every node of re but the root to its parent.
+func parentMap(re *syntax.Regexp) map[*syntax.Regexp]*syntax.Regexp {
+	parents := make(map[*syntax.Regexp]*syntax.Regexp)
+	walkRegexp(re, func(node *syntax.Regexp) bool {
+		for _, sub := range node.Sub {
+			parents[sub] = node
+		}
+		return true
+	})
+	return parents
+}
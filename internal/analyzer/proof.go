@@ -0,0 +1,151 @@
+package analyzer
+
+import (
+	"regexp/syntax"
+	"strings"
+	"time"
+
+	"github.com/theakshaypant/regret/internal/ambiguity"
+	"github.com/theakshaypant/regret/internal/parser"
+)
+
+// Witness inputs are pumped this many times when a proof is verified. EDA
+// inputs double the work with every pump; IDA inputs need far more pumps to
+// reach the same running time.
+const (
+	edaVerifyPumps = 30
+	idaVerifyPumps = 3000
+)
+
+// verifyTime is how long the backtracking matcher must run on a witness
+// before a proof counts as verified.
+var verifyTime = time.Second
+
+// Proof is a machine-readable witness that a pattern is ambiguous enough to
+// backtrack super-linearly. The input prefix + pump^n + suffix, with each of
+// PumpStrings repeated n times in order, is matched in exponentially (EDA)
+// or polynomially (IDA) many ways before the suffix rejects it.
+type Proof struct {
+	Type         string   // EDA (exponential) or IDA (polynomial)
+	Degree       int      // Degree of the polynomial for IDA, 0 for EDA
+	Prefix       string   // Leads from the start state to the ambiguous loop
+	PumpStrings  []string // Read by the ambiguous loops
+	Suffix       string   // Makes the overall match fail
+	Verification bool     // A backtracking matcher ran for over a second on the witness
+}
+
+// Input returns the witness input with every pump repeated n times.
+func (p *Proof) Input(n int) string {
+	var b strings.Builder
+	b.WriteString(p.Prefix)
+	for _, pump := range p.PumpStrings {
+		b.WriteString(strings.Repeat(pump, n))
+	}
+	b.WriteString(p.Suffix)
+	return b.String()
+}
+
+// AnalyzeWithProof is like Analyze but also searches the pattern's NFA for
+// an ambiguity witness and returns it as a Proof. Exponential witnesses are
+// preferred over polynomial ones. The proof is nil if the NFA is too large
+// or no witness is found.
+//
+// Go's regexp package never backtracks, so the proof is verified by running
+// the witness through a backtracking matcher over the NFA, the way PCRE-style
+// engines match, and timing it. Verification takes up to a second.
+func (a *Analyzer) AnalyzeWithProof(re *syntax.Regexp, pattern string) (*ComplexityScore, *Proof, error) {
+	score, err := a.Analyze(re, pattern)
+	if err != nil {
+		return score, nil, err
+	}
+
+	nfa, err := parser.BuildNFAWithLimit(re, a.opts.MaxNFAStates)
+	if err != nil {
+		return score, nil, nil
+	}
+
+	var proof *Proof
+	pumps := edaVerifyPumps
+	if prefix, pump, suffix, ok := ambiguity.ExponentialWitness(nfa); ok {
+		proof = &Proof{Type: "EDA", Prefix: prefix, PumpStrings: []string{pump}, Suffix: suffix}
+	} else {
+		degree := 2
+		if score.TimeClass == "polynomial" && score.Degree > degree {
+			degree = score.Degree
+		}
+		prefix, pump1, pump2, suffix, ok := ambiguity.PolynomialWitness(nfa, degree)
+		if !ok {
+			return score, nil, nil
+		}
+		proof = &Proof{Type: "IDA", Degree: degree, Prefix: prefix, PumpStrings: []string{pump1, pump2}, Suffix: suffix}
+		pumps = idaVerifyPumps
+	}
+
+	proof.Verification = runsLongerThan(nfa, proof.Input(pumps), verifyTime)
+	return score, proof, nil
+}
+
+// runsLongerThan reports whether a backtracking match of the whole input
+// against the NFA is still running after limit.
+func runsLongerThan(nfa *parser.NFA, input string, limit time.Duration) bool {
+	b := &backtracker{
+		nfa:      nfa,
+		input:    []rune(input),
+		deadline: time.Now().Add(limit),
+		onPath:   make(map[backtrackState]bool),
+	}
+	b.match(nfa.Start, 0)
+	return b.timedOut
+}
+
+// backtrackState is a state of the NFA at a position in the input.
+type backtrackState struct {
+	state *parser.State
+	pos   int
+}
+
+// backtracker matches input against an NFA depth first, trying the
+// transitions of each state in order and backing up on failure, with no
+// memoization. Anchors are followed like epsilon transitions.
+type backtracker struct {
+	nfa      *parser.NFA
+	input    []rune
+	deadline time.Time
+	steps    int
+	timedOut bool
+	onPath   map[backtrackState]bool // Guards against epsilon cycles
+}
+
+func (b *backtracker) match(s *parser.State, pos int) bool {
+	if b.timedOut {
+		return false
+	}
+	if b.steps++; b.steps%1024 == 0 && time.Now().After(b.deadline) {
+		b.timedOut = true
+		return false
+	}
+	if s == b.nfa.Accept && pos == len(b.input) {
+		return true
+	}
+
+	key := backtrackState{s, pos}
+	if b.onPath[key] {
+		return false
+	}
+	b.onPath[key] = true
+	defer delete(b.onPath, key)
+
+	for _, t := range s.Transitions {
+		switch {
+		case t.IsEpsilon || t.Label.Type == parser.TransitionAnchor:
+			if b.match(t.To, pos) {
+				return true
+			}
+		case pos < len(b.input) && t.Label.Matches(b.input[pos]):
+			if b.match(t.To, pos+1) {
+				return true
+			}
+		}
+	}
+	return false
+}
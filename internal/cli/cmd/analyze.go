@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"encoding/json"
 	"os"
 
 	"github.com/spf13/cobra"
@@ -32,13 +33,19 @@ Use this for in-depth understanding of pattern performance.`,
   regret analyze "(a+)+" --output=json
   
   # Table format
-  regret analyze "(a+)+" --output=table`,
+  regret analyze "(a+)+" --output=table
+
+  # Trace the decisions of the checks as JSON
+  regret analyze "\d+\.\d+" --trace`,
 	Args: cobra.ExactArgs(1),
 	Run:  runAnalyze,
 }
 
+var analyzeTrace bool
+
 func init() {
 	rootCmd.AddCommand(analyzeCmd)
+	analyzeCmd.Flags().BoolVar(&analyzeTrace, "trace", false, "Print a JSON trace of the checks that ran, the nodes they visited, the thresholds they compared and the issues they reported")
 }
 
 func runAnalyze(cmd *cobra.Command, args []string) {
@@ -51,26 +58,25 @@ func runAnalyze(cmd *cobra.Command, args []string) {
 		formatter.PrintInfo("Mode: %s", mode)
 	}
 
-	// Get validation issues
+	// Get validation issues and complexity analysis
 	opts := getOptions()
-	issues, err := regret.ValidateWithOptions(pattern, opts)
-	if err != nil {
-		formatter.PrintError("Failed to validate pattern: %v", err)
-		os.Exit(1)
+	if analyzeTrace {
+		printTrace(formatter, pattern, opts)
+		return
 	}
-
-	// Get complexity analysis
-	score, err := regret.AnalyzeComplexity(pattern)
+	report, err := regret.Report(pattern, opts)
 	if err != nil {
-		formatter.PrintError("Failed to analyze complexity: %v", err)
+		formatter.PrintError("Failed to analyze pattern: %v", err)
 		os.Exit(1)
 	}
 
 	// Create result
 	result := &output.AnalysisResult{
-		Pattern: pattern,
-		Score:   score,
-		Issues:  issues,
+		Pattern:        pattern,
+		Score:          report.Complexity,
+		Issues:         report.Issues,
+		Summary:        report.Summary,
+		ShowReferences: verbose,
 	}
 
 	// Format and print
@@ -83,3 +89,19 @@ func runAnalyze(cmd *cobra.Command, args []string) {
 		formatter.PrintInfo("Analysis complete")
 	}
 }
+
+// printTrace prints the trace of the analysis of pattern as JSON.
+func printTrace(formatter *output.Formatter, pattern string, opts *regret.Options) {
+	trace, err := regret.TraceDetection(pattern, opts)
+	if err != nil {
+		formatter.PrintError("Failed to trace analysis: %v", err)
+		os.Exit(1)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(trace); err != nil {
+		formatter.PrintError("Failed to format trace: %v", err)
+		os.Exit(1)
+	}
+}
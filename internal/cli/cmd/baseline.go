@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/theakshaypant/regret/internal/cli/output"
+)
+
+// baselineKey identifies a finding independently of its line and column,
+// so that findings stay suppressed when surrounding code moves.
+type baselineKey struct {
+	File    string
+	Pattern string
+	Issue   string
+}
+
+// baseline counts the known findings of a previous scan. Each known
+// finding suppresses one finding with the same key.
+type baseline map[baselineKey]int
+
+// loadBaseline reads a baseline written by --update-baseline or by
+// "regret scan --output=json".
+func loadBaseline(path string) (baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var result output.ScanResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+
+	b := make(baseline)
+	for _, finding := range result.Findings {
+		b[keyOf(finding)]++
+	}
+	return b, nil
+}
+
+// writeBaseline writes result to path in the format of
+// "regret scan --output=json".
+func writeBaseline(path string, result *output.ScanResult) error {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// suppress reports whether finding is known, using up one known finding.
+func (b baseline) suppress(finding output.Finding) bool {
+	key := keyOf(finding)
+	if b[key] == 0 {
+		return false
+	}
+	b[key]--
+	return true
+}
+
+func keyOf(finding output.Finding) baselineKey {
+	return baselineKey{File: finding.File, Pattern: finding.Pattern, Issue: finding.Issue}
+}
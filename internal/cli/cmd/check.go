@@ -1,11 +1,18 @@
 package cmd
 
 import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/theakshaypant/regret"
 	"github.com/theakshaypant/regret/internal/cli/output"
+	"github.com/theakshaypant/regret/internal/parser"
+	"golang.org/x/term"
 )
 
 // checkCmd represents the check command
@@ -15,8 +22,20 @@ var checkCmd = &cobra.Command{
 	Long: `Check validates a regex pattern for ReDoS vulnerabilities.
 
 This command provides a quick safety check and returns:
-  - Exit code 0: Pattern is safe
+  - Exit code 0: Pattern is safe (or a safe fix was printed with --fix)
   - Exit code 1: Pattern is unsafe or error occurred
+  - Exit code 2: Pattern is unsafe and --fix found no automatic fix
+
+A pattern is unsafe if at least one issue at or above --severity-threshold
+is found. Issues below the threshold are reported but don't fail the check.
+
+With --watch, check reads patterns from stdin, one per line, and validates
+each one as it is entered, clearing the screen before printing the result
+under a header with the current options and the number of patterns tested.
+In a terminal, patterns are typed at a prompt with line editing, and the up
+and down arrows recall earlier patterns. With --output=json only the results
+are printed, one JSON object per pattern. Watch mode runs until end of input
+(Ctrl+D) and always exits 0.
 
 Perfect for CI/CD pipelines and quick validation.`,
 	Example: `  # Check a pattern
@@ -27,42 +46,73 @@ Perfect for CI/CD pipelines and quick validation.`,
   regret check "(a+)+" --mode=thorough
   
   # JSON output for scripting
-  regret check "(a+)+" --output=json`,
-	Args: cobra.ExactArgs(1),
-	Run:  runCheck,
+  regret check "(a+)+" --output=json
+
+  # Only fail CI on high and critical issues
+  regret check "a*a*" --severity-threshold=high
+
+  # Print a safe rewrite of the pattern
+  regret check "(a+)+" --fix
+
+  # Show the rewrite as a diff
+  regret check "(a+)+" --fix --dry-run
+
+  # Validate patterns as you type them
+  regret check --watch
+  regret check --watch --mode=thorough
+
+  # Validate a list of patterns, one JSON result each
+  regret check --watch --output=json < patterns.txt`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if checkWatch {
+			return cobra.MaximumNArgs(1)(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	Run: runCheck,
 }
 
+var (
+	checkFix               bool
+	checkDryRun            bool
+	checkSeverityThreshold string
+	checkStrict            bool
+	checkWatch             bool
+)
+
 func init() {
 	rootCmd.AddCommand(checkCmd)
+	checkCmd.Flags().BoolVar(&checkFix, "fix", false, "Print a safe rewrite of unsafe patterns")
+	checkCmd.Flags().BoolVar(&checkDryRun, "dry-run", false, "With --fix, show a diff of the rewrite")
+	checkCmd.Flags().StringVar(&checkSeverityThreshold, "severity-threshold", "low", "Minimum severity that fails the check (critical|high|medium|low|info)")
+	checkCmd.Flags().BoolVar(&checkStrict, "strict", false, "Only fail on critical issues (same as --severity-threshold=critical)")
+	checkCmd.Flags().BoolVar(&checkWatch, "watch", false, "Validate each line read from stdin as a pattern, redrawing the result")
 }
 
 func runCheck(cmd *cobra.Command, args []string) {
-	pattern := args[0]
-
 	formatter := output.NewFormatter(outputFormat, noColor)
 
-	// Validate pattern
-	opts := getOptions()
-	issues, err := regret.ValidateWithOptions(pattern, opts)
+	threshold, err := getSeverityThreshold(cmd, checkSeverityThreshold, checkStrict)
 	if err != nil {
-		formatter.PrintError("Failed to validate pattern: %v", err)
+		formatter.PrintError("%v", err)
 		os.Exit(1)
 	}
 
-	// Analyze complexity for scoring
-	score, err := regret.AnalyzeComplexity(pattern)
+	opts := getOptions()
+	if checkWatch {
+		runCheckWatch(formatter, args, opts, threshold)
+		return
+	}
+
+	result, err := checkPattern(args[0], opts, threshold)
 	if err != nil {
-		formatter.PrintError("Failed to analyze complexity: %v", err)
+		formatter.PrintError("Failed to validate pattern: %v", err)
 		os.Exit(1)
 	}
 
-	// Create result
-	result := &output.CheckResult{
-		Pattern:    pattern,
-		Safe:       len(issues) == 0 && score.Safe,
-		Complexity: score.TimeComplexity.String(),
-		Score:      score.Overall,
-		Issues:     issues,
+	if checkFix {
+		runFix(formatter, result, opts)
+		return
 	}
 
 	// Format and print
@@ -77,8 +127,198 @@ func runCheck(cmd *cobra.Command, args []string) {
 	}
 }
 
+// checkPattern validates a pattern and analyzes its complexity for scoring.
+// Only issues at or above the threshold make the pattern unsafe.
+func checkPattern(pattern string, opts *regret.Options, threshold regret.Severity) (*output.CheckResult, error) {
+	report, err := regret.Report(pattern, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &output.CheckResult{
+		Pattern:    pattern,
+		Safe:       len(filterBySeverity(report.Issues, threshold)) == 0,
+		Complexity: report.Complexity.TimeComplexity.String(),
+		Score:      report.Complexity.Overall,
+		Issues:     report.Issues,
+		Summary:    report.Summary,
+	}, nil
+}
+
+// runCheckWatch validates every line read from stdin as a pattern, starting
+// with the pattern argument if one was given, until end of input. When stdin
+// and stdout are a terminal, lines are edited at a prompt with history, and
+// the screen is cleared before each result.
+func runCheckWatch(formatter *output.Formatter, args []string, opts *regret.Options, threshold regret.Severity) {
+	interactive := term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+	tested := 0
+
+	show := func(pattern string) {
+		tested++
+		header := &output.CheckWatchHeader{
+			Mode:              opts.Mode.String(),
+			SeverityThreshold: threshold.String(),
+			Tested:            tested,
+			ClearScreen:       interactive,
+		}
+		if err := formatter.FormatCheckWatchHeader(header); err != nil {
+			formatter.PrintError("Failed to format output: %v", err)
+			os.Exit(1)
+		}
+
+		result, err := checkPattern(pattern, opts, threshold)
+		if err != nil {
+			formatter.PrintError("Failed to validate pattern %q: %v", pattern, err)
+		} else if err := formatter.FormatCheckResult(result); err != nil {
+			formatter.PrintError("Failed to format output: %v", err)
+			os.Exit(1)
+		}
+	}
+
+	if len(args) > 0 {
+		show(args[0])
+	}
+
+	readLine := watchLineReader(os.Stdin, interactive)
+	for {
+		pattern, err := readLine()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			formatter.PrintError("Failed to read patterns: %v", err)
+			os.Exit(1)
+		}
+		if pattern == "" {
+			continue
+		}
+		show(pattern)
+	}
+}
+
+// watchLineReader returns a function that reads the next line of in, and
+// io.EOF at the end of input. If interactive, lines are read at a prompt
+// with line editing, and the up and down arrows recall earlier lines.
+func watchLineReader(in *os.File, interactive bool) func() (string, error) {
+	if !interactive {
+		scanner := bufio.NewScanner(in)
+		return func() (string, error) {
+			if !scanner.Scan() {
+				if err := scanner.Err(); err != nil {
+					return "", err
+				}
+				return "", io.EOF
+			}
+			return strings.TrimRight(scanner.Text(), "\r"), nil
+		}
+	}
+
+	// The terminal is only in raw mode while a line is edited, so that
+	// results and errors are printed as usual
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{in, os.Stdout}, "pattern> ")
+	fd := int(in.Fd())
+	return func() (string, error) {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return "", err
+		}
+		defer term.Restore(fd, state)
+		return t.ReadLine()
+	}
+}
+
+// runFix prints a safe rewrite of the checked pattern.
+// Exits 2 if the pattern is unsafe and no automatic fix is available.
+func runFix(formatter *output.Formatter, result *output.CheckResult, opts *regret.Options) {
+	result.FixedPattern = result.Pattern
+	if !result.Safe {
+		fixed, err := regret.SuggestWithOptions(result.Pattern, opts)
+		if err != nil && !errors.Is(err, regret.ErrNoFix) {
+			formatter.PrintError("Failed to fix pattern: %v", err)
+			os.Exit(1)
+		}
+		result.FixedPattern = fixed
+	}
+	if checkDryRun && result.FixedPattern != "" {
+		result.FixChanges = astChanges(result.Pattern, result.FixedPattern)
+	}
+
+	if err := formatter.FormatFixResult(result, checkDryRun); err != nil {
+		formatter.PrintError("Failed to format output: %v", err)
+		os.Exit(1)
+	}
+
+	if result.FixedPattern == "" {
+		os.Exit(2)
+	}
+}
+
+// astChanges describes the changes between the ASTs of two patterns, or
+// returns nil if either does not parse.
+func astChanges(before, after string) []string {
+	p := parser.NewParser()
+	a, err := p.ParseRaw(before)
+	if err != nil {
+		return nil
+	}
+	b, err := p.ParseRaw(after)
+	if err != nil {
+		return nil
+	}
+
+	var changes []string
+	for _, change := range parser.ASTDiff(a, b) {
+		changes = append(changes, change.String())
+	}
+	return changes
+}
+
+// getSeverityThreshold returns the minimum severity that fails a command.
+// --strict is a shorthand for --severity-threshold=critical.
+func getSeverityThreshold(cmd *cobra.Command, level string, strict bool) (regret.Severity, error) {
+	if strict {
+		if cmd.Flags().Changed("severity-threshold") {
+			return 0, fmt.Errorf("--strict and --severity-threshold cannot be used together")
+		}
+		return regret.Critical, nil
+	}
+
+	threshold, err := regret.SeverityFromString(level)
+	if err != nil {
+		return 0, fmt.Errorf("invalid severity threshold %q (expected critical|high|medium|low|info)", level)
+	}
+	return threshold, nil
+}
+
+// filterBySeverity returns the issues at or above the threshold.
+// Severities are ordered from Critical (most severe) to Info.
+func filterBySeverity(issues []regret.Issue, threshold regret.Severity) []regret.Issue {
+	var filtered []regret.Issue
+	for _, issue := range issues {
+		if issue.Severity <= threshold {
+			filtered = append(filtered, issue)
+		}
+	}
+	return filtered
+}
+
 func getOptions() *regret.Options {
 	opts := regret.DefaultOptions()
+	if configFile != "" {
+		var err error
+		opts, err = regret.ParseOptions(configFile)
+		if err != nil {
+			exitWithError("Failed to load config: %v", err)
+		}
+
+		// Flags win over the config file, but only when given
+		if !rootCmd.PersistentFlags().Changed("mode") {
+			return opts
+		}
+	}
 
 	// Set validation mode
 	switch mode {
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/theakshaypant/regret"
 )
 
 var (
@@ -26,7 +27,7 @@ dangerous regex patterns that can lead to catastrophic backtracking (ReDoS).
 
 It provides validation, complexity analysis, and adversarial input generation
 to help you write safe and performant regular expressions.`,
-	Version: "0.1.0",
+	Version: regret.FullVersion(),
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -43,7 +44,7 @@ func init() {
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
 	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Quiet mode (errors only)")
 	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable color output")
-	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "", "Config file path")
+	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "", "Config file with validation options (.yaml, .yml, .toml or .json)")
 }
 
 func initConfig() {
@@ -51,9 +52,6 @@ func initConfig() {
 		os.Setenv("NO_COLOR", "1")
 	}
 
-	if configFile != "" {
-		// TODO: Load config file
-	}
 }
 
 // getValidationMode converts string mode to regret validation mode
@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/theakshaypant/regret"
+	"github.com/theakshaypant/regret/internal/cli/output"
+)
+
+var (
+	scanLanguage          string
+	scanSeverityThreshold string
+	scanStrict            bool
+	scanAnnotate          bool
+	scanOutputDir         string
+	scanSummary           bool
+	scanBaseline          string
+	scanUpdateBaseline    bool
+)
+
+// scanCmd represents the scan command
+var scanCmd = &cobra.Command{
+	Use:   "scan <path>...",
+	Short: "Find dangerous regex patterns in source code",
+	Long: `Scan extracts regex patterns from source files and validates each one.
+
+Directories are scanned recursively, skipping hidden directories,
+vendor and node_modules. The language of each file is picked from
+its extension: .go, .py, .js/.mjs/.cjs/.jsx, .ts/.tsx and .java.
+Other files are skipped unless --lang is given.
+
+Go files are tokenized, so patterns are found exactly. Other languages
+are scanned line by line for common regex APIs (re.compile, new RegExp,
+regex literals, Pattern.compile, ...).
+
+With --annotate, a copy of each Go file with issues is written to
+--output-dir, with a "// regret: ..." comment above each call site
+that has issues. The scanned files are not modified.
+
+With --summary, aggregate statistics are printed instead of the
+findings: pattern counts by worst severity, the average complexity
+score and the most dangerous patterns.
+
+With --baseline, findings recorded in the baseline file are suppressed,
+so only newly introduced dangerous patterns are reported and fail the
+scan. Findings are matched by file, pattern and issue, ignoring line
+numbers, so paths must be given the same way as when the baseline was
+written. With --update-baseline, the findings of this scan are written
+to the baseline file instead and the scan does not fail.
+
+Exits with code 1 if any pattern has issues at or above the severity
+threshold.`,
+	Example: `  # Scan a project
+  regret scan .
+
+  # Scan files without a known extension as Python
+  regret scan scripts/ --lang=python
+
+  # Only fail on high and critical issues
+  regret scan . --severity-threshold=high
+
+  # Write annotated copies of Go files with issues to annotated/
+  regret scan . --annotate --output-dir=annotated
+
+  # Print aggregate statistics for an audit
+  regret scan . --summary
+
+  # Record the current findings, then only report new ones
+  regret scan . --baseline=baseline.json --update-baseline
+  regret scan . --baseline=baseline.json`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runScan,
+}
+
+func init() {
+	rootCmd.AddCommand(scanCmd)
+	scanCmd.Flags().StringVar(&scanLanguage, "lang", "", "Language of the scanned files (go|python|javascript|java); default is by extension")
+	scanCmd.Flags().StringVar(&scanSeverityThreshold, "severity-threshold", "low", "Minimum severity that fails the scan (critical|high|medium|low|info)")
+	scanCmd.Flags().BoolVar(&scanStrict, "strict", false, "Only fail on critical issues (same as --severity-threshold=critical)")
+	scanCmd.Flags().BoolVar(&scanAnnotate, "annotate", false, "Write copies of Go files with issues, annotated with comments, to --output-dir")
+	scanCmd.Flags().StringVar(&scanOutputDir, "output-dir", "", "Directory for annotated files written by --annotate")
+	scanCmd.Flags().BoolVar(&scanSummary, "summary", false, "Print aggregate statistics instead of the findings")
+	scanCmd.Flags().StringVar(&scanBaseline, "baseline", "", "Suppress the findings recorded in this file, a previous scan's JSON output")
+	scanCmd.Flags().BoolVar(&scanUpdateBaseline, "update-baseline", false, "Write the findings of this scan to the --baseline file")
+}
+
+func runScan(cmd *cobra.Command, args []string) {
+	formatter := output.NewFormatter(outputFormat, noColor)
+
+	threshold, err := getSeverityThreshold(cmd, scanSeverityThreshold, scanStrict)
+	if err != nil {
+		formatter.PrintError("%v", err)
+		os.Exit(1)
+	}
+
+	if scanAnnotate && scanOutputDir == "" {
+		formatter.PrintError("--annotate requires --output-dir")
+		os.Exit(1)
+	}
+
+	if scanUpdateBaseline && scanBaseline == "" {
+		formatter.PrintError("--update-baseline requires --baseline")
+		os.Exit(1)
+	}
+
+	var known baseline
+	if scanBaseline != "" && !scanUpdateBaseline {
+		known, err = loadBaseline(scanBaseline)
+		if err != nil {
+			formatter.PrintError("Failed to load baseline: %v", err)
+			os.Exit(1)
+		}
+	}
+
+	files, err := collectScanFiles(args)
+	if err != nil {
+		formatter.PrintError("Failed to scan: %v", err)
+		os.Exit(1)
+	}
+
+	opts := getOptions()
+	result := &output.ScanResult{TotalFiles: len(files)}
+	var patterns []string
+
+	for _, path := range files {
+		language := scanLanguage
+		if language == "" {
+			language = languageForFile(path)
+		}
+		if language == "" {
+			continue
+		}
+
+		findings, err := scanFile(path, language, opts)
+		if err != nil {
+			formatter.PrintError("Failed to scan %s: %v", path, err)
+			os.Exit(1)
+		}
+		result.ScannedFiles++
+		result.TotalPatterns += len(findings)
+
+		var fileIssues []regret.Issue
+		for _, finding := range findings {
+			patterns = append(patterns, finding.Pattern)
+			if finding.Err != nil {
+				if verbose {
+					formatter.PrintWarning("%s:%d:%d: skipped %q: %v", path, finding.Line, finding.Column, finding.Pattern, finding.Err)
+				}
+				continue
+			}
+
+			issues := filterBySeverity(finding.Issues, threshold)
+			if len(issues) == 0 {
+				continue
+			}
+
+			found := output.Finding{
+				File:    path,
+				Line:    finding.Line,
+				Column:  finding.Column,
+				Pattern: finding.Pattern,
+				Issue:   describeWorstIssue(issues),
+			}
+			if known.suppress(found) {
+				result.Suppressed++
+				continue
+			}
+
+			result.DangerousCount++
+			result.Findings = append(result.Findings, found)
+			fileIssues = append(fileIssues, issues...)
+		}
+
+		if scanAnnotate && language == "go" && len(fileIssues) > 0 {
+			if err := writeAnnotated(path, fileIssues); err != nil {
+				formatter.PrintError("Failed to annotate %s: %v", path, err)
+				os.Exit(1)
+			}
+		}
+	}
+
+	if scanUpdateBaseline {
+		if err := writeBaseline(scanBaseline, result); err != nil {
+			formatter.PrintError("Failed to write baseline: %v", err)
+			os.Exit(1)
+		}
+		formatter.PrintSuccess("Wrote %d finding(s) to baseline %s", result.DangerousCount, scanBaseline)
+		return
+	}
+
+	if scanSummary {
+		report, err := regret.ReportMany(patterns, opts)
+		if err != nil {
+			formatter.PrintError("Failed to summarize: %v", err)
+			os.Exit(1)
+		}
+		if _, err := report.WriteTo(os.Stdout); err != nil {
+			formatter.PrintError("Failed to write summary: %v", err)
+			os.Exit(1)
+		}
+	} else if err := formatter.FormatScanResult(result); err != nil {
+		formatter.PrintError("Failed to format output: %v", err)
+		os.Exit(1)
+	}
+
+	if result.DangerousCount > 0 {
+		os.Exit(1)
+	}
+}
+
+// scanFile scans a single source file.
+func scanFile(path, language string, opts *regret.Options) ([]regret.Finding, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	findings, err := regret.ScanReader(f, language, opts)
+	for i := range findings {
+		findings[i].File = path
+	}
+	return findings, err
+}
+
+// writeAnnotated writes a copy of the Go file at path, annotated with
+// issues, to the same relative path below scanOutputDir.
+func writeAnnotated(path string, issues []regret.Issue) error {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	// Rooting the path keeps ".." and absolute paths inside the directory
+	dest := filepath.Join(scanOutputDir, filepath.Clean(string(filepath.Separator)+path))
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(dest, []byte(regret.Annotate(string(source), issues)), 0o644)
+}
+
+// collectScanFiles expands directories into the regular files below them.
+func collectScanFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				name := d.Name()
+				if path != root && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.Type().IsRegular() {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// languageForFile returns the ScanReader language for a file extension,
+// or "" if the file should be skipped.
+func languageForFile(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".go":
+		return "go"
+	case ".py":
+		return "python"
+	case ".js", ".mjs", ".cjs", ".jsx", ".ts", ".tsx":
+		return "javascript"
+	case ".java":
+		return "java"
+	default:
+		return ""
+	}
+}
+
+// describeWorstIssue summarizes the most severe issue.
+func describeWorstIssue(issues []regret.Issue) string {
+	worst, _ := regret.IssueSet(issues).Worst()
+	return fmt.Sprintf("%s: %s", worst.Type, worst.Message)
+}
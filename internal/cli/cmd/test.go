@@ -9,10 +9,12 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/theakshaypant/regret"
 	"github.com/theakshaypant/regret/internal/cli/output"
+	"github.com/theakshaypant/regret/internal/parser"
 )
 
 var (
-	testSize int
+	testSize      int
+	testEmpirical bool
 )
 
 // testCmd represents the test command
@@ -28,6 +30,9 @@ This command:
   - Detects exponential/polynomial growth
   - Validates theoretical analysis
 
+With --empirical, inputs of five growing sizes up to --size are
+profiled instead, showing how matching time and allocations grow.
+
 Use this to confirm ReDoS vulnerabilities with real testing.`,
 	Example: `  # Test with default size
   regret test "(a+)+"
@@ -35,8 +40,12 @@ Use this to confirm ReDoS vulnerabilities with real testing.`,
   # Test with specific size
   regret test "(a+)+" --size=20
   
-  # Test with verbose output
-  regret test "(a+)+" --size=30 --verbose`,
+  # Test with verbose output, including the NFA states
+  # active after each input character
+  regret test "(a+)+" --size=30 --verbose
+
+  # Measure time and allocations at growing input sizes
+  regret test "(a+)+" --size=10000 --empirical`,
 	Args: cobra.ExactArgs(1),
 	Run:  runTest,
 }
@@ -44,6 +53,7 @@ Use this to confirm ReDoS vulnerabilities with real testing.`,
 func init() {
 	rootCmd.AddCommand(testCmd)
 	testCmd.Flags().IntVarP(&testSize, "size", "s", 20, "Pump size (number of repetitions)")
+	testCmd.Flags().BoolVar(&testEmpirical, "empirical", false, "Profile inputs of growing sizes up to --size")
 }
 
 func runTest(cmd *cobra.Command, args []string) {
@@ -56,7 +66,7 @@ func runTest(cmd *cobra.Command, args []string) {
 	}
 
 	// Analyze complexity to get pump pattern
-	score, err := regret.AnalyzeComplexity(pattern)
+	score, err := regret.AnalyzeComplexityWithOptions(pattern, regret.ThoroughOptions())
 	if err != nil {
 		formatter.PrintError("Failed to analyze pattern: %v", err)
 		os.Exit(1)
@@ -79,12 +89,21 @@ func runTest(cmd *cobra.Command, args []string) {
 		formatter.PrintInfo("Using auto-generated pump from complexity analysis")
 	}
 
+	if testEmpirical {
+		runEmpirical(formatter, pattern, pump)
+		return
+	}
+
 	// Generate test input
 	input := pump.Generate(testSize)
 
 	fmt.Printf("Generated input (n=%d): %s\n", testSize, truncate(input, 60))
 	fmt.Printf("Input length: %d characters\n\n", len(input))
 
+	if verbose {
+		printSimulation(formatter, pattern, input)
+	}
+
 	// Compile regex
 	re, err := regexp.Compile(pattern)
 	if err != nil {
@@ -138,6 +157,85 @@ func runTest(cmd *cobra.Command, args []string) {
 	}
 }
 
+// empiricalSteps is the number of input sizes profiled by --empirical.
+const empiricalSteps = 5
+
+// runEmpirical profiles the pattern on pump inputs of growing sizes up to
+// testSize. Exits 1 if an input times out.
+func runEmpirical(formatter *output.Formatter, pattern string, pump *regret.PumpPattern) {
+	var sizes []int
+	var inputs []string
+	for i := 1; i <= empiricalSteps; i++ {
+		size := testSize * i / empiricalSteps
+		if size == 0 || len(sizes) > 0 && sizes[len(sizes)-1] == size {
+			continue
+		}
+		sizes = append(sizes, size)
+		inputs = append(inputs, pump.Generate(size))
+	}
+
+	result, err := regret.Profile(pattern, inputs)
+	if err != nil {
+		formatter.PrintError("Failed to profile pattern: %v", err)
+		os.Exit(1)
+	}
+
+	if err := formatter.FormatProfileResult(&output.ProfileResult{Pattern: pattern, Sizes: sizes, Profile: result}); err != nil {
+		formatter.PrintError("Failed to format output: %v", err)
+		os.Exit(1)
+	}
+
+	timedOut := false
+	for _, in := range result.Inputs {
+		timedOut = timedOut || in.TimedOut
+	}
+	if timedOut {
+		formatter.PrintError("Timeout (%v) - Pattern exhibits severe ReDoS", regret.DefaultProfileTimeout)
+		os.Exit(1)
+	}
+}
+
+// maxSimulationSteps bounds the steps printed by printSimulation.
+const maxSimulationSteps = 40
+
+// printSimulation shows the NFA states active after each input character.
+func printSimulation(formatter *output.Formatter, pattern, input string) {
+	re, err := parser.NewParser().Parse(pattern)
+	if err != nil {
+		formatter.PrintWarning("Skipping NFA simulation: %v", err)
+		return
+	}
+	nfa, err := parser.BuildNFAWithLimit(re, regret.DefaultOptions().MaxNFAStates)
+	if err != nil {
+		formatter.PrintWarning("Skipping NFA simulation: %v", err)
+		return
+	}
+
+	fmt.Printf("NFA simulation (%d states, accept state %d):\n", nfa.StateCount, nfa.Accept.ID)
+	states := nfa.StartStates()
+	fmt.Printf("  start      %v\n", parser.StateIDs(states))
+
+	step := 0
+	for _, r := range input {
+		step++
+		if step > maxSimulationSteps {
+			fmt.Printf("  ... (%d more characters)\n", len([]rune(input))-maxSimulationSteps)
+			break
+		}
+		states = nfa.SimulateStep(states, r)
+		fmt.Printf("  %3d %-6q %v\n", step, r, parser.StateIDs(states))
+		if len(states) == 0 {
+			break
+		}
+	}
+
+	if nfa.Simulate(input) {
+		fmt.Printf("  → whole input matches\n\n")
+	} else {
+		fmt.Printf("  → whole input does not match\n\n")
+	}
+}
+
 func truncate(s string, maxLen int) string {
 	if len(s) <= maxLen {
 		return s
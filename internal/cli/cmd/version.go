@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/theakshaypant/regret"
@@ -12,15 +14,40 @@ var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Show version information",
 	Long:  `Display version information for the regret CLI tool.`,
-	Run:   runVersion,
+	Example: `  # Show version information
+  regret version
+
+  # Machine-readable version
+  regret version --json`,
+	Run: runVersion,
 }
 
+var versionJSON bool
+
 func init() {
 	rootCmd.AddCommand(versionCmd)
+	versionCmd.Flags().BoolVar(&versionJSON, "json", false, "Print the version as JSON")
 }
 
 func runVersion(cmd *cobra.Command, args []string) {
-	fmt.Printf("regret version %s\n", regret.Version)
+	if versionJSON || outputFormat == "json" {
+		data := map[string]interface{}{
+			"version":    regret.FullVersion(),
+			"major":      regret.CurrentVersion.Major,
+			"minor":      regret.CurrentVersion.Minor,
+			"patch":      regret.CurrentVersion.Patch,
+			"prerelease": regret.CurrentVersion.Prerelease,
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(data); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Printf("regret version %s\n", regret.FullVersion())
 	fmt.Printf("Regex threat detection and analysis tool\n")
 	fmt.Printf("\nFeatures:\n")
 	fmt.Printf("  • Fast heuristics detection\n")
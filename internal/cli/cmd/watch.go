@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/theakshaypant/regret"
+	"github.com/theakshaypant/regret/internal/cli/output"
+)
+
+var (
+	watchExitOnError bool
+)
+
+// watchCmd represents the watch command
+var watchCmd = &cobra.Command{
+	Use:   "watch <file|dir>",
+	Short: "Re-validate pattern files as they change",
+	Long: `Watch validates a file of newline-separated regex patterns and
+re-validates it every time it changes. Blank lines and lines starting
+with # are skipped.
+
+If a directory is given, every file directly inside it is watched.
+
+Watch runs until interrupted. With --exit-on-error it exits with
+code 1 as soon as an unsafe or invalid pattern is found.`,
+	Example: `  # Watch a pattern file
+  regret watch patterns.txt
+
+  # Watch every file in a directory
+  regret watch ./regexes
+
+  # Stop at the first unsafe pattern
+  regret watch patterns.txt --exit-on-error`,
+	Args: cobra.ExactArgs(1),
+	Run:  runWatch,
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	watchCmd.Flags().BoolVar(&watchExitOnError, "exit-on-error", false, "Exit with code 1 on the first unsafe or invalid pattern")
+}
+
+func runWatch(cmd *cobra.Command, args []string) {
+	path := args[0]
+
+	formatter := output.NewFormatter(outputFormat, noColor)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		formatter.PrintError("Failed to watch %s: %v", path, err)
+		os.Exit(1)
+	}
+
+	events := make(chan regret.FileValidationEvent)
+	var watcher *regret.Watcher
+	if info.IsDir() {
+		watcher, err = regret.WatchDir(path, getOptions(), events)
+	} else {
+		watcher, err = regret.WatchFile(path, getOptions(), events)
+	}
+	if err != nil {
+		formatter.PrintError("Failed to watch %s: %v", path, err)
+		os.Exit(1)
+	}
+	defer watcher.Close()
+
+	if verbose {
+		formatter.PrintInfo("Watching %s (press Ctrl+C to stop)", path)
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+
+	for {
+		select {
+		case <-interrupt:
+			return
+
+		case event := <-events:
+			if err := formatter.FormatWatchEvent(event); err != nil {
+				formatter.PrintError("Failed to format output: %v", err)
+				os.Exit(1)
+			}
+
+			if watchExitOnError && (event.Err != nil || len(event.Issues) > 0) {
+				watcher.Close()
+				os.Exit(1)
+			}
+		}
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/fatih/color"
 	"github.com/theakshaypant/regret"
@@ -17,33 +19,65 @@ type Formatter struct {
 	noColor bool
 }
 
-// NewFormatter creates a new formatter
+// NewFormatter creates a new formatter that writes to stdout
 func NewFormatter(format string, noColor bool) *Formatter {
+	return NewFormatterWithWriter(format, noColor, os.Stdout)
+}
+
+// NewFormatterWithWriter creates a new formatter that writes to w.
+// Errors are still printed to stderr.
+func NewFormatterWithWriter(format string, noColor bool, w io.Writer) *Formatter {
 	if noColor {
 		color.NoColor = true
 	}
 
 	return &Formatter{
-		writer:  os.Stdout,
+		writer:  w,
 		format:  format,
 		noColor: noColor,
 	}
 }
 
+// SetWriter sets the writer results and messages are written to
+func (f *Formatter) SetWriter(w io.Writer) {
+	f.writer = w
+}
+
 // CheckResult represents the result of a check command
 type CheckResult struct {
-	Pattern    string
-	Safe       bool
-	Complexity string
-	Score      int
-	Issues     []regret.Issue
+	Pattern      string
+	Safe         bool
+	Complexity   string
+	Score        int
+	Issues       []regret.Issue
+	Summary      string
+	FixedPattern string   // Set by --fix; empty if no automatic fix exists
+	FixChanges   []string // Set by --fix --dry-run; the AST changes of the fix
+}
+
+// CheckWatchHeader represents the header check --watch prints above each
+// result
+type CheckWatchHeader struct {
+	Mode              string
+	SeverityThreshold string
+	Tested            int  // Patterns tested so far, including this one
+	ClearScreen       bool // Clear the terminal before the header
 }
 
 // AnalysisResult represents the result of an analyze command
 type AnalysisResult struct {
+	Pattern        string
+	Score          *regret.ComplexityScore
+	Issues         []regret.Issue
+	Summary        string
+	ShowReferences bool `json:"-"` // Set by --verbose; JSON output always includes them
+}
+
+// ProfileResult represents the measurements of test --empirical
+type ProfileResult struct {
 	Pattern string
-	Score   *regret.ComplexityScore
-	Issues  []regret.Issue
+	Sizes   []int // Pump size of each input
+	Profile *regret.ProfilingResult
 }
 
 // ScanResult represents the result of a scan command
@@ -52,6 +86,7 @@ type ScanResult struct {
 	ScannedFiles   int
 	TotalPatterns  int
 	DangerousCount int
+	Suppressed     int // Findings known from a baseline, not in Findings
 	Findings       []Finding
 }
 
@@ -76,10 +111,38 @@ func (f *Formatter) FormatCheckResult(result *CheckResult) error {
 	}
 }
 
+// FormatCheckWatchHeader formats the header of a check --watch result.
+// JSON output has no header, so that it is a stream of check results.
+func (f *Formatter) FormatCheckWatchHeader(header *CheckWatchHeader) error {
+	if f.format == "json" {
+		return nil
+	}
+
+	if header.ClearScreen {
+		fmt.Fprint(f.writer, "\033[H\033[2J")
+	} else if header.Tested > 1 {
+		// Separate the result from the previous one
+		fmt.Fprintln(f.writer)
+	}
+	fmt.Fprintf(f.writer, "regret check --watch | mode: %s | severity threshold: %s | patterns tested: %d\n\n",
+		header.Mode, header.SeverityThreshold, header.Tested)
+	return nil
+}
+
 func (f *Formatter) formatCheckText(result *CheckResult) error {
 	if result.Safe {
 		fmt.Fprintf(f.writer, "%s Pattern is safe\n", f.colorize("✓", color.FgGreen))
 		fmt.Fprintf(f.writer, "Complexity: %s, Score: %d/100\n", result.Complexity, result.Score)
+
+		// Issues below the severity threshold
+		if len(result.Issues) > 0 {
+			fmt.Fprintf(f.writer, "\nIssues below threshold:\n")
+			for _, issue := range result.Issues {
+				severity := f.getSeveritySymbol(issue.Severity)
+				fmt.Fprintf(f.writer, "  %s %s: %s\n", severity, issue.Type, issue.Message)
+				f.printIssueLocation("     ", result.Pattern, issue)
+			}
+		}
 	} else {
 		fmt.Fprintf(f.writer, "%s Pattern is UNSAFE\n", f.colorize("✗", color.FgRed))
 		fmt.Fprintf(f.writer, "Complexity: %s, Score: %d/100\n",
@@ -90,9 +153,14 @@ func (f *Formatter) formatCheckText(result *CheckResult) error {
 			for _, issue := range result.Issues {
 				severity := f.getSeveritySymbol(issue.Severity)
 				fmt.Fprintf(f.writer, "  %s %s: %s\n", severity, issue.Type, issue.Message)
+				f.printIssueLocation("     ", result.Pattern, issue)
 			}
 		}
 	}
+
+	if result.Summary != "" {
+		fmt.Fprintf(f.writer, "\nSummary: %s\n", result.Summary)
+	}
 	return nil
 }
 
@@ -103,6 +171,7 @@ func (f *Formatter) formatCheckJSON(result *CheckResult) error {
 		"complexity": result.Complexity,
 		"score":      result.Score,
 		"issues":     result.Issues,
+		"summary":    result.Summary,
 	}
 
 	enc := json.NewEncoder(f.writer)
@@ -125,6 +194,61 @@ func (f *Formatter) formatCheckTable(result *CheckResult) error {
 		safeStr, result.Complexity, result.Score)
 	fmt.Fprintln(f.writer, "└──────────────┴────────────┴───────┘")
 
+	if result.Summary != "" {
+		fmt.Fprintln(f.writer, result.Summary)
+	}
+
+	return nil
+}
+
+// FormatFixResult formats the result of check --fix.
+// Text output prints only the fixed pattern so it can be consumed by scripts;
+// with dryRun it prints a diff of the rewrite instead.
+func (f *Formatter) FormatFixResult(result *CheckResult, dryRun bool) error {
+	if f.format == "json" {
+		data := map[string]interface{}{
+			"pattern":       result.Pattern,
+			"safe":          result.Safe,
+			"complexity":    result.Complexity,
+			"score":         result.Score,
+			"issues":        result.Issues,
+			"summary":       result.Summary,
+			"fixed_pattern": result.FixedPattern,
+			"fixable":       result.FixedPattern != "",
+			"dry_run":       dryRun,
+		}
+		if dryRun {
+			data["ast_changes"] = result.FixChanges
+		}
+
+		enc := json.NewEncoder(f.writer)
+		enc.SetIndent("", "  ")
+		return enc.Encode(data)
+	}
+
+	if result.FixedPattern == "" {
+		fmt.Fprintf(f.writer, "%s No automatic fix available for %s\n", f.colorize("✗", color.FgRed), result.Pattern)
+		return nil
+	}
+
+	if !dryRun {
+		fmt.Fprintln(f.writer, result.FixedPattern)
+		return nil
+	}
+
+	if result.FixedPattern == result.Pattern {
+		fmt.Fprintf(f.writer, "%s Pattern is safe, no changes\n", f.colorize("✓", color.FgGreen))
+		return nil
+	}
+
+	fmt.Fprintf(f.writer, "%s\n", f.colorize("- "+result.Pattern, color.FgRed))
+	fmt.Fprintf(f.writer, "%s\n", f.colorize("+ "+result.FixedPattern, color.FgGreen))
+	if len(result.FixChanges) > 0 {
+		fmt.Fprintln(f.writer, "\nAST changes:")
+		for _, change := range result.FixChanges {
+			fmt.Fprintf(f.writer, "  %s\n", change)
+		}
+	}
 	return nil
 }
 
@@ -163,15 +287,43 @@ func (f *Formatter) formatAnalysisText(result *AnalysisResult) error {
 	fmt.Fprintf(f.writer, "  Nesting Depth: %d\n", score.Metrics.NestingDepth)
 	fmt.Fprintf(f.writer, "  Quantifiers: %d\n", score.Metrics.QuantifierCount)
 	fmt.Fprintf(f.writer, "  Alternations: %d\n", score.Metrics.AlternationCount)
+	fmt.Fprintf(f.writer, "  Max Path Length: %d\n", score.Metrics.MaxPathLength)
+	fmt.Fprintf(f.writer, "  Branches: %d\n", score.Metrics.BranchCount)
+	fmt.Fprintf(f.writer, "  Max Backtrack Depth: %d\n", score.Metrics.MaxBacktrackDepth)
+	if score.Metrics.MinimizedStateCount > 0 {
+		fmt.Fprintf(f.writer, "  Minimized DFA States: %d\n", score.Metrics.MinimizedStateCount)
+	}
+
+	var contributors []regret.SubScore
+	for _, sub := range score.Breakdown {
+		if sub.Score > 0 {
+			contributors = append(contributors, sub)
+		}
+	}
+	if len(contributors) > 0 {
+		fmt.Fprintf(f.writer, "\nScore Breakdown:\n")
+		for _, sub := range contributors {
+			fmt.Fprintf(f.writer, "  %s: +%d (%s)\n", sub.Name, sub.Score, sub.Description)
+		}
+	}
 
 	if len(result.Issues) > 0 {
 		fmt.Fprintf(f.writer, "\nIssues:\n")
 		for _, issue := range result.Issues {
 			severity := f.getSeveritySymbol(issue.Severity)
 			fmt.Fprintf(f.writer, "  %s %s: %s\n", severity, issue.Type, issue.Message)
+			f.printIssueLocation("     ", result.Pattern, issue)
 			if issue.Suggestion != "" {
 				fmt.Fprintf(f.writer, "     Suggestion: %s\n", issue.Suggestion)
 			}
+			if result.ShowReferences {
+				for _, ref := range issue.References {
+					fmt.Fprintf(f.writer, "     See: %s\n", ref)
+				}
+				if len(issue.RelatedPatterns) > 0 {
+					fmt.Fprintf(f.writer, "     Similar: %s\n", strings.Join(issue.RelatedPatterns, "  "))
+				}
+			}
 		}
 	}
 
@@ -179,6 +331,10 @@ func (f *Formatter) formatAnalysisText(result *AnalysisResult) error {
 		fmt.Fprintf(f.writer, "\nExplanation: %s\n", score.Explanation)
 	}
 
+	if result.Summary != "" {
+		fmt.Fprintf(f.writer, "\nSummary: %s\n", result.Summary)
+	}
+
 	return nil
 }
 
@@ -202,11 +358,16 @@ func (f *Formatter) formatAnalysisTable(result *AnalysisResult) error {
 	fmt.Fprintf(f.writer, "│ Safe           │ %-23s │\n", safeStr)
 	fmt.Fprintf(f.writer, "│ Complexity     │ %-23s │\n", score.TimeComplexity.String())
 	fmt.Fprintf(f.writer, "│ Score          │ %-23d │\n", score.Overall)
+	fmt.Fprintf(f.writer, "│ Normalized     │ %-23.2f │\n", score.NormalizedScore)
 	fmt.Fprintf(f.writer, "│ Has EDA        │ %-23v │\n", score.HasEDA)
 	fmt.Fprintf(f.writer, "│ Has IDA        │ %-23v │\n", score.HasIDA)
 
 	fmt.Fprintln(f.writer, "└────────────────┴─────────────────────────┘")
 
+	if result.Summary != "" {
+		fmt.Fprintln(f.writer, result.Summary)
+	}
+
 	return nil
 }
 
@@ -223,6 +384,9 @@ func (f *Formatter) FormatScanResult(result *ScanResult) error {
 func (f *Formatter) formatScanText(result *ScanResult) error {
 	fmt.Fprintf(f.writer, "Scanned %d files\n", result.ScannedFiles)
 	fmt.Fprintf(f.writer, "Found %d regex patterns\n", result.TotalPatterns)
+	if result.Suppressed > 0 {
+		fmt.Fprintf(f.writer, "Suppressed %d known finding(s) from the baseline\n", result.Suppressed)
+	}
 
 	if result.DangerousCount == 0 {
 		fmt.Fprintf(f.writer, "%s No dangerous patterns found\n", f.colorize("✓", color.FgGreen))
@@ -249,8 +413,103 @@ func (f *Formatter) formatScanJSON(result *ScanResult) error {
 	return enc.Encode(result)
 }
 
+// FormatProfileResult formats the measurements of test --empirical as a
+// table with one row per input size
+func (f *Formatter) FormatProfileResult(result *ProfileResult) error {
+	if f.format == "json" {
+		rows := make([]map[string]interface{}, len(result.Profile.Inputs))
+		for i, in := range result.Profile.Inputs {
+			rows[i] = map[string]interface{}{
+				"size":            result.Sizes[i],
+				"length":          len(in.Input),
+				"duration_ns":     in.Duration.Nanoseconds(),
+				"allocated_bytes": in.AllocsBytes,
+				"matched":         in.Matched,
+				"timed_out":       in.TimedOut,
+			}
+		}
+		enc := json.NewEncoder(f.writer)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]interface{}{"pattern": result.Pattern, "inputs": rows})
+	}
+
+	tw := tabwriter.NewWriter(f.writer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SIZE\tLENGTH\tTIME\tALLOCATED\tMATCHED")
+	for i, in := range result.Profile.Inputs {
+		elapsed := in.Duration.String()
+		if in.TimedOut {
+			elapsed = "timeout"
+		}
+		fmt.Fprintf(tw, "%d\t%d\t%s\t%d B\t%v\n", result.Sizes[i], len(in.Input), elapsed, in.AllocsBytes, in.Matched)
+	}
+	return tw.Flush()
+}
+
+// FormatWatchEvent formats a single event from the watch command
+func (f *Formatter) FormatWatchEvent(event regret.FileValidationEvent) error {
+	if f.format == "json" {
+		data := map[string]interface{}{
+			"path":      event.Path,
+			"line":      event.Line,
+			"pattern":   event.Pattern,
+			"safe":      event.Err == nil && len(event.Issues) == 0,
+			"issues":    event.Issues,
+			"timestamp": event.Timestamp,
+		}
+		if event.Err != nil {
+			data["error"] = event.Err.Error()
+		}
+		return json.NewEncoder(f.writer).Encode(data)
+	}
+
+	timestamp := event.Timestamp.Format("15:04:05")
+	location := event.Path
+	if event.Line > 0 {
+		location = fmt.Sprintf("%s:%d", event.Path, event.Line)
+	}
+
+	switch {
+	case event.Err != nil && event.Pattern == "":
+		fmt.Fprintf(f.writer, "[%s] %s %s: %v\n", timestamp, f.colorize("✗", color.FgRed), location, event.Err)
+	case event.Err != nil:
+		fmt.Fprintf(f.writer, "[%s] %s %s: %s: %v\n", timestamp, f.colorize("✗", color.FgRed), location, event.Pattern, event.Err)
+	case len(event.Issues) == 0:
+		fmt.Fprintf(f.writer, "[%s] %s %s: %s\n", timestamp, f.colorize("✓", color.FgGreen), location, event.Pattern)
+	default:
+		fmt.Fprintf(f.writer, "[%s] %s %s: %s\n", timestamp, f.colorize("✗", color.FgRed), location, event.Pattern)
+		for _, issue := range event.Issues {
+			severity := f.getSeveritySymbol(issue.Severity)
+			fmt.Fprintf(f.writer, "    %s %s: %s\n", severity, issue.Type, issue.Message)
+			f.printIssueLocation("       ", event.Pattern, issue)
+		}
+	}
+
+	return nil
+}
+
 // Helper functions
 
+// issueContextChars is how many characters of the pattern are shown on each
+// side of the span of an issue.
+const issueContextChars = 10
+
+// printIssueLocation prints the part of pattern an issue is about, in its
+// context, unless the issue spans nothing or the whole pattern. For patterns
+// that span several lines, the line and column are printed too, and the
+// newlines of the context are escaped.
+func (f *Formatter) printIssueLocation(indent, pattern string, issue regret.Issue) {
+	if issue.Position.End <= issue.Position.Start || issue.Position.Snippet(pattern) == pattern {
+		return
+	}
+	snippet := issue.Position.ContextSnippet(pattern, issueContextChars)
+	if issue.Position.Line > 0 && strings.Contains(pattern, "\n") {
+		fmt.Fprintf(f.writer, "%sAt line %d, column %d: %s\n", indent, issue.Position.Line, issue.Position.Column,
+			strings.ReplaceAll(snippet, "\n", `\n`))
+		return
+	}
+	fmt.Fprintf(f.writer, "%sAt: %s\n", indent, snippet)
+}
+
 func (f *Formatter) colorize(text string, attr color.Attribute) string {
 	if f.noColor {
 		return text
@@ -0,0 +1,208 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/theakshaypant/regret"
+)
+
+func TestFormatter_FormatCheckResult(t *testing.T) {
+	result := &CheckResult{
+		Pattern:    "(a+)+",
+		Safe:       false,
+		Complexity: "O(2^n)",
+		Score:      70,
+		Issues: []regret.Issue{
+			{Type: regret.NestedQuantifiers, Severity: regret.Critical, Message: "Nested quantifiers detected: (a+)+"},
+		},
+		Summary: "Found 1 issue",
+	}
+
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{"text", []string{"Pattern is UNSAFE", "Score: 70/100", "nested_quantifiers: Nested quantifiers detected", "Summary: Found 1 issue"}},
+		{"table", []string{"│ No ", "O(2^n)", "Found 1 issue"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			f := NewFormatterWithWriter(tt.format, true, &buf)
+			if err := f.FormatCheckResult(result); err != nil {
+				t.Fatalf("FormatCheckResult() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("output = %q, want it to contain %q", buf.String(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatter_FormatCheckResult_IssueLocation(t *testing.T) {
+	result := &CheckResult{
+		Pattern: "^abc(x+)+d$",
+		Issues: []regret.Issue{
+			{Type: regret.NestedQuantifiers, Severity: regret.Critical, Position: regret.Position{Start: 4, End: 9}},
+			{Type: regret.ExponentialBacktracking, Severity: regret.Critical, Position: regret.Position{Start: 0, End: 11}},
+		},
+	}
+
+	var buf bytes.Buffer
+	f := NewFormatterWithWriter("text", true, &buf)
+	if err := f.FormatCheckResult(result); err != nil {
+		t.Fatalf("FormatCheckResult() error = %v", err)
+	}
+	if got := strings.Count(buf.String(), "At: "); got != 1 {
+		t.Errorf("output has %d locations, want 1:\n%s", got, buf.String())
+	}
+	if !strings.Contains(buf.String(), "At: ^abc>>>(x+)+<<<d$") {
+		t.Errorf("output = %q, want the sub-pattern marked", buf.String())
+	}
+}
+
+func TestFormatter_FormatCheckResult_MultiLineLocation(t *testing.T) {
+	result := &CheckResult{
+		Pattern: "^abc\nd(x+)+$",
+		Issues: []regret.Issue{
+			{Type: regret.NestedQuantifiers, Severity: regret.Critical, Position: regret.Position{Start: 6, End: 11, Line: 2, Column: 2}},
+		},
+	}
+
+	var buf bytes.Buffer
+	f := NewFormatterWithWriter("text", true, &buf)
+	if err := f.FormatCheckResult(result); err != nil {
+		t.Fatalf("FormatCheckResult() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), `At line 2, column 2: ^abc\nd>>>(x+)+<<<$`) {
+		t.Errorf("output = %q, want the line, column and escaped context", buf.String())
+	}
+}
+
+func TestFormatter_FormatCheckResult_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	f := NewFormatterWithWriter("json", true, &buf)
+	if err := f.FormatCheckResult(&CheckResult{Pattern: "a+", Safe: true, Complexity: "O(n)"}); err != nil {
+		t.Fatalf("FormatCheckResult() error = %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, buf.String())
+	}
+	if got["pattern"] != "a+" || got["safe"] != true {
+		t.Errorf("output = %v, want pattern a+ and safe true", got)
+	}
+}
+
+func TestFormatter_FormatCheckWatchHeader(t *testing.T) {
+	header := &CheckWatchHeader{Mode: "balanced", SeverityThreshold: "low", Tested: 2, ClearScreen: true}
+
+	var buf bytes.Buffer
+	f := NewFormatterWithWriter("text", true, &buf)
+	if err := f.FormatCheckWatchHeader(header); err != nil {
+		t.Fatalf("FormatCheckWatchHeader() error = %v", err)
+	}
+	want := "\033[H\033[2Jregret check --watch | mode: balanced | severity threshold: low | patterns tested: 2\n\n"
+	if got := buf.String(); got != want {
+		t.Errorf("text output = %q, want %q", got, want)
+	}
+
+	// JSON output is a stream of check results, without headers
+	buf.Reset()
+	f = NewFormatterWithWriter("json", true, &buf)
+	for _, pattern := range []string{"(a+)+", "a+"} {
+		if err := f.FormatCheckWatchHeader(header); err != nil {
+			t.Fatalf("FormatCheckWatchHeader() error = %v", err)
+		}
+		if err := f.FormatCheckResult(&CheckResult{Pattern: pattern}); err != nil {
+			t.Fatalf("FormatCheckResult() error = %v", err)
+		}
+	}
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var result map[string]interface{}
+		if err := dec.Decode(&result); err != nil {
+			t.Fatalf("output is not a stream of JSON objects: %v", err)
+		}
+	}
+}
+
+func TestFormatter_SetWriter(t *testing.T) {
+	var first, second bytes.Buffer
+	f := NewFormatterWithWriter("text", true, &first)
+	f.PrintInfo("one")
+	f.SetWriter(&second)
+	f.PrintInfo("two")
+
+	if got := first.String(); got != "Info: one\n" {
+		t.Errorf("first writer = %q, want %q", got, "Info: one\n")
+	}
+	if got := second.String(); got != "Info: two\n" {
+		t.Errorf("second writer = %q, want %q", got, "Info: two\n")
+	}
+}
+
+func TestFormatter_FormatScanResult_Suppressed(t *testing.T) {
+	result := &ScanResult{ScannedFiles: 1, TotalPatterns: 2, Suppressed: 2}
+
+	var buf bytes.Buffer
+	f := NewFormatterWithWriter("text", true, &buf)
+	if err := f.FormatScanResult(result); err != nil {
+		t.Fatalf("FormatScanResult() error = %v", err)
+	}
+	for _, want := range []string{"Suppressed 2 known finding(s) from the baseline", "No dangerous patterns found"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output = %q, want it to contain %q", buf.String(), want)
+		}
+	}
+}
+
+func TestFormatter_FormatProfileResult(t *testing.T) {
+	result := &ProfileResult{
+		Pattern: "(a+)+$",
+		Sizes:   []int{10, 20},
+		Profile: &regret.ProfilingResult{
+			Pattern: "(a+)+$",
+			Inputs: []regret.InputProfile{
+				{Input: "aaaaaaaaaax", Duration: time.Millisecond, AllocsBytes: 64},
+				{Input: "aaaaaaaaaaaaaaaaaaaax", Duration: 5 * time.Second, TimedOut: true},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := NewFormatterWithWriter("text", true, &buf).FormatProfileResult(result); err != nil {
+		t.Fatalf("FormatProfileResult() error = %v", err)
+	}
+	for _, want := range []string{"SIZE", "10    11      1ms      64 B", "timeout"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("text output = %q, want it to contain %q", buf.String(), want)
+		}
+	}
+
+	buf.Reset()
+	if err := NewFormatterWithWriter("json", true, &buf).FormatProfileResult(result); err != nil {
+		t.Fatalf("FormatProfileResult() error = %v", err)
+	}
+	var got struct {
+		Pattern string `json:"pattern"`
+		Inputs  []struct {
+			Size     int  `json:"size"`
+			TimedOut bool `json:"timed_out"`
+		} `json:"inputs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, buf.String())
+	}
+	if got.Pattern != "(a+)+$" || len(got.Inputs) != 2 || got.Inputs[1].Size != 20 || !got.Inputs[1].TimedOut {
+		t.Errorf("output = %+v, want both inputs with the second timed out", got)
+	}
+}
@@ -0,0 +1,211 @@
+package detector
+
+import (
+	"regexp/syntax"
+
+	"github.com/theakshaypant/regret/internal/parser"
+)
+
+// SubpatternContext describes where a dangerous sub-pattern sits in the
+// whole pattern and how that affects its exploitability.
+type SubpatternContext int
+
+const (
+	// ContextExposed means nothing around the sub-pattern limits backtracking.
+	ContextExposed SubpatternContext = iota
+
+	// ContextNested means the sub-pattern is inside another repeating
+	// quantifier, which multiplies its ambiguity.
+	ContextNested
+
+	// ContextGuarded means the rest of the pattern always matches, so a
+	// match never fails after the sub-pattern and never backtracks into it.
+	// A suffix that merely cannot match the characters of the sub-pattern,
+	// like the b of (a+)+b, does not guard it: that is what makes inputs
+	// like aaaaac fail only after every way of matching the a's was tried.
+	ContextGuarded
+)
+
+func (c SubpatternContext) String() string {
+	switch c {
+	case ContextNested:
+		return "nested"
+	case ContextGuarded:
+		return "guarded"
+	default:
+		return "exposed"
+	}
+}
+
+// ContextDetector analyzes dangerous sub-patterns of a regex in the context
+// of the whole pattern. The same loop can be safe or not depending on its
+// surroundings: in (a|a)*.* the trailing .* always matches, so a
+// backtracking engine never has to try the other ways of matching the loop,
+// while in ((a|a)*)+ the outer quantifier multiplies them.
+type ContextDetector struct {
+	root    *syntax.Regexp
+	parents map[*syntax.Regexp]*syntax.Regexp
+}
+
+// NewContextDetector creates a context detector for the top-level regex re.
+func NewContextDetector(re *syntax.Regexp) *ContextDetector {
+	c := &ContextDetector{root: re, parents: make(map[*syntax.Regexp]*syntax.Regexp)}
+
+	var visit func(node *syntax.Regexp)
+	visit = func(node *syntax.Regexp) {
+		for _, sub := range node.Sub {
+			c.parents[sub] = node
+			visit(sub)
+		}
+	}
+	visit(re)
+
+	return c
+}
+
+// Dangerous returns the sub-patterns that can backtrack catastrophically:
+// repeating quantifiers whose body contains a quantifier, like (a+)+, and
+// repeated alternations whose branches overlap, like (a|ab)*.
+func (c *ContextDetector) Dangerous() []*syntax.Regexp {
+	var nodes []*syntax.Regexp
+	parser.Walk(c.root, func(node *syntax.Regexp) (bool, error) {
+		if !repeats(node) {
+			return true, nil
+		}
+		body := node.Sub[0]
+		if parser.HasQuantifier(body) {
+			nodes = append(nodes, node)
+			return true, nil
+		}
+		for body.Op == syntax.OpCapture {
+			body = body.Sub[0]
+		}
+		if body.Op == syntax.OpAlternate && hasOverlappingBranches(body) {
+			nodes = append(nodes, node)
+		}
+		return true, nil
+	})
+	return nodes
+}
+
+// Context returns the context of node, which must be part of the regex the
+// detector was created for.
+func (c *ContextDetector) Context(node *syntax.Regexp) SubpatternContext {
+	for p := c.parents[node]; p != nil; p = c.parents[p] {
+		if repeats(p) {
+			return ContextNested
+		}
+	}
+
+	// Collect what follows node, from the innermost concatenation out
+	var rest []*syntax.Regexp
+	for child, p := node, c.parents[node]; p != nil; child, p = p, c.parents[p] {
+		if p.Op != syntax.OpConcat {
+			continue
+		}
+		for i, sub := range p.Sub {
+			if sub == child {
+				rest = append(rest, p.Sub[i+1:]...)
+				break
+			}
+		}
+	}
+	if len(rest) == 0 {
+		return ContextExposed
+	}
+
+	if alwaysMatches(rest) {
+		return ContextGuarded
+	}
+	return ContextExposed
+}
+
+// Adjust downgrades critical issues from Critical to High when the context
+// of the sub-pattern they report makes exploitation harder, and records the
+// context in Details["context"]. Issues spanning the whole pattern are
+// downgraded when every dangerous sub-pattern is guarded.
+func (c *ContextDetector) Adjust(issues []Issue, pattern string) []Issue {
+	dangerous := c.Dangerous()
+	if len(dangerous) == 0 {
+		return issues
+	}
+
+	contexts := make(map[Position]SubpatternContext)
+	allGuarded := true
+	for _, node := range dangerous {
+		start, end := parser.PositionOf(node, pattern)
+		ctx := c.Context(node)
+		contexts[Position{Start: start, End: end}] = ctx
+		if ctx != ContextGuarded {
+			allGuarded = false
+		}
+	}
+
+	for i := range issues {
+		issue := &issues[i]
+		if issue.Severity != "critical" {
+			continue
+		}
+		key := Position{Start: issue.Position.Start, End: issue.Position.End}
+		ctx, ok := contexts[key]
+		if !ok && key.Start == 0 && key.End == len(pattern) && allGuarded {
+			ctx, ok = ContextGuarded, true
+		}
+		if !ok {
+			continue
+		}
+		if issue.Details == nil {
+			issue.Details = make(map[string]interface{})
+		}
+		issue.Details["context"] = ctx.String()
+		if ctx == ContextGuarded {
+			issue.Severity = "high"
+		}
+	}
+
+	return issues
+}
+
+// repeats reports whether re is a quantifier that can repeat its body.
+func repeats(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpStar, syntax.OpPlus:
+		return true
+	case syntax.OpRepeat:
+		return re.Max == -1 || re.Max > 1
+	}
+	return false
+}
+
+// hasOverlappingBranches reports whether two branches of alt can match the
+// same prefix.
+func hasOverlappingBranches(alt *syntax.Regexp) bool {
+	for i := 0; i < len(alt.Sub); i++ {
+		for j := i + 1; j < len(alt.Sub); j++ {
+			if branchesOverlap(alt.Sub[i], alt.Sub[j]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// alwaysMatches reports whether the sequence nodes matches at any position
+// of any input, so that a match never fails after reaching it.
+func alwaysMatches(nodes []*syntax.Regexp) bool {
+	for _, node := range nodes {
+		ok := true
+		parser.Walk(node, func(n *syntax.Regexp) (bool, error) {
+			switch n.Op {
+			case syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText,
+				syntax.OpWordBoundary, syntax.OpNoWordBoundary, syntax.OpNoMatch:
+				ok = false
+			}
+			return ok, nil
+		})
+		if !ok || !matchesEmpty(node) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,87 @@
+package detector
+
+import (
+	"regexp/syntax"
+	"testing"
+
+	"github.com/theakshaypant/regret/internal/parser"
+)
+
+func TestContextDetector_Context(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    []SubpatternContext // for each dangerous sub-pattern, outermost first
+	}{
+		{"(a+)+", []SubpatternContext{ContextExposed}},
+		{"^(a+)+$", []SubpatternContext{ContextExposed}},
+		{"(a+)+.*", []SubpatternContext{ContextGuarded}},
+		{"(a+)+b", []SubpatternContext{ContextExposed}},
+		{`(\d+)+x`, []SubpatternContext{ContextExposed}},
+		{"(a+)+a", []SubpatternContext{ContextExposed}},
+		{`(\w+)+\b`, []SubpatternContext{ContextExposed}},
+		{`(.+)+\b`, []SubpatternContext{ContextExposed}},
+		{"x((a+)+)*a", []SubpatternContext{ContextExposed, ContextNested}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re, err := parser.NewParser().Parse(tt.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := NewContextDetector(re)
+			dangerous := c.Dangerous()
+			if len(dangerous) != len(tt.want) {
+				t.Fatalf("Dangerous() = %v, want %d sub-patterns", dangerous, len(tt.want))
+			}
+			for i, node := range dangerous {
+				if got := c.Context(node); got != tt.want[i] {
+					t.Errorf("Context(%s) = %v, want %v", node, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestContextDetector_Adjust(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"(a+)+", "critical"},
+		{"(a+)+.*", "high"},
+		{"(a+)+b", "critical"},
+		{"(a+)+a", "critical"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re, err := syntax.Parse(tt.pattern, syntax.Perl)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := NewDetector(&Options{Mode: Fast, Checks: CheckNestedQuantifiers | CheckContextAwareness})
+			issues, err := d.Detect(re, tt.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			found := false
+			for _, issue := range issues {
+				if issue.Type != "nested_quantifiers" {
+					continue
+				}
+				found = true
+				if issue.Severity != tt.want {
+					t.Errorf("severity = %s, want %s", issue.Severity, tt.want)
+				}
+				if issue.Details["context"] == nil {
+					t.Error("Details[context] not set")
+				}
+			}
+			if !found {
+				t.Fatal("no nested_quantifiers issue")
+			}
+		})
+	}
+}
@@ -2,9 +2,12 @@
 package detector
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"regexp/syntax"
 	"strings"
+	"time"
 
 	"github.com/theakshaypant/regret/internal/parser"
 )
@@ -18,10 +21,37 @@ const (
 	Thorough
 )
 
+// Check flags for Options.Checks. The values match regret.CheckFlags.
+const (
+	CheckNestedQuantifiers uint32 = 1 << iota
+	CheckOverlappingAlternation
+	CheckCatastrophicBacktrack
+	CheckUnboundedRepetition
+	CheckExponentialPaths
+	CheckComplexityScore
+	CheckMemoryUsage
+	CheckNFAAmbiguity
+	CheckPolynomialDegree
+	CheckContextAwareness
+	CheckCustomPlugins // Run by the caller, not the detector
+	CheckUnicodeAmbiguity
+)
+
 // Options contains configuration for detection.
 type Options struct {
-	Mode   ValidationMode
-	Checks uint32
+	Mode                   ValidationMode
+	Checks                 uint32        // 0 runs all checks
+	MaxQuantifierRange     int           // Smallest range flagged; 0 disables the quantifier range check
+	MaxRepetitionCount     int           // Smallest count flagged; 0 disables the repetition count check
+	MaxAlternationBranches int           // 0 disables the alternation size check
+	MaxNFAStates           int           // 0 means no limit
+	MaxEpsilonPaths        int           // Epsilon paths allowed into an NFA state before it is ambiguous, 0 means 1
+	MaxDFAStates           int           // 0 disables the DFA size check
+	Timeout                time.Duration // Limit for NFA analysis, 0 means no limit
+	Backreferences         bool          // The dialect supports backreferences like \1
+	ParseFlags             syntax.Flags  // Flags for re-parsing patterns, 0 means syntax.Perl
+
+	EnableExperimentalChecks bool // Run runExperimentalChecks after the mode's checks
 }
 
 // Issue represents a detected problem.
@@ -34,6 +64,7 @@ type Issue struct {
 	Example    string
 	Suggestion string
 	Complexity int
+	Details    map[string]interface{}
 }
 
 // Position represents a location in the pattern.
@@ -49,42 +80,161 @@ type Detector struct {
 	opts        *Options
 	parser      *parser.Parser
 	nfaAnalyzer *NFAAnalyzer
+	trace       *Trace // Records the decisions of the current DetectWithTrace call
+	check       string // Name of the running check, for the trace
 }
 
 // NewDetector creates a new detector with the given options.
 func NewDetector(opts *Options) *Detector {
+	nfaAnalyzer := NewNFAAnalyzer()
+	nfaAnalyzer.maxStates = opts.MaxNFAStates
+	nfaAnalyzer.maxPaths = opts.MaxEpsilonPaths
+
+	p := parser.NewParser()
+	if opts.ParseFlags != 0 {
+		p = parser.NewParserWithFlags(opts.ParseFlags)
+	}
+
 	return &Detector{
 		opts:        opts,
-		parser:      parser.NewParser(),
-		nfaAnalyzer: NewNFAAnalyzer(),
+		parser:      p,
+		nfaAnalyzer: nfaAnalyzer,
 	}
 }
 
 // Detect analyzes a parsed regex and returns detected issues.
 func (d *Detector) Detect(re *syntax.Regexp, pattern string) ([]Issue, error) {
+	return d.DetectContext(context.Background(), re, pattern)
+}
+
+// DetectContext is like Detect but stops when ctx is done. Checks look at
+// ctx after every AST node they visit. On cancellation it returns the issues
+// found so far together with ctx.Err().
+func (d *Detector) DetectContext(ctx context.Context, re *syntax.Regexp, pattern string) ([]Issue, error) {
 	var issues []Issue
+	err := d.run(ctx, re, pattern, func(found []Issue) {
+		issues = append(issues, found...)
+	})
+	return issues, err
+}
+
+// DetectAsync is like Detect but streams issues on the returned channel as
+// they are found, so that callers such as editor plugins can show them
+// before the later phases finish. The issues of the fast checks are sent
+// as soon as those checks complete, and NFA analysis sends each issue as it
+// finds it. Both channels are closed when every phase has run; the error
+// channel receives at most one error first. The issues are the same as
+// those Detect returns, in the same order.
+//
+// The caller must receive from the issue channel until it is closed, and
+// must not use the detector for anything else until then.
+func (d *Detector) DetectAsync(re *syntax.Regexp, pattern string) (<-chan Issue, <-chan error) {
+	issues := make(chan Issue)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(issues)
+		err := d.run(context.Background(), re, pattern, func(found []Issue) {
+			for _, issue := range found {
+				issues <- issue
+			}
+		})
+		if err != nil {
+			errs <- err
+		}
+	}()
+
+	return issues, errs
+}
+
+// run runs the check phases for the mode and passes the issues of each
+// phase to emit, after applying CheckContextAwareness. NFA analysis emits
+// its issues one at a time, as it finds them. On cancellation it returns
+// ctx.Err().
+func (d *Detector) run(ctx context.Context, re *syntax.Regexp, pattern string, emit func([]Issue)) error {
+	var contexts *ContextDetector
+	if d.enabled(CheckContextAwareness) {
+		contexts = NewContextDetector(re)
+	}
+
+	adjusted := func(found []Issue) {
+		if len(found) == 0 {
+			return
+		}
+		if contexts != nil {
+			found = contexts.Adjust(found, pattern)
+		}
+		d.recordIssues(found)
+		emit(found)
+	}
+
+	type phase struct {
+		name string
+		run  func(context.Context, *syntax.Regexp, string) []Issue
+	}
+	fast := phase{"fast", d.runFastChecks}
+	balanced := phase{"balanced", func(ctx context.Context, re *syntax.Regexp, pattern string) []Issue {
+		return d.runBalancedChecks(ctx, re, pattern, adjusted)
+	}}
+	thorough := phase{"thorough", d.runThoroughChecks}
+	var phases []phase
 
 	// Run checks based on mode and flags
 	switch d.opts.Mode {
 	case Fast:
-		issues = append(issues, d.runFastChecks(re, pattern)...)
+		phases = append(phases, fast)
 	case Balanced:
-		issues = append(issues, d.runFastChecks(re, pattern)...)
-		issues = append(issues, d.runBalancedChecks(re, pattern)...)
+		phases = append(phases, fast, balanced)
 	case Thorough:
-		issues = append(issues, d.runFastChecks(re, pattern)...)
-		issues = append(issues, d.runBalancedChecks(re, pattern)...)
-		issues = append(issues, d.runThoroughChecks(re, pattern)...)
+		phases = append(phases, fast, balanced, thorough)
+	}
+	// Subset construction can be slow, so it runs after the NFA analysis
+	// rather than taking its share of the timeout
+	if d.enabled(CheckMemoryUsage) {
+		phases = append(phases, phase{"memory", d.runMemoryChecks})
+	}
+	if d.opts.EnableExperimentalChecks {
+		phases = append(phases, phase{"experimental", d.runExperimentalChecks})
 	}
 
-	return issues, nil
+	for _, phase := range phases {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		d.check = ""
+		d.record("phase", "", "running %s checks", phase.name)
+		found := phase.run(ctx, re, pattern)
+		d.check = ""
+		adjusted(found)
+	}
+
+	return ctx.Err()
+}
+
+// walk is parser.Walk but stops visiting nodes once ctx is done.
+func (d *Detector) walk(ctx context.Context, re *syntax.Regexp, visitor func(*syntax.Regexp) bool) {
+	_ = parser.Walk(re, func(node *syntax.Regexp) (bool, error) {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		if d.trace != nil {
+			d.record("visit", node.String(), "%s node", node.Op)
+		}
+		return visitor(node), nil
+	})
+}
+
+// enabled reports whether the check flag is set in the options.
+func (d *Detector) enabled(flag uint32) bool {
+	return d.opts.Checks == 0 || d.opts.Checks&flag != 0
 }
 
-func (d *Detector) runFastChecks(re *syntax.Regexp, pattern string) []Issue {
+func (d *Detector) runFastChecks(ctx context.Context, re *syntax.Regexp, pattern string) []Issue {
 	var issues []Issue
 
 	// 1. Pattern length validation
-	if len(pattern) > 10000 {
+	if d.runs("pattern_length", 0) && d.exceeds("pattern length", len(pattern), 10000) {
 		issues = append(issues, Issue{
 			Type:       "pattern_too_long",
 			Severity:   "high",
@@ -92,12 +242,13 @@ func (d *Detector) runFastChecks(re *syntax.Regexp, pattern string) []Issue {
 			Pattern:    pattern,
 			Message:    fmt.Sprintf("Pattern exceeds maximum length (10000 characters): %d characters", len(pattern)),
 			Suggestion: "Consider breaking the pattern into multiple smaller patterns",
+			Details:    map[string]interface{}{"length": len(pattern), "threshold": 10000},
 		})
 	}
 
 	// 2. Nesting depth check
 	nestingDepth := parser.GetNestingDepth(re)
-	if nestingDepth > 5 {
+	if d.runs("nesting_depth", CheckNestedQuantifiers) && d.exceeds("nesting depth", nestingDepth, 5) {
 		issues = append(issues, Issue{
 			Type:       "excessive_nesting",
 			Severity:   "high",
@@ -107,12 +258,13 @@ func (d *Detector) runFastChecks(re *syntax.Regexp, pattern string) []Issue {
 			Example:    "aaa",
 			Suggestion: "Reduce nesting depth by simplifying quantifiers",
 			Complexity: nestingDepth * 15, // Rough complexity estimate
+			Details:    map[string]interface{}{"nesting_depth": nestingDepth, "threshold": 5},
 		})
 	}
 
 	// 3. Quantifier count check
 	quantifierCount := parser.CountQuantifiers(re)
-	if quantifierCount > 20 {
+	if d.runs("quantifier_count", 0) && d.exceeds("quantifier count", quantifierCount, 20) {
 		issues = append(issues, Issue{
 			Type:       "too_many_quantifiers",
 			Severity:   "medium",
@@ -121,45 +273,408 @@ func (d *Detector) runFastChecks(re *syntax.Regexp, pattern string) []Issue {
 			Message:    fmt.Sprintf("Excessive quantifiers: %d (threshold: 20)", quantifierCount),
 			Suggestion: "Simplify the pattern to reduce quantifier count",
 			Complexity: quantifierCount * 3,
+			Details:    map[string]interface{}{"quantifiers": quantifierCount, "threshold": 20},
 		})
 	}
 
-	// 4. Nested quantifier detection (most dangerous)
-	nestedIssues := d.detectNestedQuantifiers(re, pattern)
-	issues = append(issues, nestedIssues...)
+	// 4. Quantifier range check
+	if d.runs("quantifier_range", 0) {
+		rangeIssues := d.detectLargeQuantifierRanges(ctx, pattern)
+		issues = append(issues, rangeIssues...)
+	}
+
+	// 5. Alternation size check
+	if d.runs("alternation_branches", 0) {
+		branchIssues := d.detectLargeAlternations(ctx, pattern)
+		issues = append(issues, branchIssues...)
+	}
+
+	// 6. Repetition counts
+	if d.runs("repetition_count", 0) {
+		countIssues := d.detectLargeRepetitionCounts(ctx, pattern)
+		issues = append(issues, countIssues...)
+	}
+
+	// 7. Nested quantifier detection (most dangerous)
+	if d.runs("nested_quantifiers", CheckNestedQuantifiers) {
+		nestedIssues := d.detectNestedQuantifiers(ctx, re, pattern)
+		issues = append(issues, nestedIssues...)
+	}
+
+	// 8. Overlapping alternation detection
+	if d.runs("overlapping_alternation", CheckOverlappingAlternation) {
+		alternationIssues := d.detectOverlappingAlternations(ctx, re, pattern)
+		issues = append(issues, alternationIssues...)
+	}
+
+	// 9. Dangerous pattern combinations
+	if d.runs("dangerous_patterns", CheckCatastrophicBacktrack) {
+		dangerousIssues := d.detectDangerousPatterns(ctx, re, pattern)
+		issues = append(issues, dangerousIssues...)
+	}
+
+	// 10. Unanchored unbounded repetition
+	if d.runs("unbounded_repetition", CheckUnboundedRepetition) {
+		unboundedIssues := d.detectUnboundedRepetition(re, pattern)
+		issues = append(issues, unboundedIssues...)
+	}
+
+	// 11. Backreferences to groups of varying length
+	if d.opts.Backreferences && d.runs("backreference_ambiguity", CheckCatastrophicBacktrack) {
+		backrefIssues := d.detectBackreferenceAmbiguity(pattern)
+		issues = append(issues, backrefIssues...)
+	}
+
+	// 12. Characters that differ between NFC and NFD input
+	if d.runs("unicode_ambiguity", CheckUnicodeAmbiguity) {
+		issues = append(issues, d.detectUnicodeAmbiguity(ctx, re, pattern)...)
+	}
+
+	return issues
+}
+
+// runBalancedChecks runs the NFA-based checks. NFA analysis passes each
+// issue to emit as soon as it finds it, and the rest are returned.
+func (d *Detector) runBalancedChecks(ctx context.Context, re *syntax.Regexp, pattern string, emit func([]Issue)) []Issue {
+	var issues []Issue
 
-	// 5. Overlapping alternation detection
-	alternationIssues := d.detectOverlappingAlternations(re, pattern)
-	issues = append(issues, alternationIssues...)
+	// 1. Polynomial degree of adjacent overlapping quantifiers, found
+	// first so that it can be merged into the NFA issues that cover it
+	var runs []Issue
+	if d.runs("polynomial_degree", CheckPolynomialDegree) {
+		runs = d.detectPolynomialDegree(ctx, re, pattern)
+	}
+	merged := make([]bool, len(runs))
+	merge := func(issue Issue) Issue {
+		return mergePolynomialDegree(issue, runs, merged)
+	}
+
+	// 2. NFA-based EDA/IDA detection
+	if d.runs("nfa_ambiguity", CheckNFAAmbiguity) {
+		issues = append(issues, d.detectNFAAmbiguity(ctx, re, pattern, emit, merge)...)
+	}
 
-	// 6. Dangerous pattern combinations
-	dangerousIssues := d.detectDangerousPatterns(re, pattern)
-	issues = append(issues, dangerousIssues...)
+	// Runs no NFA issue covers are reported on their own
+	for i, run := range runs {
+		if !merged[i] {
+			issues = append(issues, run)
+		}
+	}
 
 	return issues
 }
 
-func (d *Detector) runBalancedChecks(re *syntax.Regexp, pattern string) []Issue {
-	// Run NFA-based EDA/IDA detection
-	issues, err := d.nfaAnalyzer.AnalyzePattern(re, pattern)
+// mergePolynomialDegree merges the runs of adjacent overlapping
+// quantifiers that lie within a polynomial_backtracking issue into it,
+// rather than reporting the same ambiguity twice: Details["degree"] becomes
+// the larger of the two degrees, and Details["adjacent_quantifiers"] the
+// length of the longest run. It marks the runs it merges.
+func mergePolynomialDegree(issue Issue, runs []Issue, merged []bool) Issue {
+	if issue.Type != "polynomial_backtracking" {
+		return issue
+	}
+	for i, run := range runs {
+		if run.Position.Start < issue.Position.Start || run.Position.End > issue.Position.End {
+			continue
+		}
+		details := make(map[string]interface{}, len(issue.Details)+2)
+		for k, v := range issue.Details {
+			details[k] = v
+		}
+		degree := run.Details["degree"].(int)
+		if existing, ok := details["degree"].(int); !ok || existing < degree {
+			details["degree"] = degree
+		}
+		if longest, ok := details["adjacent_quantifiers"].(int); !ok || longest < degree {
+			details["adjacent_quantifiers"] = degree
+		}
+		issue.Details = details
+		merged[i] = true
+	}
+	return issue
+}
+
+// detectNFAAmbiguity runs the NFA analyzer, within Options.Timeout, and
+// passes each issue it finds through merge and then to emit.
+func (d *Detector) detectNFAAmbiguity(ctx context.Context, re *syntax.Regexp, pattern string, emit func([]Issue), merge func(Issue) Issue) []Issue {
+	if d.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.opts.Timeout)
+		defer cancel()
+	}
+
+	// Run NFA-based EDA/IDA detection, emitting issues as they are found
+	streamed := 0
+	issues, err := d.nfaAnalyzer.analyze(ctx, re, pattern, func(issue Issue) {
+		streamed++
+		emit([]Issue{merge(issue)})
+	})
+	if errors.Is(err, parser.ErrNFATooLarge) {
+		d.record("threshold", "", "NFA exceeds %d states: exceeded", d.opts.MaxNFAStates)
+		return []Issue{{
+			Type:       "complexity_threshold_exceeded",
+			Severity:   "medium",
+			Position:   Position{Start: 0, End: len(pattern)},
+			Pattern:    pattern,
+			Message:    "NFA too large for analysis",
+			Suggestion: "Reduce repetition counts and alternation branches, or split the pattern",
+			Complexity: 50,
+			Details:    map[string]interface{}{"max_nfa_states": d.opts.MaxNFAStates},
+		}}
+	}
 	if err != nil {
 		// If NFA analysis fails, return empty (fall back to fast checks)
 		return []Issue{}
 	}
 
+	// Issues already emitted come first
+	issues = issues[streamed:]
+	for i := range issues {
+		issues[i] = merge(issues[i])
+	}
 	return issues
 }
 
-func (d *Detector) runThoroughChecks(re *syntax.Regexp, pattern string) []Issue {
+func (d *Detector) runThoroughChecks(ctx context.Context, re *syntax.Regexp, pattern string) []Issue {
 	// TODO: Implement adversarial testing (Phase 3)
-	return []Issue{}
+	if !d.runs("repeated_capture_groups", 0) {
+		return nil
+	}
+	return d.detectRepeatedCaptureGroups(ctx, pattern)
+}
+
+// detectRepeatedCaptureGroups finds capturing groups under a repeating
+// quantifier like (ab)+, which keep only their last iteration and add
+// bookkeeping to every step of a backtracking match. Simplify expands
+// counted repetitions, so this walks the unsimplified AST.
+func (d *Detector) detectRepeatedCaptureGroups(ctx context.Context, pattern string) []Issue {
+	raw, masked, err := d.parseRaw(pattern)
+	if err != nil {
+		return nil
+	}
+
+	var positions []parser.Position
+	var issues []Issue
+	d.walk(ctx, raw, func(node *syntax.Regexp) bool {
+		// Groups matched at most once, like (a){0,1} or (a){0}, keep every
+		// capture. Max is only set for OpRepeat; it is 0 for * and +.
+		matchedOnce := node.Op == syntax.OpRepeat && node.Max >= 0 && node.Max <= 1
+		if !parser.IsQuantifier(node) || node.Op == syntax.OpQuest || matchedOnce ||
+			node.Sub[0].Op != syntax.OpCapture {
+			return true
+		}
+
+		if positions == nil {
+			positions = parser.FindCaptureGroupPositions(raw, masked)
+		}
+		group := node.Sub[0]
+		pos := positions[group.Cap-1]
+		issues = append(issues, Issue{
+			Type:       "repeated_capture_group",
+			Severity:   "info",
+			Position:   Position{Start: pos.Start, End: pos.End},
+			Pattern:    pattern[pos.Start:pos.End],
+			Message:    fmt.Sprintf("Repeated capture group %s only captures its last iteration", pattern[pos.Start:pos.End]),
+			Suggestion: "Use a non-capturing group (?:...) unless the capture is needed",
+			Complexity: 5,
+			Details:    map[string]interface{}{"group": group.Cap},
+		})
+
+		return true
+	})
+
+	return issues
+}
+
+// parseRaw parses pattern like Parser.ParseRaw. If the dialect supports
+// backreferences they are masked first, as parser.MaskBackreferences
+// describes; the masked pattern is returned for locating nodes, and its
+// byte offsets are offsets in pattern.
+func (d *Detector) parseRaw(pattern string) (*syntax.Regexp, string, error) {
+	if d.opts.Backreferences {
+		pattern = parser.MaskBackreferences(pattern)
+	}
+	raw, err := d.parser.ParseRaw(pattern)
+	return raw, pattern, err
+}
+
+// detectLargeQuantifierRanges finds bounded repetitions like a{0,1000}
+// whose range reaches MaxQuantifierRange. Simplify expands repetitions,
+// so this walks the unsimplified AST.
+func (d *Detector) detectLargeQuantifierRanges(ctx context.Context, pattern string) []Issue {
+	limit := d.opts.MaxQuantifierRange
+	if limit <= 0 {
+		return nil
+	}
+
+	raw, masked, err := d.parseRaw(pattern)
+	if err != nil {
+		return nil
+	}
+
+	var issues []Issue
+	d.walk(ctx, raw, func(node *syntax.Regexp) bool {
+		if node.Op != syntax.OpRepeat || node.Max < 0 {
+			return true
+		}
+
+		if spread := node.Max - node.Min; d.reaches("quantifier range", spread, limit) {
+			start, end := parser.PositionOf(node, masked)
+			issues = append(issues, Issue{
+				Type:       "large_quantifier_range",
+				Severity:   "medium",
+				Position:   Position{Start: start, End: end},
+				Pattern:    node.String(),
+				Message:    fmt.Sprintf("Quantifier range too large: %s spans %d repetitions (threshold: %d)", node.String(), spread, limit),
+				Suggestion: "Lower the upper bound or validate input length before matching",
+				Complexity: 40,
+				Details:    map[string]interface{}{"range": spread, "threshold": limit},
+			})
+		}
+
+		return true
+	})
+
+	return issues
+}
+
+// detectLargeRepetitionCounts finds repetitions like a{0,1000} whose
+// minimum or maximum count reaches MaxRepetitionCount. Such counts behave
+// like an unbounded quantifier, so they are reported as unbounded
+// repetition. Like detectLargeQuantifierRanges, this walks the
+// unsimplified AST.
+func (d *Detector) detectLargeRepetitionCounts(ctx context.Context, pattern string) []Issue {
+	limit := d.opts.MaxRepetitionCount
+	if limit <= 0 {
+		return nil
+	}
+
+	raw, masked, err := d.parseRaw(pattern)
+	if err != nil {
+		return nil
+	}
+
+	var issues []Issue
+	d.walk(ctx, raw, func(node *syntax.Regexp) bool {
+		if node.Op != syntax.OpRepeat {
+			return true
+		}
+
+		count := node.Min
+		if node.Max > count {
+			count = node.Max
+		}
+		if d.reaches("repetition count", count, limit) {
+			start, end := parser.PositionOf(node, masked)
+			message := fmt.Sprintf("Repetition count too large: %s repeats up to %d times (threshold: %d)", node.String(), count, limit)
+			suggestion := "Use * or + if unbounded repetition is intended, or lower the count"
+			if node.Min == node.Max {
+				// An exact count is not a stand-in for * or +
+				message = fmt.Sprintf("Repetition count too large: %s repeats %d times (threshold: %d)", node.String(), count, limit)
+				suggestion = "Lower the count, or match the text with a shorter pattern and check its length separately"
+			}
+			issues = append(issues, Issue{
+				Type:       "unbounded_repetition",
+				Severity:   "medium",
+				Position:   Position{Start: start, End: end},
+				Pattern:    node.String(),
+				Message:    message,
+				Suggestion: suggestion,
+				Complexity: 30,
+				Details:    map[string]interface{}{"count": count, "threshold": limit},
+			})
+		}
+
+		return true
+	})
+
+	return issues
+}
+
+// detectLargeAlternations finds alternations with more branches than
+// MaxAlternationBranches. Branches are counted in the pattern text, since
+// the parser merges single-character branches such as (a|b|...|z) into a
+// class and factors out common prefixes.
+func (d *Detector) detectLargeAlternations(ctx context.Context, pattern string) []Issue {
+	limit := d.opts.MaxAlternationBranches
+	if limit <= 0 {
+		return nil
+	}
+	if _, _, err := d.parseRaw(pattern); err != nil {
+		return nil
+	}
+
+	var issues []Issue
+	for _, alt := range parser.Alternations(pattern) {
+		if ctx.Err() != nil {
+			break
+		}
+		if !d.exceeds("alternation branches", alt.Branches, limit) {
+			continue
+		}
+
+		text := pattern[alt.Start:alt.End]
+		suggestion := "Split the pattern, or look the value up in a set after matching a simpler pattern"
+		if d.literalAlternation(text) {
+			suggestion = "Use a character class for single-character branches, or match the shared shape (e.g. [a-z]+) and check the value against a set"
+		}
+
+		issues = append(issues, Issue{
+			Type:       "ambiguous_pattern",
+			Severity:   "medium",
+			Position:   Position{Start: alt.Start, End: alt.End},
+			Pattern:    text,
+			Message:    fmt.Sprintf("Alternation has too many branches: %d (threshold: %d)", alt.Branches, limit),
+			Suggestion: suggestion,
+			Complexity: 30,
+			Details:    map[string]interface{}{"branches": alt.Branches, "threshold": limit},
+		})
+	}
+
+	return issues
+}
+
+// literalAlternation reports whether every branch of the alternation text
+// is a plain string or character class.
+func (d *Detector) literalAlternation(text string) bool {
+	re, err := d.parser.ParseRaw(text)
+	if err != nil {
+		return false
+	}
+	switch re.Op {
+	case syntax.OpLiteral, syntax.OpCharClass:
+		return true
+	case syntax.OpAlternate:
+		return literalBranches(re)
+	}
+	return false
+}
+
+// literalBranches reports whether every branch of an alternation is a
+// plain string or character class.
+func literalBranches(alt *syntax.Regexp) bool {
+	for _, branch := range alt.Sub {
+		switch branch.Op {
+		case syntax.OpLiteral, syntax.OpCharClass:
+			continue
+		case syntax.OpConcat:
+			for _, sub := range branch.Sub {
+				if sub.Op != syntax.OpLiteral && sub.Op != syntax.OpCharClass {
+					return false
+				}
+			}
+		default:
+			return false
+		}
+	}
+	return true
 }
 
 // detectNestedQuantifiers finds patterns like (a+)+, (a*)*, (a?)+
-func (d *Detector) detectNestedQuantifiers(re *syntax.Regexp, pattern string) []Issue {
+func (d *Detector) detectNestedQuantifiers(ctx context.Context, re *syntax.Regexp, pattern string) []Issue {
 	var issues []Issue
 
-	parser.Walk(re, func(node *syntax.Regexp) bool {
+	d.walk(ctx, re, func(node *syntax.Regexp) bool {
 		if !parser.IsQuantifier(node) {
 			return true
 		}
@@ -168,15 +683,17 @@ func (d *Detector) detectNestedQuantifiers(re *syntax.Regexp, pattern string) []
 		if len(node.Sub) > 0 {
 			for _, sub := range node.Sub {
 				if parser.HasQuantifier(sub) {
+					start, end := parser.PositionOf(node, pattern)
 					issues = append(issues, Issue{
 						Type:       "nested_quantifiers",
 						Severity:   "critical",
-						Position:   Position{Start: 0, End: len(pattern)},
+						Position:   Position{Start: start, End: end},
 						Pattern:    node.String(),
 						Message:    fmt.Sprintf("Nested quantifiers detected: %s", node.String()),
 						Example:    generateNestedQuantifierExample(node),
 						Suggestion: "Remove nesting: simplify to a single quantifier",
 						Complexity: 90, // Very high complexity
+						Details:    map[string]interface{}{"nesting_depth": parser.GetNestingDepth(node)},
 					})
 				}
 			}
@@ -189,10 +706,10 @@ func (d *Detector) detectNestedQuantifiers(re *syntax.Regexp, pattern string) []
 }
 
 // detectOverlappingAlternations finds patterns like (a|ab)+, (a|a)*
-func (d *Detector) detectOverlappingAlternations(re *syntax.Regexp, pattern string) []Issue {
+func (d *Detector) detectOverlappingAlternations(ctx context.Context, re *syntax.Regexp, pattern string) []Issue {
 	var issues []Issue
 
-	parser.Walk(re, func(node *syntax.Regexp) bool {
+	d.walk(ctx, re, func(node *syntax.Regexp) bool {
 		if !parser.IsAlternation(node) {
 			return true
 		}
@@ -202,15 +719,19 @@ func (d *Detector) detectOverlappingAlternations(re *syntax.Regexp, pattern stri
 			for i := 0; i < len(node.Sub); i++ {
 				for j := i + 1; j < len(node.Sub); j++ {
 					if branchesOverlap(node.Sub[i], node.Sub[j]) {
+						start, end := parser.PositionOf(node, pattern)
 						issues = append(issues, Issue{
 							Type:       "overlapping_alternation",
 							Severity:   "high",
-							Position:   Position{Start: 0, End: len(pattern)},
+							Position:   Position{Start: start, End: end},
 							Pattern:    node.String(),
 							Message:    fmt.Sprintf("Overlapping alternation branches: %s", node.String()),
 							Example:    "ababababx",
 							Suggestion: "Reorder branches or use atomic grouping",
 							Complexity: 70,
+							Details: map[string]interface{}{
+								"overlapping_branches": []string{node.Sub[i].String(), node.Sub[j].String()},
+							},
 						})
 						break
 					}
@@ -225,9 +746,26 @@ func (d *Detector) detectOverlappingAlternations(re *syntax.Regexp, pattern stri
 }
 
 // detectDangerousPatterns finds other dangerous combinations
-func (d *Detector) detectDangerousPatterns(re *syntax.Regexp, pattern string) []Issue {
+func (d *Detector) detectDangerousPatterns(ctx context.Context, re *syntax.Regexp, pattern string) []Issue {
 	var issues []Issue
 
+	// Examples come from a polynomial ambiguity witness when one exists.
+	// The NFA is only built once a dangerous pattern is found.
+	var witness string
+	witnessBuilt := false
+	example := func(fallback string) string {
+		if !witnessBuilt {
+			witnessBuilt = true
+			if nfa, err := parser.BuildNFAWithLimit(re, d.opts.MaxNFAStates); err == nil {
+				witness = polynomialExample(ctx, nfa, 2, "")
+			}
+		}
+		if witness == "" {
+			return fallback
+		}
+		return witness
+	}
+
 	// Pattern 1: Multiple overlapping quantifiers like a*a*
 	if strings.Contains(pattern, "*.*") || strings.Contains(pattern, "+.+") {
 		issues = append(issues, Issue{
@@ -236,9 +774,10 @@ func (d *Detector) detectDangerousPatterns(re *syntax.Regexp, pattern string) []
 			Position:   Position{Start: 0, End: len(pattern)},
 			Pattern:    pattern,
 			Message:    "Overlapping unbounded quantifiers detected",
-			Example:    "aaaaaaaax",
+			Example:    example("aaaaaaaax"),
 			Suggestion: "Use possessive quantifiers or atomic grouping",
 			Complexity: 60,
+			Details:    map[string]interface{}{"degree": 2},
 		})
 	}
 
@@ -252,16 +791,17 @@ func (d *Detector) detectDangerousPatterns(re *syntax.Regexp, pattern string) []
 	}
 
 	for _, dp := range dangerousPatterns {
-		if strings.Contains(pattern, dp) {
+		if start := strings.Index(pattern, dp); start >= 0 {
 			issues = append(issues, Issue{
 				Type:       "polynomial_backtracking",
 				Severity:   "high",
-				Position:   Position{Start: 0, End: len(pattern)},
+				Position:   Position{Start: start, End: start + len(dp)},
 				Pattern:    dp,
 				Message:    fmt.Sprintf("Potentially dangerous pattern detected: %s", dp),
-				Example:    "aaaaaaax",
+				Example:    example("aaaaaaax"),
 				Suggestion: "Consolidate or reorder quantifiers",
 				Complexity: 65,
+				Details:    map[string]interface{}{"degree": 2},
 			})
 		}
 	}
@@ -269,6 +809,246 @@ func (d *Detector) detectDangerousPatterns(re *syntax.Regexp, pattern string) []
 	return issues
 }
 
+// detectUnboundedRepetition finds patterns like .*password.* that pad a
+// search with unbounded repetition at an unanchored end. Unanchored
+// patterns already match anywhere in the input, so a leading .* only adds
+// work: a backtracking engine retries it from every start position, which
+// is quadratic on input without a match. A trailing .* is redundant for
+// MatchString-style checks. The repetition is only flagged when the rest
+// of the pattern must match something, since otherwise the repetition is
+// what the pattern matches.
+func (d *Detector) detectUnboundedRepetition(re *syntax.Regexp, pattern string) []Issue {
+	if re.Op != syntax.OpConcat || len(re.Sub) < 2 {
+		return nil
+	}
+	n := len(re.Sub)
+
+	var issues []Issue
+	for _, edge := range []struct {
+		node  *syntax.Regexp
+		rest  []*syntax.Regexp
+		where string
+	}{
+		{re.Sub[0], re.Sub[1:], "start"},
+		{re.Sub[n-1], re.Sub[:n-1], "end"},
+	} {
+		rep, ok := unboundedCharRepetition(edge.node)
+		if !ok || matchesEmpty(&syntax.Regexp{Op: syntax.OpConcat, Sub: edge.rest}) {
+			continue
+		}
+
+		start, end := parser.PositionOf(edge.node, pattern)
+		issue := Issue{
+			Type:       "unbounded_repetition",
+			Severity:   "low",
+			Position:   Position{Start: start, End: end},
+			Pattern:    edge.node.String(),
+			Message:    fmt.Sprintf("Unbounded repetition %s at the %s of an unanchored pattern", edge.node.String(), edge.where),
+			Suggestion: "Remove the repetition, since an unanchored pattern already matches anywhere in the input, or anchor the pattern with ^ and $",
+			Complexity: 20,
+			Details:    map[string]interface{}{"edge": edge.where},
+		}
+		if edge.where == "start" {
+			issue.Example = strings.Repeat(string(sampleRune(rep.Sub[0])), 20)
+		}
+		issues = append(issues, issue)
+	}
+
+	return issues
+}
+
+// unboundedCharRepetition reports whether re, possibly inside capture
+// groups, is an unbounded repetition of a wildcard or character class such
+// as .* or \w+, and returns the repetition.
+func unboundedCharRepetition(re *syntax.Regexp) (*syntax.Regexp, bool) {
+	for re.Op == syntax.OpCapture {
+		re = re.Sub[0]
+	}
+	if re.Op != syntax.OpStar && re.Op != syntax.OpPlus && (re.Op != syntax.OpRepeat || re.Max != -1) {
+		return nil, false
+	}
+	switch re.Sub[0].Op {
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL, syntax.OpCharClass:
+		return re, true
+	}
+	return nil, false
+}
+
+// matchesEmpty reports whether re can match the empty string.
+func matchesEmpty(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpLiteral, syntax.OpCharClass, syntax.OpAnyChar, syntax.OpAnyCharNotNL, syntax.OpNoMatch:
+		return false
+	case syntax.OpCapture, syntax.OpPlus:
+		return matchesEmpty(re.Sub[0])
+	case syntax.OpRepeat:
+		return re.Min == 0 || matchesEmpty(re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if !matchesEmpty(sub) {
+				return false
+			}
+		}
+		return true
+	case syntax.OpAlternate:
+		for _, sub := range re.Sub {
+			if matchesEmpty(sub) {
+				return true
+			}
+		}
+		return false
+	default:
+		// Star, Quest, empty match and zero-width assertions
+		return true
+	}
+}
+
+// sampleRune returns a character matched by a single-character node.
+func sampleRune(re *syntax.Regexp) rune {
+	switch re.Op {
+	case syntax.OpCharClass:
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			for r := re.Rune[i]; r <= re.Rune[i+1]; r++ {
+				if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+					return r
+				}
+				if r > 'z' {
+					break
+				}
+			}
+		}
+		if len(re.Rune) > 0 {
+			return re.Rune[0]
+		}
+	}
+	return 'a'
+}
+
+// runMemoryChecks estimates the memory matching the pattern needs. It runs
+// after the checks of every mode.
+func (d *Detector) runMemoryChecks(ctx context.Context, re *syntax.Regexp, pattern string) []Issue {
+	if !d.runs("memory_usage", CheckMemoryUsage) {
+		return nil
+	}
+	return d.detectMemoryUsage(ctx, re, pattern)
+}
+
+// dfaStateCap bounds the DFA size estimate of detectMemoryUsage.
+const dfaStateCap = 10000
+
+// maxBacktrackDepth is the backtracking stack depth above which
+// detectMemoryUsage warns about stack overflows.
+const maxBacktrackDepth = 1000
+
+// detectMemoryUsage estimates the memory matching the pattern needs, from
+// the pattern's NFA: the number of DFA states and the depth of the
+// backtracking stack.
+func (d *Detector) detectMemoryUsage(ctx context.Context, re *syntax.Regexp, pattern string) []Issue {
+	nfa, err := parser.BuildNFAWithLimit(re, d.opts.MaxNFAStates)
+	if err != nil {
+		// NFAs over the limit are reported by the NFA analysis
+		return nil
+	}
+
+	var issues []Issue
+	issues = append(issues, d.detectDFASize(ctx, nfa, pattern)...)
+
+	if depth := nfa.EstimateMaxBacktrackDepth(); d.exceeds("backtrack depth", depth, maxBacktrackDepth) {
+		issues = append(issues, Issue{
+			Type:       "complexity_threshold_exceeded",
+			Severity:   "low",
+			Position:   Position{Start: 0, End: len(pattern)},
+			Pattern:    pattern,
+			Message:    fmt.Sprintf("Backtracking depth of up to %d choice points (threshold: %d); backtracking engines may overflow their stack on inputs longer than %d characters", depth, maxBacktrackDepth, depth),
+			Suggestion: "Replace long runs of optional or counted elements with a single bounded repetition",
+			Complexity: 20,
+			Details:    map[string]interface{}{"max_backtrack_depth": depth},
+		})
+	}
+
+	return issues
+}
+
+// detectDFASize estimates the number of states subset construction needs
+// to turn the NFA into a DFA. Engines that build DFAs, such as RE2, use
+// memory in proportion to it. The worst case is 2^n states for an NFA with
+// n states; only when that bound exceeds MaxDFAStates is the construction
+// run, up to dfaStateCap states, to get the actual count.
+func (d *Detector) detectDFASize(ctx context.Context, nfa *parser.NFA, pattern string) []Issue {
+	limit := d.opts.MaxDFAStates
+	if limit <= 0 {
+		return nil
+	}
+
+	worstCase := dfaStateCap
+	if nfa.StateCount < 14 {
+		worstCase = min(1<<nfa.StateCount, dfaStateCap)
+	}
+	if worstCase <= limit {
+		return nil
+	}
+
+	estimate, err := nfa.CountDFAStates(ctx, dfaStateCap)
+	if err != nil || !d.exceeds("estimated DFA states", estimate, limit) {
+		return nil
+	}
+
+	return []Issue{{
+		Type:       "complexity_threshold_exceeded",
+		Severity:   "low",
+		Position:   Position{Start: 0, End: len(pattern)},
+		Pattern:    pattern,
+		Message:    fmt.Sprintf("Pattern needs an estimated %d DFA states (threshold: %d); engines that build DFAs, such as RE2, may use excessive memory", estimate, limit),
+		Suggestion: "Reduce counted repetitions after an unbounded repetition, or split the pattern",
+		Complexity: 20,
+		Details:    map[string]interface{}{"estimated_dfa_states": estimate},
+	}}
+}
+
+// detectBackreferenceAmbiguity finds backreferences like the \1 in (.+)\1
+// whose group contains an unbounded quantifier. To match the backreference,
+// a backtracking engine tries every length the group can capture, so
+// matching takes at least quadratic time. Go's syntax has no backreferences,
+// so this reads them from the pattern text.
+func (d *Detector) detectBackreferenceAmbiguity(pattern string) []Issue {
+	var issues []Issue
+	for _, ref := range parser.FindBackreferences(pattern) {
+		start, end, ok := parser.CaptureGroupSpan(pattern, ref.Group)
+		if !ok || end > ref.Pos.Start {
+			continue
+		}
+		group, err := syntax.Parse(pattern[start:end], syntax.Perl)
+		if err != nil || !hasUnboundedQuantifier(group) {
+			continue
+		}
+
+		escape := pattern[ref.Pos.Start:ref.Pos.End]
+		issues = append(issues, Issue{
+			Type:       "backreference_ambiguity",
+			Severity:   "medium",
+			Position:   Position{Start: start, End: ref.Pos.End},
+			Pattern:    pattern[start:ref.Pos.End],
+			Message:    fmt.Sprintf("Backreference %s refers to group %s, which can capture text of many lengths; a backtracking engine tries every capture", escape, pattern[start:end]),
+			Suggestion: "Bound the repetition inside the group, or match the repeated text without a backreference",
+			Complexity: 40,
+			Details:    map[string]interface{}{"group": ref.Group},
+		})
+	}
+	return issues
+}
+
+// hasUnboundedQuantifier reports whether re contains a *, + or {n,}.
+func hasUnboundedQuantifier(re *syntax.Regexp) bool {
+	found := false
+	parser.Walk(re, func(node *syntax.Regexp) (bool, error) {
+		if node.Op == syntax.OpStar || node.Op == syntax.OpPlus || node.Op == syntax.OpRepeat && node.Max == -1 {
+			found = true
+		}
+		return !found, nil
+	})
+	return found
+}
+
 // Helper function to generate example input for nested quantifiers
 func generateNestedQuantifierExample(node *syntax.Regexp) string {
 	// For patterns like (a+)+, generate aaaaaaa
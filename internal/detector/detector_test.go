@@ -1,8 +1,13 @@
 package detector
 
 import (
+	"context"
+	"errors"
+	"reflect"
 	"regexp/syntax"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/theakshaypant/regret/internal/parser"
 )
@@ -151,7 +156,7 @@ func TestDetector_NestedQuantifiers(t *testing.T) {
 
 			opts := &Options{Mode: Fast}
 			d := NewDetector(opts)
-			issues := d.detectNestedQuantifiers(re, tt.pattern)
+			issues := d.detectNestedQuantifiers(context.Background(), re, tt.pattern)
 
 			if tt.expectIssues && len(issues) == 0 {
 				t.Error("Expected nested quantifier issues but got none")
@@ -164,6 +169,99 @@ func TestDetector_NestedQuantifiers(t *testing.T) {
 	}
 }
 
+func TestDetector_IssuePositions(t *testing.T) {
+	tests := []struct {
+		name      string
+		pattern   string
+		issueType string
+		want      string
+	}{
+		{"nested quantifier", `^user-(a+)+$`, "nested_quantifiers", `(a+)+`},
+		{"overlapping alternation", `^id:(?:(a)|(ab))+$`, "overlapping_alternation", `(a)|(ab)`},
+		{"large quantifier range", `^id-a{1,500}$`, "large_quantifier_range", `a{1,500}`},
+		{"dangerous pattern", `^key=\d*\d+$`, "polynomial_backtracking", `\d*\d+`},
+	}
+
+	p := parser.NewParser()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re, err := p.Parse(tt.pattern)
+			if err != nil {
+				t.Fatalf("Failed to parse: %v", err)
+			}
+
+			d := NewDetector(&Options{Mode: Fast, MaxQuantifierRange: 100})
+			issues, _ := d.Detect(re, tt.pattern)
+
+			found := false
+			for _, issue := range issues {
+				if issue.Type != tt.issueType {
+					continue
+				}
+				found = true
+				if got := tt.pattern[issue.Position.Start:issue.Position.End]; got != tt.want {
+					t.Errorf("%s position covers %q, want %q", tt.issueType, got, tt.want)
+				}
+			}
+			if !found {
+				t.Fatalf("Detect(%q) found no %s issue", tt.pattern, tt.issueType)
+			}
+		})
+	}
+}
+
+func TestDetector_RepeatedCaptureGroups(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    []string // Source text of each repeated group
+	}{
+		{`^(\d{1,3}\.){3}\d{1,3}$`, []string{`(\d{1,3}\.)`}},
+		{`^(?:id-)(ab)+(cd)*$`, []string{`(ab)`, `(cd)`}},
+		{`^(ab)?(cd){1}$`, nil},
+		{`(a){0}(b)+`, []string{`(b)`}},
+		{`^(ab){0,1}(cd){0}$`, nil},
+		{`^(?:ab)+$`, nil},
+	}
+
+	p := parser.NewParser()
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re, err := p.Parse(tt.pattern)
+			if err != nil {
+				t.Fatalf("Failed to parse: %v", err)
+			}
+
+			d := NewDetector(&Options{Mode: Thorough})
+			issues, _ := d.Detect(re, tt.pattern)
+
+			var got []string
+			for _, issue := range issues {
+				if issue.Type != "repeated_capture_group" {
+					continue
+				}
+				if issue.Severity != "info" {
+					t.Errorf("severity = %s, want info", issue.Severity)
+				}
+				got = append(got, tt.pattern[issue.Position.Start:issue.Position.End])
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("repeated groups = %q, want %q", got, tt.want)
+			}
+
+			// Only reported in Thorough mode
+			d = NewDetector(&Options{Mode: Balanced})
+			issues, _ = d.Detect(re, tt.pattern)
+			for _, issue := range issues {
+				if issue.Type == "repeated_capture_group" {
+					t.Errorf("Balanced mode reported %s", issue.Message)
+				}
+			}
+		})
+	}
+}
+
 func TestDetector_DangerousPatterns(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -190,7 +288,7 @@ func TestDetector_DangerousPatterns(t *testing.T) {
 
 			opts := &Options{Mode: Fast}
 			d := NewDetector(opts)
-			issues := d.detectDangerousPatterns(re, tt.pattern)
+			issues := d.detectDangerousPatterns(context.Background(), re, tt.pattern)
 
 			if tt.expectIssues && len(issues) == 0 {
 				t.Error("Expected dangerous pattern issues but got none")
@@ -287,6 +385,98 @@ func TestDetector_QuantifierCount(t *testing.T) {
 	}
 }
 
+func TestDetector_QuantifierRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		limit    int
+		expected bool
+	}{
+		{"range above limit", "a{1,500}", 100, true},
+		{"range at limit", "a{0,100}", 100, true},
+		{"range below limit", "a{1,100}", 100, false},
+		{"narrow range", "a{400,500}", 200, false},
+		{"unbounded repeat", "a{5,}", 1, false},
+		{"exact count", "a{500}", 1, false},
+		{"nested range", "(ab{1,50})+", 10, true},
+		{"check disabled", "a{1,1000}", 0, false},
+	}
+
+	p := parser.NewParser()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re, err := p.Parse(tt.pattern)
+			if err != nil {
+				t.Fatalf("Failed to parse: %v", err)
+			}
+
+			d := NewDetector(&Options{Mode: Fast, MaxQuantifierRange: tt.limit})
+			issues, _ := d.Detect(re, tt.pattern)
+
+			found := false
+			for _, issue := range issues {
+				if issue.Type == "large_quantifier_range" {
+					found = true
+					if issue.Severity != "medium" {
+						t.Errorf("large_quantifier_range severity = %s, want medium", issue.Severity)
+					}
+				}
+			}
+
+			if found != tt.expected {
+				t.Errorf("Detect(%q) large_quantifier_range = %v, want %v", tt.pattern, found, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDetector_AlternationBranches(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		limit    int
+		expected bool
+	}{
+		{"branches above limit", "^(red|green|blue|cyan|white)$", 4, true},
+		{"branches at limit", "^(red|green|blue|cyan)$", 4, false},
+		{"single characters merged into a class", "^(a|b|c|d|e|f)+$", 4, true},
+		{"character class", "^[abcdef]+$", 4, false},
+		{"escaped bars", `^(a\|b\|c\|d\|e)$`, 4, false},
+		{"bars in a class", "^([|]|x)$", 2, false},
+		{"nested groups", "^(?:(?:ab|cd)|ef|gh)$", 2, true},
+		{"check disabled", "^(red|green|blue|cyan|white)$", 0, false},
+	}
+
+	p := parser.NewParser()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re, err := p.Parse(tt.pattern)
+			if err != nil {
+				t.Fatalf("Failed to parse: %v", err)
+			}
+
+			d := NewDetector(&Options{Mode: Fast, MaxAlternationBranches: tt.limit})
+			issues, _ := d.Detect(re, tt.pattern)
+
+			found := false
+			for _, issue := range issues {
+				if issue.Type == "ambiguous_pattern" {
+					found = true
+					if issue.Severity != "medium" {
+						t.Errorf("ambiguous_pattern severity = %s, want medium", issue.Severity)
+					}
+				}
+			}
+
+			if found != tt.expected {
+				t.Errorf("Detect(%q) ambiguous_pattern = %v, want %v", tt.pattern, found, tt.expected)
+			}
+		})
+	}
+}
+
 func TestDetector_PatternLength(t *testing.T) {
 	// Create an excessively long pattern
 	longPattern := ""
@@ -351,7 +541,7 @@ func TestBranchesOverlap(t *testing.T) {
 
 			// The pattern might be simplified, so we need to find the alternation
 			var foundAlternation bool
-			parser.Walk(re, func(node *syntax.Regexp) bool {
+			parser.Walk(re, func(node *syntax.Regexp) (bool, error) {
 				if parser.IsAlternation(node) {
 					foundAlternation = true
 					if len(node.Sub) >= 2 {
@@ -360,9 +550,9 @@ func TestBranchesOverlap(t *testing.T) {
 							t.Errorf("Expected overlap=%v, got %v", tt.expected, overlap)
 						}
 					}
-					return false
+					return false, nil
 				}
-				return true
+				return true, nil
 			})
 
 			// Some patterns get simplified and lose their alternation nodes
@@ -411,3 +601,519 @@ func (m ValidationMode) String() string {
 		return "Unknown"
 	}
 }
+
+func TestDetector_DetectContextDeadline(t *testing.T) {
+	re := parser.NewParser().MustParse("(a+)+")
+	d := NewDetector(&Options{Mode: Thorough})
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	issues, err := d.DetectContext(ctx, re, "(a+)+")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("DetectContext() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if len(issues) != 0 {
+		t.Errorf("expected no issues from an expired context, got %v", issues)
+	}
+
+	// The detector is usable again afterwards
+	issues, err = d.Detect(re, "(a+)+")
+	if err != nil || len(issues) == 0 {
+		t.Errorf("Detect() = %v, %v; want issues", issues, err)
+	}
+}
+
+func TestDetector_DetectAsync(t *testing.T) {
+	patterns := []string{"(a+)+b", `^\d*\w*$`, "(x|xy)*z", "^[a-z]+$", "((a|a)*)+"}
+
+	for _, pattern := range patterns {
+		t.Run(pattern, func(t *testing.T) {
+			re := parser.NewParser().MustParse(pattern)
+			d := NewDetector(&Options{Mode: Thorough})
+
+			want, err := d.Detect(re, pattern)
+			if err != nil {
+				t.Fatalf("Detect() error = %v", err)
+			}
+
+			issues, errs := d.DetectAsync(re, pattern)
+			var got []Issue
+			for issue := range issues {
+				got = append(got, issue)
+			}
+			for err := range errs {
+				t.Errorf("DetectAsync() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("DetectAsync() issues = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestDetector_Checks(t *testing.T) {
+	tests := []struct {
+		name      string
+		pattern   string
+		checks    uint32
+		wantIssue bool
+	}{
+		{"nested enabled", "(a+)+", CheckNestedQuantifiers, true},
+		{"nested disabled", "(a+)+", CheckOverlappingAlternation, false},
+		{"alternation enabled", "(?:(a)|(ab))+", CheckOverlappingAlternation, true},
+		{"alternation disabled", "(?:(a)|(ab))+", CheckNestedQuantifiers, false},
+		{"zero runs all", "(?:(a)|(ab))+", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re := parser.NewParser().MustParse(tt.pattern)
+			// Balanced also runs NFA analysis unless CheckNFAAmbiguity is unset
+			d := NewDetector(&Options{Mode: Balanced, Checks: tt.checks})
+
+			issues, err := d.Detect(re, tt.pattern)
+			if err != nil {
+				t.Fatalf("Detect() error = %v", err)
+			}
+			if got := len(issues) > 0; got != tt.wantIssue {
+				t.Errorf("Detect(%s) with checks %b = %v, want issues: %v", tt.pattern, tt.checks, issues, tt.wantIssue)
+			}
+		})
+	}
+}
+
+func TestDetector_UnboundedRepetition(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    int
+	}{
+		{".*password.*", 2},
+		{`\w+@example\.com`, 1},
+		{"(.*)error", 1},
+		{"^.*password.*$", 0},
+		{"password", 0},
+		{".*", 0},
+		{"^[a-z]+$", 0},
+		{"(ab)*c", 0}, // repetition of more than one character
+		{`\w+\s*`, 1}, // the rest of the pattern can be empty at the start
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re := parser.NewParser().MustParse(tt.pattern)
+			d := NewDetector(&Options{Mode: Fast, Checks: CheckUnboundedRepetition})
+
+			issues, err := d.Detect(re, tt.pattern)
+			if err != nil {
+				t.Fatalf("Detect() error = %v", err)
+			}
+			if len(issues) != tt.want {
+				t.Fatalf("Detect(%s) = %v, want %d issues", tt.pattern, issues, tt.want)
+			}
+			for _, issue := range issues {
+				if issue.Type != "unbounded_repetition" || issue.Severity != "low" {
+					t.Errorf("issue = %s/%s, want unbounded_repetition/low", issue.Type, issue.Severity)
+				}
+			}
+		})
+	}
+}
+
+func TestDetector_UnicodeAmbiguity(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string // character reported, "" for no issue
+	}{
+		{`^[àáâ]+$`, "U+00E0"},
+		{`café`, "U+00E9"},
+		{`[\x{AC00}-\x{AC10}]`, "U+AC00"}, // Hangul syllables decompose into jamo
+		{`[^a]+`, "U+00E0"},               // à is a followed by U+0300
+		{`^[a-z0-9]+$`, ""},
+		{`.+`, ""}, // matches the decomposed forms too
+		{`[éa-z\x{300}-\x{36F}]`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re := parser.NewParser().MustParse(tt.pattern)
+			d := NewDetector(&Options{Mode: Fast, Checks: CheckUnicodeAmbiguity})
+
+			issues, err := d.Detect(re, tt.pattern)
+			if err != nil {
+				t.Fatalf("Detect() error = %v", err)
+			}
+			if tt.want == "" {
+				if len(issues) != 0 {
+					t.Errorf("Detect(%s) = %v, want no issues", tt.pattern, issues)
+				}
+				return
+			}
+			if len(issues) != 1 || issues[0].Type != "unicode_ambiguity" || issues[0].Severity != "low" {
+				t.Fatalf("Detect(%s) = %v, want one low unicode_ambiguity issue", tt.pattern, issues)
+			}
+			if got := issues[0].Details["character"]; got != tt.want {
+				t.Errorf("Details[character] = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// The check only runs when its flag is set
+	re := parser.NewParser().MustParse("café")
+	d := NewDetector(&Options{Mode: Fast, Checks: CheckNestedQuantifiers})
+	if issues, _ := d.Detect(re, "café"); len(issues) != 0 {
+		t.Errorf("Detect() without CheckUnicodeAmbiguity = %v, want no issues", issues)
+	}
+}
+
+func TestDetector_PolynomialDegree(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    []int // degree of each issue
+	}{
+		{`^\d*\w*$`, []int{2}},
+		{`^a*a*a*$`, []int{3}},
+		{`^(\d+)(\d+)(\d+)x$`, []int{3}},
+		{`^\d*[a-z]*\w*$`, []int{2}}, // \d and [a-z] are disjoint
+		{`^\d+[a-z]+$`, nil},
+		{`^.*x.*$`, nil},
+		{`^(ab)*(ab)*$`, nil}, // not single characters
+		{`^a{2,5}a{2,5}$`, nil},
+		{`^\d+-\d+$`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re := parser.NewParser().MustParse(tt.pattern)
+			d := NewDetector(&Options{Mode: Balanced, Checks: CheckPolynomialDegree})
+
+			issues, err := d.Detect(re, tt.pattern)
+			if err != nil {
+				t.Fatalf("Detect() error = %v", err)
+			}
+			var got []int
+			for _, issue := range issues {
+				if issue.Type != "polynomial_backtracking" {
+					t.Errorf("issue type = %s, want polynomial_backtracking", issue.Type)
+				}
+				got = append(got, issue.Details["degree"].(int))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Detect(%s) degrees = %v, want %v", tt.pattern, got, tt.want)
+			}
+		})
+	}
+
+	// The check only runs when its flag is set
+	re := parser.NewParser().MustParse(`^\d*\w*$`)
+	d := NewDetector(&Options{Mode: Balanced, Checks: CheckNestedQuantifiers})
+	if issues, _ := d.Detect(re, `^\d*\w*$`); len(issues) != 0 {
+		t.Errorf("Detect() without CheckPolynomialDegree = %v, want no issues", issues)
+	}
+
+	// A run the NFA analysis also reports is merged into its issue
+	re = parser.NewParser().MustParse(`\d*\w*x`)
+	d = NewDetector(&Options{Mode: Balanced, Checks: CheckNFAAmbiguity | CheckPolynomialDegree})
+	issues, err := d.Detect(re, `\d*\w*x`)
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	if len(issues) != 1 || issues[0].Type != "polynomial_backtracking" ||
+		issues[0].Details["degree"] != 2 || issues[0].Details["adjacent_quantifiers"] != 2 {
+		t.Errorf("Detect() = %v, want one polynomial_backtracking issue of degree 2 with adjacent_quantifiers", issues)
+	}
+}
+
+func TestDetector_ExperimentalChecks(t *testing.T) {
+	tests := []struct {
+		pattern string
+		enabled bool
+		want    bool
+	}{
+		{"^(a+)+$", true, true},
+		{`^(?:\d|\d\d)+$`, true, true},
+		{"^(a+)+$", false, false},
+		{"^a*a*$", true, false},
+		{"^[a-z]+@[a-z]+$", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re := parser.NewParser().MustParse(tt.pattern)
+			d := NewDetector(&Options{Mode: Fast, Checks: CheckNFAAmbiguity, EnableExperimentalChecks: tt.enabled})
+
+			issues, err := d.Detect(re, tt.pattern)
+			if err != nil {
+				t.Fatalf("Detect() error = %v", err)
+			}
+
+			var found *Issue
+			for i := range issues {
+				if strings.HasPrefix(issues[i].Message, "Experimental:") {
+					found = &issues[i]
+				}
+			}
+			if (found != nil) != tt.want {
+				t.Fatalf("Detect(%s) experimental issue = %v, want %v", tt.pattern, found, tt.want)
+			}
+			if found == nil {
+				return
+			}
+			if found.Severity != "info" {
+				t.Errorf("experimental issue severity = %s, want info", found.Severity)
+			}
+			if found.Example == "" {
+				t.Error("experimental issue has no example")
+			}
+		})
+	}
+}
+
+func TestDetector_MemoryUsage(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		maxDFA   int
+		checks   uint32
+		wantSize int // 0 means no issue
+	}{
+		{"[ab]*a[ab]{14}", 1000, CheckMemoryUsage, dfaStateCap},
+		{`\pL*a\pL{14}`, 1000, CheckMemoryUsage, dfaStateCap},
+		{"[ab]*a[ab]{12}", 1000, CheckMemoryUsage, 8194},
+		{"[ab]*a[ab]{9}", 1000, CheckMemoryUsage, 1026},
+		{"[ab]*a[ab]{9}", 2000, CheckMemoryUsage, 0},
+		{"[ab]*a[ab]{12}", 0, CheckMemoryUsage, 0},
+		{"[ab]*a[ab]{12}", 1000, CheckNestedQuantifiers, 0},
+		{"^[a-z]+@[a-z]+\\.com$", 1000, CheckMemoryUsage, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re := parser.NewParser().MustParse(tt.pattern)
+			d := NewDetector(&Options{Mode: Fast, Checks: tt.checks, MaxDFAStates: tt.maxDFA})
+
+			issues, err := d.Detect(re, tt.pattern)
+			if err != nil {
+				t.Fatalf("Detect() error = %v", err)
+			}
+
+			var got int
+			for _, issue := range issues {
+				if n, ok := issue.Details["estimated_dfa_states"].(int); ok {
+					got = n
+					if issue.Severity != "low" {
+						t.Errorf("severity = %s, want low", issue.Severity)
+					}
+				}
+			}
+			if got != tt.wantSize {
+				t.Errorf("Detect(%s) estimated DFA states = %d, want %d (issues: %v)", tt.pattern, got, tt.wantSize, issues)
+			}
+		})
+	}
+}
+
+func TestDetector_MemoryUsageRunsLast(t *testing.T) {
+	pattern := `(a+)+[ab]*a[ab]{12}`
+	re := parser.NewParser().MustParse(pattern)
+	d := NewDetector(&Options{Mode: Balanced, Checks: CheckNFAAmbiguity | CheckMemoryUsage, MaxDFAStates: 1000})
+
+	issues, err := d.Detect(re, pattern)
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	if len(issues) < 2 {
+		t.Fatalf("Detect() = %v, want NFA and memory issues", issues)
+	}
+	if _, ok := issues[len(issues)-1].Details["estimated_dfa_states"]; !ok {
+		t.Errorf("last issue = %+v, want the DFA size issue after the NFA analysis", issues[len(issues)-1])
+	}
+}
+
+func TestDetector_BacktrackDepth(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    int // 0 means no issue
+	}{
+		{"(?:a?b?){600}", 1200},
+		{"(?:a?){400}", 0},
+		{"^[a-z]+$", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re := parser.NewParser().MustParse(tt.pattern)
+			d := NewDetector(&Options{Mode: Fast, Checks: CheckMemoryUsage})
+
+			issues, err := d.Detect(re, tt.pattern)
+			if err != nil {
+				t.Fatalf("Detect() error = %v", err)
+			}
+
+			var got int
+			for _, issue := range issues {
+				if n, ok := issue.Details["max_backtrack_depth"].(int); ok {
+					got = n
+				}
+			}
+			if got != tt.want {
+				t.Errorf("Detect(%s) max backtrack depth = %d, want %d", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetector_BackreferenceAmbiguity(t *testing.T) {
+	tests := []struct {
+		pattern        string
+		backreferences bool
+		wantPattern    string // "" means no issue
+	}{
+		{`(.+)\1+`, true, `(.+)\1`},
+		{`^(\w+)\s+\1$`, true, `(\w+)\s+\1`},
+		{`(ab)\1`, true, ""},     // fixed length
+		{`(a{1,3})\1`, true, ""}, // bounded
+		{`(.+)\1+`, false, ""},   // dialect without backreferences
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re := parser.NewParser().MustParse(parser.ExpandBackreferences(tt.pattern))
+			d := NewDetector(&Options{Mode: Fast, Checks: CheckCatastrophicBacktrack, Backreferences: tt.backreferences})
+
+			issues, err := d.Detect(re, tt.pattern)
+			if err != nil {
+				t.Fatalf("Detect() error = %v", err)
+			}
+
+			var got string
+			for _, issue := range issues {
+				if issue.Type == "backreference_ambiguity" {
+					got = issue.Pattern
+				}
+			}
+			if got != tt.wantPattern {
+				t.Errorf("Detect(%s) backreference issue pattern = %q, want %q (issues: %v)", tt.pattern, got, tt.wantPattern, issues)
+			}
+		})
+	}
+}
+
+func TestDetector_RawChecksWithBackreferences(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		opts      Options
+		wantType  string
+		wantMatch string // Source text at the issue's position
+	}{
+		{`^(x)\1[0-9a-f]{128}$`, Options{Mode: Fast, MaxRepetitionCount: 100}, "unbounded_repetition", `[0-9a-f]{128}`},
+		{`^(x)\1a{1,500}$`, Options{Mode: Fast, MaxQuantifierRange: 100}, "large_quantifier_range", `a{1,500}`},
+		{`^(x)\1(ab)+$`, Options{Mode: Thorough}, "repeated_capture_group", `(ab)`},
+		{`^(x)\1(?:a|b|c|d)$`, Options{Mode: Fast, MaxAlternationBranches: 3}, "ambiguous_pattern", `a|b|c|d`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re := parser.NewParser().MustParse(parser.ExpandBackreferences(tt.pattern))
+			opts := tt.opts
+			opts.Backreferences = true
+			d := NewDetector(&opts)
+
+			issues, err := d.Detect(re, tt.pattern)
+			if err != nil {
+				t.Fatalf("Detect() error = %v", err)
+			}
+
+			var got []string
+			for _, issue := range issues {
+				if issue.Type == tt.wantType {
+					got = append(got, tt.pattern[issue.Position.Start:issue.Position.End])
+				}
+			}
+			if len(got) != 1 || got[0] != tt.wantMatch {
+				t.Errorf("Detect(%s) %s issues at %q, want one at %q", tt.pattern, tt.wantType, got, tt.wantMatch)
+			}
+		})
+	}
+}
+
+func TestDetector_IssueDetails(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		issueType string
+		keys      []string
+	}{
+		{"(a+)+", "nested_quantifiers", []string{"nesting_depth"}},
+		{"(?:(a)|(ab))+", "overlapping_alternation", []string{"overlapping_branches"}},
+		{"(a+)+", "exponential_backtracking", []string{"nfa_state_count", "ambiguous_states"}},
+		{`\d+\d+`, "polynomial_backtracking", []string{"degree"}},
+		{"(a)+", "repeated_capture_group", []string{"group"}},
+		{".*x", "unbounded_repetition", []string{"edge"}},
+		{"a{1,50}", "large_quantifier_range", []string{"range", "threshold"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.issueType, func(t *testing.T) {
+			re := parser.NewParser().MustParse(tt.pattern)
+			d := NewDetector(&Options{Mode: Thorough, MaxQuantifierRange: 10})
+
+			issues, err := d.Detect(re, tt.pattern)
+			if err != nil {
+				t.Fatalf("Detect() error = %v", err)
+			}
+			found := false
+			for _, issue := range issues {
+				if issue.Type != tt.issueType {
+					continue
+				}
+				found = true
+				for _, key := range tt.keys {
+					if _, ok := issue.Details[key]; !ok {
+						t.Errorf("%s issue for %s has no Details[%s]: %v", issue.Type, tt.pattern, key, issue.Details)
+					}
+				}
+			}
+			if !found {
+				t.Fatalf("Detect(%s) = %v, want a %s issue", tt.pattern, issues, tt.issueType)
+			}
+		})
+	}
+}
+
+func TestDetector_DetectWithTrace(t *testing.T) {
+	pattern := "(a+)+"
+	re := parser.NewParser().MustParse(pattern)
+	d := NewDetector(&Options{Mode: Fast, Checks: CheckNestedQuantifiers})
+
+	issues, trace, err := d.DetectWithTrace(re, pattern)
+	if err != nil {
+		t.Fatalf("DetectWithTrace() error = %v", err)
+	}
+	if len(issues) == 0 {
+		t.Fatal("DetectWithTrace() found no issues")
+	}
+
+	kinds := make(map[string]int)
+	skipped := false
+	for _, event := range trace.Events {
+		kinds[event.Kind]++
+		if event.Kind == "check" && event.Check == "unbounded_repetition" && strings.HasPrefix(event.Message, "skipped") {
+			skipped = true
+		}
+	}
+	for _, kind := range []string{"phase", "check", "visit", "threshold"} {
+		if kinds[kind] == 0 {
+			t.Errorf("trace has no %s events: %v", kind, trace.Events)
+		}
+	}
+	if kinds["issue"] != len(issues) {
+		t.Errorf("trace has %d issue events, want %d", kinds["issue"], len(issues))
+	}
+	if !skipped {
+		t.Error("trace does not record that the disabled unbounded_repetition check was skipped")
+	}
+
+	if d.trace != nil {
+		t.Error("tracing still enabled after DetectWithTrace")
+	}
+}
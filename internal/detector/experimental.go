@@ -0,0 +1,63 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"regexp/syntax"
+	"strings"
+
+	"github.com/theakshaypant/regret/internal/ambiguity"
+	"github.com/theakshaypant/regret/internal/parser"
+)
+
+// edaExamplePumps is how many times the pump string of an exponential
+// ambiguity witness is repeated in an issue example.
+const edaExamplePumps = 20
+
+// runExperimentalChecks runs detection algorithms that are not yet stable
+// enough to be part of the regular checks. They only run when
+// Options.EnableExperimentalChecks is set and report Info issues until they
+// graduate.
+//
+// Each check documents where its false positives come from.
+func (d *Detector) runExperimentalChecks(_ context.Context, re *syntax.Regexp, pattern string) []Issue {
+	var issues []Issue
+
+	// 1. Exponential ambiguity witness from the product automaton
+	if d.runs("exponential_witness", 0) {
+		issues = append(issues, d.detectExponentialWitness(re, pattern)...)
+	}
+
+	return issues
+}
+
+// detectExponentialWitness searches the product automaton NFA × NFA for a
+// state that loops back to itself along two different paths on the same
+// input, which is the formal condition for exponential ambiguity, and
+// reports a witness input built from it.
+//
+// The search is exact for the NFA, so false positives come from patterns
+// where every candidate suffix is accepted and a backtracking engine finds a
+// match before exploring the ambiguous paths.
+func (d *Detector) detectExponentialWitness(re *syntax.Regexp, pattern string) []Issue {
+	nfa, err := parser.BuildNFAWithLimit(re, d.opts.MaxNFAStates)
+	if err != nil {
+		return nil
+	}
+	prefix, pump, suffix, ok := ambiguity.ExponentialWitness(nfa)
+	if !ok {
+		return nil
+	}
+
+	return []Issue{{
+		Type:       "exponential_backtracking",
+		Severity:   "info",
+		Position:   Position{Start: 0, End: len(pattern)},
+		Pattern:    pattern,
+		Message:    fmt.Sprintf("Experimental: each repetition of %q can be matched in more than one way, doubling the paths a backtracking engine tries", pump),
+		Example:    prefix + strings.Repeat(pump, edaExamplePumps) + suffix,
+		Suggestion: "Rewrite the repeated part so that each input can only be matched one way",
+		Complexity: 90,
+		Details:    map[string]interface{}{"pump": pump},
+	}}
+}
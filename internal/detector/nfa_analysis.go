@@ -2,15 +2,28 @@
 package detector
 
 import (
+	"context"
 	"regexp/syntax"
+	"strings"
 
+	"github.com/theakshaypant/regret/internal/ambiguity"
 	"github.com/theakshaypant/regret/internal/parser"
 )
 
+// idaExamplePumps is how many times each pump string of a polynomial
+// ambiguity witness is repeated in example inputs.
+const idaExamplePumps = 10
+
 // NFAAnalyzer performs NFA-based analysis for EDA/IDA detection.
 type NFAAnalyzer struct {
-	nfa    *parser.NFA
-	parser *parser.Parser
+	nfa        *parser.NFA
+	parser     *parser.Parser
+	loopStates map[int]int     // State ID -> index of its cycle in FindCycles
+	maxStates  int             // NFA state limit, 0 means no limit
+	maxPaths   int             // Epsilon paths allowed into a state, 0 means 1
+	ambiguous  int             // Number of ambiguous states found by detectEDA
+	ctx        context.Context // Deadline of the current analyze call
+	onIssue    func(Issue)     // Called with each EDA/IDA issue as it is found
 }
 
 // NewNFAAnalyzer creates a new NFA analyzer.
@@ -23,12 +36,19 @@ func NewNFAAnalyzer() *NFAAnalyzer {
 // AnalyzePattern analyzes a regex pattern using NFA-based methods.
 func (a *NFAAnalyzer) AnalyzePattern(re *syntax.Regexp, pattern string) ([]Issue, error) {
 	// Build NFA from regex
-	nfa, err := parser.BuildNFA(re)
+	nfa, err := parser.BuildNFAWithLimit(re, a.maxStates)
 	if err != nil {
 		return nil, err
 	}
 
 	a.nfa = nfa
+	a.ambiguous = 0
+	a.loopStates = make(map[int]int)
+	for i, cycle := range nfa.FindCycles() {
+		for _, id := range cycle {
+			a.loopStates[id] = i
+		}
+	}
 
 	var issues []Issue
 
@@ -43,6 +63,40 @@ func (a *NFAAnalyzer) AnalyzePattern(re *syntax.Regexp, pattern string) ([]Issue
 	return issues, nil
 }
 
+// AnalyzePatternWithTimeout is like AnalyzePattern but stops when ctx is
+// done. The graph searches look at ctx on every state they visit. If ctx
+// expires, the issues found so far are returned together with an info
+// issue noting that the analysis is incomplete.
+func (a *NFAAnalyzer) AnalyzePatternWithTimeout(ctx context.Context, re *syntax.Regexp, pattern string) ([]Issue, error) {
+	return a.analyze(ctx, re, pattern, nil)
+}
+
+// analyze is AnalyzePatternWithTimeout but also passes each EDA/IDA issue
+// to onIssue, if not nil, as soon as it is found.
+func (a *NFAAnalyzer) analyze(ctx context.Context, re *syntax.Regexp, pattern string, onIssue func(Issue)) ([]Issue, error) {
+	a.ctx, a.onIssue = ctx, onIssue
+	defer func() { a.ctx, a.onIssue = nil, nil }()
+
+	issues, err := a.AnalyzePattern(re, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() != nil {
+		issues = append(issues, Issue{
+			Type:       "complexity_threshold_exceeded",
+			Severity:   "info",
+			Position:   Position{Start: 0, End: len(pattern)},
+			Pattern:    pattern,
+			Message:    "NFA analysis incomplete: " + ctx.Err().Error(),
+			Suggestion: "Increase the timeout or simplify the pattern",
+			Details:    map[string]interface{}{"reason": ctx.Err().Error()},
+		})
+	}
+
+	return issues, nil
+}
+
 // detectEDA detects Exponential Degree of Ambiguity.
 // This occurs when patterns have multiple paths that can match the same input,
 // and the number of paths grows exponentially with input length.
@@ -54,12 +108,17 @@ func (a *NFAAnalyzer) detectEDA(re *syntax.Regexp, pattern string) []Issue {
 	// 2. Check if ambiguity is nested (quantifiers within quantifiers)
 	// 3. Check for overlapping alternations inside quantifiers
 
-	ambiguousStates := a.findAmbiguousStates()
+	ambiguousStates := a.findAmbiguousStates(a.pathThreshold())
+	a.ambiguous = len(ambiguousStates)
 
 	for _, state := range ambiguousStates {
-		// Check if this ambiguity is in a loop (quantifier)
-		if a.isInQuantifierLoop(state) {
-			issues = append(issues, Issue{
+		if a.done() {
+			break
+		}
+		// Check if this ambiguity is in a loop (quantifier) and the loop
+		// can consume the same input along more than one path
+		if a.isInQuantifierLoop(state) && a.hasOverlappingLoopPaths(state) {
+			issues = a.report(issues, Issue{
 				Type:       "exponential_backtracking",
 				Severity:   "critical",
 				Position:   Position{Start: 0, End: len(pattern)},
@@ -68,6 +127,7 @@ func (a *NFAAnalyzer) detectEDA(re *syntax.Regexp, pattern string) []Issue {
 				Example:    a.generateEDAExample(state),
 				Suggestion: "Remove nested quantifiers or use atomic grouping",
 				Complexity: 95,
+				Details:    a.details(),
 			})
 		}
 	}
@@ -76,7 +136,7 @@ func (a *NFAAnalyzer) detectEDA(re *syntax.Regexp, pattern string) []Issue {
 	// This catches patterns that might be missed by pure NFA analysis
 	nestedQuantifiers := a.findNestedQuantifiersInNFA(re)
 	if len(nestedQuantifiers) > 0 {
-		issues = append(issues, Issue{
+		issues = a.report(issues, Issue{
 			Type:       "exponential_backtracking",
 			Severity:   "critical",
 			Position:   Position{Start: 0, End: len(pattern)},
@@ -85,6 +145,7 @@ func (a *NFAAnalyzer) detectEDA(re *syntax.Regexp, pattern string) []Issue {
 			Example:    "aaaaaaaax",
 			Suggestion: "Simplify quantifier nesting",
 			Complexity: 95,
+			Details:    a.details(),
 		})
 	}
 
@@ -119,15 +180,18 @@ func (a *NFAAnalyzer) detectIDA(re *syntax.Regexp, pattern string) []Issue {
 				complexityStr = "O(n^k)"
 			}
 
-			issues = append(issues, Issue{
+			details := a.details()
+			details["degree"] = degree
+			issues = a.report(issues, Issue{
 				Type:       "polynomial_backtracking",
 				Severity:   "high",
 				Position:   Position{Start: 0, End: len(pattern)},
 				Pattern:    pattern,
 				Message:    "Polynomial ambiguity detected: " + complexityStr,
-				Example:    "aaaaaaax",
+				Example:    polynomialExample(a.context(), a.nfa, degree, "aaaaaaax"),
 				Suggestion: "Consolidate overlapping quantifiers or use possessive quantifiers",
 				Complexity: complexity,
+				Details:    details,
 			})
 		}
 	}
@@ -135,14 +199,64 @@ func (a *NFAAnalyzer) detectIDA(re *syntax.Regexp, pattern string) []Issue {
 	return issues
 }
 
-// findAmbiguousStates finds states that can be reached via multiple paths.
-func (a *NFAAnalyzer) findAmbiguousStates() []*parser.State {
-	var ambiguous []*parser.State
+// details returns the Details of an issue found by the NFA analysis: the
+// number of NFA states and of ambiguous states among them.
+func (a *NFAAnalyzer) details() map[string]interface{} {
+	return map[string]interface{}{
+		"nfa_state_count":  a.nfa.StateCount,
+		"ambiguous_states": a.ambiguous,
+	}
+}
+
+// report appends issue to issues and passes it to the onIssue callback,
+// if set, so that callers can stream issues while the analysis goes on.
+func (a *NFAAnalyzer) report(issues []Issue, issue Issue) []Issue {
+	if a.onIssue != nil {
+		a.onIssue(issue)
+	}
+	return append(issues, issue)
+}
+
+// context returns the context of the current analysis, or
+// context.Background() if there is none.
+func (a *NFAAnalyzer) context() context.Context {
+	if a.ctx == nil {
+		return context.Background()
+	}
+	return a.ctx
+}
+
+// done reports whether the context of the current analysis is done.
+func (a *NFAAnalyzer) done() bool {
+	return a.ctx != nil && a.ctx.Err() != nil
+}
+
+// walk is parser.Walk but stops visiting nodes once the analysis context is done.
+func (a *NFAAnalyzer) walk(re *syntax.Regexp, visitor func(*syntax.Regexp) bool) {
+	_ = parser.Walk(re, func(node *syntax.Regexp) (bool, error) {
+		if a.ctx != nil {
+			if err := a.ctx.Err(); err != nil {
+				return false, err
+			}
+		}
+		return visitor(node), nil
+	})
+}
 
+// findAmbiguousStates finds states that can consume the next character
+// along more than maxPaths distinct epsilon paths, either because the paths
+// lead to different consuming transitions, as in an alternation, or because
+// several paths lead to the same one, as in (a*)*.
+func (a *NFAAnalyzer) findAmbiguousStates(maxPaths int) []*parser.State {
+	// Counting stops just above the threshold
+	limit := maxPaths + 1
+
+	var ambiguous []*parser.State
 	for _, state := range a.nfa.States {
-		// Count distinct paths to this state
-		pathCount := a.countPathsToState(state)
-		if pathCount > 1 {
+		if a.done() {
+			break
+		}
+		if a.countConsumingPaths(state, limit) > maxPaths {
 			ambiguous = append(ambiguous, state)
 		}
 	}
@@ -150,50 +264,110 @@ func (a *NFAAnalyzer) findAmbiguousStates() []*parser.State {
 	return ambiguous
 }
 
-// countPathsToState counts distinct paths from start to given state.
-func (a *NFAAnalyzer) countPathsToState(target *parser.State) int {
-	// Simplified path counting (exact counting is expensive)
-	// We use epsilon closure size as a proxy for ambiguity
-	closure := parser.ComputeEpsilonClosure(target)
+// countConsumingPaths counts the distinct epsilon paths from a state to a
+// consuming transition, capped at limit.
+func (a *NFAAnalyzer) countConsumingPaths(from *parser.State, limit int) int {
+	if a.done() {
+		return 1
+	}
 
-	// If state is reachable via many epsilon transitions, it's likely ambiguous
-	if len(closure) > 3 {
-		return len(closure)
+	total := 0
+	for state, paths := range a.nfa.EpsilonPathCountsWithLimit(from, limit) {
+		for _, trans := range state.Transitions {
+			if trans.IsEpsilon || trans.Label.Type == parser.TransitionAnchor {
+				continue
+			}
+			total += paths
+			if total >= limit {
+				return limit
+			}
+		}
 	}
+	return total
+}
 
-	return 1
+// pathThreshold returns the number of epsilon paths a state may be entered
+// by before it is considered ambiguous.
+func (a *NFAAnalyzer) pathThreshold() int {
+	if a.maxPaths <= 0 {
+		return 1
+	}
+	return a.maxPaths
 }
 
 // isInQuantifierLoop checks if a state is part of a quantifier loop.
+// A state is in a loop only if it belongs to a non-trivial strongly
+// connected component of the NFA, not merely if a cycle is reachable from it.
 func (a *NFAAnalyzer) isInQuantifierLoop(state *parser.State) bool {
-	// Check if state has epsilon transitions that form a cycle
-	visited := make(map[*parser.State]bool)
-	return a.hasCycle(state, visited)
+	_, ok := a.loopStates[state.ID]
+	return ok
 }
 
-// hasCycle checks if there's a cycle reachable from the given state.
-func (a *NFAAnalyzer) hasCycle(state *parser.State, visited map[*parser.State]bool) bool {
-	if visited[state] {
-		return true // Found a cycle
+// hasOverlappingLoopPaths checks if two different consuming transitions inside
+// the state's loop are reachable from it without consuming input and accept a
+// common character. Alternations such as (ab|cd)* loop without ambiguity
+// because each branch starts with a different character.
+func (a *NFAAnalyzer) hasOverlappingLoopPaths(state *parser.State) bool {
+	loop := a.loopStates[state.ID]
+
+	var consuming []*parser.Transition
+	for s := range parser.ComputeEpsilonClosure(state) {
+		for _, trans := range s.Transitions {
+			if trans.IsEpsilon || trans.Label.Type == parser.TransitionAnchor {
+				continue
+			}
+			if target, ok := a.loopStates[trans.To.ID]; !ok || target != loop {
+				continue
+			}
+			consuming = append(consuming, trans)
+		}
+	}
+
+	for i := 0; i < len(consuming); i++ {
+		for j := i + 1; j < len(consuming); j++ {
+			if labelsOverlap(consuming[i].Label, consuming[j].Label) {
+				return true
+			}
+		}
 	}
 
-	visited[state] = true
+	return false
+}
 
-	for _, next := range state.EpsilonTo {
-		if a.hasCycle(next, visited) {
-			return true
+// labelsOverlap checks if two consuming transition labels accept a common rune.
+func labelsOverlap(x, y parser.TransitionLabel) bool {
+	if x.Type == parser.TransitionAny || y.Type == parser.TransitionAny {
+		return true
+	}
+
+	for _, rx := range labelRanges(x) {
+		for _, ry := range labelRanges(y) {
+			if rx.Lo <= ry.Hi && ry.Lo <= rx.Hi {
+				return true
+			}
 		}
 	}
 
-	delete(visited, state) // Backtrack
 	return false
 }
 
+func labelRanges(label parser.TransitionLabel) []parser.RuneRange {
+	if label.Type == parser.TransitionClass && label.Class != nil {
+		return label.Class.Ranges
+	}
+
+	ranges := make([]parser.RuneRange, len(label.Runes))
+	for i, r := range label.Runes {
+		ranges[i] = parser.RuneRange{Lo: r, Hi: r}
+	}
+	return ranges
+}
+
 // findNestedQuantifiersInNFA finds nested quantifiers using AST traversal.
 func (a *NFAAnalyzer) findNestedQuantifiersInNFA(re *syntax.Regexp) []string {
 	var nested []string
 
-	parser.Walk(re, func(node *syntax.Regexp) bool {
+	a.walk(re, func(node *syntax.Regexp) bool {
 		if !parser.IsQuantifier(node) {
 			return true
 		}
@@ -218,7 +392,7 @@ func (a *NFAAnalyzer) findOverlappingQuantifierSequences(re *syntax.Regexp) [][]
 	var currentSeq []string
 
 	// Walk the AST looking for consecutive quantifiers
-	parser.Walk(re, func(node *syntax.Regexp) bool {
+	a.walk(re, func(node *syntax.Regexp) bool {
 		if node.Op == syntax.OpConcat {
 			// Check children for quantifier sequences
 			currentSeq = []string{}
@@ -264,6 +438,16 @@ func (a *NFAAnalyzer) quantifiersCanOverlap(q1, q2 *syntax.Regexp) bool {
 	return true
 }
 
+// polynomialExample builds an example input from a polynomial ambiguity
+// witness of the NFA, or returns fallback if no witness is found.
+func polynomialExample(ctx context.Context, nfa *parser.NFA, degree int, fallback string) string {
+	prefix, pump1, pump2, suffix, ok := ambiguity.PolynomialWitnessContext(ctx, nfa, degree)
+	if !ok {
+		return fallback
+	}
+	return prefix + strings.Repeat(pump1, idaExamplePumps) + strings.Repeat(pump2, idaExamplePumps) + suffix
+}
+
 // generateEDAExample generates an example input that triggers EDA.
 func (a *NFAAnalyzer) generateEDAExample(state *parser.State) string {
 	// Generate string that would cause exponential backtracking
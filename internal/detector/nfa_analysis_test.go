@@ -1,8 +1,11 @@
 package detector
 
 import (
+	"context"
+	"strings"
 	"testing"
 
+	"github.com/theakshaypant/regret/internal/ambiguity"
 	"github.com/theakshaypant/regret/internal/parser"
 )
 
@@ -84,6 +87,41 @@ func TestNFAAnalyzer_AnalyzePattern(t *testing.T) {
 	}
 }
 
+func TestNFAAnalyzer_AnalyzePatternWithTimeout(t *testing.T) {
+	re := parser.NewParser().MustParse("(a+)+")
+	analyzer := NewNFAAnalyzer()
+
+	issues, err := analyzer.AnalyzePatternWithTimeout(context.Background(), re, "(a+)+")
+	if err != nil {
+		t.Fatalf("AnalyzePatternWithTimeout() error = %v", err)
+	}
+	for _, issue := range issues {
+		if issue.Severity == "info" {
+			t.Errorf("unexpected incomplete-analysis issue: %+v", issue)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	issues, err = analyzer.AnalyzePatternWithTimeout(ctx, re, "(a+)+")
+	if err != nil {
+		t.Fatalf("AnalyzePatternWithTimeout() error = %v", err)
+	}
+	if len(issues) == 0 {
+		t.Fatal("AnalyzePatternWithTimeout() returned no issues for an expired context")
+	}
+	last := issues[len(issues)-1]
+	if last.Severity != "info" || !strings.Contains(last.Message, "incomplete") {
+		t.Errorf("last issue = %+v, want info issue noting incomplete analysis", last)
+	}
+
+	// The context does not outlive the call
+	if analyzer.done() {
+		t.Error("analyzer still uses the expired context after the call")
+	}
+}
+
 func TestNFAAnalyzer_DetectEDA(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -163,6 +201,61 @@ func TestNFAAnalyzer_DetectIDA(t *testing.T) {
 	}
 }
 
+func TestNFAAnalyzer_IDAWitnessExample(t *testing.T) {
+	tests := []struct {
+		pattern                      string
+		prefix, pump1, pump2, suffix string
+		witness                      bool
+		example                      string
+	}{
+		{"^a*a*$", "a", "a", "a", "!", true, "a" + strings.Repeat("a", 2*idaExamplePumps) + "!"},
+		{`^id=\d+\d+$`, "id=0", "0", "0", "!", true, "id=0" + strings.Repeat("0", 2*idaExamplePumps) + "!"},
+		// No witness: a and b loops never read the same input, so the
+		// example falls back to the generic one
+		{"^a+b+$", "", "", "", "", false, "aaaaaaax"},
+	}
+
+	p := parser.NewParser()
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re, err := p.Parse(tt.pattern)
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+
+			nfa, err := parser.BuildNFA(re)
+			if err != nil {
+				t.Fatalf("BuildNFA error: %v", err)
+			}
+			prefix, pump1, pump2, suffix, ok := ambiguity.PolynomialWitness(nfa, 2)
+			if ok != tt.witness || prefix != tt.prefix || pump1 != tt.pump1 || pump2 != tt.pump2 || suffix != tt.suffix {
+				t.Errorf("PolynomialWitness() = (%q, %q, %q, %q, %v), want (%q, %q, %q, %q, %v)",
+					prefix, pump1, pump2, suffix, ok, tt.prefix, tt.pump1, tt.pump2, tt.suffix, tt.witness)
+			}
+
+			issues, err := NewNFAAnalyzer().AnalyzePattern(re, tt.pattern)
+			if err != nil {
+				t.Fatalf("AnalyzePattern error: %v", err)
+			}
+
+			found := false
+			for _, issue := range issues {
+				if issue.Type != "polynomial_backtracking" {
+					continue
+				}
+				found = true
+				if issue.Example != tt.example {
+					t.Errorf("Example = %q, want %q", issue.Example, tt.example)
+				}
+			}
+			if !found {
+				t.Errorf("AnalyzePattern(%q) reported no polynomial_backtracking issue", tt.pattern)
+			}
+		})
+	}
+}
+
 func TestNFAAnalyzer_ComputeAmbiguityDegree(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -305,3 +398,84 @@ func TestNFAAnalyzer_FindNestedQuantifiers(t *testing.T) {
 		})
 	}
 }
+
+func TestNFAAnalyzer_LoopAmbiguity(t *testing.T) {
+	tests := []struct {
+		name      string
+		pattern   string
+		expectEDA bool
+	}{
+		{"disjoint alternation in loop", "(ab|cd)*", false},
+		{"anchored alternation in loop", "^(GET|POST|PUT)+$", false},
+		{"overlapping alternation in loop", "(a.|.b)*", true},
+		{"overlapping classes in loop", `(\w|\d\s)+`, true},
+	}
+
+	analyzer := NewNFAAnalyzer()
+	p := parser.NewParser()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re, err := p.Parse(tt.pattern)
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+
+			issues, err := analyzer.AnalyzePattern(re, tt.pattern)
+			if err != nil {
+				t.Fatalf("AnalyzePattern() error = %v", err)
+			}
+
+			hasEDA := false
+			for _, issue := range issues {
+				if issue.Type == "exponential_backtracking" {
+					hasEDA = true
+				}
+			}
+			if hasEDA != tt.expectEDA {
+				t.Errorf("AnalyzePattern(%q) EDA = %v, want %v", tt.pattern, hasEDA, tt.expectEDA)
+			}
+		})
+	}
+}
+
+func TestNFAAnalyzer_FindAmbiguousStates(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		maxPaths int
+		want     bool
+	}{
+		{"abc", 1, false},
+		{"ab|cd", 1, true}, // two paths out of the start state
+		{"(a*)*", 1, true}, // several epsilon paths to the same transition
+		{"ab|cd", 2, false},
+		{"ab|cd|ef", 2, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			nfa, err := parser.BuildNFA(parser.NewParser().MustParse(tt.pattern))
+			if err != nil {
+				t.Fatalf("BuildNFA() error = %v", err)
+			}
+			a := NewNFAAnalyzer()
+			a.nfa = nfa
+
+			if got := len(a.findAmbiguousStates(tt.maxPaths)) > 0; got != tt.want {
+				t.Errorf("findAmbiguousStates(%q) with maxPaths %d found states = %v, want %v", tt.pattern, tt.maxPaths, got, tt.want)
+			}
+			if nfa.MaxEpsilonPaths != 0 {
+				t.Errorf("findAmbiguousStates(%q) set the NFA's MaxEpsilonPaths to %d", tt.pattern, nfa.MaxEpsilonPaths)
+			}
+		})
+	}
+}
+
+func TestNewDetector_MaxEpsilonPaths(t *testing.T) {
+	for _, tt := range []struct{ maxPaths, want int }{{0, 1}, {3, 3}} {
+		d := NewDetector(&Options{MaxEpsilonPaths: tt.maxPaths})
+		if got := d.nfaAnalyzer.pathThreshold(); got != tt.want {
+			t.Errorf("NewDetector(MaxEpsilonPaths: %d) path threshold = %d, want %d", tt.maxPaths, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"regexp/syntax"
+
+	"github.com/theakshaypant/regret/internal/parser"
+)
+
+// detectPolynomialDegree finds maximal runs of adjacent unbounded
+// quantifiers, like \d*\w*[a-z0-9]+, whose character classes pairwise
+// intersect. An input of k characters can be split between the d
+// quantifiers of a run in O(k^d) ways, all of which a backtracking engine
+// tries before the rest of the pattern fails, so the length of the run is
+// the polynomial degree. It is reported in Details["degree"].
+func (d *Detector) detectPolynomialDegree(ctx context.Context, re *syntax.Regexp, pattern string) []Issue {
+	var issues []Issue
+
+	// Examples come from a polynomial ambiguity witness when one exists.
+	// The NFA is only built once a run is found.
+	var nfa *parser.NFA
+	nfaBuilt := false
+	example := func(degree int) string {
+		if !nfaBuilt {
+			nfaBuilt = true
+			nfa, _ = parser.BuildNFAWithLimit(re, d.opts.MaxNFAStates)
+		}
+		if nfa == nil {
+			return "aaaaaaax"
+		}
+		return polynomialExample(ctx, nfa, degree, "aaaaaaax")
+	}
+
+	d.walk(ctx, re, func(node *syntax.Regexp) bool {
+		if node.Op != syntax.OpConcat {
+			return true
+		}
+		for _, run := range overlappingQuantifierRuns(node.Sub) {
+			degree := len(run)
+			start, _ := parser.PositionOf(run[0], pattern)
+			_, end := parser.PositionOf(run[degree-1], pattern)
+			if start < 0 || end < start {
+				start, end = 0, len(pattern)
+			}
+
+			issues = append(issues, Issue{
+				Type:       "polynomial_backtracking",
+				Severity:   "high",
+				Position:   Position{Start: start, End: end},
+				Pattern:    pattern[start:end],
+				Message:    fmt.Sprintf("%d adjacent quantifiers match overlapping characters: %s", degree, polynomialClass(degree)),
+				Example:    example(degree),
+				Suggestion: "Merge the overlapping quantifiers into one, or make their character classes disjoint",
+				Complexity: min(50+degree*10, 90),
+				Details:    map[string]interface{}{"degree": degree},
+			})
+		}
+		return true
+	})
+
+	return issues
+}
+
+// overlappingQuantifierRuns returns the maximal runs of at least two
+// adjacent unbounded single-character quantifiers in subs whose character
+// classes pairwise intersect.
+func overlappingQuantifierRuns(subs []*syntax.Regexp) [][]*syntax.Regexp {
+	var runs [][]*syntax.Regexp
+	var run []*syntax.Regexp
+	var classes []*parser.CharClass
+
+	flush := func() {
+		if len(run) >= 2 {
+			runs = append(runs, run)
+		}
+		run, classes = nil, nil
+	}
+
+	for _, sub := range subs {
+		class, ok := unboundedCharClass(sub)
+		if !ok {
+			flush()
+			continue
+		}
+		// A quantifier that does not overlap the whole run ends it; the
+		// next run starts after the last quantifier it does not overlap
+		keep := len(classes)
+		for keep > 0 && classes[keep-1].Intersects(class) {
+			keep--
+		}
+		if keep > 0 {
+			restRun := append([]*syntax.Regexp(nil), run[keep:]...)
+			restClasses := append([]*parser.CharClass(nil), classes[keep:]...)
+			flush()
+			run, classes = restRun, restClasses
+		}
+		run = append(run, sub)
+		classes = append(classes, class)
+	}
+	flush()
+
+	return runs
+}
+
+// unboundedCharClass reports whether re, possibly inside capture groups,
+// is an unbounded repetition of a single character, like a*, \d+ or
+// [a-z]{2,}, and returns the characters it repeats.
+func unboundedCharClass(re *syntax.Regexp) (*parser.CharClass, bool) {
+	for re.Op == syntax.OpCapture {
+		re = re.Sub[0]
+	}
+	if re.Op != syntax.OpStar && re.Op != syntax.OpPlus && (re.Op != syntax.OpRepeat || re.Max != -1) {
+		return nil, false
+	}
+	body := re.Sub[0]
+	switch {
+	case body.Op == syntax.OpLiteral && len(body.Rune) == 1,
+		body.Op == syntax.OpCharClass, body.Op == syntax.OpAnyChar, body.Op == syntax.OpAnyCharNotNL:
+		return &parser.CharClass{Ranges: parser.Charset(body)}, true
+	}
+	return nil, false
+}
+
+// polynomialClass returns the time complexity of polynomial backtracking
+// of the given degree.
+func polynomialClass(degree int) string {
+	switch degree {
+	case 2:
+		return "O(n²)"
+	case 3:
+		return "O(n³)"
+	default:
+		return "O(n^k)"
+	}
+}
@@ -0,0 +1,111 @@
+package detector
+
+import (
+	"fmt"
+	"regexp/syntax"
+)
+
+// Trace is a log of the decisions made while analyzing a pattern, for
+// understanding why a pattern was or was not flagged.
+type Trace struct {
+	Events []TraceEvent
+}
+
+// TraceEvent is one decision of the analysis.
+type TraceEvent struct {
+	// Kind is "phase" when a group of checks starts, "check" when a check
+	// runs or is skipped, "visit" when a check visits an AST node,
+	// "threshold" when a value is compared with a limit and "issue" when
+	// an issue is reported.
+	Kind string
+
+	// Check is the check the event belongs to, empty for phases.
+	Check string
+
+	// Node is the visited node or the pattern of the issue, if any.
+	Node string
+
+	// Message describes the decision.
+	Message string
+}
+
+// DetectWithTrace is like Detect but also returns a trace of the checks
+// that ran, the nodes they visited, the thresholds they compared and the
+// issues they reported. Tracing slows analysis down, so it is meant for
+// debugging false positives rather than routine validation.
+func (d *Detector) DetectWithTrace(re *syntax.Regexp, pattern string) ([]Issue, Trace, error) {
+	var trace Trace
+	d.trace = &trace
+	defer func() {
+		d.trace = nil
+		d.check = ""
+	}()
+
+	issues, err := d.Detect(re, pattern)
+	return issues, trace, err
+}
+
+// record appends an event to the trace of the current DetectWithTrace
+// call, if any.
+func (d *Detector) record(kind, node, format string, args ...interface{}) {
+	if d.trace == nil {
+		return
+	}
+	d.trace.Events = append(d.trace.Events, TraceEvent{
+		Kind:    kind,
+		Check:   d.check,
+		Node:    node,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// recordIssues records the issues reported by the running phase.
+func (d *Detector) recordIssues(issues []Issue) {
+	if d.trace == nil {
+		return
+	}
+	for _, issue := range issues {
+		message := fmt.Sprintf("%s %s: %s", issue.Severity, issue.Type, issue.Message)
+		if ctx, ok := issue.Details["context"]; ok {
+			message += fmt.Sprintf(" (context: %v)", ctx)
+		}
+		d.record("issue", issue.Pattern, "%s", message)
+	}
+}
+
+// runs reports whether the check flag is set, like enabled, and records
+// in the trace that the named check runs or is skipped. A zero flag is for
+// checks that always run.
+func (d *Detector) runs(name string, flag uint32) bool {
+	d.check = name
+	if flag != 0 && !d.enabled(flag) {
+		d.record("check", "", "skipped: disabled in Options.Checks")
+		return false
+	}
+	d.record("check", "", "running")
+	return true
+}
+
+// exceeds reports whether value is over limit, recording the comparison
+// in the trace.
+func (d *Detector) exceeds(what string, value, limit int) bool {
+	return d.compare(what, value, limit, value > limit)
+}
+
+// reaches is like exceeds but also reports values equal to limit.
+func (d *Detector) reaches(what string, value, limit int) bool {
+	return d.compare(what, value, limit, value >= limit)
+}
+
+// compare records the outcome over of comparing value to limit in the
+// trace, and returns it.
+func (d *Detector) compare(what string, value, limit int, over bool) bool {
+	if d.trace != nil {
+		result := "within"
+		if over {
+			result = "exceeded"
+		}
+		d.record("threshold", "", "%s %d, threshold %d: %s", what, value, limit, result)
+	}
+	return over
+}
@@ -0,0 +1,123 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"regexp/syntax"
+	"sync"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/theakshaypant/regret/internal/parser"
+)
+
+// maxDecomposable is above the last character with a canonical
+// decomposition, U+2FA1D in the CJK compatibility ideographs supplement.
+const maxDecomposable = 0x2FFFF
+
+var (
+	decomposableOnce   sync.Once
+	decomposableRanges []parser.RuneRange
+)
+
+// decomposable returns the characters that have a canonical decomposition,
+// such as U+00E9 é, which NFD spells as e followed by U+0301. They are
+// computed once.
+func decomposable() []parser.RuneRange {
+	decomposableOnce.Do(func() {
+		for r := rune(0xC0); r <= maxDecomposable; r++ {
+			if norm.NFD.IsNormalString(string(r)) {
+				continue
+			}
+			if n := len(decomposableRanges); n > 0 && decomposableRanges[n-1].Hi == r-1 {
+				decomposableRanges[n-1].Hi = r
+			} else {
+				decomposableRanges = append(decomposableRanges, parser.RuneRange{Lo: r, Hi: r})
+			}
+		}
+	})
+	return decomposableRanges
+}
+
+// detectUnicodeAmbiguity finds literals and character classes that match
+// characters with canonical decompositions, like [àáâ]+. Such a character
+// only matches input in NFC, its composed form: the same text in NFD, as
+// produced by some platforms and input methods, spells it with a
+// combining mark and does not match. Classes that also match every
+// character of the decomposition, like . or [^a], are not reported.
+func (d *Detector) detectUnicodeAmbiguity(ctx context.Context, re *syntax.Regexp, pattern string) []Issue {
+	var issues []Issue
+
+	d.walk(ctx, re, func(node *syntax.Regexp) bool {
+		if node.Op != syntax.OpLiteral && node.Op != syntax.OpCharClass {
+			return true
+		}
+		r, ok := firstDecomposable(parser.Charset(node), node)
+		if !ok {
+			return true
+		}
+
+		start, end := parser.PositionOf(node, pattern)
+		kind := "Character class"
+		if node.Op == syntax.OpLiteral {
+			kind = "Literal"
+		}
+		issues = append(issues, Issue{
+			Type:     "unicode_ambiguity",
+			Severity: "low",
+			Position: Position{Start: start, End: end},
+			Pattern:  pattern[start:end],
+			Message: fmt.Sprintf("%s matches characters with canonical decompositions, such as %U %q, which NFD input spells as %+q",
+				kind, r, r, norm.NFD.String(string(r))),
+			Example:    norm.NFD.String(string(r)),
+			Suggestion: "Normalize input to NFC with golang.org/x/text/unicode/norm before matching, or also match the decomposed forms",
+			Details: map[string]interface{}{
+				"character":  fmt.Sprintf("%U", r),
+				"decomposed": norm.NFD.String(string(r)),
+			},
+		})
+		return true
+	})
+
+	return issues
+}
+
+// firstDecomposable returns the first character of ranges, the charset of
+// node, that has a canonical decomposition node does not also match.
+func firstDecomposable(ranges []parser.RuneRange, node *syntax.Regexp) (rune, bool) {
+	found, ok := rune(0), false
+	for _, x := range ranges {
+		if x.Hi < 0xC0 || (ok && x.Lo >= found) {
+			continue
+		}
+		for _, y := range decomposable() {
+			if y.Lo > x.Hi || (ok && y.Lo >= found) {
+				break
+			}
+			for r := max(x.Lo, y.Lo); r <= min(x.Hi, y.Hi); r++ {
+				if !matchesAll(ranges, norm.NFD.String(string(r))) {
+					found, ok = r, true
+					break
+				}
+			}
+		}
+	}
+	return found, ok
+}
+
+// matchesAll reports whether every character of s is in ranges.
+func matchesAll(ranges []parser.RuneRange, s string) bool {
+	for _, r := range s {
+		in := false
+		for _, x := range ranges {
+			if x.Lo <= r && r <= x.Hi {
+				in = true
+				break
+			}
+		}
+		if !in {
+			return false
+		}
+	}
+	return true
+}
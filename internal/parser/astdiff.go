@@ -0,0 +1,166 @@
+package parser
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"slices"
+)
+
+// ASTOp is the kind of an ASTChange.
+type ASTOp int
+
+const (
+	// ASTAdded means After was inserted into the second AST.
+	ASTAdded ASTOp = iota
+
+	// ASTRemoved means Before was deleted from the first AST.
+	ASTRemoved
+
+	// ASTModified means Before was replaced by After.
+	ASTModified
+)
+
+func (op ASTOp) String() string {
+	switch op {
+	case ASTAdded:
+		return "added"
+	case ASTRemoved:
+		return "removed"
+	default:
+		return "modified"
+	}
+}
+
+// ASTChange is one difference between two regex ASTs.
+type ASTChange struct {
+	Op ASTOp
+
+	// Path lists the child indices from the root to the changed node: in
+	// the second AST for added nodes, and in the first AST otherwise.
+	Path []int
+
+	// Before is the node of the first AST, nil if Op is ASTAdded. After is
+	// the node of the second AST, nil if Op is ASTRemoved.
+	Before, After *syntax.Regexp
+}
+
+func (c ASTChange) String() string {
+	switch c.Op {
+	case ASTAdded:
+		return fmt.Sprintf("added %v: %s", c.Path, c.After)
+	case ASTRemoved:
+		return fmt.Sprintf("removed %v: %s", c.Path, c.Before)
+	default:
+		return fmt.Sprintf("modified %v: %s → %s", c.Path, c.Before, c.After)
+	}
+}
+
+// ASTDiff returns the changes that turn the AST a into b, in the order of a
+// preorder walk, or nil if they are equal.
+//
+// The diff is a top-down tree edit distance (Selkow's): nodes that differ
+// in anything but their children are reported as one ASTModified change
+// for the whole subtree, while the children of nodes that only differ
+// below them are aligned like the lines of a text diff, with an insertion,
+// deletion or modification costing 1. Aligning two lists of children
+// compares each pair of them once, so the diff makes O(n²) subtree
+// comparisons for ASTs of n nodes.
+//
+// Example:
+//
+//	a, _ := p.ParseRaw(`(a+)+b`)
+//	b, _ := p.ParseRaw(`a+b`)
+//	for _, change := range parser.ASTDiff(a, b) {
+//	    fmt.Println(change) // modified [0]: (a+)+ → a+
+//	}
+func ASTDiff(a, b *syntax.Regexp) []ASTChange {
+	var changes []ASTChange
+	diffNodes(a, b, nil, nil, &changes)
+	return changes
+}
+
+// diffNodes appends the changes from a to b, found at pathA and pathB.
+func diffNodes(a, b *syntax.Regexp, pathA, pathB []int, changes *[]ASTChange) {
+	switch {
+	case a == nil && b == nil:
+		return
+	case a == nil:
+		*changes = append(*changes, ASTChange{Op: ASTAdded, Path: pathB, After: b})
+		return
+	case b == nil:
+		*changes = append(*changes, ASTChange{Op: ASTRemoved, Path: pathA, Before: a})
+		return
+	case a.Equal(b):
+		return
+	case !sameNode(a, b):
+		*changes = append(*changes, ASTChange{Op: ASTModified, Path: pathA, Before: a, After: b})
+		return
+	}
+
+	// Align the children by edit distance: cost[i][j] is the cost of
+	// turning a.Sub[i:] into b.Sub[j:], and replace[i][j] the cost of
+	// turning a.Sub[i] into b.Sub[j]
+	m, n := len(a.Sub), len(b.Sub)
+	cost := make([][]int, m+1)
+	replace := make([][]int, m)
+	for i := range cost {
+		cost[i] = make([]int, n+1)
+		if i < m {
+			replace[i] = make([]int, n)
+			for j := range replace[i] {
+				if !a.Sub[i].Equal(b.Sub[j]) {
+					replace[i][j] = 1
+				}
+			}
+		}
+	}
+	for i := m; i >= 0; i-- {
+		for j := n; j >= 0; j-- {
+			switch {
+			case i == m:
+				cost[i][j] = n - j
+			case j == n:
+				cost[i][j] = m - i
+			default:
+				cost[i][j] = min(cost[i+1][j+1]+replace[i][j], cost[i+1][j]+1, cost[i][j+1]+1)
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < m || j < n {
+		switch {
+		case i < m && j < n && cost[i][j] == cost[i+1][j+1]+replace[i][j]:
+			diffNodes(a.Sub[i], b.Sub[j], childPath(pathA, i), childPath(pathB, j), changes)
+			i, j = i+1, j+1
+		case i < m && (j == n || cost[i][j] == cost[i+1][j]+1):
+			diffNodes(a.Sub[i], nil, childPath(pathA, i), nil, changes)
+			i++
+		default:
+			diffNodes(nil, b.Sub[j], nil, childPath(pathB, j), changes)
+			j++
+		}
+	}
+}
+
+// sameNode reports whether a and b are equal apart from their children.
+func sameNode(a, b *syntax.Regexp) bool {
+	if a.Op != b.Op || a.Flags != b.Flags {
+		return false
+	}
+	switch a.Op {
+	case syntax.OpLiteral, syntax.OpCharClass:
+		return slices.Equal(a.Rune, b.Rune)
+	case syntax.OpRepeat:
+		return a.Min == b.Min && a.Max == b.Max
+	case syntax.OpCapture:
+		return a.Cap == b.Cap && a.Name == b.Name
+	}
+	return true
+}
+
+// childPath returns a copy of path with index appended, so that paths of
+// siblings do not share storage.
+func childPath(path []int, index int) []int {
+	return append(slices.Clip(path), index)
+}
@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"regexp/syntax"
+	"testing"
+)
+
+func TestASTDiff(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want []string
+	}{
+		{"abc", "abc", nil},
+		{"(a+)+b", "a+b", []string{"modified [0 0]: (a+) → a"}},
+		{"x(a|b)y", "x(a|b)", []string{"removed [2]: y"}},
+		{"x(a|b)", "x(a|b)y", []string{"added [2]: y"}},
+		{`^\d+-\d+$`, `^\d+-\w+$`, []string{"modified [3 0]: [0-9] → [0-9A-Z_a-z]"}},
+		{"a{2,5}", "a{2,}", []string{"modified []: a{2,5} → a{2,}"}},
+		{"foo|bar", "foo|qux|bar", []string{"added [1]: qux"}},
+	}
+
+	p := NewParser()
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			a, err := p.ParseRaw(tt.a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := p.ParseRaw(tt.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			changes := ASTDiff(a, b)
+			if len(changes) != len(tt.want) {
+				t.Fatalf("ASTDiff() = %v, want %q", changes, tt.want)
+			}
+			for i, change := range changes {
+				if got := change.String(); got != tt.want[i] {
+					t.Errorf("change %d = %q, want %q", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestASTDiff_Paths(t *testing.T) {
+	p := NewParser()
+	a, _ := p.ParseRaw("ab(c|d)e")
+	b, _ := p.ParseRaw("xb(c|f)")
+
+	changes := ASTDiff(a, b)
+	if len(changes) != 3 {
+		t.Fatalf("ASTDiff() = %v, want 3 changes", changes)
+	}
+	for _, change := range changes {
+		if change.Op == ASTAdded {
+			if got := nodeAt(b, change.Path); got != change.After {
+				t.Errorf("%v: node at path in b = %v, want %v", change, got, change.After)
+			}
+		} else if got := nodeAt(a, change.Path); got != change.Before {
+			t.Errorf("%v: node at path in a = %v, want %v", change, got, change.Before)
+		}
+	}
+}
+
+func nodeAt(re *syntax.Regexp, path []int) *syntax.Regexp {
+	for _, i := range path {
+		re = re.Sub[i]
+	}
+	return re
+}
@@ -0,0 +1,114 @@
+package parser
+
+import (
+	"regexp/syntax"
+	"strings"
+)
+
+// Backreference is a \1 to \9 escape in a pattern, which matches the text
+// last captured by a group. Go's syntax rejects backreferences, but
+// backtracking engines such as PCRE support them.
+type Backreference struct {
+	Group int      // Number of the capturing group referred to
+	Pos   Position // Byte range of the escape in the pattern
+}
+
+// FindBackreferences returns the backreferences in pattern, skipping
+// character classes. Only single-digit references are recognized, since
+// Go reads escapes like \12 as octal.
+func FindBackreferences(pattern string) []Backreference {
+	var refs []Backreference
+	inClass := false
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '\\':
+			if !inClass && i+1 < len(pattern) && '1' <= pattern[i+1] && pattern[i+1] <= '9' &&
+				(i+2 == len(pattern) || pattern[i+2] < '0' || pattern[i+2] > '9') {
+				refs = append(refs, Backreference{
+					Group: int(pattern[i+1] - '0'),
+					Pos:   Position{Start: i, End: i + 2},
+				})
+			}
+			i++ // skip escaped character
+		case inClass:
+			if c == ']' {
+				inClass = false
+			}
+		case c == '[':
+			inClass = true
+			// A ']' right after '[' or '[^' is a literal
+			if i+1 < len(pattern) && pattern[i+1] == '^' {
+				i++
+			}
+			if i+1 < len(pattern) && pattern[i+1] == ']' {
+				i++
+			}
+		}
+	}
+
+	return refs
+}
+
+// ExpandBackreferences replaces every backreference in pattern with a
+// non-capturing copy of the group it refers to, so that the result parses
+// with Go's syntax. The copy matches any text the group can match, which
+// includes the text the backreference matches, so the result accepts a
+// superset of the original language. References to groups that do not
+// exist, or that have not been closed yet, are left as they are.
+func ExpandBackreferences(pattern string) string {
+	refs := FindBackreferences(pattern)
+	if len(refs) == 0 {
+		return pattern
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, ref := range refs {
+		start, end, ok := CaptureGroupSpan(pattern, ref.Group)
+		if !ok || end > ref.Pos.Start {
+			continue
+		}
+		group, err := syntax.Parse(pattern[start:end], syntax.Perl)
+		if err != nil {
+			continue
+		}
+		b.WriteString(pattern[last:ref.Pos.Start])
+		b.WriteString("(?:" + stripCaptures(group).String() + ")")
+		last = ref.Pos.End
+	}
+	b.WriteString(pattern[last:])
+
+	return b.String()
+}
+
+// MaskBackreferences replaces every backreference in pattern with \B, an
+// empty-width assertion of the same length, so that the result parses with
+// Go's syntax and byte offsets in it are offsets in pattern. Unlike
+// ExpandBackreferences, the result does not match what the references do;
+// it is meant for checks of the pattern's structure, such as its counted
+// repetitions and capture groups.
+func MaskBackreferences(pattern string) string {
+	refs := FindBackreferences(pattern)
+	if len(refs) == 0 {
+		return pattern
+	}
+
+	masked := []byte(pattern)
+	for _, ref := range refs {
+		copy(masked[ref.Pos.Start:ref.Pos.End], `\B`)
+	}
+	return string(masked)
+}
+
+// stripCaptures replaces every capturing group in re with its contents, so
+// that copies of a group do not shift the numbering of later groups.
+func stripCaptures(re *syntax.Regexp) *syntax.Regexp {
+	for re.Op == syntax.OpCapture {
+		re = re.Sub[0]
+	}
+	for i, sub := range re.Sub {
+		re.Sub[i] = stripCaptures(sub)
+	}
+	return re
+}
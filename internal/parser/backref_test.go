@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindBackreferences(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    []Backreference
+	}{
+		{`(.+)\1`, []Backreference{{Group: 1, Pos: Position{4, 6}}}},
+		{`(a)(b)\2\1`, []Backreference{{Group: 2, Pos: Position{6, 8}}, {Group: 1, Pos: Position{8, 10}}}},
+		{`\\1`, nil},    // escaped backslash
+		{`[\1]`, nil},   // inside a character class
+		{`(a)\12`, nil}, // octal escape
+		{`\0`, nil},     // not a group number
+		{`abc`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			if got := FindBackreferences(tt.pattern); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindBackreferences(%q) = %v, want %v", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandBackreferences(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{`(.+)\1+`, `(.+)(?:(?-s:.+))+`},
+		{`(a(b))-\1`, `(a(b))-(?:ab)`},
+		{`(?P<x>\d+)=\1`, `(?P<x>\d+)=(?:[0-9]+)`},
+		{`(a\1)`, `(a\1)`}, // group not closed yet
+		{`(a)\2`, `(a)\2`}, // no such group
+		{`abc`, `abc`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			if got := ExpandBackreferences(tt.pattern); got != tt.want {
+				t.Errorf("ExpandBackreferences(%q) = %q, want %q", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskBackreferences(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{`(x)\1[0-9a-f]{128}`, `(x)\B[0-9a-f]{128}`},
+		{`(a)(b)\2\1`, `(a)(b)\B\B`},
+		{`[\1](a)`, `[\1](a)`}, // in a character class
+		{`abc`, `abc`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			if got := MaskBackreferences(tt.pattern); got != tt.want {
+				t.Errorf("MaskBackreferences(%q) = %q, want %q", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
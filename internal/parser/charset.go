@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"regexp/syntax"
+	"sort"
+	"unicode"
+)
+
+// InnermostQuantifiedBody returns the body of the most deeply nested
+// repeating quantifier in re, such as a in (a+)+, or nil if re repeats
+// nothing. Optional parts (x?, x{0,1}) do not count as repeating. Among
+// quantifiers at the same depth the first one wins.
+func InnermostQuantifiedBody(re *syntax.Regexp) *syntax.Regexp {
+	var body *syntax.Regexp
+	best := 0
+
+	var visit func(node *syntax.Regexp, depth int)
+	visit = func(node *syntax.Regexp, depth int) {
+		if IsQuantifier(node) && node.Op != syntax.OpQuest && node.Max != 1 {
+			depth++
+			if depth > best {
+				best = depth
+				body = node.Sub[0]
+			}
+		}
+		for _, sub := range node.Sub {
+			visit(sub, depth)
+		}
+	}
+	visit(re, 0)
+
+	return body
+}
+
+// Charset returns the sorted, non-overlapping ranges of characters that re
+// can match at any position. Case-folded literals include their other cases.
+func Charset(re *syntax.Regexp) []RuneRange {
+	var ranges []RuneRange
+	Walk(re, func(node *syntax.Regexp) (bool, error) {
+		switch node.Op {
+		case syntax.OpLiteral:
+			for _, r := range node.Rune {
+				ranges = append(ranges, RuneRange{Lo: r, Hi: r})
+				if node.Flags&syntax.FoldCase != 0 {
+					for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+						ranges = append(ranges, RuneRange{Lo: f, Hi: f})
+					}
+				}
+			}
+		case syntax.OpCharClass:
+			for i := 0; i+1 < len(node.Rune); i += 2 {
+				ranges = append(ranges, RuneRange{Lo: node.Rune[i], Hi: node.Rune[i+1]})
+			}
+		case syntax.OpAnyChar:
+			ranges = append(ranges, RuneRange{Lo: 0, Hi: unicode.MaxRune})
+		case syntax.OpAnyCharNotNL:
+			ranges = append(ranges, RuneRange{Lo: 0, Hi: '\n' - 1}, RuneRange{Lo: '\n' + 1, Hi: unicode.MaxRune})
+		}
+		return true, nil
+	})
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Lo < ranges[j].Lo })
+	var merged []RuneRange
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && r.Lo <= merged[n-1].Hi+1 {
+			if r.Hi > merged[n-1].Hi {
+				merged[n-1].Hi = r.Hi
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// CharsetSize returns the number of distinct characters re can match at
+// any position.
+func CharsetSize(re *syntax.Regexp) int {
+	size := 0
+	for _, r := range Charset(re) {
+		size += int(r.Hi-r.Lo) + 1
+	}
+	return size
+}
+
+// SampleRune returns a character from the charset of re, preferring
+// lowercase letters, then digits, then uppercase letters, then any printable
+// character. It returns false if re matches no characters.
+func SampleRune(re *syntax.Regexp) (rune, bool) {
+	charset := Charset(re)
+	if len(charset) == 0 {
+		return 0, false
+	}
+
+	for _, want := range []RuneRange{{Lo: 'a', Hi: 'z'}, {Lo: '0', Hi: '9'}, {Lo: 'A', Hi: 'Z'}} {
+		for _, r := range charset {
+			if r.Hi >= want.Lo && r.Lo <= want.Hi {
+				return max(r.Lo, want.Lo), true
+			}
+		}
+	}
+	for _, r := range charset {
+		for c := r.Lo; c <= r.Hi && c < r.Lo+256; c++ {
+			if unicode.IsPrint(c) {
+				return c, true
+			}
+		}
+	}
+	return charset[0].Lo, true
+}
@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"testing"
+	"unicode"
+)
+
+func TestInnermostQuantifiedBody(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string // printed body, "" for none
+	}{
+		{"(a+)+", "a"},
+		{"(?:[0-9]+x)*", "[0-9]"},
+		{"a?b", ""},
+		{"abc", ""},
+		{"a*(b+)", "a"}, // same depth: first wins
+	}
+
+	for _, tt := range tests {
+		body := InnermostQuantifiedBody(NewParser().MustParse(tt.pattern))
+		got := ""
+		if body != nil {
+			got = body.String()
+		}
+		if got != tt.want {
+			t.Errorf("InnermostQuantifiedBody(%q) = %q, want %q", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestCharsetSize(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    int
+	}{
+		{"a", 1},
+		{"(?i)a", 2},
+		{"[a-z]", 26},
+		{"[a-z]|[m-z0-9]", 36},
+		{`\w`, 63},
+		{".", unicode.MaxRune},
+		{"(?s).", unicode.MaxRune + 1},
+	}
+
+	for _, tt := range tests {
+		if got := CharsetSize(NewParser().MustParse(tt.pattern)); got != tt.want {
+			t.Errorf("CharsetSize(%q) = %d, want %d", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestSampleRune(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    rune
+	}{
+		{"[0-9a-f]", 'a'},
+		{"[0-9]", '0'},
+		{"[A-Z]", 'A'},
+		{"[!-/]", '!'},
+		{".", 'a'},
+	}
+
+	for _, tt := range tests {
+		got, ok := SampleRune(NewParser().MustParse(tt.pattern))
+		if !ok || got != tt.want {
+			t.Errorf("SampleRune(%q) = %q, %v; want %q", tt.pattern, got, ok, tt.want)
+		}
+	}
+}
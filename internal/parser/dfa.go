@@ -0,0 +1,119 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+)
+
+// ErrDFATooLarge indicates subset construction stopped at the state limit.
+var ErrDFATooLarge = errors.New("DFA state limit exceeded")
+
+// DFA is a deterministic finite automaton built from an NFA by subset
+// construction. Runes are grouped into classes that every transition of the
+// NFA treats alike, and Transitions is keyed by the lowest rune of each
+// class, as listed in Alphabet. The dead state, for the empty set of NFA
+// states, is left out: a missing transition rejects the input.
+type DFA struct {
+	Start       *DFAState
+	States      []*DFAState
+	Transitions map[*DFAState]map[rune]*DFAState
+
+	// Alphabet holds the lowest rune of each class in ascending order. A
+	// class extends to the rune before the next one.
+	Alphabet []rune
+}
+
+// DFAState is a state of a DFA: the epsilon-closure of a set of NFA states.
+type DFAState struct {
+	ID        int
+	NFAStates []int // IDs of the NFA states, in ascending order
+	IsAccept  bool  // Contains the NFA's accept state
+}
+
+// DFABuilder converts NFAs to DFAs.
+type DFABuilder struct {
+	// MaxStates is the number of DFA states above which Build gives up
+	// with ErrDFATooLarge. 0 means no limit other than MaxSubsetSteps.
+	MaxStates int
+}
+
+// NewDFABuilder creates a DFA builder that stops at maxStates states.
+func NewDFABuilder(maxStates int) *DFABuilder {
+	return &DFABuilder{MaxStates: maxStates}
+}
+
+// Build returns the DFA of the NFA by the standard subset construction,
+// where each DFA state is the epsilon-closure of the NFA states reachable on
+// some input. State 0 is the start state. Anchors are ignored, as in
+// Simulate, and the DFA is not minimized (see NFA.Minimize).
+func (b *DFABuilder) Build(nfa *NFA) (*DFA, error) {
+	limit := b.MaxStates
+	if limit <= 0 {
+		limit = math.MaxInt
+	}
+
+	alphabet := nfa.representativeRunes()
+	d, err := nfa.subsetConstruction(context.Background(), alphabet, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DFA{
+		Transitions: make(map[*DFAState]map[rune]*DFAState),
+		Alphabet:    alphabet,
+	}
+	states := make([]*DFAState, len(d.trans))
+	for s := range d.trans {
+		if s == d.dead {
+			continue
+		}
+		states[s] = &DFAState{ID: len(result.States), NFAStates: d.sets[s], IsAccept: d.accept[s]}
+		result.States = append(result.States, states[s])
+	}
+	result.Start = states[0]
+
+	for s, row := range d.trans {
+		if s == d.dead {
+			continue
+		}
+		out := make(map[rune]*DFAState)
+		for c, t := range row {
+			if t != d.dead {
+				out[alphabet[c]] = states[t]
+			}
+		}
+		result.Transitions[states[s]] = out
+	}
+
+	return result, nil
+}
+
+// StateCount returns the number of states of the DFA, not counting the
+// dead state.
+func (d *DFA) StateCount() int {
+	return len(d.States)
+}
+
+// Step returns the state reached from s on r, or nil if the input is
+// rejected.
+func (d *DFA) Step(s *DFAState, r rune) *DFAState {
+	c := sort.Search(len(d.Alphabet), func(i int) bool { return d.Alphabet[i] > r }) - 1
+	if c < 0 {
+		return nil
+	}
+	return d.Transitions[s][d.Alphabet[c]]
+}
+
+// Match reports whether the DFA accepts the whole input string, reading
+// each rune once without backtracking.
+func (d *DFA) Match(input string) bool {
+	s := d.Start
+	for _, r := range input {
+		if s = d.Step(s, r); s == nil {
+			return false
+		}
+	}
+	return s != nil && s.IsAccept
+}
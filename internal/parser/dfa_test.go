@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDFABuilder_Build(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    int // DFA states without the dead state
+	}{
+		{"abc", 4},
+		{"[ab]*", 2}, // not minimized
+		{"(a|b)*abb", 5},
+		{"[ab]*a[ab]{2}", 9},
+		{"[^x]+", 2},
+	}
+
+	inputs := []string{"", "a", "b", "ab", "abb", "aab", "babb", "aaaa", "abc", "xxx", "ababa", "héllo"}
+	p := NewParser()
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			nfa, err := BuildNFA(p.MustParse(tt.pattern))
+			if err != nil {
+				t.Fatalf("BuildNFA() error = %v", err)
+			}
+			dfa, err := NewDFABuilder(0).Build(nfa)
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			if got := dfa.StateCount(); got != tt.want {
+				t.Errorf("StateCount() = %d, want %d", got, tt.want)
+			}
+			for _, input := range inputs {
+				if got, want := dfa.Match(input), nfa.Simulate(input); got != want {
+					t.Errorf("Match(%q) = %v, want %v", input, got, want)
+				}
+			}
+			for _, s := range dfa.States {
+				if len(s.NFAStates) == 0 {
+					t.Errorf("state %d has no NFA states", s.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestDFABuilder_Build_TooLarge(t *testing.T) {
+	nfa, err := BuildNFA(NewParser().MustParse("[ab]*a[ab]{8}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewDFABuilder(100).Build(nfa); !errors.Is(err, ErrDFATooLarge) {
+		t.Errorf("Build() error = %v, want ErrDFATooLarge", err)
+	}
+}
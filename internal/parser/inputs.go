@@ -0,0 +1,117 @@
+package parser
+
+import (
+	"regexp/syntax"
+	"sort"
+	"unicode"
+)
+
+// maxInputSearch is the number of partial inputs AcceptedInputs explores
+// before giving up, which bounds the search on NFAs with many branches.
+const maxInputSearch = 10000
+
+// AcceptedInputs returns up to limit distinct inputs the NFA accepts, each
+// at most maxLen runes long, shortest first. Paths through the NFA are
+// explored breadth first, reading one readable character per transition:
+// a transition on [a-z] reads 'a' and a transition on . reads 'a' too, so
+// the inputs show the structure of the pattern rather than every character
+// it accepts. Anchors are ignored, as in Simulate.
+func (nfa *NFA) AcceptedInputs(limit, maxLen int) []string {
+	type partial struct {
+		states map[*State]bool
+		input  []rune
+	}
+
+	var inputs []string
+	queue := []partial{{states: nfa.StartStates()}}
+	for explored := 0; len(queue) > 0 && len(inputs) < limit && explored < maxInputSearch; explored++ {
+		p := queue[0]
+		queue = queue[1:]
+
+		if p.states[nfa.Accept] {
+			inputs = append(inputs, string(p.input))
+		}
+		if len(p.input) >= maxLen {
+			continue
+		}
+
+		for _, r := range sampleRunes(p.states) {
+			next := nfa.SimulateStep(p.states, r)
+			if len(next) == 0 {
+				continue
+			}
+			input := append(append([]rune(nil), p.input...), r)
+			queue = append(queue, partial{states: next, input: input})
+		}
+	}
+
+	return inputs
+}
+
+// sampleRunes returns one readable character for every consuming
+// transition leaving states, without duplicates, in transition order.
+func sampleRunes(states map[*State]bool) []rune {
+	// Visit states in ID order so that the inputs are deterministic
+	ordered := make([]*State, 0, len(states))
+	for state := range states {
+		ordered = append(ordered, state)
+	}
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].ID < ordered[j].ID })
+
+	var runes []rune
+	seen := make(map[rune]bool)
+	for _, state := range ordered {
+		for _, t := range state.Transitions {
+			r, ok := t.Label.sample()
+			if ok && !seen[r] {
+				seen[r] = true
+				runes = append(runes, r)
+			}
+		}
+	}
+	return runes
+}
+
+// sample returns a readable character accepted by a consuming transition
+// label, preferring lowercase letters, then digits, then uppercase letters,
+// then any printable character. It returns false for epsilon and anchor
+// labels.
+func (l TransitionLabel) sample() (rune, bool) {
+	var ranges []RuneRange
+	switch l.Type {
+	case TransitionLiteral:
+		if len(l.Runes) == 0 {
+			return 0, false
+		}
+		return l.Runes[0], true
+	case TransitionClass:
+		if l.Class == nil || len(l.Class.Ranges) == 0 {
+			return 0, false
+		}
+		ranges = l.Class.Ranges
+	case TransitionAny:
+		if l.Op == syntax.OpAnyCharNotNL {
+			ranges = []RuneRange{{Lo: 0, Hi: '\n' - 1}, {Lo: '\n' + 1, Hi: unicode.MaxRune}}
+		} else {
+			ranges = []RuneRange{{Lo: 0, Hi: unicode.MaxRune}}
+		}
+	default:
+		return 0, false
+	}
+
+	for _, want := range []RuneRange{{Lo: 'a', Hi: 'z'}, {Lo: '0', Hi: '9'}, {Lo: 'A', Hi: 'Z'}} {
+		for _, r := range ranges {
+			if r.Hi >= want.Lo && r.Lo <= want.Hi {
+				return max(r.Lo, want.Lo), true
+			}
+		}
+	}
+	for _, r := range ranges {
+		for c := r.Lo; c <= r.Hi && c < r.Lo+256; c++ {
+			if unicode.IsPrint(c) {
+				return c, true
+			}
+		}
+	}
+	return ranges[0].Lo, true
+}
@@ -0,0 +1,42 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNFA_AcceptedInputs(t *testing.T) {
+	tests := []struct {
+		pattern string
+		limit   int
+		maxLen  int
+		want    []string
+	}{
+		{"abc", 5, 10, []string{"abc"}},
+		{"a+", 3, 10, []string{"a", "aa", "aaa"}},
+		{"a*", 3, 10, []string{"", "a", "aa"}},
+		{"[0-9]{2}", 5, 10, []string{"00"}},
+		{"ab|cd", 5, 10, []string{"ab", "cd"}},
+		{"a+", 10, 2, []string{"a", "aa"}},
+		{"abc", 5, 2, nil},
+	}
+
+	p := NewParser()
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			nfa, err := BuildNFA(p.MustParse(tt.pattern))
+			if err != nil {
+				t.Fatalf("BuildNFA() error = %v", err)
+			}
+			got := nfa.AcceptedInputs(tt.limit, tt.maxLen)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AcceptedInputs() = %q, want %q", got, tt.want)
+			}
+			for _, input := range got {
+				if !nfa.Simulate(input) {
+					t.Errorf("NFA does not accept %q", input)
+				}
+			}
+		})
+	}
+}
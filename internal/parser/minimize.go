@@ -0,0 +1,320 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"unicode"
+)
+
+// MaxMinimizeStates is the number of DFA states above which Minimize gives up.
+const MaxMinimizeStates = 10000
+
+// MaxSubsetSteps bounds the work of subset construction: the number of DFA
+// states times the number of rune classes they have transitions on. Past
+// it, subset construction gives up with ErrDFATooLarge.
+const MaxSubsetSteps = 100000
+
+// Minimize returns the minimum-state DFA equivalent to the NFA, built by
+// subset construction and Hopcroft's partition refinement. The DFA is
+// returned as an NFA whose states have at most one transition per rune and
+// no epsilon transitions, except that every accepting state has an epsilon
+// transition to a separate Accept state, since an NFA has a single accept
+// state. The dead state, from which no input is accepted, is left out, so
+// the DFA has StateCount-1 states.
+//
+// Anchors are ignored, as in Simulate. Minimize returns nil if subset
+// construction needs more than MaxMinimizeStates states or MaxSubsetSteps
+// steps.
+func (nfa *NFA) Minimize() *NFA {
+	return nfa.MinimizeContext(context.Background())
+}
+
+// MinimizeContext is like Minimize but also returns nil if ctx is done
+// before subset construction completes.
+func (nfa *NFA) MinimizeContext(ctx context.Context) *NFA {
+	alphabet := nfa.representativeRunes()
+	dfa, err := nfa.subsetConstruction(ctx, alphabet, MaxMinimizeStates)
+	if err != nil {
+		return nil
+	}
+	block := dfa.hopcroft(len(alphabet))
+	return dfa.build(block, alphabet)
+}
+
+// dfa is a DFA over the classes of runes given by representativeRunes.
+type dfa struct {
+	trans  [][]int // trans[s][c] is the state reached from s on class c
+	accept []bool
+	dead   int     // State for the empty set of NFA states, or -1
+	sets   [][]int // sets[s] lists the IDs of the NFA states of s
+}
+
+// subsetConstruction builds the DFA of the NFA. State 0 is the start state.
+// The runes of alphabet are grouped into equivalence classes first, so that
+// each DFA state is stepped once per class rather than once per rune. If the
+// DFA has more than limit states or needs more than MaxSubsetSteps steps, it
+// returns ErrDFATooLarge, and if ctx is done first, ctx.Err(), together with
+// the states found so far, some of them without transitions.
+func (nfa *NFA) subsetConstruction(ctx context.Context, alphabet []rune, limit int) (*dfa, error) {
+	class, reps := nfa.runeClasses(alphabet)
+	d := &dfa{dead: -1}
+	index := make(map[string]int)
+	var sets []map[*State]bool
+
+	add := func(states map[*State]bool) int {
+		k := fmt.Sprint(StateIDs(states))
+		if i, ok := index[k]; ok {
+			return i
+		}
+		i := len(sets)
+		index[k] = i
+		sets = append(sets, states)
+		d.sets = append(d.sets, StateIDs(states))
+		d.accept = append(d.accept, states[nfa.Accept])
+		if len(states) == 0 {
+			d.dead = i
+		}
+		return i
+	}
+
+	add(nfa.StartStates())
+	next := make([]int, len(reps))
+	for s := 0; s < len(sets); s++ {
+		if err := ctx.Err(); err != nil {
+			return d, err
+		}
+		if len(sets) > limit || (s+1)*len(reps) > MaxSubsetSteps {
+			return d, ErrDFATooLarge
+		}
+		for i, r := range reps {
+			next[i] = add(nfa.SimulateStep(sets[s], r))
+		}
+		row := make([]int, len(alphabet))
+		for c := range alphabet {
+			row[c] = next[class[c]]
+		}
+		d.trans = append(d.trans, row)
+	}
+
+	return d, nil
+}
+
+// runeClasses groups the runes of alphabet that every consuming transition
+// of the NFA accepts or rejects alike, like the letters of \pL in \pL*a,
+// returning the class of each rune and one rune of each class.
+func (nfa *NFA) runeClasses(alphabet []rune) (class []int, reps []rune) {
+	var labels []TransitionLabel
+	seen := make(map[string]bool)
+	for _, state := range nfa.States {
+		for _, t := range state.Transitions {
+			if t.IsEpsilon || t.Label.Type == TransitionEpsilon || t.Label.Type == TransitionAnchor {
+				continue
+			}
+			var ranges []RuneRange
+			if t.Label.Class != nil {
+				ranges = t.Label.Class.Ranges
+			}
+			k := fmt.Sprint(t.Label.Type, t.Label.Op, t.Label.Runes, ranges)
+			if !seen[k] {
+				seen[k] = true
+				labels = append(labels, t.Label)
+			}
+		}
+	}
+
+	class = make([]int, len(alphabet))
+	index := make(map[string]int)
+	signature := make([]byte, len(labels))
+	for c, r := range alphabet {
+		for i, l := range labels {
+			signature[i] = 0
+			if l.Matches(r) {
+				signature[i] = 1
+			}
+		}
+		i, ok := index[string(signature)]
+		if !ok {
+			i = len(reps)
+			index[string(signature)] = i
+			reps = append(reps, r)
+		}
+		class[c] = i
+	}
+	return class, reps
+}
+
+// hopcroft partitions the DFA states into classes of equivalent states,
+// returning the class of each state.
+func (d *dfa) hopcroft(symbols int) []int {
+	n := len(d.trans)
+
+	// inverse[c][t] lists the states that reach t on class c
+	inverse := make([][][]int, symbols)
+	for c := range inverse {
+		inverse[c] = make([][]int, n)
+	}
+	for s, row := range d.trans {
+		for c, t := range row {
+			inverse[c][t] = append(inverse[c][t], s)
+		}
+	}
+
+	// Start from accepting and rejecting states
+	block := make([]int, n)
+	var blocks [][]int
+	var accepting, rejecting []int
+	for s := 0; s < n; s++ {
+		if d.accept[s] {
+			accepting = append(accepting, s)
+		} else {
+			rejecting = append(rejecting, s)
+		}
+	}
+	for _, b := range [][]int{accepting, rejecting} {
+		if len(b) == 0 {
+			continue
+		}
+		for _, s := range b {
+			block[s] = len(blocks)
+		}
+		blocks = append(blocks, b)
+	}
+
+	// Refine the partition until no splitter divides a block
+	inWork := make([]bool, len(blocks))
+	var work []int
+	for b := range blocks {
+		work = append(work, b)
+		inWork[b] = true
+	}
+	for len(work) > 0 {
+		splitter := work[len(work)-1]
+		work = work[:len(work)-1]
+		inWork[splitter] = false
+
+		for c := 0; c < symbols; c++ {
+			// States that reach the splitter on c, grouped by block
+			reaching := make(map[int][]int)
+			for _, t := range blocks[splitter] {
+				for _, s := range inverse[c][t] {
+					reaching[block[s]] = append(reaching[block[s]], s)
+				}
+			}
+
+			for b, in := range reaching {
+				if len(in) == len(blocks[b]) {
+					continue
+				}
+				isIn := make(map[int]bool, len(in))
+				for _, s := range in {
+					isIn[s] = true
+				}
+				var out []int
+				for _, s := range blocks[b] {
+					if !isIn[s] {
+						out = append(out, s)
+					}
+				}
+
+				// in keeps block b and out becomes a new block
+				blocks[b] = in
+				nb := len(blocks)
+				blocks = append(blocks, out)
+				inWork = append(inWork, false)
+				for _, s := range out {
+					block[s] = nb
+				}
+
+				switch {
+				case inWork[b]:
+					work = append(work, nb)
+					inWork[nb] = true
+				case len(in) <= len(out):
+					work = append(work, b)
+					inWork[b] = true
+				default:
+					work = append(work, nb)
+					inWork[nb] = true
+				}
+			}
+		}
+	}
+
+	return block
+}
+
+// build returns the DFA whose states are the classes of block as an NFA,
+// leaving out the class of the dead state.
+func (d *dfa) build(block []int, alphabet []rune) *NFA {
+	result := NewNFA()
+	dead := -1
+	if d.dead >= 0 {
+		dead = block[d.dead]
+	}
+
+	// Number the classes in order of their first state, so that the
+	// class of the start state comes first
+	states := make(map[int]*State)
+	accepting := make(map[*State]bool)
+	var order []int
+	for s := range d.trans {
+		b := block[s]
+		if b == dead {
+			continue
+		}
+		if _, ok := states[b]; !ok {
+			states[b] = result.NewState()
+			accepting[states[b]] = d.accept[s]
+			order = append(order, s)
+		}
+	}
+
+	result.Accept = result.NewState()
+	result.Accept.IsAccept = true
+	if len(order) == 0 {
+		// Nothing is accepted
+		result.Start = result.NewState()
+		return result
+	}
+	result.Start = states[block[0]]
+
+	for _, s := range order {
+		from := states[block[s]]
+
+		// Merge adjacent rune classes that lead to the same state
+		ranges := make(map[*State][]RuneRange)
+		var targets []*State
+		for c, t := range d.trans[s] {
+			if block[t] == dead {
+				continue
+			}
+			to := states[block[t]]
+			hi := rune(unicode.MaxRune)
+			if c+1 < len(alphabet) {
+				hi = alphabet[c+1] - 1
+			}
+			rs := ranges[to]
+			if n := len(rs); n > 0 && rs[n-1].Hi+1 == alphabet[c] {
+				rs[n-1].Hi = hi
+			} else {
+				if rs == nil {
+					targets = append(targets, to)
+				}
+				rs = append(rs, RuneRange{Lo: alphabet[c], Hi: hi})
+			}
+			ranges[to] = rs
+		}
+
+		for _, to := range targets {
+			result.AddTransition(from, to, TransitionLabel{
+				Type:  TransitionClass,
+				Class: &CharClass{Ranges: ranges[to]},
+			})
+		}
+		if accepting[from] {
+			result.AddEpsilonTransition(from, result.Accept)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,53 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestNFA_Minimize(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    int // DFA states without the dead state
+	}{
+		{"abc", 4},
+		{"[ab]*", 1},
+		{"x*x*x*", 1},
+		{"(a|b)*abb", 4},
+		{"a|ab", 3},
+		{"(?:aa)*", 2},
+		{"[ab]*a[ab]{4}", 32}, // the last 5 characters
+	}
+
+	inputs := []string{"", "a", "b", "ab", "abb", "aab", "babb", "aaaa", "abbab", "abc", "xxx", "ababa"}
+	p := NewParser()
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			nfa, err := BuildNFA(p.MustParse(tt.pattern))
+			if err != nil {
+				t.Fatalf("BuildNFA() error = %v", err)
+			}
+			dfa := nfa.Minimize()
+			if dfa == nil {
+				t.Fatal("Minimize() = nil")
+			}
+			if got := dfa.StateCount - 1; got != tt.want {
+				t.Errorf("Minimize() has %d states, want %d", got, tt.want)
+			}
+			for _, input := range inputs {
+				if got, want := dfa.Simulate(input), nfa.Simulate(input); got != want {
+					t.Errorf("minimized Simulate(%q) = %v, want %v", input, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNFA_Minimize_Limit(t *testing.T) {
+	nfa, err := BuildNFA(NewParser().MustParse("[ab]*a[ab]{14}"))
+	if err != nil {
+		t.Fatalf("BuildNFA() error = %v", err)
+	}
+	if dfa := nfa.Minimize(); dfa != nil {
+		t.Errorf("Minimize() = %d states, want nil above MaxMinimizeStates", dfa.StateCount)
+	}
+}
@@ -2,10 +2,21 @@
 package parser
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"regexp/syntax"
+	"sort"
+	"unicode"
 )
 
+// ErrNFATooLarge indicates NFA construction stopped at the state limit.
+var ErrNFATooLarge = errors.New("NFA state limit exceeded")
+
+// DefaultMaxEpsilonPaths is the count at which CountEpsilonPaths stops
+// counting when NFA.MaxEpsilonPaths is 0.
+const DefaultMaxEpsilonPaths = 64
+
 // NFA represents a Non-deterministic Finite Automaton constructed from a regex.
 type NFA struct {
 	Start       *State
@@ -13,6 +24,16 @@ type NFA struct {
 	States      []*State
 	StateCount  int
 	Transitions map[*State][]*Transition
+
+	// MaxEpsilonPaths caps the counts returned by CountEpsilonPaths.
+	// 0 means DefaultMaxEpsilonPaths.
+	MaxEpsilonPaths int
+
+	// Pairs maps each state of an NFA built by ProductWith to the states
+	// of the two NFAs it pairs. It is nil for other NFAs.
+	Pairs map[*State][2]*State
+
+	maxStates int // 0 means no limit
 }
 
 // State represents a state in the NFA.
@@ -62,6 +83,19 @@ type RuneRange struct {
 	Hi rune
 }
 
+// Intersects reports whether a character is in both c and other. Like
+// TransitionLabel.Matches, it compares Ranges and ignores Negate.
+func (c *CharClass) Intersects(other *CharClass) bool {
+	for _, x := range c.Ranges {
+		for _, y := range other.Ranges {
+			if x.Lo <= y.Hi && y.Lo <= x.Hi {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // NewNFA creates a new empty NFA.
 func NewNFA() *NFA {
 	return &NFA{
@@ -110,7 +144,15 @@ func (nfa *NFA) AddEpsilonTransition(from, to *State) *Transition {
 
 // BuildNFA constructs an NFA from a parsed regex AST.
 func BuildNFA(re *syntax.Regexp) (*NFA, error) {
+	return BuildNFAWithLimit(re, 0)
+}
+
+// BuildNFAWithLimit is like BuildNFA but stops construction and returns
+// ErrNFATooLarge once the NFA has more than maxStates states.
+// A maxStates of 0 means no limit.
+func BuildNFAWithLimit(re *syntax.Regexp, maxStates int) (*NFA, error) {
 	nfa := NewNFA()
+	nfa.maxStates = maxStates
 
 	// Create start and accept states
 	start := nfa.NewState()
@@ -130,6 +172,10 @@ func BuildNFA(re *syntax.Regexp) (*NFA, error) {
 
 // buildNFAFromRegexp recursively builds NFA from regex AST.
 func buildNFAFromRegexp(nfa *NFA, re *syntax.Regexp, start, accept *State) error {
+	if nfa.maxStates > 0 && nfa.StateCount > nfa.maxStates {
+		return fmt.Errorf("%w: more than %d states", ErrNFATooLarge, nfa.maxStates)
+	}
+
 	switch re.Op {
 	case syntax.OpLiteral:
 		return buildLiteral(nfa, re, start, accept)
@@ -436,3 +482,487 @@ func computeEpsilonClosureHelper(state *State, closure map[*State]bool) {
 		computeEpsilonClosureHelper(next, closure)
 	}
 }
+
+// CountEpsilonPaths counts the distinct paths from one state to another
+// that follow only epsilon transitions, capped at MaxEpsilonPaths. A state
+// reached along more than one such path can be entered in several ways
+// without consuming input, which is the source of ambiguity. If a path can
+// go around an epsilon cycle there are infinitely many, and the cap is
+// returned. It returns 0 if to is not reachable from from.
+func (nfa *NFA) CountEpsilonPaths(from, to *State) int {
+	return nfa.EpsilonPathCounts(from)[to]
+}
+
+// EpsilonPathCounts is like CountEpsilonPaths but counts the paths to every
+// state in the epsilon closure of from at once.
+func (nfa *NFA) EpsilonPathCounts(from *State) map[*State]int {
+	return nfa.EpsilonPathCountsWithLimit(from, nfa.MaxEpsilonPaths)
+}
+
+// EpsilonPathCountsWithLimit is like EpsilonPathCounts but caps the counts
+// at limit instead of MaxEpsilonPaths, so that callers with their own limit
+// need not modify an NFA they share. A limit of 0 means
+// DefaultMaxEpsilonPaths.
+func (nfa *NFA) EpsilonPathCountsWithLimit(from *State, limit int) map[*State]int {
+	if limit <= 0 {
+		limit = DefaultMaxEpsilonPaths
+	}
+
+	reach := ComputeEpsilonClosure(from)
+	indegree := make(map[*State]int, len(reach))
+	for s := range reach {
+		for _, next := range s.EpsilonTo {
+			indegree[next]++
+		}
+	}
+
+	// Count in topological order. States left over are on or behind an
+	// epsilon cycle and have unboundedly many paths.
+	counts := make(map[*State]int, len(reach))
+	var queue []*State
+	if indegree[from] == 0 {
+		counts[from] = 1
+		queue = append(queue, from)
+	}
+	done := make(map[*State]bool, len(reach))
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		done[s] = true
+		for _, next := range s.EpsilonTo {
+			counts[next] = min(counts[next]+counts[s], limit)
+			indegree[next]--
+			if indegree[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+	for s := range reach {
+		if !done[s] {
+			counts[s] = limit
+		}
+	}
+
+	return counts
+}
+
+// Matches reports whether a consuming transition with this label accepts r.
+// Epsilon and anchor labels consume no input and never match.
+func (l TransitionLabel) Matches(r rune) bool {
+	switch l.Type {
+	case TransitionLiteral:
+		for _, lr := range l.Runes {
+			if lr == r {
+				return true
+			}
+		}
+	case TransitionClass:
+		if l.Class != nil {
+			for _, rr := range l.Class.Ranges {
+				if rr.Lo <= r && r <= rr.Hi {
+					return true
+				}
+			}
+		}
+	case TransitionAny:
+		return l.Op != syntax.OpAnyCharNotNL || r != '\n'
+	}
+	return false
+}
+
+// StartStates returns the states active before any input is consumed:
+// the closure of the start state.
+func (nfa *NFA) StartStates() map[*State]bool {
+	return nfa.Closure(map[*State]bool{nfa.Start: true})
+}
+
+// Closure returns the given states plus every state reachable from them
+// without consuming input. Anchors are followed like epsilon transitions,
+// so simulation does not check them.
+func (nfa *NFA) Closure(states map[*State]bool) map[*State]bool {
+	closure := make(map[*State]bool, len(states))
+	var stack []*State
+	for state := range states {
+		closure[state] = true
+		stack = append(stack, state)
+	}
+
+	for len(stack) > 0 {
+		state := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, t := range state.Transitions {
+			if (t.IsEpsilon || t.Label.Type == TransitionAnchor) && !closure[t.To] {
+				closure[t.To] = true
+				stack = append(stack, t.To)
+			}
+		}
+	}
+
+	return closure
+}
+
+// SimulateStep returns the states active after consuming input from the
+// given states: the closure of every state reached by a transition that
+// matches input. This is one step of the standard NFA simulation.
+func (nfa *NFA) SimulateStep(states map[*State]bool, input rune) map[*State]bool {
+	next := make(map[*State]bool)
+	for state := range states {
+		for _, t := range state.Transitions {
+			if t.Label.Matches(input) {
+				next[t.To] = true
+			}
+		}
+	}
+	return nfa.Closure(next)
+}
+
+// Simulate reports whether the NFA accepts the whole input string.
+// Anchors are not checked (see Closure), and matching is exact where the
+// regex would match case-insensitively, since the NFA does not record flags.
+func (nfa *NFA) Simulate(input string) bool {
+	states := nfa.StartStates()
+	for _, r := range input {
+		states = nfa.SimulateStep(states, r)
+		if len(states) == 0 {
+			return false
+		}
+	}
+	return states[nfa.Accept]
+}
+
+// CountDFAStates counts the states of the DFA that subset construction
+// builds from the NFA, including the empty dead state, and stops counting
+// at limit. Input is read one representative rune per class of runes that
+// every transition treats alike, so the count is exact up to the limit.
+// The DFA is not minimized.
+// Anchors are ignored, as in Simulate.
+//
+// Counting also stops after MaxSubsetSteps steps of the construction, in
+// which case the count is a lower bound. If ctx is done first, it returns
+// ctx.Err().
+func (nfa *NFA) CountDFAStates(ctx context.Context, limit int) (int, error) {
+	d, err := nfa.subsetConstruction(ctx, nfa.representativeRunes(), limit)
+	if err != nil && !errors.Is(err, ErrDFATooLarge) {
+		return 0, err
+	}
+	return min(len(d.sets), limit), nil
+}
+
+// representativeRunes returns one rune from each class of runes that every
+// consuming transition of the NFA either accepts or rejects as a whole: the
+// lowest rune and the rune after each range accepted by a transition.
+func (nfa *NFA) representativeRunes() []rune {
+	seen := map[rune]bool{0: true}
+	add := func(lo, hi rune) {
+		seen[lo] = true
+		if hi < unicode.MaxRune {
+			seen[hi+1] = true
+		}
+	}
+
+	for _, state := range nfa.States {
+		for _, t := range state.Transitions {
+			switch t.Label.Type {
+			case TransitionLiteral:
+				for _, r := range t.Label.Runes {
+					add(r, r)
+				}
+			case TransitionClass:
+				if t.Label.Class != nil {
+					for _, rr := range t.Label.Class.Ranges {
+						add(rr.Lo, rr.Hi)
+					}
+				}
+			case TransitionAny:
+				add('\n', '\n')
+			}
+		}
+	}
+
+	runes := make([]rune, 0, len(seen))
+	for r := range seen {
+		runes = append(runes, r)
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+	return runes
+}
+
+// StateIDs returns the IDs of a set of states in ascending order.
+func StateIDs(states map[*State]bool) []int {
+	ids := make([]int, 0, len(states))
+	for state := range states {
+		ids = append(ids, state.ID)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// EstimateMaxBacktrackDepth estimates how deep the stack of a backtracking
+// engine grows while matching: the largest number of choice points, states
+// with more than one way out such as loop entries, alternations and optional
+// elements, on a path from the start to the accept state. Each choice point
+// the matcher passes leaves a frame to backtrack to. Loops are followed
+// once, so a loop counts once, while counted repetitions like (a?){500} are
+// unrolled and count once per copy. An engine that keeps a frame per loop
+// iteration grows its stack further on long inputs, so the estimate is the
+// depth of a single pass through the pattern, not an upper bound.
+func (nfa *NFA) EstimateMaxBacktrackDepth() int {
+	return nfa.EstimateLongestPath(func(trans *Transition) int {
+		if len(trans.From.Transitions) > 1 {
+			return 1
+		}
+		return 0
+	})
+}
+
+// EstimateLongestPath estimates the largest total weight of a path from the
+// start to the accept state, where each transition weighs weight(trans).
+// Transitions back into a state already on the path are skipped, so each
+// loop is followed once. The longest path from a state is memoized when it
+// is first explored, with the states on the path to it excluded, and reused
+// on paths where they are not, so the result depends on the order in which
+// transitions are explored. It is exact for NFAs without loops, and 0 if
+// the accept state cannot be reached.
+func (nfa *NFA) EstimateLongestPath(weight func(trans *Transition) int) int {
+	const onPath, done = 1, 2
+	color := make(map[*State]int)
+	longest := make(map[*State]int)
+
+	var visit func(state *State) int
+	visit = func(state *State) int {
+		if color[state] == done {
+			return longest[state]
+		}
+
+		color[state] = onPath
+		best := -1 // accept state not reachable
+		if state == nfa.Accept {
+			best = 0
+		}
+
+		for _, trans := range state.Transitions {
+			if color[trans.To] == onPath {
+				continue
+			}
+			rest := visit(trans.To)
+			if rest < 0 {
+				continue
+			}
+			if rest += weight(trans); rest > best {
+				best = rest
+			}
+		}
+
+		color[state] = done
+		longest[state] = best
+		return best
+	}
+
+	return max(visit(nfa.Start), 0)
+}
+
+// FindCycles returns the states that lie on a cycle of the NFA transition graph,
+// grouped by strongly connected component. Both epsilon and consuming
+// transitions are considered. Only non-trivial components are returned:
+// those with more than one state, or a single state with a self-loop.
+// Each component lists state IDs in ascending order.
+func (nfa *NFA) FindCycles() [][]int {
+	t := &tarjan{
+		index:   make(map[*State]int),
+		lowlink: make(map[*State]int),
+		onStack: make(map[*State]bool),
+	}
+
+	for _, state := range nfa.States {
+		if _, seen := t.index[state]; !seen {
+			t.strongConnect(state)
+		}
+	}
+
+	var cycles [][]int
+	for _, component := range t.components {
+		if len(component) == 1 && !hasSelfLoop(component[0]) {
+			continue
+		}
+
+		ids := make([]int, len(component))
+		for i, state := range component {
+			ids[i] = state.ID
+		}
+		sort.Ints(ids)
+		cycles = append(cycles, ids)
+	}
+
+	sort.Slice(cycles, func(i, j int) bool {
+		return cycles[i][0] < cycles[j][0]
+	})
+
+	return cycles
+}
+
+// tarjan holds the bookkeeping for Tarjan's strongly connected components algorithm.
+type tarjan struct {
+	counter    int
+	index      map[*State]int
+	lowlink    map[*State]int
+	onStack    map[*State]bool
+	stack      []*State
+	components [][]*State
+}
+
+func (t *tarjan) strongConnect(state *State) {
+	t.index[state] = t.counter
+	t.lowlink[state] = t.counter
+	t.counter++
+	t.stack = append(t.stack, state)
+	t.onStack[state] = true
+
+	for _, trans := range state.Transitions {
+		next := trans.To
+		if _, seen := t.index[next]; !seen {
+			t.strongConnect(next)
+			if t.lowlink[next] < t.lowlink[state] {
+				t.lowlink[state] = t.lowlink[next]
+			}
+		} else if t.onStack[next] && t.index[next] < t.lowlink[state] {
+			t.lowlink[state] = t.index[next]
+		}
+	}
+
+	// State is the root of a component: pop it off the stack
+	if t.lowlink[state] == t.index[state] {
+		var component []*State
+		for {
+			top := t.stack[len(t.stack)-1]
+			t.stack = t.stack[:len(t.stack)-1]
+			t.onStack[top] = false
+			component = append(component, top)
+			if top == state {
+				break
+			}
+		}
+		t.components = append(t.components, component)
+	}
+}
+
+func hasSelfLoop(state *State) bool {
+	for _, trans := range state.Transitions {
+		if trans.To == state {
+			return true
+		}
+	}
+	return false
+}
+
+// ProductWith returns the product of the NFA and other: an NFA whose states
+// are the pairs of their states reachable from (nfa.Start, other.Start),
+// recorded in Pairs. A pair (p, q) moves on a rune to (p', q') when both
+// NFAs can move on it, from states in the closures of p and q, to p' and
+// q'. The product has no epsilon transitions except into Accept, which
+// pairs nfa.Accept and other.Accept, so it accepts the strings both NFAs
+// accept. Anchors are ignored, as in Simulate.
+//
+// The product of an NFA with itself shows ambiguity between states: if a
+// pair (q, r) with q != r lies on a cycle of the product through a pair
+// (p, p), some string leads from p back to p along two different paths,
+// which is exponential degree of ambiguity (EDA). Ambiguity that lies only
+// in the number of epsilon paths between two states, as in (a+)+, does
+// not show in the product; CountEpsilonPaths counts it.
+func (nfa *NFA) ProductWith(other *NFA) *NFA {
+	product := NewNFA()
+	product.Pairs = make(map[*State][2]*State)
+
+	index := make(map[[2]*State]*State)
+	var queue [][2]*State
+	state := func(pair [2]*State) *State {
+		if s, ok := index[pair]; ok {
+			return s
+		}
+		s := product.NewState()
+		index[pair] = s
+		product.Pairs[s] = pair
+		queue = append(queue, pair)
+		return s
+	}
+
+	product.Start = state([2]*State{nfa.Start, other.Start})
+	product.Accept = product.NewState()
+	product.Accept.IsAccept = true
+	product.Pairs[product.Accept] = [2]*State{nfa.Accept, other.Accept}
+
+	for len(queue) > 0 {
+		pair := queue[0]
+		queue = queue[1:]
+		from := index[pair]
+		closureP := nfa.Closure(map[*State]bool{pair[0]: true})
+		closureQ := other.Closure(map[*State]bool{pair[1]: true})
+
+		// Iterate in state order so that the product is numbered the same
+		// way on every call
+		for _, p := range nfa.States {
+			if !closureP[p] {
+				continue
+			}
+			for _, tp := range p.Transitions {
+				rangesP := tp.Label.ranges()
+				if len(rangesP) == 0 {
+					continue
+				}
+				for _, q := range other.States {
+					if !closureQ[q] {
+						continue
+					}
+					for _, tq := range q.Transitions {
+						if ranges := intersectRanges(rangesP, tq.Label.ranges()); len(ranges) > 0 {
+							product.AddTransition(from, state([2]*State{tp.To, tq.To}), TransitionLabel{
+								Type:  TransitionClass,
+								Class: &CharClass{Ranges: ranges},
+							})
+						}
+					}
+				}
+			}
+		}
+		if closureP[nfa.Accept] && closureQ[other.Accept] {
+			product.AddEpsilonTransition(from, product.Accept)
+		}
+	}
+
+	return product
+}
+
+// ranges returns the runes accepted by a consuming transition label, as
+// Matches does, or nil for epsilon and anchor labels.
+func (l TransitionLabel) ranges() []RuneRange {
+	switch l.Type {
+	case TransitionLiteral:
+		ranges := make([]RuneRange, len(l.Runes))
+		for i, r := range l.Runes {
+			ranges[i] = RuneRange{Lo: r, Hi: r}
+		}
+		return ranges
+	case TransitionClass:
+		if l.Class != nil {
+			return l.Class.Ranges
+		}
+	case TransitionAny:
+		if l.Op == syntax.OpAnyCharNotNL {
+			return []RuneRange{{Lo: 0, Hi: '\n' - 1}, {Lo: '\n' + 1, Hi: unicode.MaxRune}}
+		}
+		return []RuneRange{{Lo: 0, Hi: unicode.MaxRune}}
+	}
+	return nil
+}
+
+// intersectRanges returns the runes in both x and y.
+func intersectRanges(x, y []RuneRange) []RuneRange {
+	var out []RuneRange
+	for _, rx := range x {
+		for _, ry := range y {
+			if lo, hi := max(rx.Lo, ry.Lo), min(rx.Hi, ry.Hi); lo <= hi {
+				out = append(out, RuneRange{Lo: lo, Hi: hi})
+			}
+		}
+	}
+	return out
+}
@@ -1,6 +1,10 @@
 package parser
 
 import (
+	"context"
+	"errors"
+	"regexp"
+	"regexp/syntax"
 	"testing"
 )
 
@@ -334,6 +338,85 @@ func TestComputeEpsilonClosure_Cycle(t *testing.T) {
 	}
 }
 
+func TestNFA_CountEpsilonPaths(t *testing.T) {
+	nfa := NewNFA()
+	s1 := nfa.NewState()
+	s2 := nfa.NewState()
+	s3 := nfa.NewState()
+	s4 := nfa.NewState()
+	s5 := nfa.NewState()
+
+	// Diamond: s1 -> s2 -> s4 and s1 -> s3 -> s4, then s4 -a-> s5
+	nfa.AddEpsilonTransition(s1, s2)
+	nfa.AddEpsilonTransition(s1, s3)
+	nfa.AddEpsilonTransition(s2, s4)
+	nfa.AddEpsilonTransition(s3, s4)
+	nfa.AddTransition(s4, s5, TransitionLabel{Type: TransitionLiteral, Runes: []rune{'a'}})
+
+	tests := []struct {
+		from, to *State
+		want     int
+	}{
+		{s1, s1, 1},
+		{s1, s2, 1},
+		{s1, s4, 2},
+		{s2, s4, 1},
+		{s1, s5, 0}, // only reachable by consuming input
+		{s4, s1, 0},
+	}
+	for _, tt := range tests {
+		if got := nfa.CountEpsilonPaths(tt.from, tt.to); got != tt.want {
+			t.Errorf("CountEpsilonPaths(%d, %d) = %d, want %d", tt.from.ID, tt.to.ID, got, tt.want)
+		}
+	}
+}
+
+func TestNFA_CountEpsilonPaths_Limit(t *testing.T) {
+	// A chain of n diamonds has 2^n paths
+	nfa := NewNFA()
+	first := nfa.NewState()
+	last := first
+	for range 10 {
+		a, b, next := nfa.NewState(), nfa.NewState(), nfa.NewState()
+		nfa.AddEpsilonTransition(last, a)
+		nfa.AddEpsilonTransition(last, b)
+		nfa.AddEpsilonTransition(a, next)
+		nfa.AddEpsilonTransition(b, next)
+		last = next
+	}
+
+	if got := nfa.CountEpsilonPaths(first, last); got != DefaultMaxEpsilonPaths {
+		t.Errorf("CountEpsilonPaths() = %d, want default cap %d", got, DefaultMaxEpsilonPaths)
+	}
+
+	nfa.MaxEpsilonPaths = 2000
+	if got := nfa.CountEpsilonPaths(first, last); got != 1024 {
+		t.Errorf("CountEpsilonPaths() = %d, want 1024", got)
+	}
+}
+
+func TestNFA_CountEpsilonPaths_Cycle(t *testing.T) {
+	nfa := NewNFA()
+	s1 := nfa.NewState()
+	s2 := nfa.NewState()
+	s3 := nfa.NewState()
+	s4 := nfa.NewState()
+
+	// s1 -> s2 <-> s3 -> s4: paths to s4 can go around the cycle
+	nfa.AddEpsilonTransition(s1, s2)
+	nfa.AddEpsilonTransition(s2, s3)
+	nfa.AddEpsilonTransition(s3, s2)
+	nfa.AddEpsilonTransition(s3, s4)
+	nfa.MaxEpsilonPaths = 5
+
+	if got := nfa.CountEpsilonPaths(s1, s4); got != 5 {
+		t.Errorf("CountEpsilonPaths() through a cycle = %d, want the cap 5", got)
+	}
+	if got := nfa.CountEpsilonPaths(s1, s1); got != 1 {
+		t.Errorf("CountEpsilonPaths() to itself = %d, want 1", got)
+	}
+}
+
 func TestBuildNFA_ComplexPattern(t *testing.T) {
 	patterns := []string{
 		"(a|b)*c",
@@ -363,3 +446,328 @@ func TestBuildNFA_ComplexPattern(t *testing.T) {
 		})
 	}
 }
+
+func TestNFA_FindCycles(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		want    int // number of non-trivial components
+	}{
+		{"no loop", "abc", 0},
+		{"optional", "a?b", 0},
+		{"star", "a*", 1},
+		{"plus", "a+b", 1},
+		{"nested plus", "(a+)+", 1},
+		{"separate loops", "a+b+", 2},
+	}
+
+	p := NewParser()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nfa, err := BuildNFA(p.MustParse(tt.pattern))
+			if err != nil {
+				t.Fatalf("BuildNFA() error = %v", err)
+			}
+
+			cycles := nfa.FindCycles()
+			if len(cycles) != tt.want {
+				t.Errorf("FindCycles() returned %d components, want %d: %v", len(cycles), tt.want, cycles)
+			}
+
+			for _, cycle := range cycles {
+				for i := 1; i < len(cycle); i++ {
+					if cycle[i-1] >= cycle[i] {
+						t.Errorf("component %v is not sorted", cycle)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestNFA_FindCycles_SelfLoop(t *testing.T) {
+	nfa := NewNFA()
+	s0 := nfa.NewState()
+	s1 := nfa.NewState()
+
+	nfa.AddTransition(s0, s1, TransitionLabel{Type: TransitionLiteral, Runes: []rune{'a'}})
+	nfa.AddTransition(s1, s1, TransitionLabel{Type: TransitionLiteral, Runes: []rune{'b'}})
+
+	cycles := nfa.FindCycles()
+	if len(cycles) != 1 || len(cycles[0]) != 1 || cycles[0][0] != s1.ID {
+		t.Errorf("FindCycles() = %v, want [[%d]]", cycles, s1.ID)
+	}
+}
+
+func TestBuildNFAWithLimit(t *testing.T) {
+	re := NewParser().MustParse(`(a|b|c|d){100}`)
+
+	if _, err := BuildNFAWithLimit(re, 100); !errors.Is(err, ErrNFATooLarge) {
+		t.Errorf("BuildNFAWithLimit() error = %v, want %v", err, ErrNFATooLarge)
+	}
+
+	nfa, err := BuildNFAWithLimit(re, 0)
+	if err != nil {
+		t.Fatalf("BuildNFAWithLimit() without limit error = %v", err)
+	}
+	if nfa.StateCount <= 100 {
+		t.Errorf("StateCount = %d, expected more than 100 states", nfa.StateCount)
+	}
+}
+
+func TestNFA_Simulate(t *testing.T) {
+	patterns := []string{
+		`a`, `abc`, `a|bc`, `a*`, `(a+)+`, `(a|ab)*c`, `[a-c]+x?`, `a{2,3}`,
+		`.b`, `(?s).b`, `^\d+$`, `(\w+\s?)*`, `x(y|z)*x`,
+	}
+	inputs := []string{"", "a", "aa", "aaa", "aaaa", "abc", "bc", "abababc", "cabx", "\nb", "xb", "123", "12a", "foo bar", "xyzzyx", "xx"}
+
+	p := NewParser()
+	for _, pattern := range patterns {
+		re, err := p.Parse(pattern)
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %v", pattern, err)
+		}
+		nfa, err := BuildNFA(re)
+		if err != nil {
+			t.Fatalf("BuildNFA(%q) error = %v", pattern, err)
+		}
+		full := regexp.MustCompile(`\A(?:` + pattern + `)\z`)
+
+		for _, input := range inputs {
+			if got, want := nfa.Simulate(input), full.MatchString(input); got != want {
+				t.Errorf("Simulate(%q) on %q = %v, want %v", input, pattern, got, want)
+			}
+		}
+	}
+}
+
+func TestCharClass_Intersects(t *testing.T) {
+	digits := &CharClass{Ranges: []RuneRange{{Lo: '0', Hi: '9'}}}
+	word := &CharClass{Ranges: []RuneRange{{Lo: '0', Hi: '9'}, {Lo: 'A', Hi: 'Z'}, {Lo: '_', Hi: '_'}, {Lo: 'a', Hi: 'z'}}}
+	lower := &CharClass{Ranges: []RuneRange{{Lo: 'a', Hi: 'z'}}}
+	edge := &CharClass{Ranges: []RuneRange{{Lo: 'z', Hi: 'z'}}}
+
+	tests := []struct {
+		a, b *CharClass
+		want bool
+	}{
+		{digits, word, true},
+		{word, digits, true},
+		{digits, lower, false},
+		{lower, edge, true},
+		{digits, &CharClass{}, false},
+	}
+	for i, tt := range tests {
+		if got := tt.a.Intersects(tt.b); got != tt.want {
+			t.Errorf("case %d: Intersects() = %v, want %v", i, got, tt.want)
+		}
+	}
+}
+
+func TestNFA_SimulateStep(t *testing.T) {
+	re, _ := NewParser().Parse(`ab`)
+	nfa, err := BuildNFA(re)
+	if err != nil {
+		t.Fatalf("BuildNFA() error = %v", err)
+	}
+
+	states := nfa.StartStates()
+	if !states[nfa.Start] {
+		t.Error("StartStates() does not contain the start state")
+	}
+
+	states = nfa.SimulateStep(states, 'a')
+	if len(states) == 0 || states[nfa.Accept] {
+		t.Errorf("after 'a' states = %v, want non-accepting states", StateIDs(states))
+	}
+
+	states = nfa.SimulateStep(states, 'b')
+	if !states[nfa.Accept] {
+		t.Errorf("after 'ab' states = %v, want accept state %d", StateIDs(states), nfa.Accept.ID)
+	}
+
+	if states := nfa.SimulateStep(nfa.StartStates(), 'x'); len(states) != 0 {
+		t.Errorf("after 'x' states = %v, want none", StateIDs(states))
+	}
+}
+
+func TestNFA_CountDFAStates(t *testing.T) {
+	tests := []struct {
+		pattern string
+		limit   int
+		want    int
+	}{
+		{"abc", 100, 5},            // start, after a, ab, abc, dead
+		{"[ab]*", 100, 3},          // start, loop and dead
+		{"[ab]*a[ab]{4}", 100, 34}, // the last 5 characters, start and dead
+		{"[ab]*a[ab]{12}", 1000, 1000},
+		{`\pL*a\pL{4}`, 100, 34}, // the letters other than a form one class
+	}
+
+	p := NewParser()
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			nfa, err := BuildNFA(p.MustParse(tt.pattern))
+			if err != nil {
+				t.Fatalf("BuildNFA() error = %v", err)
+			}
+			got, err := nfa.CountDFAStates(context.Background(), tt.limit)
+			if err != nil || got != tt.want {
+				t.Errorf("CountDFAStates(%d) = %d, %v, want %d", tt.limit, got, err, tt.want)
+			}
+		})
+	}
+
+	nfa, err := BuildNFA(p.MustParse(`[ab]*a[ab]{12}`))
+	if err != nil {
+		t.Fatalf("BuildNFA() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := nfa.CountDFAStates(ctx, 1000); !errors.Is(err, context.Canceled) {
+		t.Errorf("CountDFAStates() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestNFA_ProductWith(t *testing.T) {
+	pairs := [][2]string{
+		{`[a-c]+`, `[b-d]*b`},
+		{`(a|ab)*c`, `a*b?c`},
+		{`.b`, `\nb|xb`},
+		{`abc`, `x+`},
+	}
+	inputs := []string{"", "b", "bb", "ab", "cb", "bcb", "c", "ac", "abc", "aabc", "\nb", "xb", "xx"}
+
+	p := NewParser()
+	for _, pair := range pairs {
+		a, err := BuildNFA(p.MustParse(pair[0]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := BuildNFA(p.MustParse(pair[1]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		product := a.ProductWith(b)
+
+		if got := product.Pairs[product.Start]; got != [2]*State{a.Start, b.Start} {
+			t.Errorf("%q × %q: start pairs %v", pair[0], pair[1], got)
+		}
+		for _, input := range inputs {
+			if got, want := product.Simulate(input), a.Simulate(input) && b.Simulate(input); got != want {
+				t.Errorf("%q × %q: Simulate(%q) = %v, want %v", pair[0], pair[1], input, got, want)
+			}
+		}
+	}
+}
+
+func TestNFA_ProductWith_EDA(t *testing.T) {
+	tests := []struct {
+		pattern string
+		eda     bool
+	}{
+		{`(a|aa)*`, true},
+		{`(ab|[a-z]b)*`, true},
+		{`(a|ab)*`, false},
+		{`a*a*`, false},
+		{`[a-z]+`, false},
+	}
+
+	p := NewParser()
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			nfa, err := BuildNFA(p.MustParse(tt.pattern))
+			if err != nil {
+				t.Fatal(err)
+			}
+			product := nfa.ProductWith(nfa)
+
+			byID := make(map[int]*State)
+			for _, s := range product.States {
+				byID[s.ID] = s
+			}
+			eda := false
+			for _, cycle := range product.FindCycles() {
+				diagonal, diverging := false, false
+				for _, id := range cycle {
+					pair := product.Pairs[byID[id]]
+					if pair[0] == pair[1] {
+						diagonal = true
+					} else {
+						diverging = true
+					}
+				}
+				eda = eda || diagonal && diverging
+			}
+			if eda != tt.eda {
+				t.Errorf("EDA = %v, want %v", eda, tt.eda)
+			}
+		})
+	}
+}
+
+func TestEstimateMaxBacktrackDepth(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    int
+	}{
+		{"abc", 0},
+		{"a|bc", 1},
+		{"a*b*", 4},             // entry and loop end of each star
+		{"(?:a?){10}", 10},      // every copy of the counted repetition
+		{"(?:a?b?){600}", 1200}, // deep enough to be flagged
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re, err := syntax.Parse(tt.pattern, syntax.Perl)
+			if err != nil {
+				t.Fatalf("Failed to parse pattern: %v", err)
+			}
+			nfa, err := BuildNFA(re.Simplify())
+			if err != nil {
+				t.Fatalf("BuildNFA() error = %v", err)
+			}
+			if got := nfa.EstimateMaxBacktrackDepth(); got != tt.want {
+				t.Errorf("EstimateMaxBacktrackDepth() for %q = %d, want %d", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEstimateLongestPath(t *testing.T) {
+	consuming := func(trans *Transition) int {
+		if trans.IsEpsilon || trans.Label.Type == TransitionAnchor {
+			return 0
+		}
+		return 1
+	}
+
+	tests := []struct {
+		pattern string
+		want    int
+	}{
+		{"abc", 3},
+		{"a|bc", 2},
+		{"^a*b$", 2}, // one pass through the loop, anchors weigh nothing
+		{"(?:ab|c)+d", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re, err := syntax.Parse(tt.pattern, syntax.Perl)
+			if err != nil {
+				t.Fatalf("Failed to parse pattern: %v", err)
+			}
+			nfa, err := BuildNFA(re.Simplify())
+			if err != nil {
+				t.Fatalf("BuildNFA() error = %v", err)
+			}
+			if got := nfa.EstimateLongestPath(consuming); got != tt.want {
+				t.Errorf("EstimateLongestPath() for %q = %d, want %d", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
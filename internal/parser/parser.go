@@ -5,6 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"regexp/syntax"
+	"sort"
+	"strings"
+	"sync"
 )
 
 var (
@@ -31,7 +34,14 @@ func NewParserWithFlags(flags syntax.Flags) *Parser {
 
 // Parse parses a regex pattern into an AST.
 func (p *Parser) Parse(pattern string) (*syntax.Regexp, error) {
-	re, err := syntax.Parse(pattern, p.flags)
+	return p.ParseWithFlags(pattern, p.flags)
+}
+
+// ParseWithFlags is like Parse but uses flags instead of the parser's
+// flags, for example syntax.Perl | syntax.FoldCase for a pattern that will
+// be matched case-insensitively.
+func (p *Parser) ParseWithFlags(pattern string, flags syntax.Flags) (*syntax.Regexp, error) {
+	re, err := syntax.Parse(pattern, flags)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrInvalidPattern, err)
 	}
@@ -42,6 +52,86 @@ func (p *Parser) Parse(pattern string) (*syntax.Regexp, error) {
 	return re, nil
 }
 
+// ParseRaw parses a regex pattern into an AST without simplifying it.
+// Unlike Parse, bounded repetitions such as a{2,5} are kept as OpRepeat
+// nodes instead of being expanded.
+func (p *Parser) ParseRaw(pattern string) (*syntax.Regexp, error) {
+	re, err := syntax.Parse(pattern, p.flags)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPattern, err)
+	}
+	return re, nil
+}
+
+// ParseError is the failure to parse one pattern of a ParseMulti batch.
+type ParseError struct {
+	Index   int    // Index of the pattern in the batch
+	Pattern string // Pattern that failed to parse
+	Err     error  // Error returned by Parse, wrapping ErrInvalidPattern
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("pattern %d (%q): %v", e.Index, e.Pattern, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// MultiParseError lists every pattern of a ParseMulti batch that failed to
+// parse, in the order of the batch.
+type MultiParseError struct {
+	Errors []*ParseError
+}
+
+func (e *MultiParseError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d of the patterns failed to parse: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the error of each failed pattern, so that errors.Is and
+// errors.As look through all of them, as with errors.Join.
+func (e *MultiParseError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// ParseMulti parses patterns concurrently. The returned slice has the AST of
+// each pattern at its index, or nil for patterns that failed to parse. If
+// any failed, the error is a *MultiParseError listing all of them, so
+// callers can tell which patterns are invalid without parsing them again.
+func (p *Parser) ParseMulti(patterns []string) ([]*syntax.Regexp, error) {
+	results := make([]*syntax.Regexp, len(patterns))
+	errs := make([]error, len(patterns))
+
+	var wg sync.WaitGroup
+	for i, pattern := range patterns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = p.Parse(pattern)
+		}()
+	}
+	wg.Wait()
+
+	var failed []*ParseError
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, &ParseError{Index: i, Pattern: patterns[i], Err: err})
+		}
+	}
+	if failed != nil {
+		return results, &MultiParseError{Errors: failed}
+	}
+	return results, nil
+}
+
 // MustParse is like Parse but panics on error. Useful for testing.
 func (p *Parser) MustParse(pattern string) *syntax.Regexp {
 	re, err := p.Parse(pattern)
@@ -156,24 +246,31 @@ func CountCaptures(re *syntax.Regexp) int {
 	return count
 }
 
-// Walk traverses the regex AST and calls the visitor function for each node.
-func Walk(re *syntax.Regexp, visitor func(*syntax.Regexp) bool) {
-	if !visitor(re) {
-		return
+// Walk traverses the regex AST depth first and calls the visitor function
+// for each node. The children of a node are skipped if the visitor returns
+// false for it. If the visitor returns an error, Walk stops at once and
+// returns it.
+func Walk(re *syntax.Regexp, visitor func(*syntax.Regexp) (bool, error)) error {
+	descend, err := visitor(re)
+	if err != nil || !descend {
+		return err
 	}
 	for _, sub := range re.Sub {
-		Walk(sub, visitor)
+		if err := Walk(sub, visitor); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
 // FindQuantifiers finds all quantifier nodes in the regex.
 func FindQuantifiers(re *syntax.Regexp) []*syntax.Regexp {
 	var quantifiers []*syntax.Regexp
-	Walk(re, func(node *syntax.Regexp) bool {
+	Walk(re, func(node *syntax.Regexp) (bool, error) {
 		if IsQuantifier(node) {
 			quantifiers = append(quantifiers, node)
 		}
-		return true
+		return true, nil
 	})
 	return quantifiers
 }
@@ -181,11 +278,11 @@ func FindQuantifiers(re *syntax.Regexp) []*syntax.Regexp {
 // FindAlternations finds all alternation nodes in the regex.
 func FindAlternations(re *syntax.Regexp) []*syntax.Regexp {
 	var alternations []*syntax.Regexp
-	Walk(re, func(node *syntax.Regexp) bool {
+	Walk(re, func(node *syntax.Regexp) (bool, error) {
 		if IsAlternation(node) {
 			alternations = append(alternations, node)
 		}
-		return true
+		return true, nil
 	})
 	return alternations
 }
@@ -194,3 +291,326 @@ func FindAlternations(re *syntax.Regexp) []*syntax.Regexp {
 func String(re *syntax.Regexp) string {
 	return re.String()
 }
+
+// Clone returns a deep copy of the regex AST so callers can rewrite it
+// without mutating the original tree.
+func Clone(re *syntax.Regexp) *syntax.Regexp {
+	if re == nil {
+		return nil
+	}
+
+	clone := *re
+	if re.Rune != nil {
+		clone.Rune = append([]rune(nil), re.Rune...)
+	}
+	if re.Sub != nil {
+		clone.Sub = make([]*syntax.Regexp, len(re.Sub))
+		for i, sub := range re.Sub {
+			clone.Sub[i] = Clone(sub)
+		}
+	}
+	return &clone
+}
+
+// Alternation is a group of a pattern, or the whole pattern, whose top level
+// is split into branches by |.
+type Alternation struct {
+	Start, End int // Byte range of the group's contents
+	Branches   int
+}
+
+// Alternations returns the alternations in pattern as written, ordered by
+// start offset. It reads the pattern text rather than the AST because the
+// parser merges single-character branches such as a|b|c into a character
+// class and factors out common prefixes. Escaped bars and bars inside
+// character classes are skipped.
+func Alternations(pattern string) []Alternation {
+	type group struct{ start, bars int }
+	open := []group{{}} // the whole pattern is the outermost group
+	var alts []Alternation
+	closeGroup := func(end int) {
+		g := open[len(open)-1]
+		open = open[:len(open)-1]
+		if g.bars > 0 {
+			alts = append(alts, Alternation{Start: g.start, End: end, Branches: g.bars + 1})
+		}
+	}
+
+	inClass := false
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '\\':
+			i++ // skip escaped character
+		case inClass:
+			if strings.HasPrefix(pattern[i:], "[:") {
+				if end := strings.Index(pattern[i+2:], ":]"); end >= 0 {
+					i += end + 3 // skip [:alpha:]
+				}
+			} else if c == ']' {
+				inClass = false
+			}
+		case c == '[':
+			inClass = true
+			// A ']' right after '[' or '[^' is a literal
+			if i+1 < len(pattern) && pattern[i+1] == '^' {
+				i++
+			}
+			if i+1 < len(pattern) && pattern[i+1] == ']' {
+				i++
+			}
+		case c == '(':
+			open = append(open, group{start: groupBodyStart(pattern, i)})
+		case c == ')':
+			if len(open) > 1 {
+				closeGroup(i)
+			}
+		case c == '|':
+			open[len(open)-1].bars++
+		}
+	}
+	for len(open) > 0 {
+		closeGroup(len(pattern))
+	}
+
+	sort.Slice(alts, func(i, j int) bool { return alts[i].Start < alts[j].Start })
+	return alts
+}
+
+// groupBodyStart returns the offset of the contents of the group opened at
+// pattern[i], after any (?:, (?i:, (?P<name> or (?<name> prefix.
+func groupBodyStart(pattern string, i int) int {
+	if !strings.HasPrefix(pattern[i:], "(?") {
+		return i + 1
+	}
+	if end := strings.IndexAny(pattern[i+2:], ":>)"); end >= 0 {
+		if pattern[i+2+end] == ')' {
+			return i + 2 + end // flags only, as in (?i)
+		}
+		return i + 3 + end
+	}
+	return i + 1
+}
+
+// CaptureGroupSpan returns the byte range of the index-th capturing group
+// (1-based, matching syntax.Regexp.Cap) in the original pattern, including
+// its parentheses. Escaped parentheses and parentheses inside character
+// classes are skipped, and non-capturing groups such as (?:...) are not counted.
+func CaptureGroupSpan(pattern string, index int) (start, end int, ok bool) {
+	var open []int // start offsets of open groups, -1 for non-capturing
+	captures := 0
+	inClass := false
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '\\':
+			i++ // skip escaped character
+		case inClass:
+			if c == ']' {
+				inClass = false
+			}
+		case c == '[':
+			inClass = true
+			// A ']' right after '[' or '[^' is a literal
+			if i+1 < len(pattern) && pattern[i+1] == '^' {
+				i++
+			}
+			if i+1 < len(pattern) && pattern[i+1] == ']' {
+				i++
+			}
+		case c == '(':
+			if isCapturingGroup(pattern[i:]) {
+				captures++
+				if captures == index {
+					open = append(open, i)
+					continue
+				}
+			}
+			open = append(open, -1)
+		case c == ')':
+			if len(open) == 0 {
+				return 0, 0, false
+			}
+			groupStart := open[len(open)-1]
+			open = open[:len(open)-1]
+			if groupStart >= 0 {
+				return groupStart, i + 1, true
+			}
+		}
+	}
+
+	return 0, 0, false
+}
+
+// Position is a byte range in a pattern.
+type Position struct {
+	Start int
+	End   int
+}
+
+// ComputeLineColumn returns the 1-indexed line and column of the byte at
+// offset in pattern, for patterns that span several lines, such as raw
+// string literals. Lines are separated by \n, and columns count bytes, like
+// go/token. Offsets outside the pattern are clamped to it.
+func ComputeLineColumn(pattern string, offset int) (line, col int) {
+	offset = min(max(offset, 0), len(pattern))
+	before := pattern[:offset]
+	return strings.Count(before, "\n") + 1, offset - strings.LastIndexByte(before, '\n')
+}
+
+// FindCaptureGroupPositions returns the byte range of every capturing group
+// in re, including its parentheses. The result is indexed by group number
+// minus one, so positions[0] is the range of group 1.
+//
+// Groups are located by counting parentheses in the original pattern. If
+// that fails, for example because re was not parsed from pattern, the
+// printed form of each group is searched for instead, starting after the
+// previous group so repeated groups like (a)(a) are told apart. Groups
+// that cannot be found get the range of the whole pattern.
+func FindCaptureGroupPositions(re *syntax.Regexp, pattern string) []Position {
+	groups := make(map[int]*syntax.Regexp)
+	Walk(re, func(node *syntax.Regexp) (bool, error) {
+		if node.Op == syntax.OpCapture {
+			if _, seen := groups[node.Cap]; !seen {
+				groups[node.Cap] = node
+			}
+		}
+		return true, nil
+	})
+
+	positions := make([]Position, len(groups))
+	offset := 0
+	for i := range positions {
+		node, ok := groups[i+1]
+		if !ok {
+			positions[i] = Position{Start: 0, End: len(pattern)}
+			continue
+		}
+
+		if start, end, ok := CaptureGroupSpan(pattern, node.Cap); ok {
+			positions[i] = Position{Start: start, End: end}
+			offset = start + 1
+			continue
+		}
+
+		printed := node.String()
+		if start, ok := indexOutsideEscapes(pattern[offset:], printed); ok {
+			start += offset
+			positions[i] = Position{Start: start, End: start + len(printed)}
+			offset = start + 1
+			continue
+		}
+
+		positions[i] = Position{Start: 0, End: len(pattern)}
+	}
+
+	return positions
+}
+
+// isCapturingGroup reports whether the group opening at the start of s captures.
+func isCapturingGroup(s string) bool {
+	if len(s) < 2 || s[1] != '?' {
+		return true
+	}
+	// Named groups: (?P<name>...) and (?<name>...)
+	return len(s) > 3 && (s[2] == 'P' && s[3] == '<' || s[2] == '<')
+}
+
+// PositionOf returns the byte range of the sub-expression re in the original
+// pattern. Capture groups, and quantifiers applied directly to them, are
+// located by group index. Other nodes are located by their printed form,
+// skipping occurrences that start inside an escape sequence or a character
+// class. The printed form is normalized (\d prints as [0-9]), so when it does
+// not appear in the pattern the whole pattern is returned.
+func PositionOf(re *syntax.Regexp, pattern string) (start, end int) {
+	switch {
+	case re.Op == syntax.OpCapture:
+		if start, end, ok := CaptureGroupSpan(pattern, re.Cap); ok {
+			return start, end
+		}
+	case IsQuantifier(re) && len(re.Sub) == 1 && re.Sub[0].Op == syntax.OpCapture:
+		if start, end, ok := CaptureGroupSpan(pattern, re.Sub[0].Cap); ok {
+			if n := quantifierLen(pattern[end:]); n > 0 {
+				return start, end + n
+			}
+		}
+	}
+
+	if start, ok := indexOutsideEscapes(pattern, re.String()); ok {
+		return start, start + len(re.String())
+	}
+
+	return 0, len(pattern)
+}
+
+// indexOutsideEscapes is like strings.Index but only accepts occurrences that
+// start at a token boundary: not in the middle of an escape sequence and not
+// inside a character class.
+func indexOutsideEscapes(pattern, sub string) (int, bool) {
+	if sub == "" {
+		return 0, false
+	}
+
+	// boundary[i] is true if a token can start at pattern[i]
+	boundary := make([]bool, len(pattern)+1)
+	boundary[len(pattern)] = true
+	inClass := false
+	for i := 0; i < len(pattern); i++ {
+		boundary[i] = !inClass
+		switch c := pattern[i]; {
+		case c == '\\':
+			i++ // skip escaped character
+		case inClass:
+			if c == ']' {
+				inClass = false
+			}
+		case c == '[':
+			inClass = true
+			// A ']' right after '[' or '[^' is a literal
+			if i+1 < len(pattern) && pattern[i+1] == '^' {
+				i++
+			}
+			if i+1 < len(pattern) && pattern[i+1] == ']' {
+				i++
+			}
+		}
+	}
+
+	for offset := 0; offset < len(pattern); {
+		i := strings.Index(pattern[offset:], sub)
+		if i < 0 {
+			return 0, false
+		}
+		start := offset + i
+		if boundary[start] && boundary[start+len(sub)] {
+			return start, true
+		}
+		offset = start + 1
+	}
+
+	return 0, false
+}
+
+// quantifierLen returns the length of the quantifier at the start of s
+// (*, +, ?, {n}, {n,} or {n,m}, optionally followed by a lazy ?), or 0.
+func quantifierLen(s string) int {
+	n := 0
+	switch {
+	case s == "":
+		return 0
+	case s[0] == '*' || s[0] == '+' || s[0] == '?':
+		n = 1
+	case s[0] == '{':
+		end := strings.IndexByte(s, '}')
+		if end < 0 {
+			return 0
+		}
+		n = end + 1
+	default:
+		return 0
+	}
+
+	if n < len(s) && s[n] == '?' {
+		n++
+	}
+	return n
+}
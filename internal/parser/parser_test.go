@@ -1,7 +1,10 @@
 package parser
 
 import (
+	"errors"
 	"regexp/syntax"
+	"slices"
+	"strconv"
 	"testing"
 )
 
@@ -36,6 +39,52 @@ func TestParser_Parse(t *testing.T) {
 	}
 }
 
+func TestParser_ParseWithFlags(t *testing.T) {
+	p := NewParser()
+
+	re, err := p.ParseWithFlags("a", syntax.Perl|syntax.FoldCase)
+	if err != nil {
+		t.Fatalf("ParseWithFlags() error = %v", err)
+	}
+	if re.Flags&syntax.FoldCase == 0 {
+		t.Errorf("ParseWithFlags() = %v, want a case-insensitive literal", re)
+	}
+
+	if _, err := p.ParseWithFlags(`\d+`, syntax.POSIX|syntax.OneLine); !errors.Is(err, ErrInvalidPattern) {
+		t.Errorf("ParseWithFlags(POSIX) error = %v, want ErrInvalidPattern", err)
+	}
+	if _, err := p.Parse(`\d+`); err != nil {
+		t.Errorf("Parse() error = %v, want the parser's own flags to be used", err)
+	}
+}
+
+func TestParser_ParseMulti(t *testing.T) {
+	p := NewParser()
+
+	results, err := p.ParseMulti([]string{"a+", "(a", "b*", "[z-a]"})
+	var multi *MultiParseError
+	if !errors.As(err, &multi) {
+		t.Fatalf("ParseMulti() error = %v, want a *MultiParseError", err)
+	}
+	if len(multi.Errors) != 2 || multi.Errors[0].Index != 1 || multi.Errors[1].Index != 3 {
+		t.Errorf("ParseMulti() failed patterns = %v, want indices 1 and 3", multi.Errors)
+	}
+	if !errors.Is(err, ErrInvalidPattern) {
+		t.Error("ParseMulti() error does not wrap ErrInvalidPattern")
+	}
+	if results[0] == nil || results[1] != nil || results[2] == nil || results[3] != nil {
+		t.Errorf("ParseMulti() results = %v, want nil only for failed patterns", results)
+	}
+
+	results, err = p.ParseMulti([]string{"a+", "b*"})
+	if err != nil {
+		t.Fatalf("ParseMulti() error = %v", err)
+	}
+	if len(results) != 2 || results[0].Op != syntax.OpPlus {
+		t.Errorf("ParseMulti() = %v", results)
+	}
+}
+
 func TestParser_Validate(t *testing.T) {
 	p := NewParser()
 
@@ -184,12 +233,260 @@ func TestWalk(t *testing.T) {
 	re := p.MustParse(pattern)
 
 	count := 0
-	Walk(re, func(node *syntax.Regexp) bool {
+	err := Walk(re, func(node *syntax.Regexp) (bool, error) {
 		count++
-		return true
+		return true, nil
 	})
 
+	if err != nil {
+		t.Errorf("Walk() error = %v", err)
+	}
 	if count == 0 {
 		t.Error("Walk() visited 0 nodes, expected more")
 	}
 }
+
+func TestWalk_Error(t *testing.T) {
+	re := NewParser().MustParse("(a+)+b*c")
+	errStop := errors.New("stop")
+
+	var visited []syntax.Op
+	err := Walk(re, func(node *syntax.Regexp) (bool, error) {
+		visited = append(visited, node.Op)
+		if node.Op == syntax.OpPlus {
+			return true, errStop
+		}
+		return true, nil
+	})
+
+	if !errors.Is(err, errStop) {
+		t.Errorf("Walk() error = %v, want %v", err, errStop)
+	}
+	// Nothing is visited after the first +
+	if got := visited[len(visited)-1]; got != syntax.OpPlus {
+		t.Errorf("last visited node = %v, want %v", got, syntax.OpPlus)
+	}
+	for _, op := range visited {
+		if op == syntax.OpStar {
+			t.Errorf("Walk() visited %v after the error", op)
+		}
+	}
+}
+
+func TestWalk_SkipChildren(t *testing.T) {
+	re := NewParser().MustParse("(a+)+b")
+
+	count := 0
+	err := Walk(re, func(node *syntax.Regexp) (bool, error) {
+		count++
+		return node.Op != syntax.OpPlus, nil
+	})
+
+	if err != nil {
+		t.Errorf("Walk() error = %v", err)
+	}
+	// concat, plus and the literal b; the body of (a+)+ is skipped
+	if count != 3 {
+		t.Errorf("Walk() visited %d nodes, want 3", count)
+	}
+}
+
+func TestClone(t *testing.T) {
+	p := NewParser()
+
+	re := p.MustParse("(a+)+b*")
+	clone := Clone(re)
+
+	if !clone.Equal(re) {
+		t.Fatalf("Clone() = %s, want %s", clone, re)
+	}
+
+	// Mutating the clone must not affect the original
+	clone.Sub[0].Sub[0].Sub[0].Sub[0].Rune[0] = 'z'
+	if re.String() != "(a+)+b*" {
+		t.Errorf("original mutated through clone: %s", re)
+	}
+}
+
+func TestCaptureGroupSpan(t *testing.T) {
+	tests := []struct {
+		pattern string
+		index   int
+		want    string
+		wantOK  bool
+	}{
+		{`(a+)+`, 1, `(a+)`, true},
+		{`^(\w+\s?)*$`, 1, `(\w+\s?)`, true},
+		{`(a)(b(c))`, 2, `(b(c))`, true},
+		{`(a)(b(c))`, 3, `(c)`, true},
+		{`(?:x)(y)`, 1, `(y)`, true},
+		{`(?P<name>a+)`, 1, `(?P<name>a+)`, true},
+		{`\((a)\)`, 1, `(a)`, true},
+		{`[(](b)`, 1, `(b)`, true},
+		{`[]()](b)`, 1, `(b)`, true},
+		{`(a)`, 2, ``, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			start, end, ok := CaptureGroupSpan(tt.pattern, tt.index)
+			if ok != tt.wantOK {
+				t.Fatalf("CaptureGroupSpan(%q, %d) ok = %v, want %v", tt.pattern, tt.index, ok, tt.wantOK)
+			}
+			if ok && tt.pattern[start:end] != tt.want {
+				t.Errorf("CaptureGroupSpan(%q, %d) = %q, want %q", tt.pattern, tt.index, tt.pattern[start:end], tt.want)
+			}
+		})
+	}
+}
+
+func TestAlternations(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    []string // text of each alternation, then its branch count
+	}{
+		{`abc`, nil},
+		{`a|b|c`, []string{`a|b|c`, "3"}},
+		{`^(a|b|c)+$`, []string{`a|b|c`, "3"}},
+		{`(?:x|y)|(?P<n>ab|cd|ef)`, []string{`(?:x|y)|(?P<n>ab|cd|ef)`, "2", `x|y`, "2", `ab|cd|ef`, "3"}},
+		{`(?i:a|b)`, []string{`a|b`, "2"}},
+		{`a\|b`, nil},
+		{`[|]|[[:alpha:]|]`, []string{`[|]|[[:alpha:]|]`, "2"}},
+		{`[]|]x`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			var got []string
+			for _, alt := range Alternations(tt.pattern) {
+				got = append(got, tt.pattern[alt.Start:alt.End], strconv.Itoa(alt.Branches))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Alternations(%q) = %q, want %q", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindCaptureGroupPositions(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{`^(\d+)-(\d+)$`, []string{`(\d+)`, `(\d+)`}},
+		{`(a)(b(c))`, []string{`(a)`, `(b(c))`, `(c)`}},
+		{`(?:x)(y)+`, []string{`(y)`}},
+		{`\((a)\)[(]`, []string{`(a)`}},
+		{`[a-z]+`, nil},
+	}
+
+	p := NewParser()
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re, err := p.ParseRaw(tt.pattern)
+			if err != nil {
+				t.Fatalf("Failed to parse: %v", err)
+			}
+
+			positions := FindCaptureGroupPositions(re, tt.pattern)
+			if len(positions) != len(tt.want) {
+				t.Fatalf("FindCaptureGroupPositions(%q) returned %d positions, want %d", tt.pattern, len(positions), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if got := tt.pattern[positions[i].Start:positions[i].End]; got != want {
+					t.Errorf("group %d = %q, want %q", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFindCaptureGroupPositions_PrintedForm(t *testing.T) {
+	// The stray ) stops parenthesis counting, so groups are found by their
+	// printed form, each searched for after the previous one
+	re := NewParser().MustParse(`(ab)(ab)`)
+	pattern := `)(ab)(ab)`
+
+	positions := FindCaptureGroupPositions(re, pattern)
+	if len(positions) != 2 {
+		t.Fatalf("FindCaptureGroupPositions() returned %d positions, want 2", len(positions))
+	}
+	if positions[0].Start != 1 || positions[1].Start != 5 {
+		t.Errorf("FindCaptureGroupPositions() = %v, want groups at 1 and 5", positions)
+	}
+}
+
+func TestComputeLineColumn(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		offset    int
+		line, col int
+	}{
+		{"(a+)+", 0, 1, 1},
+		{"(a+)+", 3, 1, 4},
+		{"^abc\n(a+)+$", 4, 1, 5},
+		{"^abc\n(a+)+$", 5, 2, 1},
+		{"^abc\n(a+)+$", 7, 2, 3},
+		{"a\n\nb", 3, 3, 1},
+		{"ab", 10, 1, 3},
+		{"ab", -1, 1, 1},
+	}
+
+	for _, tt := range tests {
+		line, col := ComputeLineColumn(tt.pattern, tt.offset)
+		if line != tt.line || col != tt.col {
+			t.Errorf("ComputeLineColumn(%q, %d) = %d, %d, want %d, %d", tt.pattern, tt.offset, line, col, tt.line, tt.col)
+		}
+	}
+}
+
+func TestPositionOf(t *testing.T) {
+	p := NewParser()
+
+	// find returns the first node matching the predicate
+	find := func(re *syntax.Regexp, match func(*syntax.Regexp) bool) *syntax.Regexp {
+		var found *syntax.Regexp
+		Walk(re, func(node *syntax.Regexp) (bool, error) {
+			if found == nil && match(node) {
+				found = node
+			}
+			return found == nil, nil
+		})
+		return found
+	}
+	isOuterQuantifier := func(node *syntax.Regexp) bool {
+		return IsQuantifier(node) && len(node.Sub) > 0 && node.Sub[0].Op == syntax.OpCapture
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		match   func(*syntax.Regexp) bool
+		want    string
+	}{
+		{"quantified group", `^foo(a+)+$`, isOuterQuantifier, `(a+)+`},
+		{"lazy quantifier", `x(ab)*?y`, isOuterQuantifier, `(ab)*?`},
+		{"bounded quantifier", `x(ab){2,}y`, isOuterQuantifier, `(ab){2,}`},
+		{"capture", `a(b)c`, IsCapture, `(b)`},
+		{"alternation", `x(?:foo|bar)y`, IsAlternation, `foo|bar`},
+		{"skips character class", `[a+]a+`, func(node *syntax.Regexp) bool { return node.Op == syntax.OpPlus }, `a+`},
+		{"skips escape", `\a+a+`, func(node *syntax.Regexp) bool {
+			return node.Op == syntax.OpPlus && node.Sub[0].Rune[0] == 'a'
+		}, `a+`},
+		{"normalized form falls back to whole pattern", `x\d+`, func(node *syntax.Regexp) bool { return node.Op == syntax.OpPlus }, `x\d+`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := find(p.MustParse(tt.pattern), tt.match)
+			if node == nil {
+				t.Fatalf("no matching node in %q", tt.pattern)
+			}
+			start, end := PositionOf(node, tt.pattern)
+			if got := tt.pattern[start:end]; got != tt.want {
+				t.Errorf("PositionOf(%s, %q) = %q, want %q", node, tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package pump
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// calibrationSizes are the input lengths CalibrateForEngine times.
+var calibrationSizes = []int{1, 10, 100, 1000}
+
+// calibrationRuns is how many times each input is matched, so that the
+// time per match is not dominated by timer resolution.
+const calibrationRuns = 200
+
+// CalibrateForEngine measures how fast a regex engine processes input on
+// this machine, in nanoseconds per character. It matches re against inputs
+// of 1, 10, 100 and 1000 characters and fits a line through the times; the
+// slope is returned. re should be a safe pattern that reads its whole
+// input. If re is nil, ^.{n}$ is compiled for each input length n.
+//
+// Multiplying the result by the number of characters an adversarial input
+// makes the engine step through gives an estimate of its match time, as
+// regret.PumpPattern.EstimateMatchTime does.
+func (g *Generator) CalibrateForEngine(re *regexp.Regexp) (float64, error) {
+	xs := make([]float64, len(calibrationSizes))
+	ys := make([]float64, len(calibrationSizes))
+	for i, n := range calibrationSizes {
+		r := re
+		if r == nil {
+			var err error
+			if r, err = regexp.Compile(fmt.Sprintf("^.{%d}$", n)); err != nil {
+				return 0, fmt.Errorf("calibrate: %w", err)
+			}
+		}
+
+		input := strings.Repeat("a", n)
+		start := time.Now()
+		for j := 0; j < calibrationRuns; j++ {
+			r.MatchString(input)
+		}
+		xs[i] = float64(n)
+		ys[i] = float64(time.Since(start).Nanoseconds()) / calibrationRuns
+	}
+
+	nsPerChar := slope(xs, ys)
+	if nsPerChar <= 0 {
+		return 0, errors.New("calibrate: match time did not grow with input length")
+	}
+	return nsPerChar, nil
+}
+
+// slope returns the slope of the least-squares line through the points
+// (xs[i], ys[i]).
+func slope(xs, ys []float64) float64 {
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= float64(len(xs))
+	meanY /= float64(len(ys))
+
+	var cov, varX float64
+	for i := range xs {
+		cov += (xs[i] - meanX) * (ys[i] - meanY)
+		varX += (xs[i] - meanX) * (xs[i] - meanX)
+	}
+	if varX == 0 {
+		return 0
+	}
+	return cov / varX
+}
@@ -0,0 +1,30 @@
+package pump
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestCalibrateForEngine(t *testing.T) {
+	g := NewGenerator(nil)
+	for _, re := range []*regexp.Regexp{nil, regexp.MustCompile(`^[a-z]*$`)} {
+		nsPerChar, err := g.CalibrateForEngine(re)
+		if err != nil {
+			t.Fatalf("CalibrateForEngine(%v) error = %v", re, err)
+		}
+		if nsPerChar <= 0 {
+			t.Errorf("CalibrateForEngine(%v) = %v, want > 0", re, nsPerChar)
+		}
+	}
+}
+
+func TestSlope(t *testing.T) {
+	xs := []float64{1, 10, 100, 1000}
+	ys := []float64{52, 70, 250, 2050} // 2ns per character plus 50ns
+	if got := slope(xs, ys); got < 1.999 || got > 2.001 {
+		t.Errorf("slope() = %v, want 2", got)
+	}
+	if got := slope([]float64{5, 5}, []float64{1, 2}); got != 0 {
+		t.Errorf("slope() with constant xs = %v, want 0", got)
+	}
+}
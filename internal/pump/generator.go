@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"regexp/syntax"
 	"strings"
+	"unicode"
+
+	"github.com/theakshaypant/regret/internal/parser"
 )
 
 // Options contains configuration for pump pattern generation.
@@ -12,6 +15,12 @@ type Options struct {
 	PumpSize       int  // Size of pumped component (default: 10)
 	MaxPumpSize    int  // Maximum pump size (default: 100)
 	IncludeFailure bool // Include failing suffix (default: true)
+
+	// UseGeometricGrowth makes generated patterns suggest the doubling sizes
+	// 1, 2, 4, ..., 32 instead of sizes growing by a fixed step, so that the
+	// inputs of GenerateSequence show the match time doubling at each step
+	// for exponential patterns.
+	UseGeometricGrowth bool
 }
 
 // PumpPattern represents an adversarial input pattern.
@@ -67,6 +76,65 @@ func (g *Generator) Generate(re *syntax.Regexp, pattern string) ([]PumpPattern,
 	return patterns, nil
 }
 
+// failSuffixes are the suffixes GenerateMultiple varies between.
+var failSuffixes = []string{"x", "!", " ", "\n"}
+
+// GenerateMultiple returns up to n structurally distinct pump patterns for
+// building a test corpus. It varies, from fastest to slowest, the pump
+// mechanism (nested, overlapping or alternation, as detected by Generate),
+// the character used as the pump component, and the failing suffix. Pump
+// characters are taken from the innermost repeated expression, the one
+// that matches ambiguously, so that every variant still pumps it. Each
+// returned pattern has a unique Description.
+func (g *Generator) GenerateMultiple(re *syntax.Regexp, pattern string, n int) ([]PumpPattern, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	mechanisms, err := g.Generate(re, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	body := parser.InnermostQuantifiedBody(re)
+	if body == nil {
+		body = re
+	}
+	chars := extractPumpChars(body)
+	seen := make(map[string]bool)
+	var patterns []PumpPattern
+
+	for _, suffix := range failSuffixes {
+		for _, char := range chars {
+			for _, base := range mechanisms {
+				p := base
+				// Single-character pumps are interchangeable; multi-character
+				// components such as alternation branches are kept as is
+				if len([]rune(p.PumpComponent)) == 1 {
+					p.PumpComponent = char
+				}
+				if p.PumpComponent == suffix {
+					continue
+				}
+				p.FailSuffix = suffix
+				p.Description = fmt.Sprintf("%s (pump %q, suffix %q)", base.Description, p.PumpComponent, suffix)
+
+				if seen[p.Description] {
+					continue
+				}
+				seen[p.Description] = true
+				patterns = append(patterns, p)
+
+				if len(patterns) == n {
+					return patterns, nil
+				}
+			}
+		}
+	}
+
+	return patterns, nil
+}
+
 // generateNestedQuantifierPump generates pump for patterns like (a+)+.
 func (g *Generator) generateNestedQuantifierPump(re *syntax.Regexp) PumpPattern {
 	// For (a+)+, generate aaaaaa...x where x doesn't match
@@ -74,12 +142,19 @@ func (g *Generator) generateNestedQuantifierPump(re *syntax.Regexp) PumpPattern
 
 	baseChar := extractPumpChar(re)
 
+	description := "Nested quantifiers cause exponential backtracking. Each 'a' doubles the number of ways to match."
+	if outer, inner := findNestedQuantifiers(re); outer != nil {
+		description = fmt.Sprintf("The outer `%s` quantifier on `%s` combined with the inner `%s` causes exponential backtracking: "+
+			"there are 2^n ways to assign n `%s` characters to repetitions of the outer and inner quantifiers.",
+			quantifierSymbol(outer), outer.Sub[0].String(), quantifierSymbol(inner), baseChar)
+	}
+
 	return PumpPattern{
 		BaseString:    "",
 		PumpComponent: baseChar,
 		FailSuffix:    "x",
-		Description:   "Nested quantifiers cause exponential backtracking. Each 'a' doubles the number of ways to match.",
-		Sizes:         []int{5, 10, 15, 20, 25},
+		Description:   description,
+		Sizes:         g.sizes(5, 10, 15, 20, 25),
 	}
 }
 
@@ -90,12 +165,24 @@ func (g *Generator) generateOverlappingQuantifierPump(re *syntax.Regexp) PumpPat
 
 	baseChar := extractPumpChar(re)
 
+	description := "Overlapping quantifiers cause polynomial backtracking. Regex tries all ways to split input between quantifiers."
+	if run := findAdjacentQuantifiers(re); run != nil {
+		quoted := make([]string, len(run))
+		for i, q := range run {
+			quoted[i] = "`" + q.String() + "`"
+		}
+		list := strings.Join(quoted[:len(quoted)-1], ", ") + " and " + quoted[len(quoted)-1]
+		description = fmt.Sprintf("The adjacent quantifiers %s can match the same `%s` characters, which causes polynomial backtracking: "+
+			"there are about n^%d ways to split n `%s` characters between them.",
+			list, baseChar, len(run), baseChar)
+	}
+
 	return PumpPattern{
 		BaseString:    "",
 		PumpComponent: baseChar,
 		FailSuffix:    "x",
-		Description:   "Overlapping quantifiers cause polynomial backtracking. Regex tries all ways to split input between quantifiers.",
-		Sizes:         []int{10, 20, 30, 40, 50},
+		Description:   description,
+		Sizes:         g.sizes(10, 20, 30, 40, 50),
 	}
 }
 
@@ -104,12 +191,19 @@ func (g *Generator) generateAlternationPump(re *syntax.Regexp) PumpPattern {
 	// For (a|ab)+, generate ababab...x
 	// This forces backtracking between the alternation branches
 
+	description := "Overlapping alternation branches cause backtracking. Regex tries each branch at each position."
+	if alt, i, j := findOverlappingBranches(re); alt != nil {
+		description = fmt.Sprintf("The branches `%s` and `%s` of the alternation `%s` can match the same prefix, which causes backtracking: "+
+			"at each position the regex tries `%s`, then backtracks to try `%s`.",
+			alt.Sub[i].String(), alt.Sub[j].String(), alt.String(), alt.Sub[i].String(), alt.Sub[j].String())
+	}
+
 	return PumpPattern{
 		BaseString:    "",
 		PumpComponent: "ab",
 		FailSuffix:    "x",
-		Description:   "Overlapping alternation branches cause backtracking. Regex tries each branch at each position.",
-		Sizes:         []int{5, 10, 15, 20},
+		Description:   description,
+		Sizes:         g.sizes(5, 10, 15, 20),
 	}
 }
 
@@ -125,7 +219,37 @@ func (g *Generator) generateGenericPump(re *syntax.Regexp) PumpPattern {
 		PumpComponent: baseChar,
 		FailSuffix:    "x",
 		Description:   "Generic pump pattern to test regex performance",
-		Sizes:         []int{10, 50, 100},
+		Sizes:         g.sizes(10, 50, 100),
+	}
+}
+
+// witnessSizes are the suggested pump sizes of witness-derived patterns.
+var witnessSizes = []int{1, 5, 10, 20, 50}
+
+// geometricSizes are the suggested pump sizes with UseGeometricGrowth.
+var geometricSizes = []int{1, 2, 4, 8, 16, 32}
+
+// sizes returns the suggested pump sizes of a new pattern: a copy of
+// geometricSizes with UseGeometricGrowth, or else of arithmetic.
+func (g *Generator) sizes(arithmetic ...int) []int {
+	if g.opts.UseGeometricGrowth {
+		return append([]int(nil), geometricSizes...)
+	}
+	return append([]int(nil), arithmetic...)
+}
+
+// GenerateFromWitness builds a pump pattern from the strings of a formal
+// EDA or IDA witness, such as those found by the product automaton in the
+// ambiguity package, instead of the characters guessed by extractPumpChar.
+// The input prefix + pump^n + suffix is matched in exponentially or
+// polynomially many ways before the suffix rejects it.
+func (g *Generator) GenerateFromWitness(prefix, pump, suffix string) PumpPattern {
+	return PumpPattern{
+		BaseString:    prefix,
+		PumpComponent: pump,
+		FailSuffix:    suffix,
+		Description:   "Formal EDA/IDA witness",
+		Sizes:         g.sizes(witnessSizes...),
 	}
 }
 
@@ -156,52 +280,78 @@ func (p *PumpPattern) GenerateSequence() []string {
 // Helper functions
 
 func hasNestedQuantifiers(re *syntax.Regexp) bool {
-	result := false
+	outer, _ := findNestedQuantifiers(re)
+	return outer != nil
+}
+
+// findNestedQuantifiers returns the first quantifier whose body contains
+// another quantifier, and the first such inner quantifier.
+func findNestedQuantifiers(re *syntax.Regexp) (outer, inner *syntax.Regexp) {
 	walk(re, func(node *syntax.Regexp) bool {
+		if outer != nil {
+			return false
+		}
 		if isQuantifier(node) {
 			for _, sub := range node.Sub {
-				if containsQuantifier(sub) {
-					result = true
+				if q := firstQuantifier(sub); q != nil {
+					outer, inner = node, q
 					return false
 				}
 			}
 		}
 		return true
 	})
-	return result
+	return outer, inner
 }
 
 func hasOverlappingQuantifiers(re *syntax.Regexp) bool {
-	result := false
+	return findAdjacentQuantifiers(re) != nil
+}
+
+// findAdjacentQuantifiers returns the first run of two or more adjacent
+// quantifiers in a concatenation, as long as the run goes.
+func findAdjacentQuantifiers(re *syntax.Regexp) []*syntax.Regexp {
+	var run []*syntax.Regexp
 	walk(re, func(node *syntax.Regexp) bool {
+		if run != nil {
+			return false
+		}
 		if node.Op == syntax.OpConcat {
-			consecutive := 0
-			for _, sub := range node.Sub {
-				if isQuantifier(sub) {
-					consecutive++
-					if consecutive >= 2 {
-						result = true
-						return false
-					}
-				} else {
-					consecutive = 0
+			start := 0
+			for i, sub := range node.Sub {
+				if !isQuantifier(sub) {
+					start = i + 1
+					continue
+				}
+				if i-start >= 1 && (i+1 == len(node.Sub) || !isQuantifier(node.Sub[i+1])) {
+					run = node.Sub[start : i+1]
+					return false
 				}
 			}
 		}
 		return true
 	})
-	return result
+	return run
 }
 
 func hasOverlappingAlternation(re *syntax.Regexp) bool {
-	result := false
+	alt, _, _ := findOverlappingBranches(re)
+	return alt != nil
+}
+
+// findOverlappingBranches returns the first alternation with two branches
+// i < j that share a prefix.
+func findOverlappingBranches(re *syntax.Regexp) (alt *syntax.Regexp, i, j int) {
 	walk(re, func(node *syntax.Regexp) bool {
+		if alt != nil {
+			return false
+		}
 		if node.Op == syntax.OpAlternate && len(node.Sub) >= 2 {
 			// Simple check: if any branches share a prefix
-			for i := 0; i < len(node.Sub); i++ {
-				for j := i + 1; j < len(node.Sub); j++ {
-					if branchesOverlap(node.Sub[i], node.Sub[j]) {
-						result = true
+			for a := 0; a < len(node.Sub); a++ {
+				for b := a + 1; b < len(node.Sub); b++ {
+					if branchesOverlap(node.Sub[a], node.Sub[b]) {
+						alt, i, j = node, a, b
 						return false
 					}
 				}
@@ -209,7 +359,7 @@ func hasOverlappingAlternation(re *syntax.Regexp) bool {
 		}
 		return true
 	})
-	return result
+	return alt, i, j
 }
 
 func branchesOverlap(a, b *syntax.Regexp) bool {
@@ -225,6 +375,14 @@ func branchesOverlap(a, b *syntax.Regexp) bool {
 }
 
 func extractPumpChar(re *syntax.Regexp) string {
+	// Pump a character the innermost repeated expression accepts, so that
+	// ([0-9]+)+ is pumped with digits
+	if body := parser.InnermostQuantifiedBody(re); body != nil {
+		if r, ok := parser.SampleRune(body); ok {
+			return string(r)
+		}
+	}
+
 	// Try to extract a character that can be pumped
 	var result string
 
@@ -252,6 +410,43 @@ func extractPumpChar(re *syntax.Regexp) string {
 	return result
 }
 
+// extractPumpChars collects the printable characters re can match in order
+// of appearance: literals, the bounds of each class range, and 'a'
+// for wildcards. Falls back to 'a' if there are none.
+func extractPumpChars(re *syntax.Regexp) []string {
+	var chars []string
+	seen := make(map[rune]bool)
+	add := func(r rune) {
+		if !seen[r] && unicode.IsPrint(r) {
+			seen[r] = true
+			chars = append(chars, string(r))
+		}
+	}
+
+	walk(re, func(node *syntax.Regexp) bool {
+		switch node.Op {
+		case syntax.OpLiteral:
+			for _, r := range node.Rune {
+				add(r)
+			}
+		case syntax.OpCharClass:
+			for i := 0; i+1 < len(node.Rune); i += 2 {
+				add(node.Rune[i])
+				add(node.Rune[i+1])
+			}
+		case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+			add('a')
+		}
+		return true
+	})
+
+	if len(chars) == 0 {
+		chars = []string{"a"}
+	}
+
+	return chars
+}
+
 func walk(re *syntax.Regexp, visitor func(*syntax.Regexp) bool) {
 	if !visitor(re) {
 		return
@@ -269,16 +464,44 @@ func isQuantifier(re *syntax.Regexp) bool {
 	return false
 }
 
-func containsQuantifier(re *syntax.Regexp) bool {
+// firstQuantifier returns the first quantifier in re, in pre-order, or nil.
+func firstQuantifier(re *syntax.Regexp) *syntax.Regexp {
 	if isQuantifier(re) {
-		return true
+		return re
 	}
 	for _, sub := range re.Sub {
-		if containsQuantifier(sub) {
-			return true
+		if q := firstQuantifier(sub); q != nil {
+			return q
 		}
 	}
-	return false
+	return nil
+}
+
+// quantifierSymbol returns the operator of a quantifier as written in a
+// pattern, such as "+", "*?" or "{2,5}".
+func quantifierSymbol(re *syntax.Regexp) string {
+	var symbol string
+	switch re.Op {
+	case syntax.OpStar:
+		symbol = "*"
+	case syntax.OpPlus:
+		symbol = "+"
+	case syntax.OpQuest:
+		symbol = "?"
+	case syntax.OpRepeat:
+		switch {
+		case re.Max == -1:
+			symbol = fmt.Sprintf("{%d,}", re.Min)
+		case re.Min == re.Max:
+			symbol = fmt.Sprintf("{%d}", re.Min)
+		default:
+			symbol = fmt.Sprintf("{%d,%d}", re.Min, re.Max)
+		}
+	}
+	if re.Flags&syntax.NonGreedy != 0 {
+		symbol += "?"
+	}
+	return symbol
 }
 
 // String returns a string representation of the pump pattern.
@@ -1,9 +1,13 @@
 package pump
 
 import (
+	"reflect"
 	"regexp/syntax"
 	"strings"
 	"testing"
+
+	"github.com/theakshaypant/regret/internal/ambiguity"
+	"github.com/theakshaypant/regret/internal/parser"
 )
 
 func TestNewGenerator(t *testing.T) {
@@ -209,6 +213,81 @@ func TestPumpPattern_GenerateInput(t *testing.T) {
 	}
 }
 
+func TestGenerateFromWitness(t *testing.T) {
+	g := NewGenerator(nil)
+
+	p := g.GenerateFromWitness("<", "ab", "!")
+	if p.BaseString != "<" || p.PumpComponent != "ab" || p.FailSuffix != "!" {
+		t.Errorf("GenerateFromWitness() = %+v, want the witness strings", p)
+	}
+	if p.Description != "Formal EDA/IDA witness" {
+		t.Errorf("Description = %q", p.Description)
+	}
+	if want := []int{1, 5, 10, 20, 50}; !reflect.DeepEqual(p.Sizes, want) {
+		t.Errorf("Sizes = %v, want %v", p.Sizes, want)
+	}
+	if got := p.GenerateInput(3); got != "<ababab!" {
+		t.Errorf("GenerateInput(3) = %q, want %q", got, "<ababab!")
+	}
+
+	// The sizes of one pattern must not leak into another
+	p.Sizes[0] = 100
+	if q := g.GenerateFromWitness("", "a", "x"); q.Sizes[0] != 1 {
+		t.Errorf("Sizes[0] = %d after modifying another pattern, want 1", q.Sizes[0])
+	}
+}
+
+func TestGenerator_UseGeometricGrowth(t *testing.T) {
+	want := []int{1, 2, 4, 8, 16, 32}
+	g := NewGenerator(&Options{PumpSize: 10, MaxPumpSize: 100, IncludeFailure: true, UseGeometricGrowth: true})
+
+	for _, pattern := range []string{`(a+)+`, `a*a*`, `(a|ab)+`, `abc`} {
+		patterns, err := g.Generate(parser.NewParser().MustParse(pattern), pattern)
+		if err != nil {
+			t.Fatalf("Generate(%q) error = %v", pattern, err)
+		}
+		for _, p := range patterns {
+			if !reflect.DeepEqual(p.Sizes, want) {
+				t.Errorf("Generate(%q) Sizes = %v, want %v", pattern, p.Sizes, want)
+			}
+		}
+	}
+
+	p := g.GenerateFromWitness("", "a", "x")
+	if !reflect.DeepEqual(p.Sizes, want) {
+		t.Errorf("GenerateFromWitness() Sizes = %v, want %v", p.Sizes, want)
+	}
+	for i, input := range p.GenerateSequence() {
+		if got := strings.Count(input, "a"); got != 1<<i {
+			t.Errorf("input %d contains %d pumps, want %d", i, got, 1<<i)
+		}
+	}
+
+	// The sizes of one pattern must not leak into another
+	p.Sizes[0] = 100
+	if q := g.GenerateFromWitness("", "a", "x"); q.Sizes[0] != 1 {
+		t.Errorf("Sizes[0] = %d after modifying another pattern, want 1", q.Sizes[0])
+	}
+}
+
+func TestGenerateFromWitness_ProductAutomaton(t *testing.T) {
+	nfa, err := parser.BuildNFA(parser.NewParser().MustParse(`^(?:a|aa)+$`))
+	if err != nil {
+		t.Fatalf("BuildNFA() error = %v", err)
+	}
+	prefix, pump, suffix, ok := ambiguity.ExponentialWitness(nfa)
+	if !ok {
+		t.Fatal("ExponentialWitness() found no witness")
+	}
+
+	p := NewGenerator(nil).GenerateFromWitness(prefix, pump, suffix)
+	for _, input := range p.GenerateSequence() {
+		if nfa.Simulate(input) {
+			t.Errorf("witness input %q matches, want it rejected", input)
+		}
+	}
+}
+
 func TestPumpPattern_GenerateSequence(t *testing.T) {
 	pump := PumpPattern{
 		BaseString:    "",
@@ -304,6 +383,43 @@ func TestPumpDetection(t *testing.T) {
 	})
 }
 
+func TestGenerate_Description(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"(a+)+", "The outer `+` quantifier on `(a+)` combined with the inner `+` causes exponential backtracking: " +
+			"there are 2^n ways to assign n `a` characters to repetitions of the outer and inner quantifiers."},
+		{"(?:x{2,}?)*", "The outer `*` quantifier on `x{2,}?` combined with the inner `{2,}?`"},
+		{`\d+\d+\d+`, "The adjacent quantifiers `[0-9]+`, `[0-9]+` and `[0-9]+` can match the same `0` characters, " +
+			"which causes polynomial backtracking: there are about n^3 ways"},
+		{"(x|x+y)z", "The branches `x` and `x+y` of the alternation `x|x+y` can match the same prefix"},
+	}
+
+	generator := NewGenerator(nil)
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re, err := syntax.Parse(tt.pattern, syntax.Perl)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			patterns, err := generator.Generate(re, tt.pattern)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			var descriptions []string
+			for _, p := range patterns {
+				if strings.HasPrefix(p.Description, tt.want) {
+					return
+				}
+				descriptions = append(descriptions, p.Description)
+			}
+			t.Errorf("Generate(%s) descriptions = %q, want one starting with %q", tt.pattern, descriptions, tt.want)
+		})
+	}
+}
+
 func TestHelperFunctions(t *testing.T) {
 	t.Run("hasNestedQuantifiers", func(t *testing.T) {
 		tests := []struct {
@@ -363,8 +479,9 @@ func TestHelperFunctions(t *testing.T) {
 		}{
 			{"a+", "a"},
 			{"(x*)+", "x"},
-			{"[0-9]+", "a"}, // Falls back to 'a' for char classes
-			{".+", "a"},     // Falls back to 'a' for any char
+			{"[0-9]+", "0"},    // A character the class accepts
+			{"([A-Z]+)+", "A"}, // From the innermost repeated body
+			{".+", "a"},        // Prefers 'a' for any char
 		}
 
 		for _, tt := range tests {
@@ -429,3 +546,93 @@ func BenchmarkGenerateInput(b *testing.B) {
 		_ = pump.GenerateInput(100)
 	}
 }
+
+func TestGenerateMultiple(t *testing.T) {
+	generator := NewGenerator(nil)
+
+	tests := []struct {
+		name    string
+		pattern string
+		n       int
+		want    int
+	}{
+		{"nested quantifier", "(a+)+", 3, 3},
+		{"class with several pump characters", "([a-z0-9]+)*$", 10, 10},
+		{"fewer variants than requested", "(a+)+", 100, 4},
+		{"zero requested", "(a+)+", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re, err := syntax.Parse(tt.pattern, syntax.Perl)
+			if err != nil {
+				t.Fatalf("Failed to parse pattern: %v", err)
+			}
+
+			patterns, err := generator.GenerateMultiple(re, tt.pattern, tt.n)
+			if err != nil {
+				t.Fatalf("GenerateMultiple() error = %v", err)
+			}
+			if len(patterns) != tt.want {
+				t.Fatalf("GenerateMultiple() returned %d patterns, want %d", len(patterns), tt.want)
+			}
+
+			descriptions := make(map[string]bool)
+			for _, p := range patterns {
+				if descriptions[p.Description] {
+					t.Errorf("duplicate description %q", p.Description)
+				}
+				descriptions[p.Description] = true
+				if p.PumpComponent == p.FailSuffix {
+					t.Errorf("pump component %q equals fail suffix", p.PumpComponent)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateMultiple_PumpCharsFromAmbiguousPart(t *testing.T) {
+	pattern := `^(\d+)+[a-z]$`
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		t.Fatalf("Failed to parse pattern: %v", err)
+	}
+
+	patterns, err := NewGenerator(nil).GenerateMultiple(re, pattern, 20)
+	if err != nil {
+		t.Fatalf("GenerateMultiple() error = %v", err)
+	}
+	if len(patterns) == 0 {
+		t.Fatal("GenerateMultiple() returned no patterns")
+	}
+	for _, p := range patterns {
+		if p.PumpComponent < "0" || p.PumpComponent > "9" {
+			t.Errorf("pump component %q is not matched by \\d+ (%s)", p.PumpComponent, p.Description)
+		}
+	}
+}
+
+func TestGenerateMultiple_VariesComponents(t *testing.T) {
+	re, err := syntax.Parse("^([a-c]+)+$", syntax.Perl)
+	if err != nil {
+		t.Fatalf("Failed to parse pattern: %v", err)
+	}
+
+	patterns, err := NewGenerator(nil).GenerateMultiple(re, "^([a-c]+)+$", 20)
+	if err != nil {
+		t.Fatalf("GenerateMultiple() error = %v", err)
+	}
+
+	components := make(map[string]bool)
+	suffixes := make(map[string]bool)
+	for _, p := range patterns {
+		components[p.PumpComponent] = true
+		suffixes[p.FailSuffix] = true
+	}
+	if !components["a"] || !components["c"] {
+		t.Errorf("expected pump components a and c, got %v", components)
+	}
+	if len(suffixes) < 2 {
+		t.Errorf("expected several fail suffixes, got %v", suffixes)
+	}
+}
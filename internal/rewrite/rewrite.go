@@ -0,0 +1,304 @@
+// Package rewrite implements AST rewrite rules that turn dangerous regex
+// constructs into safer equivalents.
+package rewrite
+
+import (
+	"regexp/syntax"
+	"sort"
+	"unicode"
+
+	"github.com/theakshaypant/regret/internal/parser"
+)
+
+// Result contains the outcome of rewriting a regex.
+type Result struct {
+	Regexp  *syntax.Regexp // Rewritten AST (a copy, the input is never mutated)
+	Applied []string       // Descriptions of the rules that fired
+}
+
+// Changed reports whether any rewrite rule was applied.
+func (r *Result) Changed() bool {
+	return len(r.Applied) > 0
+}
+
+// Rewrite applies all rewrite rules to a copy of the regex AST.
+// Rules are applied bottom-up so that nested constructs collapse fully,
+// e.g. ((a+)+)+ becomes a+.
+func Rewrite(re *syntax.Regexp) *Result {
+	result := &Result{}
+	result.Regexp = rewriteNode(parser.Clone(re), result)
+	return result
+}
+
+func rewriteNode(re *syntax.Regexp, result *Result) *syntax.Regexp {
+	for i, sub := range re.Sub {
+		re.Sub[i] = rewriteNode(sub, result)
+	}
+
+	switch re.Op {
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest:
+		return collapseNestedQuantifier(re, result)
+	case syntax.OpConcat:
+		return mergeAdjacentQuantifiers(re, result)
+	}
+
+	return re
+}
+
+// collapseNestedQuantifier rewrites (x+)+ to x+, (x*)+ to x*, (x?)* to x*, etc.
+// Only quantifiers whose entire body is another quantifier are collapsed.
+func collapseNestedQuantifier(re *syntax.Regexp, result *Result) *syntax.Regexp {
+	inner := unwrapGroup(re.Sub[0])
+	if !isSimpleQuantifier(inner) {
+		return re
+	}
+
+	result.Applied = append(result.Applied, "collapse nested quantifier "+re.String())
+
+	return &syntax.Regexp{
+		Op:    combineQuantifiers(re.Op, inner.Op),
+		Flags: re.Flags,
+		Sub:   inner.Sub,
+	}
+}
+
+// mergeAdjacentQuantifiers rewrites x*x* to x*, x*x+ to x+, etc.
+func mergeAdjacentQuantifiers(re *syntax.Regexp, result *Result) *syntax.Regexp {
+	if len(re.Sub) < 2 {
+		return re
+	}
+
+	merged := make([]*syntax.Regexp, 0, len(re.Sub))
+	for _, sub := range re.Sub {
+		if len(merged) > 0 {
+			prev := merged[len(merged)-1]
+			if op, ok := mergeSiblings(prev, sub); ok {
+				result.Applied = append(result.Applied, "merge overlapping quantifiers "+prev.String()+sub.String())
+				merged[len(merged)-1] = &syntax.Regexp{
+					Op:    op,
+					Flags: prev.Flags,
+					Sub:   prev.Sub,
+				}
+				continue
+			}
+		}
+		merged = append(merged, sub)
+	}
+
+	if len(merged) == 1 {
+		return merged[0]
+	}
+
+	re.Sub = merged
+	return re
+}
+
+// mergeSiblings returns the quantifier equivalent to a followed by b when both
+// quantify the same expression.
+func mergeSiblings(a, b *syntax.Regexp) (syntax.Op, bool) {
+	if !isSimpleQuantifier(a) || !isSimpleQuantifier(b) {
+		return 0, false
+	}
+	if !a.Sub[0].Equal(b.Sub[0]) {
+		return 0, false
+	}
+
+	switch {
+	case a.Op == syntax.OpStar && b.Op == syntax.OpStar:
+		return syntax.OpStar, true
+	case a.Op == syntax.OpStar || b.Op == syntax.OpStar:
+		// x*x+ and x+x* match one or more; x*x? and x?x* match zero or more
+		if a.Op == syntax.OpPlus || b.Op == syntax.OpPlus {
+			return syntax.OpPlus, true
+		}
+		return syntax.OpStar, true
+	case a.Op == syntax.OpPlus && b.Op == syntax.OpQuest, a.Op == syntax.OpQuest && b.Op == syntax.OpPlus:
+		return syntax.OpPlus, true
+	}
+
+	// x+x+ and x?x? change the minimum/maximum count, leave them alone
+	return 0, false
+}
+
+// combineQuantifiers returns the single quantifier equivalent to outer(inner(x)).
+func combineQuantifiers(outer, inner syntax.Op) syntax.Op {
+	if outer == inner && outer != syntax.OpStar {
+		return outer
+	}
+	return syntax.OpStar
+}
+
+func isSimpleQuantifier(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest:
+		return len(re.Sub) == 1
+	}
+	return false
+}
+
+// unwrapGroup strips capture groups around an expression.
+func unwrapGroup(re *syntax.Regexp) *syntax.Regexp {
+	for re.Op == syntax.OpCapture && len(re.Sub) == 1 {
+		re = re.Sub[0]
+	}
+	return re
+}
+
+// Widen rewrites repeated groups whose body only matches single characters,
+// such as ([a-z]+[0-9]?)*, into a repeated character class of every
+// character the body can match, [0-9a-z]*. Unlike Rewrite, the result
+// matches a superset of the original strings.
+func Widen(re *syntax.Regexp) *Result {
+	result := &Result{}
+	result.Regexp = widenNode(parser.Clone(re), result)
+	return result
+}
+
+func widenNode(re *syntax.Regexp, result *Result) *syntax.Regexp {
+	if re.Op == syntax.OpStar || re.Op == syntax.OpPlus {
+		body := unwrapGroup(re.Sub[0])
+		var ranges []rune
+		if parser.HasQuantifier(body) && charRanges(body, &ranges) {
+			result.Applied = append(result.Applied, "widen "+re.String()+" to a character class")
+
+			op := re.Op
+			if canBeEmpty(body) {
+				op = syntax.OpStar
+			}
+			class := &syntax.Regexp{Op: syntax.OpCharClass, Rune: normalizeRanges(ranges)}
+			if len(class.Rune) == 2 && class.Rune[0] == class.Rune[1] {
+				class = &syntax.Regexp{Op: syntax.OpLiteral, Rune: class.Rune[:1]}
+			}
+			return &syntax.Regexp{Op: op, Flags: re.Flags, Sub: []*syntax.Regexp{class}}
+		}
+	}
+
+	for i, sub := range re.Sub {
+		re.Sub[i] = widenNode(sub, result)
+	}
+	return re
+}
+
+// Narrow rewrites a repeated group that itself contains an unbounded
+// quantifier, such as (a+b)+, into a single occurrence of the group:
+// (a+b)+ becomes (a+b) and (a+b)* becomes (a+b)?. Unlike Rewrite, the
+// result matches a subset of the original strings.
+func Narrow(re *syntax.Regexp) *Result {
+	result := &Result{}
+	result.Regexp = narrowNode(parser.Clone(re), result)
+	return result
+}
+
+func narrowNode(re *syntax.Regexp, result *Result) *syntax.Regexp {
+	if (re.Op == syntax.OpStar || re.Op == syntax.OpPlus) && hasUnboundedQuantifier(re.Sub[0]) {
+		result.Applied = append(result.Applied, "narrow "+re.String()+" to a single occurrence")
+		if re.Op == syntax.OpPlus {
+			return re.Sub[0]
+		}
+		return &syntax.Regexp{Op: syntax.OpQuest, Flags: re.Flags, Sub: re.Sub}
+	}
+
+	for i, sub := range re.Sub {
+		re.Sub[i] = narrowNode(sub, result)
+	}
+	return re
+}
+
+// charRanges adds the characters re can match to ranges, as lo, hi pairs
+// like syntax.Regexp.Rune, and reports whether re only matches strings of
+// those characters without any other constraint: literals, character
+// classes and quantifiers, concatenations and alternations of them.
+// Case-folded literals are not supported.
+func charRanges(re *syntax.Regexp, ranges *[]rune) bool {
+	switch re.Op {
+	case syntax.OpLiteral:
+		if re.Flags&syntax.FoldCase != 0 {
+			return false
+		}
+		for _, r := range re.Rune {
+			*ranges = append(*ranges, r, r)
+		}
+		return true
+	case syntax.OpCharClass:
+		*ranges = append(*ranges, re.Rune...)
+		return true
+	case syntax.OpAnyCharNotNL:
+		*ranges = append(*ranges, 0, '\n'-1, '\n'+1, unicode.MaxRune)
+		return true
+	case syntax.OpAnyChar:
+		*ranges = append(*ranges, 0, unicode.MaxRune)
+		return true
+	case syntax.OpEmptyMatch:
+		return true
+	case syntax.OpCapture, syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat,
+		syntax.OpConcat, syntax.OpAlternate:
+		for _, sub := range re.Sub {
+			if !charRanges(sub, ranges) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// normalizeRanges sorts lo, hi pairs and merges those that overlap or touch.
+func normalizeRanges(ranges []rune) []rune {
+	pairs := make([][2]rune, 0, len(ranges)/2)
+	for i := 0; i+1 < len(ranges); i += 2 {
+		pairs = append(pairs, [2]rune{ranges[i], ranges[i+1]})
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i][0] < pairs[j][0] })
+
+	var merged []rune
+	for _, p := range pairs {
+		if n := len(merged); n > 0 && p[0] <= merged[n-1]+1 {
+			if p[1] > merged[n-1] {
+				merged[n-1] = p[1]
+			}
+			continue
+		}
+		merged = append(merged, p[0], p[1])
+	}
+	return merged
+}
+
+// canBeEmpty reports whether re matches the empty string.
+func canBeEmpty(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpEmptyMatch, syntax.OpStar, syntax.OpQuest:
+		return true
+	case syntax.OpRepeat:
+		return re.Min == 0 || canBeEmpty(re.Sub[0])
+	case syntax.OpCapture, syntax.OpPlus:
+		return canBeEmpty(re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if !canBeEmpty(sub) {
+				return false
+			}
+		}
+		return true
+	case syntax.OpAlternate:
+		for _, sub := range re.Sub {
+			if canBeEmpty(sub) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasUnboundedQuantifier reports whether re contains a *, + or {n,}.
+func hasUnboundedQuantifier(re *syntax.Regexp) bool {
+	switch {
+	case re.Op == syntax.OpStar, re.Op == syntax.OpPlus, re.Op == syntax.OpRepeat && re.Max == -1:
+		return true
+	}
+	for _, sub := range re.Sub {
+		if hasUnboundedQuantifier(sub) {
+			return true
+		}
+	}
+	return false
+}
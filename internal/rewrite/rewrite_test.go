@@ -0,0 +1,111 @@
+package rewrite
+
+import (
+	"testing"
+
+	"github.com/theakshaypant/regret/internal/parser"
+)
+
+func TestRewrite(t *testing.T) {
+	tests := []struct {
+		pattern     string
+		want        string
+		wantChanged bool
+	}{
+		{"(a+)+", "a+", true},
+		{"(a*)*", "a*", true},
+		{"(a+)*", "a*", true},
+		{"(a?)+", "a*", true},
+		{"(a?)?", "a?", true},
+		{"((a+)+)+", "a+", true},
+		{"([0-9]+)+x", "[0-9]+x", true},
+		{"a*a*", "a*", true},
+		{"a*a+", "a+", true},
+		{"a+a?", "a+", true},
+		{"a+a+", "a+a+", false},
+		{"a*b*", "a*b*", false},
+		{"[a-z]+x", "[a-z]+x", false},
+	}
+
+	p := parser.NewParser()
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re := p.MustParse(tt.pattern)
+			original := re.String()
+
+			result := Rewrite(re)
+			if got := result.Regexp.String(); got != tt.want {
+				t.Errorf("Rewrite(%q) = %q, want %q", tt.pattern, got, tt.want)
+			}
+			if result.Changed() != tt.wantChanged {
+				t.Errorf("Rewrite(%q).Changed() = %v, want %v", tt.pattern, result.Changed(), tt.wantChanged)
+			}
+			if re.String() != original {
+				t.Errorf("Rewrite(%q) mutated input AST to %q", tt.pattern, re.String())
+			}
+		})
+	}
+}
+
+func TestWiden(t *testing.T) {
+	tests := []struct {
+		pattern     string
+		want        string
+		wantChanged bool
+	}{
+		{"(a+b)+", "[ab]+", true},
+		{"([a-z]+[0-9]?)*", "[0-9a-z]*", true},
+		{"(a*b?)+", "[ab]*", true}, // the body can be empty
+		{"(a+)+", "a+", true},
+		{"x(a|b+)+y", "x[ab]+y", true},
+		{"(a+$)+", "(a+$)+", false}, // anchors are not characters
+		{"(?i:(a+b)+)", "(?i:(a+b)+)", false},
+		{"(ab)+", "(ab)+", false}, // nothing nested to widen
+	}
+
+	p := parser.NewParser()
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re := p.MustParse(tt.pattern)
+			want := p.MustParse(tt.want).String()
+
+			result := Widen(re)
+			if got := result.Regexp.String(); got != want {
+				t.Errorf("Widen(%q) = %q, want %q", tt.pattern, got, want)
+			}
+			if result.Changed() != tt.wantChanged {
+				t.Errorf("Widen(%q).Changed() = %v, want %v", tt.pattern, result.Changed(), tt.wantChanged)
+			}
+		})
+	}
+}
+
+func TestNarrow(t *testing.T) {
+	tests := []struct {
+		pattern     string
+		want        string
+		wantChanged bool
+	}{
+		{"(a+b)+", "(a+b)", true},
+		{"(a+b)*", "(a+b)?", true},
+		{"x(a*b){2,}", "x(a*b){2}", true}, // simplified to (a*b)(a*b)+
+		{"(ab)+", "(ab)+", false},
+	}
+
+	p := parser.NewParser()
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re := p.MustParse(tt.pattern)
+			want := p.MustParse(tt.want).String()
+
+			result := Narrow(re)
+			if got := result.Regexp.String(); got != want {
+				t.Errorf("Narrow(%q) = %q, want %q", tt.pattern, got, want)
+			}
+			if result.Changed() != tt.wantChanged {
+				t.Errorf("Narrow(%q).Changed() = %v, want %v", tt.pattern, result.Changed(), tt.wantChanged)
+			}
+		})
+	}
+}
@@ -0,0 +1,322 @@
+package regret
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FilterIssues returns the issues for which predicate returns true, in
+// their original order. The input slice is not modified.
+//
+// Example:
+//
+//	issues, _ := regret.Validate(pattern)
+//	serious := regret.FilterIssues(issues, regret.BySeverity(regret.High))
+func FilterIssues(issues []Issue, predicate func(Issue) bool) []Issue {
+	var filtered []Issue
+	for _, issue := range issues {
+		if predicate(issue) {
+			filtered = append(filtered, issue)
+		}
+	}
+	return filtered
+}
+
+// BySeverity returns a predicate for FilterIssues that accepts issues at
+// least as severe as min. BySeverity(High) accepts Critical and High issues.
+func BySeverity(min Severity) func(Issue) bool {
+	return func(issue Issue) bool {
+		return issue.Severity <= min
+	}
+}
+
+// ByType returns a predicate for FilterIssues that accepts issues of any
+// of the given types.
+func ByType(types ...IssueType) func(Issue) bool {
+	return func(issue Issue) bool {
+		for _, t := range types {
+			if issue.Type == t {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ByComplexity returns a predicate for FilterIssues that accepts issues
+// whose Complexity is at least min.
+func ByComplexity(min int) func(Issue) bool {
+	return func(issue Issue) bool {
+		return issue.Complexity >= min
+	}
+}
+
+// GroupByType groups issues by type. Each group keeps the original order.
+func GroupByType(issues []Issue) map[IssueType][]Issue {
+	groups := make(map[IssueType][]Issue)
+	for _, issue := range issues {
+		groups[issue.Type] = append(groups[issue.Type], issue)
+	}
+	return groups
+}
+
+// GroupBySeverity groups issues by severity. Each group keeps the original
+// order.
+func GroupBySeverity(issues []Issue) map[Severity][]Issue {
+	groups := make(map[Severity][]Issue)
+	for _, issue := range issues {
+		groups[issue.Severity] = append(groups[issue.Severity], issue)
+	}
+	return groups
+}
+
+// issueJSON is the serialized form of an Issue used by MarshalIssues.
+type issueJSON struct {
+	Type       IssueType              `json:"type"`
+	Severity   Severity               `json:"severity"`
+	Position   positionJSON           `json:"position"`
+	Pattern    string                 `json:"pattern"`
+	Message    string                 `json:"message"`
+	Example    string                 `json:"example,omitempty"`
+	Suggestion string                 `json:"suggestion,omitempty"`
+	Complexity int                    `json:"complexity"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+	References []string               `json:"references,omitempty"`
+	Related    []string               `json:"related_patterns,omitempty"`
+}
+
+type positionJSON struct {
+	Start  int `json:"start"`
+	End    int `json:"end"`
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+}
+
+func newIssueJSON(issue Issue) issueJSON {
+	p := issue.Position
+	return issueJSON{
+		Type:       issue.Type,
+		Severity:   issue.Severity,
+		Position:   positionJSON{Start: p.Start, End: p.End, Line: p.Line, Column: p.Column},
+		Pattern:    issue.Pattern,
+		Message:    issue.Message,
+		Example:    issue.Example,
+		Suggestion: issue.Suggestion,
+		Complexity: issue.Complexity,
+		Details:    issue.Details,
+		References: issue.References,
+		Related:    issue.RelatedPatterns,
+	}
+}
+
+func (v issueJSON) issue() Issue {
+	p := v.Position
+	return Issue{
+		Type:            v.Type,
+		Severity:        v.Severity,
+		Position:        Position{Start: p.Start, End: p.End, Line: p.Line, Column: p.Column},
+		Pattern:         v.Pattern,
+		Message:         v.Message,
+		Example:         v.Example,
+		Suggestion:      v.Suggestion,
+		Complexity:      v.Complexity,
+		Details:         v.Details,
+		References:      v.References,
+		RelatedPatterns: v.Related,
+	}
+}
+
+// issueCSVHeader names the columns written by MarshalIssues in CSV format.
+var issueCSVHeader = []string{
+	"type", "severity", "start", "end", "line", "column", "pattern",
+	"message", "example", "suggestion", "complexity", "details", "references", "related_patterns",
+}
+
+// MarshalIssues encodes issues in one of these formats:
+//
+//   - "json": a JSON array of objects with snake_case keys, such as
+//     {"type": "nested_quantifiers", "severity": "critical", ...}
+//   - "jsonl": the same objects, one per line, for streaming logs
+//   - "csv": a header row followed by one row per issue. Details are
+//     written as a JSON object, References separated by spaces and
+//     RelatedPatterns as a JSON array.
+//
+// Issue types and severities are written by name. UnmarshalIssues reads
+// the same formats.
+//
+// Example:
+//
+//	issues, _ := regret.Validate(pattern)
+//	data, err := regret.MarshalIssues(issues, "jsonl")
+func MarshalIssues(issues []Issue, format string) ([]byte, error) {
+	switch strings.ToLower(format) {
+	case "json":
+		values := make([]issueJSON, len(issues))
+		for i, issue := range issues {
+			values[i] = newIssueJSON(issue)
+		}
+		return json.Marshal(values)
+	case "jsonl":
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		for _, issue := range issues {
+			if err := enc.Encode(newIssueJSON(issue)); err != nil {
+				return nil, err
+			}
+		}
+		return buf.Bytes(), nil
+	case "csv":
+		return marshalIssuesCSV(issues)
+	default:
+		return nil, fmt.Errorf("unsupported issue format %q (expected json|jsonl|csv)", format)
+	}
+}
+
+func marshalIssuesCSV(issues []Issue) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(issueCSVHeader); err != nil {
+		return nil, err
+	}
+	for _, issue := range issues {
+		details := ""
+		if len(issue.Details) > 0 {
+			data, err := json.Marshal(issue.Details)
+			if err != nil {
+				return nil, err
+			}
+			details = string(data)
+		}
+		related := ""
+		if len(issue.RelatedPatterns) > 0 {
+			data, err := json.Marshal(issue.RelatedPatterns)
+			if err != nil {
+				return nil, err
+			}
+			related = string(data)
+		}
+		if err := w.Write([]string{
+			issue.Type.String(),
+			issue.Severity.String(),
+			strconv.Itoa(issue.Position.Start),
+			strconv.Itoa(issue.Position.End),
+			strconv.Itoa(issue.Position.Line),
+			strconv.Itoa(issue.Position.Column),
+			issue.Pattern,
+			issue.Message,
+			issue.Example,
+			issue.Suggestion,
+			strconv.Itoa(issue.Complexity),
+			details,
+			strings.Join(issue.References, " "),
+			related,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// UnmarshalIssues decodes issues written by MarshalIssues in the given
+// format. Unknown issue types and severities return errors wrapping
+// ErrUnknownIssueType and ErrUnknownSeverity. Numbers in Details decode
+// as float64, as with encoding/json.
+func UnmarshalIssues(data []byte, format string) ([]Issue, error) {
+	switch strings.ToLower(format) {
+	case "json":
+		var values []issueJSON
+		if err := json.Unmarshal(data, &values); err != nil {
+			return nil, err
+		}
+		issues := make([]Issue, len(values))
+		for i, v := range values {
+			issues[i] = v.issue()
+		}
+		return issues, nil
+	case "jsonl":
+		var issues []Issue
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(nil, len(data)+1)
+		for line := 1; scanner.Scan(); line++ {
+			if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+				continue
+			}
+			var v issueJSON
+			if err := json.Unmarshal(scanner.Bytes(), &v); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			issues = append(issues, v.issue())
+		}
+		return issues, scanner.Err()
+	case "csv":
+		return unmarshalIssuesCSV(data)
+	default:
+		return nil, fmt.Errorf("unsupported issue format %q (expected json|jsonl|csv)", format)
+	}
+}
+
+func unmarshalIssuesCSV(data []byte) ([]Issue, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = len(issueCSVHeader)
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	issues := make([]Issue, 0, len(records)-1)
+	for i, rec := range records[1:] {
+		issue, err := issueFromCSV(rec)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+2, err)
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// issueFromCSV decodes a row in the column order of issueCSVHeader.
+func issueFromCSV(rec []string) (Issue, error) {
+	var issue Issue
+	var err error
+	if issue.Type, err = IssueTypeFromString(rec[0]); err != nil {
+		return Issue{}, err
+	}
+	if issue.Severity, err = SeverityFromString(rec[1]); err != nil {
+		return Issue{}, err
+	}
+
+	for i, dst := range []*int{
+		&issue.Position.Start, &issue.Position.End, &issue.Position.Line, &issue.Position.Column,
+	} {
+		if *dst, err = strconv.Atoi(rec[2+i]); err != nil {
+			return Issue{}, fmt.Errorf("%s: %w", issueCSVHeader[2+i], err)
+		}
+	}
+	issue.Pattern, issue.Message, issue.Example, issue.Suggestion = rec[6], rec[7], rec[8], rec[9]
+	if issue.Complexity, err = strconv.Atoi(rec[10]); err != nil {
+		return Issue{}, fmt.Errorf("complexity: %w", err)
+	}
+	if rec[11] != "" {
+		if err := json.Unmarshal([]byte(rec[11]), &issue.Details); err != nil {
+			return Issue{}, fmt.Errorf("details: %w", err)
+		}
+	}
+	if refs := strings.Fields(rec[12]); len(refs) > 0 {
+		issue.References = refs
+	}
+	if rec[13] != "" {
+		if err := json.Unmarshal([]byte(rec[13]), &issue.RelatedPatterns); err != nil {
+			return Issue{}, fmt.Errorf("related_patterns: %w", err)
+		}
+	}
+	return issue, nil
+}
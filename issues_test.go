@@ -0,0 +1,142 @@
+package regret
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testIssues = []Issue{
+	{Type: NestedQuantifiers, Severity: Critical, Complexity: 90},
+	{Type: PolynomialBacktracking, Severity: High, Complexity: 60},
+	{Type: UnboundedRepetition, Severity: Medium, Complexity: 30},
+	{Type: NestedQuantifiers, Severity: Low, Complexity: 10},
+}
+
+func TestFilterIssues(t *testing.T) {
+	tests := []struct {
+		name      string
+		predicate func(Issue) bool
+		want      []int // indexes into testIssues
+	}{
+		{"severity high", BySeverity(High), []int{0, 1}},
+		{"severity info", BySeverity(Info), []int{0, 1, 2, 3}},
+		{"type", ByType(NestedQuantifiers), []int{0, 3}},
+		{"several types", ByType(PolynomialBacktracking, UnboundedRepetition), []int{1, 2}},
+		{"no types", ByType(), nil},
+		{"complexity", ByComplexity(30), []int{0, 1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var want []Issue
+			for _, i := range tt.want {
+				want = append(want, testIssues[i])
+			}
+			if got := FilterIssues(testIssues, tt.predicate); !reflect.DeepEqual(got, want) {
+				t.Errorf("FilterIssues() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestGroupIssues(t *testing.T) {
+	byType := GroupByType(testIssues)
+	if len(byType) != 3 || len(byType[NestedQuantifiers]) != 2 || byType[NestedQuantifiers][1].Severity != Low {
+		t.Errorf("GroupByType() = %v", byType)
+	}
+
+	bySeverity := GroupBySeverity(testIssues)
+	if len(bySeverity) != 4 || len(bySeverity[Critical]) != 1 || bySeverity[Info] != nil {
+		t.Errorf("GroupBySeverity() = %v", bySeverity)
+	}
+}
+
+func TestMarshalIssues_RoundTrip(t *testing.T) {
+	issues := []Issue{
+		{
+			Type:            NestedQuantifiers,
+			Severity:        Critical,
+			Position:        Position{Start: 1, End: 7, Line: 1, Column: 2},
+			Pattern:         "(a+)+",
+			Message:         "nested quantifiers, \"exponential\"",
+			Example:         "aaaa!",
+			Suggestion:      "use a+",
+			Complexity:      90,
+			Details:         map[string]interface{}{"depth": float64(2)},
+			References:      []string{"https://example.com/a", "https://example.com/b"},
+			RelatedPatterns: []string{"(a*)*", "(x, y)+"},
+		},
+		{Type: UnboundedRepetition, Severity: Medium, Pattern: ".*", Message: "line1\nline2", Complexity: 30},
+	}
+
+	for _, format := range []string{"json", "jsonl", "csv"} {
+		t.Run(format, func(t *testing.T) {
+			data, err := MarshalIssues(issues, format)
+			if err != nil {
+				t.Fatalf("MarshalIssues() error = %v", err)
+			}
+			got, err := UnmarshalIssues(data, format)
+			if err != nil {
+				t.Fatalf("UnmarshalIssues() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, issues) {
+				t.Errorf("round trip = %+v, want %+v", got, issues)
+			}
+		})
+	}
+}
+
+func TestMarshalIssues_Format(t *testing.T) {
+	issues := []Issue{{Type: NestedQuantifiers, Severity: Critical, Pattern: "(a+)+", Complexity: 90}}
+
+	data, err := MarshalIssues(issues, "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"type":"nested_quantifiers"`) ||
+		!strings.Contains(string(data), `"severity":"critical"`) {
+		t.Errorf("json = %s, want type and severity by name", data)
+	}
+
+	data, err = MarshalIssues(issues, "csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "type,severity,") ||
+		!strings.HasPrefix(lines[1], "nested_quantifiers,critical,") {
+		t.Errorf("csv = %q", data)
+	}
+
+	if _, err := MarshalIssues(issues, "xml"); err == nil {
+		t.Error("MarshalIssues(xml) error = nil, want unsupported format")
+	}
+}
+
+func TestUnmarshalIssues_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		format string
+		want   error
+	}{
+		{"unknown type", `[{"type":"bogus","severity":"low"}]`, "json", ErrUnknownIssueType},
+		{"unknown severity jsonl", `{"type":"nested_quantifiers","severity":"severe"}`, "jsonl", ErrUnknownSeverity},
+		{"unknown type csv",
+			"type,severity,start,end,line,column,pattern,message,example,suggestion,complexity,details,references,related_patterns\n" +
+				"bogus,low,0,0,0,0,a,,,,0,,,\n", "csv", ErrUnknownIssueType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := UnmarshalIssues([]byte(tt.data), tt.format); !errors.Is(err, tt.want) {
+				t.Errorf("UnmarshalIssues() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := UnmarshalIssues(nil, "yaml"); err == nil {
+		t.Error("UnmarshalIssues(yaml) error = nil, want unsupported format")
+	}
+}
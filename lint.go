@@ -0,0 +1,106 @@
+package regret
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+)
+
+// Lint parses Go source and validates, with DefaultOptions, every string
+// literal passed to regexp.Compile, regexp.MustCompile and the other
+// functions ScanReader recognizes. It returns a Finding for each, in
+// source order, with File set to filename and Line and Column taken from
+// the parsed file, so tools such as linters and editors can report them
+// without running the regret command.
+//
+// Unlike ScanReader, Lint works on the syntax tree: it follows the import
+// of package regexp under another name or as a dot import, and ignores
+// calls in comments and strings. The returned error is set if the source
+// does not parse; validation failures are reported in Finding.Err.
+//
+// Example:
+//
+//	findings, err := regret.Lint(src, "main.go")
+//	if err != nil {
+//	    return err
+//	}
+//	for _, finding := range findings {
+//	    if len(finding.Issues) > 0 {
+//	        fmt.Printf("%s:%d:%d: unsafe regex %s\n", finding.File, finding.Line, finding.Column, finding.Pattern)
+//	    }
+//	}
+func Lint(source []byte, filename string) ([]Finding, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, source, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	name, ok := regexpImportName(file)
+	if !ok {
+		return nil, nil
+	}
+
+	var findings []Finding
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 || !isRegexpCall(call.Fun, name) {
+			return true
+		}
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		pattern, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return true
+		}
+		position := fset.Position(lit.Pos())
+		findings = append(findings, Finding{
+			File:    filename,
+			Line:    position.Line,
+			Column:  position.Column,
+			Pattern: pattern,
+		})
+		return true
+	})
+
+	v := NewValidator(DefaultOptions())
+	for i := range findings {
+		findings[i].Issues, findings[i].Err = v.Validate(findings[i].Pattern)
+	}
+
+	return findings, nil
+}
+
+// regexpImportName returns the name package regexp is imported under in
+// file, "." for a dot import, or false if it is not imported.
+func regexpImportName(file *ast.File) (string, bool) {
+	for _, spec := range file.Imports {
+		if path, _ := strconv.Unquote(spec.Path.Value); path != "regexp" {
+			continue
+		}
+		if spec.Name == nil {
+			return "regexp", true
+		}
+		if spec.Name.Name == "_" {
+			return "", false
+		}
+		return spec.Name.Name, true
+	}
+	return "", false
+}
+
+// isRegexpCall reports whether fun names a function of package regexp
+// that takes a pattern, with the package imported under name.
+func isRegexpCall(fun ast.Expr, name string) bool {
+	switch fun := fun.(type) {
+	case *ast.SelectorExpr:
+		pkg, ok := fun.X.(*ast.Ident)
+		return ok && pkg.Name == name && goRegexpFuncs[fun.Sel.Name]
+	case *ast.Ident:
+		return name == "." && goRegexpFuncs[fun.Name]
+	}
+	return false
+}
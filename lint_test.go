@@ -0,0 +1,73 @@
+package regret
+
+import (
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	src := []byte(`package main
+
+import (
+	re "regexp"
+	"strings"
+)
+
+// regexp.MustCompile("(a+)+") in a comment is ignored
+var (
+	word   = re.MustCompile(` + "`^[a-z]+$`" + `)
+	nested = re.MustCompile("(a+)+")
+)
+
+func main() {
+	if _, err := re.Compile("(x*)*y"); err != nil {
+		panic(err)
+	}
+	_ = strings.Contains("regexp.Compile(\"(b+)+\")", "b")
+}
+`)
+
+	findings, err := Lint(src, "main.go")
+	if err != nil {
+		t.Fatalf("Lint() error = %v", err)
+	}
+
+	want := []struct {
+		line, column int
+		pattern      string
+		safe         bool
+	}{
+		{10, 26, `^[a-z]+$`, true},
+		{11, 26, `(a+)+`, false},
+		{15, 26, `(x*)*y`, false},
+	}
+	if len(findings) != len(want) {
+		t.Fatalf("Lint() found %d patterns, want %d: %+v", len(findings), len(want), findings)
+	}
+	for i, w := range want {
+		f := findings[i]
+		if f.File != "main.go" || f.Line != w.line || f.Column != w.column || f.Pattern != w.pattern {
+			t.Errorf("finding %d = %s:%d:%d %q, want main.go:%d:%d %q", i, f.File, f.Line, f.Column, f.Pattern, w.line, w.column, w.pattern)
+		}
+		if safe := f.Err == nil && len(f.Issues) == 0; safe != w.safe {
+			t.Errorf("finding %d (%s) safe = %v, want %v", i, f.Pattern, safe, w.safe)
+		}
+	}
+}
+
+func TestLint_DotImport(t *testing.T) {
+	src := []byte("package p\n\nimport . \"regexp\"\n\nvar r = MustCompile(`(a+)+`)\n")
+
+	findings, err := Lint(src, "p.go")
+	if err != nil {
+		t.Fatalf("Lint() error = %v", err)
+	}
+	if len(findings) != 1 || findings[0].Pattern != "(a+)+" || findings[0].Line != 5 {
+		t.Errorf("Lint() = %+v, want (a+)+ on line 5", findings)
+	}
+}
+
+func TestLint_SyntaxError(t *testing.T) {
+	if _, err := Lint([]byte("package p\n\nfunc {"), "p.go"); err == nil {
+		t.Error("Lint() expected error for invalid Go source")
+	}
+}
@@ -0,0 +1,112 @@
+// Package metrics exports regret validation statistics to Prometheus.
+//
+// It lives in its own package so that only programs which import it
+// depend on the Prometheus client. Wrap a validator and register the
+// collectors once at startup:
+//
+//	v := metrics.WrapValidator(regret.NewValidator(nil))
+//	if err := metrics.Register(prometheus.DefaultRegisterer); err != nil {
+//	    log.Fatal(err)
+//	}
+//	issues, err := v.Validate(userPattern)
+//
+// The following metrics are recorded:
+//   - regret_validations_total{result="safe|unsafe|error"}: validations by outcome
+//   - regret_complexity_score: histogram of complexity scores of validated patterns
+//   - regret_cache_size: results in the result cache of the wrapped validator
+//     (see regret.Options.CacheSize)
+package metrics
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/theakshaypant/regret"
+)
+
+// Values of the result label of regret_validations_total.
+const (
+	ResultSafe   = "safe"
+	ResultUnsafe = "unsafe"
+	ResultError  = "error"
+)
+
+var (
+	// ValidationsTotal counts validations by outcome.
+	ValidationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "regret_validations_total",
+		Help: "Total number of regex patterns validated, by result.",
+	}, []string{"result"})
+
+	// ComplexityScore records the complexity score (0-100) of validated patterns.
+	ComplexityScore = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "regret_complexity_score",
+		Help:    "Complexity score of validated regex patterns.",
+		Buckets: []float64{10, 30, 50, 70, 90, 100},
+	})
+
+	// CacheSize is the number of results in the cache of the validator
+	// that validated last, as reported by regret.Validator.CacheLen.
+	CacheSize = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "regret_cache_size",
+		Help: "Number of validation results in the validator's cache.",
+	})
+)
+
+// Register registers the regret collectors with reg.
+// Collectors that are already registered are not an error, so Register
+// may be called by several packages sharing a registry.
+func Register(reg prometheus.Registerer) error {
+	for _, c := range []prometheus.Collector{ValidationsTotal, ComplexityScore, CacheSize} {
+		if err := reg.Register(c); err != nil {
+			var already prometheus.AlreadyRegisteredError
+			if !errors.As(err, &already) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// InstrumentedValidator is a regret.Validator that records metrics
+// for every validation.
+type InstrumentedValidator struct {
+	*regret.Validator
+}
+
+// WrapValidator returns a validator that records metrics around v.
+func WrapValidator(v *regret.Validator) *InstrumentedValidator {
+	return &InstrumentedValidator{Validator: v}
+}
+
+// Validate validates a pattern and records its outcome in
+// regret_validations_total and the size of the validator's cache in
+// regret_cache_size. For patterns that validate without error, the
+// complexity score is recorded in regret_complexity_score.
+func (v *InstrumentedValidator) Validate(pattern string) ([]regret.Issue, error) {
+	issues, err := v.Validator.Validate(pattern)
+	CacheSize.Set(float64(v.Validator.CacheLen()))
+	if err != nil {
+		ValidationsTotal.WithLabelValues(ResultError).Inc()
+		return issues, err
+	}
+
+	if len(issues) == 0 {
+		ValidationsTotal.WithLabelValues(ResultSafe).Inc()
+	} else {
+		ValidationsTotal.WithLabelValues(ResultUnsafe).Inc()
+	}
+
+	if score, err := v.Validator.AnalyzeComplexity(pattern); err == nil {
+		ComplexityScore.Observe(float64(score.Overall))
+	}
+
+	return issues, nil
+}
+
+// IsSafe reports whether pattern is valid and has no issues, recording the
+// validation like Validate.
+func (v *InstrumentedValidator) IsSafe(pattern string) bool {
+	issues, err := v.Validate(pattern)
+	return err == nil && len(issues) == 0
+}
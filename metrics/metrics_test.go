@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/theakshaypant/regret"
+)
+
+func TestInstrumentedValidator_Validate(t *testing.T) {
+	v := WrapValidator(regret.NewValidator(nil))
+
+	safe := testutil.ToFloat64(ValidationsTotal.WithLabelValues(ResultSafe))
+	unsafe := testutil.ToFloat64(ValidationsTotal.WithLabelValues(ResultUnsafe))
+	failed := testutil.ToFloat64(ValidationsTotal.WithLabelValues(ResultError))
+	observed := histogramCount(t)
+
+	if _, err := v.Validate(`^[a-z]+$`); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if issues, err := v.Validate(`(a+)+`); err != nil || len(issues) == 0 {
+		t.Fatalf("Validate() = %v, %v, want issues", issues, err)
+	}
+	if _, err := v.Validate(`(a+`); err == nil {
+		t.Fatal("Validate() expected error for invalid pattern")
+	}
+
+	if got := testutil.ToFloat64(ValidationsTotal.WithLabelValues(ResultSafe)) - safe; got != 1 {
+		t.Errorf("safe validations = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(ValidationsTotal.WithLabelValues(ResultUnsafe)) - unsafe; got != 1 {
+		t.Errorf("unsafe validations = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(ValidationsTotal.WithLabelValues(ResultError)) - failed; got != 1 {
+		t.Errorf("error validations = %v, want 1", got)
+	}
+	if got := histogramCount(t) - observed; got != 2 {
+		t.Errorf("complexity score observations = %v, want 2", got)
+	}
+}
+
+func TestInstrumentedValidator_IsSafe(t *testing.T) {
+	v := WrapValidator(regret.NewValidator(nil))
+	unsafe := testutil.ToFloat64(ValidationsTotal.WithLabelValues(ResultUnsafe))
+
+	if v.IsSafe(`(a+)+`) {
+		t.Error("IsSafe((a+)+) = true, want false")
+	}
+	if got := testutil.ToFloat64(ValidationsTotal.WithLabelValues(ResultUnsafe)) - unsafe; got != 1 {
+		t.Errorf("unsafe validations = %v, want 1", got)
+	}
+}
+
+func TestInstrumentedValidator_CacheSize(t *testing.T) {
+	opts := regret.DefaultOptions()
+	opts.CacheSize = 10
+	opts.Timeout = 0
+	v := WrapValidator(regret.NewValidator(opts))
+
+	for _, pattern := range []string{`^[a-z]+$`, `(a+)+`, `^[a-z]+$`} {
+		if _, err := v.Validate(pattern); err != nil {
+			t.Fatalf("Validate(%q) error = %v", pattern, err)
+		}
+	}
+	if got := testutil.ToFloat64(CacheSize); got != 2 {
+		t.Errorf("regret_cache_size = %v, want 2", got)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	if err := Register(reg); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := Register(reg); err != nil {
+		t.Errorf("second Register() error = %v", err)
+	}
+}
+
+func histogramCount(t *testing.T) uint64 {
+	t.Helper()
+
+	reg := prometheus.NewRegistry()
+	if err := reg.Register(ComplexityScore); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() == "regret_complexity_score" {
+			return family.GetMetric()[0].GetHistogram().GetSampleCount()
+		}
+	}
+	t.Fatal("regret_complexity_score not gathered")
+	return 0
+}
@@ -0,0 +1,46 @@
+package regret
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// DiffOptions describes every field that differs between a and b, in the
+// order the fields are declared, as "Field: <a value> → <b value>". A nil
+// a or b stands for DefaultOptions(), as in ValidateWithOptions. It is
+// meant for error messages and debugging, such as finding out why a
+// pattern passes with one set of options and fails with another.
+//
+// Example:
+//
+//	for _, d := range regret.DiffOptions(regret.DefaultOptions(), regret.ThoroughOptions()) {
+//	    fmt.Println(d) // Mode: balanced → thorough, Timeout: 100ms → 1s, ...
+//	}
+func DiffOptions(a, b *Options) []string {
+	if a == nil {
+		a = DefaultOptions()
+	}
+	if b == nil {
+		b = DefaultOptions()
+	}
+
+	va, vb := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()
+	var diffs []string
+	for i := 0; i < va.NumField(); i++ {
+		fa, fb := va.Field(i).Interface(), vb.Field(i).Interface()
+		if reflect.DeepEqual(fa, fb) {
+			continue
+		}
+		diffs = append(diffs, fmt.Sprintf("%s: %s → %s", va.Type().Field(i).Name, optionValue(fa), optionValue(fb)))
+	}
+	return diffs
+}
+
+// optionValue formats the value of an option field for DiffOptions.
+func optionValue(v interface{}) string {
+	if checks, ok := v.(CheckFlags); ok {
+		// Bitmasks read better in hex
+		return fmt.Sprintf("%#x", uint32(checks))
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,41 @@
+package regret
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffOptions(t *testing.T) {
+	diffs := DiffOptions(DefaultOptions(), ThoroughOptions())
+	for _, want := range []string{
+		"Mode: balanced → thorough",
+		"Timeout: 100ms → 1s",
+		"Checks: 0x87 → 0xffffffff",
+		"MaxPatternLength: 1000 → 2000",
+	} {
+		found := false
+		for _, d := range diffs {
+			if d == want {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("DiffOptions() = %q, want it to contain %q", diffs, want)
+		}
+	}
+
+	if diffs := DiffOptions(nil, DefaultOptions()); diffs != nil {
+		t.Errorf("DiffOptions(nil, DefaultOptions()) = %q, want no differences", diffs)
+	}
+
+	opts := DefaultOptions()
+	opts.SeverityOverride = map[IssueType]Severity{PolynomialBacktracking: Critical}
+	opts.DenyList = []string{"(a+)+"}
+	want := []string{
+		"SeverityOverride: map[] → map[polynomial_backtracking:critical]",
+		"DenyList: [] → [(a+)+]",
+	}
+	if diffs := DiffOptions(nil, opts); !reflect.DeepEqual(diffs, want) {
+		t.Errorf("DiffOptions() = %q, want %q", diffs, want)
+	}
+}
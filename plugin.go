@@ -0,0 +1,70 @@
+package regret
+
+import (
+	"regexp/syntax"
+	"sort"
+	"sync"
+
+	"github.com/theakshaypant/regret/internal/parser"
+)
+
+// CheckPlugin is a custom check that runs alongside the built-in detectors.
+// Plugins let applications add domain-specific rules, such as rejecting
+// patterns that match credit card numbers.
+//
+// Plugins run when CheckCustomPlugins is set in Options.Checks.
+type CheckPlugin interface {
+	// Name uniquely identifies the plugin in the registry.
+	Name() string
+
+	// Check inspects the parsed pattern and returns any issues found.
+	// The AST is a private copy, so plugins may not affect other checks.
+	// The line and column of each issue are set from its Position.Start.
+	Check(re *syntax.Regexp, pattern string) []Issue
+}
+
+// plugins holds registered plugins keyed by name.
+var plugins sync.Map
+
+// RegisterPlugin adds a custom check to the plugin registry.
+// Registering a plugin with the same name as an existing one replaces it.
+// It is safe to call from multiple goroutines.
+//
+// Example:
+//
+//	regret.RegisterPlugin(myPlugin)
+//	opts := regret.DefaultOptions()
+//	opts.Checks |= regret.CheckCustomPlugins
+//	issues, err := regret.ValidateWithOptions(pattern, opts)
+func RegisterPlugin(p CheckPlugin) {
+	if p == nil {
+		panic("regret: RegisterPlugin plugin is nil")
+	}
+	plugins.Store(p.Name(), p)
+}
+
+// UnregisterPlugin removes the plugin with the given name from the registry.
+// It is a no-op if no such plugin is registered.
+func UnregisterPlugin(name string) {
+	plugins.Delete(name)
+}
+
+// runPlugins runs all registered plugins in name order.
+// Each plugin receives its own deep copy of the AST.
+func runPlugins(re *syntax.Regexp, pattern string) []Issue {
+	var registered []CheckPlugin
+	plugins.Range(func(_, value interface{}) bool {
+		registered = append(registered, value.(CheckPlugin))
+		return true
+	})
+
+	sort.Slice(registered, func(i, j int) bool {
+		return registered[i].Name() < registered[j].Name()
+	})
+
+	var issues []Issue
+	for _, p := range registered {
+		issues = append(issues, p.Check(parser.Clone(re), pattern)...)
+	}
+	return issues
+}
@@ -0,0 +1,116 @@
+package regret
+
+import (
+	"regexp/syntax"
+	"strings"
+	"testing"
+)
+
+// cardNumberPlugin flags patterns that look like credit card matchers.
+type cardNumberPlugin struct{}
+
+func (cardNumberPlugin) Name() string { return "card-number" }
+
+func (cardNumberPlugin) Check(re *syntax.Regexp, pattern string) []Issue {
+	if !strings.Contains(pattern, `\d{16}`) {
+		return nil
+	}
+	return []Issue{{
+		Type:     ContextuallyDangerous,
+		Severity: High,
+		Pattern:  pattern,
+		Message:  "pattern matches credit card numbers",
+	}}
+}
+
+// mutatingPlugin rewrites every node it sees to check AST isolation.
+type mutatingPlugin struct{}
+
+func (mutatingPlugin) Name() string { return "mutator" }
+
+func (mutatingPlugin) Check(re *syntax.Regexp, pattern string) []Issue {
+	re.Op = syntax.OpEmptyMatch
+	re.Sub = nil
+	return nil
+}
+
+func TestRegisterPlugin(t *testing.T) {
+	RegisterPlugin(cardNumberPlugin{})
+	defer UnregisterPlugin("card-number")
+
+	opts := DefaultOptions()
+	opts.Checks |= CheckCustomPlugins
+
+	issues, err := ValidateWithOptions(`^\d{16}$`, opts)
+	if err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v", err)
+	}
+	if !hasMessage(issues, "pattern matches credit card numbers") {
+		t.Errorf("expected plugin issue, got %v", issues)
+	}
+
+	// Plugins only run when the flag is set
+	issues, err = ValidateWithOptions(`^\d{16}$`, DefaultOptions())
+	if err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v", err)
+	}
+	if hasMessage(issues, "pattern matches credit card numbers") {
+		t.Error("plugin ran without CheckCustomPlugins")
+	}
+}
+
+func TestUnregisterPlugin(t *testing.T) {
+	RegisterPlugin(cardNumberPlugin{})
+	UnregisterPlugin("card-number")
+
+	opts := DefaultOptions()
+	opts.Checks |= CheckCustomPlugins
+
+	issues, err := ValidateWithOptions(`^\d{16}$`, opts)
+	if err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v", err)
+	}
+	if hasMessage(issues, "pattern matches credit card numbers") {
+		t.Error("unregistered plugin still ran")
+	}
+}
+
+// recordingPlugin remembers the AST it was given.
+type recordingPlugin struct {
+	seen *string
+}
+
+func (recordingPlugin) Name() string { return "recorder" }
+
+func (p recordingPlugin) Check(re *syntax.Regexp, pattern string) []Issue {
+	*p.seen = re.String()
+	return nil
+}
+
+func TestPlugin_ReceivesCopyOfAST(t *testing.T) {
+	var seen string
+	RegisterPlugin(mutatingPlugin{})
+	RegisterPlugin(recordingPlugin{seen: &seen})
+	defer UnregisterPlugin("mutator")
+	defer UnregisterPlugin("recorder")
+
+	opts := DefaultOptions()
+	opts.Checks |= CheckCustomPlugins
+
+	// mutator runs before recorder (name order)
+	if _, err := ValidateWithOptions(`(a+)+`, opts); err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v", err)
+	}
+	if seen != "(a+)+" {
+		t.Errorf("recorder saw %q, want unmodified AST (a+)+", seen)
+	}
+}
+
+func hasMessage(issues []Issue, msg string) bool {
+	for _, issue := range issues {
+		if issue.Message == msg {
+			return true
+		}
+	}
+	return false
+}
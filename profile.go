@@ -0,0 +1,113 @@
+package regret
+
+import (
+	"fmt"
+	"regexp"
+	"runtime"
+	"time"
+)
+
+// DefaultProfileTimeout is the time Profile lets each input run.
+const DefaultProfileTimeout = 5 * time.Second
+
+// ProfilingResult holds the measurements of Profile.
+type ProfilingResult struct {
+	// Pattern is the profiled pattern.
+	Pattern string
+
+	// Inputs holds one measurement per input, in input order.
+	Inputs []InputProfile
+}
+
+// InputProfile is the measurement of matching one input.
+type InputProfile struct {
+	// Input is the matched input.
+	Input string
+
+	// Duration is how long matching took, or the timeout if TimedOut.
+	Duration time.Duration
+
+	// AllocsBytes is the number of bytes allocated while matching. It is
+	// read from runtime.MemStats, so allocations by other goroutines
+	// running at the same time are counted too.
+	AllocsBytes uint64
+
+	// Matched reports whether the pattern matched the input. It is false
+	// if TimedOut.
+	Matched bool
+
+	// TimedOut is set if matching did not finish within the timeout.
+	TimedOut bool
+}
+
+// Profile measures how long the pattern takes to match each input, and how
+// much it allocates, with Go's regexp package. Where AnalyzeComplexity
+// estimates the cost of a pattern, Profile measures it, for example on
+// the inputs of a PumpPattern at growing sizes.
+//
+// Each input may run for DefaultProfileTimeout; see ProfileWithTimeout.
+// Invalid patterns return an error wrapping ErrInvalidPattern.
+//
+// Example:
+//
+//	result, err := regret.Profile(`^(a+)+$`, []string{
+//	    strings.Repeat("a", 1000) + "!",
+//	    strings.Repeat("a", 10000) + "!",
+//	})
+//	for _, in := range result.Inputs {
+//	    fmt.Println(len(in.Input), in.Duration, in.AllocsBytes)
+//	}
+func Profile(pattern string, inputs []string) (*ProfilingResult, error) {
+	return ProfileWithTimeout(pattern, inputs, DefaultProfileTimeout)
+}
+
+// ProfileWithTimeout is like Profile but lets each input run for at most
+// timeout. Matching runs in its own goroutine; when it times out, the input
+// is marked TimedOut and profiling moves on to the next input, leaving the
+// goroutine to finish in the background, since a match cannot be stopped.
+// A timeout of 0 or less means no timeout.
+func ProfileWithTimeout(pattern string, inputs []string, timeout time.Duration) (*ProfilingResult, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPattern, err)
+	}
+
+	result := &ProfilingResult{Pattern: pattern, Inputs: make([]InputProfile, len(inputs))}
+	for i, input := range inputs {
+		result.Inputs[i] = profileInput(re, input, timeout)
+	}
+	return result, nil
+}
+
+// profileInput matches re against input and measures it.
+func profileInput(re *regexp.Regexp, input string, timeout time.Duration) InputProfile {
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+
+	matched := make(chan bool, 1)
+	go func() {
+		matched <- re.MatchString(input)
+	}()
+
+	select {
+	case m := <-matched:
+		elapsed := time.Since(start)
+		runtime.ReadMemStats(&after)
+		return InputProfile{
+			Input:       input,
+			Duration:    elapsed,
+			AllocsBytes: after.TotalAlloc - before.TotalAlloc,
+			Matched:     m,
+		}
+	case <-expired:
+		return InputProfile{Input: input, Duration: timeout, TimedOut: true}
+	}
+}
@@ -0,0 +1,45 @@
+package regret
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProfile(t *testing.T) {
+	inputs := []string{"abc", strings.Repeat("a", 1000) + "!", ""}
+
+	result, err := Profile(`^a+$|^abc$`, inputs)
+	if err != nil {
+		t.Fatalf("Profile() error = %v", err)
+	}
+	if result.Pattern != `^a+$|^abc$` || len(result.Inputs) != len(inputs) {
+		t.Fatalf("Profile() = %+v, want one profile per input", result)
+	}
+	for i, want := range []bool{true, false, false} {
+		in := result.Inputs[i]
+		if in.Input != inputs[i] || in.Matched != want || in.TimedOut {
+			t.Errorf("Inputs[%d] = {Input: %.10q, Matched: %v, TimedOut: %v}, want Matched %v",
+				i, in.Input, in.Matched, in.TimedOut, want)
+		}
+	}
+
+	if _, err := Profile("(abc", inputs); !errors.Is(err, ErrInvalidPattern) {
+		t.Errorf("Profile(invalid) error = %v, want ErrInvalidPattern", err)
+	}
+}
+
+func TestProfileWithTimeout(t *testing.T) {
+	input := strings.Repeat("ab", 1<<20)
+
+	result, err := ProfileWithTimeout(`(a|b)*c`, []string{input}, time.Nanosecond)
+	if err != nil {
+		t.Fatalf("ProfileWithTimeout() error = %v", err)
+	}
+	in := result.Inputs[0]
+	if !in.TimedOut || in.Matched || in.Duration != time.Nanosecond {
+		t.Errorf("Inputs[0] = {Matched: %v, TimedOut: %v, Duration: %v}, want a timeout",
+			in.Matched, in.TimedOut, in.Duration)
+	}
+}
@@ -1,6 +1,7 @@
 package regret
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -173,3 +174,25 @@ func TestAnalyzeComplexity_PumpConsistency(t *testing.T) {
 		prevScore = score
 	}
 }
+
+func TestAnalyzeComplexity_PumpSizes(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    []int
+	}{
+		{"(a+)+", []int{1, 2, 4, 8, 16, 32}},              // exponential: doubling sizes
+		{`(\d*\d*x)(\w*\w*y)`, []int{10, 20, 30, 40, 50}}, // polynomial
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			score, err := AnalyzeComplexity(tt.pattern)
+			if err != nil {
+				t.Fatalf("AnalyzeComplexity() error = %v", err)
+			}
+			if !reflect.DeepEqual(score.PumpSizes, tt.want) {
+				t.Errorf("PumpSizes = %v, want %v", score.PumpSizes, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package regret
+
+// Research papers, vulnerability reports and guides describing each kind
+// of issue.
+const (
+	refWeideman2016 = "https://doi.org/10.1007/978-3-319-40946-7_27" // Weideman et al., Analyzing Matching Time Behavior of Backtracking Regular Expression Matchers by Using Ambiguity of NFA (CIAA 2016)
+	refWustholz2017 = "https://arxiv.org/abs/1701.04045"             // Wüstholz et al., Static Detection of DoS Vulnerabilities in Programs that use Regular Expressions (TACAS 2017)
+	refDavis2018    = "https://doi.org/10.1145/3236024.3236027"      // Davis et al., The Impact of Regular Expression Denial of Service (ReDoS) in Practice (ESEC/FSE 2018)
+	refOWASP        = "https://owasp.org/www-community/attacks/Regular_expression_Denial_of_Service_-_ReDoS"
+	refUAX15        = "https://www.unicode.org/reports/tr15/" // Unicode Normalization Forms
+
+	refMomentCVE    = "https://nvd.nist.gov/vuln/detail/CVE-2017-18214" // moment.js date parsing
+	refMomentCVE2   = "https://nvd.nist.gov/vuln/detail/CVE-2022-31129" // moment.js RFC 2822 parsing
+	refValidatorCVE = "https://nvd.nist.gov/vuln/detail/CVE-2021-3765"  // validator.js rtrim
+)
+
+// issueReferences maps issue types to their references, most specific first.
+var issueReferences = map[IssueType][]string{
+	NestedQuantifiers:           {refWeideman2016, refWustholz2017, refMomentCVE, refOWASP},
+	ExponentialBacktracking:     {refWeideman2016, refWustholz2017, refMomentCVE, refOWASP},
+	OverlappingAlternation:      {refWeideman2016, refOWASP},
+	PolynomialBacktracking:      {refWeideman2016, refDavis2018, refValidatorCVE, refMomentCVE2, refOWASP},
+	UnboundedRepetition:         {refDavis2018, refValidatorCVE, refOWASP},
+	ComplexityThresholdExceeded: {refDavis2018, refOWASP},
+	AmbiguousPattern:            {refWeideman2016, refOWASP},
+	ContextuallyDangerous:       {refOWASP},
+	LargeQuantifierRange:        {refOWASP},
+	BackreferenceAmbiguity:      {refDavis2018, refOWASP},
+	UnicodeAmbiguity:            {refUAX15},
+}
+
+// References returns links to research papers, CVE entries and guides that
+// describe issues of type t, or nil if there are none. The returned slice
+// is a copy and may be modified.
+//
+// Validate fills in Issue.References with the same links.
+func References(t IssueType) []string {
+	refs := issueReferences[t]
+	if refs == nil {
+		return nil
+	}
+	return append([]string(nil), refs...)
+}
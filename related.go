@@ -0,0 +1,112 @@
+package regret
+
+import (
+	_ "embed"
+	"encoding/json"
+	"regexp/syntax"
+	"strings"
+	"sync"
+)
+
+// maxRelatedPatterns is the number of patterns Issue.RelatedPatterns lists
+// at most.
+const maxRelatedPatterns = 5
+
+//go:embed testdata/evil_patterns.json
+var evilPatternsJSON []byte
+
+var (
+	relatedOnce sync.Once
+	relatedDB   map[string][]string // Known dangerous patterns by shape
+)
+
+// relatedPatterns returns up to maxRelatedPatterns known dangerous patterns
+// whose quantifier nesting has the same shape as pattern. Known patterns
+// that contain pattern, such as (a+)+b for (a+)+, are left out since they
+// show nothing new. It returns nil if pattern does not parse or is not
+// structurally dangerous.
+func relatedPatterns(pattern string) []string {
+	relatedOnce.Do(loadRelatedDB)
+
+	shape, ok := patternShape(pattern)
+	if !ok {
+		return nil
+	}
+
+	var related []string
+	for _, known := range relatedDB[shape] {
+		if strings.Contains(known, pattern) {
+			continue
+		}
+		related = append(related, known)
+		if len(related) == maxRelatedPatterns {
+			break
+		}
+	}
+	return related
+}
+
+// loadRelatedDB indexes the embedded database of known dangerous patterns
+// by shape, keeping the order of the database.
+func loadRelatedDB() {
+	relatedDB = make(map[string][]string)
+
+	var db struct {
+		Patterns []struct {
+			Pattern string `json:"pattern"`
+		} `json:"patterns"`
+	}
+	if err := json.Unmarshal(evilPatternsJSON, &db); err != nil {
+		return
+	}
+	for _, p := range db.Patterns {
+		if shape, ok := patternShape(p.Pattern); ok {
+			relatedDB[shape] = append(relatedDB[shape], p.Pattern)
+		}
+	}
+}
+
+// patternShape returns the quantifierShape of pattern, or false if pattern
+// does not parse or has no unbounded quantifier. A single quantifier, like
+// .*, is not dangerous by its structure either, and since the parser turns
+// (a|a)* into a*, matching it would relate every unbounded repetition to
+// the overlapping alternations of the database.
+func patternShape(pattern string) (string, bool) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	shape := quantifierShape(re)
+	return shape, strings.Contains(shape, "Q") && shape != "Q()"
+}
+
+// quantifierShape returns a key that is equal for patterns whose unbounded
+// quantifiers nest the same way, such as (a+)+, (a*)+ and ([a-z]+)+.
+// Characters, anchors, groups and bounded quantifiers are left out, and an
+// alternation whose branches have no quantifier is reduced to a single A.
+func quantifierShape(re *syntax.Regexp) string {
+	switch re.Op {
+	case syntax.OpStar, syntax.OpPlus:
+		return "Q(" + quantifierShape(re.Sub[0]) + ")"
+	case syntax.OpRepeat:
+		if re.Max == -1 {
+			return "Q(" + quantifierShape(re.Sub[0]) + ")"
+		}
+		return quantifierShape(re.Sub[0])
+	case syntax.OpAlternate:
+		branches := make([]string, len(re.Sub))
+		for i, sub := range re.Sub {
+			branches[i] = quantifierShape(sub)
+		}
+		if strings.Join(branches, "") == "" {
+			return "A"
+		}
+		return "A[" + strings.Join(branches, "|") + "]"
+	}
+
+	var b strings.Builder
+	for _, sub := range re.Sub {
+		b.WriteString(quantifierShape(sub))
+	}
+	return b.String()
+}
@@ -0,0 +1,45 @@
+package regret
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRelatedPatterns(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"(a+)+", []string{"(a*)*", "(.*)*", "(.+)+", "(a*)+", "(.*a){2,}"}},
+		{`^(\d+)+$`, []string{"(a+)+", "(a*)*", "(a+)+b", "(.*)*", "(.+)+"}},
+		{"[0-9]*[0-9]+", []string{"a*a*", `\d*\d+`, `\w*\w+`}},
+		{"(x|xy)*", []string{"(a|ab)+"}},
+		{".*", nil},
+		{"^[a-z]+$", nil},
+		{"abc", nil},
+		{"(abc", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			if got := relatedPatterns(tt.pattern); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("relatedPatterns(%q) = %q, want %q", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate_RelatedPatterns(t *testing.T) {
+	issues, err := Validate("([a-z]+)+$")
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if len(issues) == 0 {
+		t.Fatal("Validate() found no issues")
+	}
+	for _, issue := range issues {
+		if issue.Type == NestedQuantifiers && len(issue.RelatedPatterns) == 0 {
+			t.Errorf("nested quantifier issue %q has no related patterns", issue.Pattern)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package regret
+
+import (
+	"fmt"
+	"time"
+)
+
+// ValidationReport is the result of validating and analyzing a pattern in
+// one call.
+type ValidationReport struct {
+	// Pattern is the reported pattern.
+	Pattern string
+
+	// Issues contains the issues Validate reports for the pattern.
+	Issues IssueSet
+
+	// Complexity is the complexity analysis of the pattern. Its Issues
+	// field holds the same issues as the report.
+	Complexity *ComplexityScore
+
+	// Summary states the most important findings in one sentence.
+	Summary string
+
+	// Duration is how long validation and analysis took.
+	Duration time.Duration
+
+	// Options are the options the pattern was reported with.
+	Options *Options
+}
+
+// Report validates a pattern and analyzes its complexity in one call,
+// parsing it once for both. It returns the same issues as
+// ValidateWithOptions and the same score as AnalyzeComplexityWithOptions.
+// If opts is nil, DefaultOptions() is used.
+//
+// Example:
+//
+//	report, err := regret.Report("(a+)+", regret.ThoroughOptions())
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Println(report.Summary)
+func Report(pattern string, opts *Options) (*ValidationReport, error) {
+	return NewValidator(opts).Report(pattern)
+}
+
+// Report validates a pattern and analyzes its complexity with the
+// validator's options. See the package-level Report.
+func (v *Validator) Report(pattern string) (*ValidationReport, error) {
+	start := time.Now()
+	decoded, err := decodePattern(pattern, v.opts)
+	if err != nil {
+		return nil, err
+	}
+	report, err := v.report(decoded, start)
+	return report, encodedError(err, pattern, decoded)
+}
+
+// report is Report for a decoded pattern. Like Validate, it applies the
+// policies of screen before the length check and parsing, so denied and
+// allowed patterns get their issues even if they cannot be analyzed.
+func (v *Validator) report(pattern string, start time.Time) (*ValidationReport, error) {
+	issues, screened, err := v.screen(pattern)
+	if err != nil {
+		return nil, err
+	}
+	tooLong := v.opts.MaxPatternLength > 0 && len(pattern) > v.opts.MaxPatternLength
+	if tooLong && !screened {
+		return nil, fmt.Errorf("%w: %d > %d", ErrPatternTooLong, len(pattern), v.opts.MaxPatternLength)
+	}
+
+	var score *ComplexityScore
+	a := newAnalyzer(v.opts)
+	re, err := a.parser.Parse(expandBackreferences(pattern, v.opts))
+	switch {
+	case screened && (tooLong || err != nil):
+		score = unanalyzedScore(pattern, err)
+	case err != nil:
+		return nil, err
+	default:
+		if !screened {
+			if issues, err = v.validateParsed(re, pattern); err != nil {
+				return nil, err
+			}
+		}
+		if score, err = a.analyze(re, pattern); err != nil {
+			return nil, err
+		}
+	}
+	score.Issues = issues
+
+	return &ValidationReport{
+		Pattern:    pattern,
+		Issues:     issues,
+		Complexity: score,
+		Summary:    summarize(issues, score),
+		Duration:   time.Since(start),
+		Options:    v.opts,
+	}, nil
+}
+
+// unanalyzedScore is the score of a pattern whose result a policy decided
+// but that is too long or cannot be parsed, so it was not analyzed. parseErr
+// is the parse error, if any.
+func unanalyzedScore(pattern string, parseErr error) *ComplexityScore {
+	reason := "it is longer than Options.MaxPatternLength"
+	if parseErr != nil {
+		reason = parseErr.Error()
+	}
+	return &ComplexityScore{
+		TimeComplexity:  Unknown,
+		SpaceComplexity: Unknown,
+		Explanation:     fmt.Sprintf("%s was not analyzed: %s", quote(pattern), reason),
+	}
+}
+
+// summarize describes the worst issue and the complexity in one sentence.
+func summarize(issues IssueSet, score *ComplexityScore) string {
+	complexity := fmt.Sprintf("%s time, complexity score %d/100", score.TimeComplexity, score.Overall)
+
+	worst, ok := issues.Worst()
+	if !ok {
+		if score.Safe {
+			return fmt.Sprintf("No issues found; %s.", complexity)
+		}
+		return fmt.Sprintf("No issues found, but the pattern is unsafe: %s.", complexity)
+	}
+
+	count := "1 issue"
+	if len(issues) > 1 {
+		count = fmt.Sprintf("%d issues", len(issues))
+	}
+	return fmt.Sprintf("Found %s, the most severe a %s %s at position %d; %s.",
+		count, worst.Severity, worst.Type, worst.Position.Start, complexity)
+}
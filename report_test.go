@@ -0,0 +1,104 @@
+package regret
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	tests := []struct {
+		pattern     string
+		wantIssues  bool
+		wantSummary string
+	}{
+		{"^[a-z]+$", false, "No issues found; O(n) time"},
+		{"(a+)+", true, "the most severe a critical nested_quantifiers at position 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			opts := ThoroughOptions()
+			report, err := Report(tt.pattern, opts)
+			if err != nil {
+				t.Fatalf("Report() error = %v", err)
+			}
+			if report.Pattern != tt.pattern || report.Options != opts {
+				t.Errorf("Report() Pattern = %q, Options = %p", report.Pattern, report.Options)
+			}
+			if got := len(report.Issues) > 0; got != tt.wantIssues {
+				t.Errorf("Report() has issues = %v, want %v", got, tt.wantIssues)
+			}
+			if !strings.Contains(report.Summary, tt.wantSummary) {
+				t.Errorf("Summary = %q, want it to contain %q", report.Summary, tt.wantSummary)
+			}
+			if report.Duration <= 0 {
+				t.Error("Duration not set")
+			}
+
+			// The report matches separate Validate and AnalyzeComplexity calls
+			issues, err := ValidateWithOptions(tt.pattern, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(issues) != len(report.Issues) {
+				t.Errorf("Issues = %v, want %v", report.Issues, issues)
+			}
+			score, err := AnalyzeComplexityWithOptions(tt.pattern, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if score.Overall != report.Complexity.Overall || score.Safe != report.Complexity.Safe {
+				t.Errorf("Complexity = %+v, want %+v", report.Complexity, score)
+			}
+		})
+	}
+}
+
+func TestReport_Errors(t *testing.T) {
+	if _, err := Report("(a+", nil); err == nil {
+		t.Error("Report() of an invalid pattern error = nil")
+	}
+
+	opts := DefaultOptions()
+	opts.MaxPatternLength = 3
+	if _, err := Report("abcd", opts); !errors.Is(err, ErrPatternTooLong) {
+		t.Errorf("Report() error = %v, want ErrPatternTooLong", err)
+	}
+}
+
+func TestReport_ScreenedBeforeParsing(t *testing.T) {
+	// Go cannot parse lookbehinds, but policies decide the result first
+	pattern := `(?<=x)a`
+	tests := []struct {
+		name  string
+		setup func(*Options)
+	}{
+		{"deny list", func(o *Options) { o.DenyList = []string{pattern} }},
+		{"allowed unsafe pattern", func(o *Options) { o.AllowUnsafePatterns = []string{pattern} }},
+		{"allow unsafe", func(o *Options) { o.AllowUnsafe = true }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.MaxPatternLength = 3
+			tt.setup(opts)
+
+			want, err := ValidateWithOptions(pattern, opts)
+			if err != nil {
+				t.Fatalf("ValidateWithOptions() error = %v", err)
+			}
+			report, err := Report(pattern, opts)
+			if err != nil {
+				t.Fatalf("Report() error = %v", err)
+			}
+			if len(report.Issues) != len(want) {
+				t.Errorf("Report() issues = %v, want %v", report.Issues, want)
+			}
+			if report.Complexity.TimeComplexity != Unknown {
+				t.Errorf("TimeComplexity = %v, want %v", report.Complexity.TimeComplexity, Unknown)
+			}
+		})
+	}
+}
@@ -0,0 +1,583 @@
+package regret
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp/syntax"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RuleSet is a named validation policy that can be shared between
+// services as a JSON file.
+//
+// A RuleSet file looks like:
+//
+//	{
+//	  "name": "payments",
+//	  "description": "Policy for the payments services",
+//	  "owner": "security@example.com",
+//	  "options": {
+//	    "mode": "thorough",
+//	    "timeout": "500ms",
+//	    "max_nesting_depth": 2,
+//	    "deny_list": ["(a+)+"]
+//	  }
+//	}
+//
+// Options missing from the file keep their DefaultOptions values.
+type RuleSet struct {
+	// Name identifies the rule set.
+	Name string
+
+	// Description explains what the rule set is for.
+	Description string
+
+	// Owner is the team or person responsible for the rule set.
+	Owner string
+
+	// Options are the validation options the rule set enforces.
+	Options *Options
+
+	validatorOnce sync.Once
+	validator     *Validator
+}
+
+// NewRuleSet creates a rule set with the given options.
+// If opts is nil, DefaultOptions() is used.
+func NewRuleSet(name string, opts *Options) *RuleSet {
+	if opts == nil {
+		opts = DefaultOptions()
+	}
+	return &RuleSet{Name: name, Options: opts}
+}
+
+// Merge returns a new rule set that applies other on top of r, so that a
+// service can start from a shared rule set and tighten it.
+//
+// Options in other that are set, meaning neither zero nor equal to their
+// DefaultOptions() value, replace those in r; the rest keep r's value. This
+// lets other be a full set of options or a sparse Options literal, but means
+// Merge cannot reset an option to its zero or default value (for example
+// Mode: Fast or Timeout: 0). Deny lists and allowed unsafe patterns are
+// combined. Name, Description and Owner are taken from other when set.
+//
+// Example:
+//
+//	base, err := regret.LoadRuleSet("https://example.com/regret-rules.json")
+//	if err != nil {
+//	    return err
+//	}
+//	local := regret.NewRuleSet("checkout", &regret.Options{MaxNestingDepth: 1})
+//	rules := base.Merge(local)
+func (r *RuleSet) Merge(other *RuleSet) *RuleSet {
+	merged := &RuleSet{
+		Name:        r.Name,
+		Description: r.Description,
+		Owner:       r.Owner,
+		Options:     mergeOptions(r.options(), other.options()),
+	}
+	if other.Name != "" {
+		merged.Name = other.Name
+	}
+	if other.Description != "" {
+		merged.Description = other.Description
+	}
+	if other.Owner != "" {
+		merged.Owner = other.Owner
+	}
+	return merged
+}
+
+// Validate validates a pattern with the rule set's options.
+// The options are read on first use; later changes to r.Options have no effect.
+func (r *RuleSet) Validate(pattern string) ([]Issue, error) {
+	r.validatorOnce.Do(func() {
+		r.validator = NewValidator(r.options())
+	})
+	return r.validator.Validate(pattern)
+}
+
+func (r *RuleSet) options() *Options {
+	if r.Options == nil {
+		return DefaultOptions()
+	}
+	return r.Options
+}
+
+// mergeOptions returns a copy of base with every field of overrides that is
+// neither zero nor equal to its default applied on top. Deny lists and
+// allowed unsafe patterns are combined.
+func mergeOptions(base, overrides *Options) *Options {
+	def := DefaultOptions()
+	merged := *base
+	merged.DenyList = append([]string(nil), base.DenyList...)
+	merged.AllowUnsafePatterns = append([]string(nil), base.AllowUnsafePatterns...)
+
+	if overrides.Mode != Fast && overrides.Mode != def.Mode {
+		merged.Mode = overrides.Mode
+	}
+	if overrides.Timeout != 0 && overrides.Timeout != def.Timeout {
+		merged.Timeout = overrides.Timeout
+	}
+	if overrides.TimeoutBehavior != TimeoutError && overrides.TimeoutBehavior != def.TimeoutBehavior {
+		merged.TimeoutBehavior = overrides.TimeoutBehavior
+	}
+	if overrides.Dialect != DialectPCRE && overrides.Dialect != def.Dialect {
+		merged.Dialect = overrides.Dialect
+	}
+	if overrides.ParseFlags != 0 && overrides.ParseFlags != def.ParseFlags {
+		merged.ParseFlags = overrides.ParseFlags
+	}
+	if overrides.PatternEncoding != "" && overrides.PatternEncoding != def.PatternEncoding {
+		merged.PatternEncoding = overrides.PatternEncoding
+	}
+	if overrides.Checks != 0 && overrides.Checks != def.Checks {
+		merged.Checks = overrides.Checks
+	}
+	if overrides.MaxComplexityScore != 0 && overrides.MaxComplexityScore != def.MaxComplexityScore {
+		merged.MaxComplexityScore = overrides.MaxComplexityScore
+	}
+	if overrides.EnableSafetyMargin != 0 && overrides.EnableSafetyMargin != def.EnableSafetyMargin {
+		merged.EnableSafetyMargin = overrides.EnableSafetyMargin
+	}
+	if overrides.MaxPatternLength != 0 && overrides.MaxPatternLength != def.MaxPatternLength {
+		merged.MaxPatternLength = overrides.MaxPatternLength
+	}
+	if overrides.MaxNestingDepth != 0 && overrides.MaxNestingDepth != def.MaxNestingDepth {
+		merged.MaxNestingDepth = overrides.MaxNestingDepth
+	}
+	if overrides.MaxQuantifiers != 0 && overrides.MaxQuantifiers != def.MaxQuantifiers {
+		merged.MaxQuantifiers = overrides.MaxQuantifiers
+	}
+	if overrides.MaxQuantifierRange != 0 && overrides.MaxQuantifierRange != def.MaxQuantifierRange {
+		merged.MaxQuantifierRange = overrides.MaxQuantifierRange
+	}
+	if overrides.MaxRepetitionCount != 0 && overrides.MaxRepetitionCount != def.MaxRepetitionCount {
+		merged.MaxRepetitionCount = overrides.MaxRepetitionCount
+	}
+	if overrides.MaxAlternationBranches != 0 && overrides.MaxAlternationBranches != def.MaxAlternationBranches {
+		merged.MaxAlternationBranches = overrides.MaxAlternationBranches
+	}
+	if overrides.MaxNFAStates != 0 && overrides.MaxNFAStates != def.MaxNFAStates {
+		merged.MaxNFAStates = overrides.MaxNFAStates
+	}
+	if overrides.MaxDFAStates != 0 && overrides.MaxDFAStates != def.MaxDFAStates {
+		merged.MaxDFAStates = overrides.MaxDFAStates
+	}
+	if overrides.MaxIssues != 0 && overrides.MaxIssues != def.MaxIssues {
+		merged.MaxIssues = overrides.MaxIssues
+	}
+	if len(overrides.SeverityOverride) > 0 {
+		severities := make(map[IssueType]Severity, len(base.SeverityOverride)+len(overrides.SeverityOverride))
+		for t, sev := range base.SeverityOverride {
+			severities[t] = sev
+		}
+		for t, sev := range overrides.SeverityOverride {
+			severities[t] = sev
+		}
+		merged.SeverityOverride = severities
+	}
+	if overrides.StrictMode && !def.StrictMode {
+		merged.StrictMode = overrides.StrictMode
+	}
+	if overrides.TreatWarningsAsErrors && !def.TreatWarningsAsErrors {
+		merged.TreatWarningsAsErrors = overrides.TreatWarningsAsErrors
+	}
+	if overrides.EnableExperimentalChecks && !def.EnableExperimentalChecks {
+		merged.EnableExperimentalChecks = overrides.EnableExperimentalChecks
+	}
+	if overrides.DenyListFile != "" && overrides.DenyListFile != def.DenyListFile {
+		merged.DenyListFile = overrides.DenyListFile
+	}
+	if overrides.Concurrency != 0 && overrides.Concurrency != def.Concurrency {
+		merged.Concurrency = overrides.Concurrency
+	}
+	if overrides.CacheSize != 0 && overrides.CacheSize != def.CacheSize {
+		merged.CacheSize = overrides.CacheSize
+	}
+	if overrides.AllowUnsafe && !def.AllowUnsafe {
+		merged.AllowUnsafe = overrides.AllowUnsafe
+	}
+	merged.DenyList = append(merged.DenyList, overrides.DenyList...)
+	merged.AllowUnsafePatterns = append(merged.AllowUnsafePatterns, overrides.AllowUnsafePatterns...)
+
+	return &merged
+}
+
+// ruleSetJSON is the JSON form of a RuleSet.
+type ruleSetJSON struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Owner       string       `json:"owner,omitempty"`
+	Options     *optionsJSON `json:"options,omitempty"`
+}
+
+// optionsJSON is the serialized form of Options, shared by rule sets and
+// config files. Fields are pointers so that missing fields can be told
+// apart from zero values.
+type optionsJSON struct {
+	Mode                     *string           `json:"mode,omitempty" yaml:"mode,omitempty" toml:"mode,omitempty"`
+	Timeout                  *string           `json:"timeout,omitempty" yaml:"timeout,omitempty" toml:"timeout,omitempty"`
+	TimeoutBehavior          *string           `json:"timeout_behavior,omitempty" yaml:"timeout_behavior,omitempty" toml:"timeout_behavior,omitempty"`
+	Dialect                  *string           `json:"dialect,omitempty" yaml:"dialect,omitempty" toml:"dialect,omitempty"`
+	ParseFlags               *syntax.Flags     `json:"parse_flags,omitempty" yaml:"parse_flags,omitempty" toml:"parse_flags,omitempty"`
+	PatternEncoding          *string           `json:"pattern_encoding,omitempty" yaml:"pattern_encoding,omitempty" toml:"pattern_encoding,omitempty"`
+	Checks                   *CheckFlags       `json:"checks,omitempty" yaml:"checks,omitempty" toml:"checks,omitempty"`
+	MaxComplexityScore       *int              `json:"max_complexity_score,omitempty" yaml:"max_complexity_score,omitempty" toml:"max_complexity_score,omitempty"`
+	EnableSafetyMargin       *float64          `json:"enable_safety_margin,omitempty" yaml:"enable_safety_margin,omitempty" toml:"enable_safety_margin,omitempty"`
+	MaxPatternLength         *int              `json:"max_pattern_length,omitempty" yaml:"max_pattern_length,omitempty" toml:"max_pattern_length,omitempty"`
+	MaxNestingDepth          *int              `json:"max_nesting_depth,omitempty" yaml:"max_nesting_depth,omitempty" toml:"max_nesting_depth,omitempty"`
+	MaxQuantifiers           *int              `json:"max_quantifiers,omitempty" yaml:"max_quantifiers,omitempty" toml:"max_quantifiers,omitempty"`
+	MaxQuantifierRange       *int              `json:"max_quantifier_range,omitempty" yaml:"max_quantifier_range,omitempty" toml:"max_quantifier_range,omitempty"`
+	MaxRepetitionCount       *int              `json:"max_repetition_count,omitempty" yaml:"max_repetition_count,omitempty" toml:"max_repetition_count,omitempty"`
+	MaxAlternationBranches   *int              `json:"max_alternation_branches,omitempty" yaml:"max_alternation_branches,omitempty" toml:"max_alternation_branches,omitempty"`
+	MaxNFAStates             *int              `json:"max_nfa_states,omitempty" yaml:"max_nfa_states,omitempty" toml:"max_nfa_states,omitempty"`
+	MaxDFAStates             *int              `json:"max_dfa_states,omitempty" yaml:"max_dfa_states,omitempty" toml:"max_dfa_states,omitempty"`
+	MaxIssues                *int              `json:"max_issues,omitempty" yaml:"max_issues,omitempty" toml:"max_issues,omitempty"`
+	SeverityOverride         map[string]string `json:"severity_override,omitempty" yaml:"severity_override,omitempty" toml:"severity_override,omitempty"`
+	StrictMode               *bool             `json:"strict_mode,omitempty" yaml:"strict_mode,omitempty" toml:"strict_mode,omitempty"`
+	TreatWarningsAsErrors    *bool             `json:"treat_warnings_as_errors,omitempty" yaml:"treat_warnings_as_errors,omitempty" toml:"treat_warnings_as_errors,omitempty"`
+	EnableExperimentalChecks *bool             `json:"enable_experimental_checks,omitempty" yaml:"enable_experimental_checks,omitempty" toml:"enable_experimental_checks,omitempty"`
+	DenyList                 []string          `json:"deny_list,omitempty" yaml:"deny_list,omitempty" toml:"deny_list,omitempty"`
+	DenyListFile             *string           `json:"deny_list_file,omitempty" yaml:"deny_list_file,omitempty" toml:"deny_list_file,omitempty"`
+	AllowUnsafePatterns      []string          `json:"allow_unsafe_patterns,omitempty" yaml:"allow_unsafe_patterns,omitempty" toml:"allow_unsafe_patterns,omitempty"`
+	Concurrency              *int              `json:"concurrency,omitempty" yaml:"concurrency,omitempty" toml:"concurrency,omitempty"`
+	CacheSize                *int              `json:"cache_size,omitempty" yaml:"cache_size,omitempty" toml:"cache_size,omitempty"`
+	AllowUnsafe              *bool             `json:"allow_unsafe,omitempty" yaml:"allow_unsafe,omitempty" toml:"allow_unsafe,omitempty"`
+}
+
+// MarshalJSON encodes the rule set with every option spelled out.
+func (r *RuleSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ruleSetJSON{
+		Name:        r.Name,
+		Description: r.Description,
+		Owner:       r.Owner,
+		Options:     newOptionsJSON(r.options()),
+	})
+}
+
+// newOptionsJSON returns the serialized form of opts with every field set.
+func newOptionsJSON(opts *Options) *optionsJSON {
+	mode := opts.Mode.String()
+	timeout := opts.Timeout.String()
+	behavior := opts.TimeoutBehavior.String()
+	dialect := opts.Dialect.String()
+
+	return &optionsJSON{
+		Mode:                     &mode,
+		Timeout:                  &timeout,
+		TimeoutBehavior:          &behavior,
+		Dialect:                  &dialect,
+		ParseFlags:               &opts.ParseFlags,
+		PatternEncoding:          &opts.PatternEncoding,
+		Checks:                   &opts.Checks,
+		MaxComplexityScore:       &opts.MaxComplexityScore,
+		EnableSafetyMargin:       &opts.EnableSafetyMargin,
+		MaxPatternLength:         &opts.MaxPatternLength,
+		MaxNestingDepth:          &opts.MaxNestingDepth,
+		MaxQuantifiers:           &opts.MaxQuantifiers,
+		MaxQuantifierRange:       &opts.MaxQuantifierRange,
+		MaxRepetitionCount:       &opts.MaxRepetitionCount,
+		MaxAlternationBranches:   &opts.MaxAlternationBranches,
+		MaxNFAStates:             &opts.MaxNFAStates,
+		MaxDFAStates:             &opts.MaxDFAStates,
+		MaxIssues:                &opts.MaxIssues,
+		SeverityOverride:         severityOverrideNames(opts.SeverityOverride),
+		StrictMode:               &opts.StrictMode,
+		TreatWarningsAsErrors:    &opts.TreatWarningsAsErrors,
+		EnableExperimentalChecks: &opts.EnableExperimentalChecks,
+		DenyList:                 opts.DenyList,
+		DenyListFile:             &opts.DenyListFile,
+		AllowUnsafePatterns:      opts.AllowUnsafePatterns,
+		Concurrency:              &opts.Concurrency,
+		CacheSize:                &opts.CacheSize,
+		AllowUnsafe:              &opts.AllowUnsafe,
+	}
+}
+
+// UnmarshalJSON decodes a rule set. Options missing from the input keep
+// their DefaultOptions values; unknown option names are rejected.
+func (r *RuleSet) UnmarshalJSON(data []byte) error {
+	var v ruleSetJSON
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+
+	opts := DefaultOptions()
+	if v.Options != nil {
+		if err := v.Options.apply(opts); err != nil {
+			return fmt.Errorf("rule set %q: %w", v.Name, err)
+		}
+	}
+
+	*r = RuleSet{Name: v.Name, Description: v.Description, Owner: v.Owner, Options: opts}
+	return nil
+}
+
+// apply sets the fields present in o on opts.
+func (o *optionsJSON) apply(opts *Options) error {
+	if o.Mode != nil {
+		mode, err := parseValidationMode(*o.Mode)
+		if err != nil {
+			return err
+		}
+		opts.Mode = mode
+	}
+	if o.Timeout != nil {
+		timeout, err := time.ParseDuration(*o.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid timeout: %w", err)
+		}
+		opts.Timeout = timeout
+	}
+	if o.TimeoutBehavior != nil {
+		behavior, err := parseTimeoutBehavior(*o.TimeoutBehavior)
+		if err != nil {
+			return err
+		}
+		opts.TimeoutBehavior = behavior
+	}
+	if o.Dialect != nil {
+		dialect, err := parseDialect(*o.Dialect)
+		if err != nil {
+			return err
+		}
+		opts.Dialect = dialect
+	}
+	if o.ParseFlags != nil {
+		opts.ParseFlags = *o.ParseFlags
+	}
+	if o.PatternEncoding != nil {
+		opts.PatternEncoding = *o.PatternEncoding
+	}
+	if o.Checks != nil {
+		opts.Checks = *o.Checks
+	}
+	for _, f := range []struct {
+		src *int
+		dst *int
+	}{
+		{o.MaxComplexityScore, &opts.MaxComplexityScore},
+		{o.MaxPatternLength, &opts.MaxPatternLength},
+		{o.MaxNestingDepth, &opts.MaxNestingDepth},
+		{o.MaxQuantifiers, &opts.MaxQuantifiers},
+		{o.MaxQuantifierRange, &opts.MaxQuantifierRange},
+		{o.MaxRepetitionCount, &opts.MaxRepetitionCount},
+		{o.MaxAlternationBranches, &opts.MaxAlternationBranches},
+		{o.MaxNFAStates, &opts.MaxNFAStates},
+		{o.MaxDFAStates, &opts.MaxDFAStates},
+		{o.MaxIssues, &opts.MaxIssues},
+		{o.Concurrency, &opts.Concurrency},
+		{o.CacheSize, &opts.CacheSize},
+	} {
+		if f.src != nil {
+			*f.dst = *f.src
+		}
+	}
+	if o.EnableSafetyMargin != nil {
+		opts.EnableSafetyMargin = *o.EnableSafetyMargin
+	}
+	if o.SeverityOverride != nil {
+		opts.SeverityOverride = make(map[IssueType]Severity, len(o.SeverityOverride))
+		for name, sevName := range o.SeverityOverride {
+			t, err := IssueTypeFromString(name)
+			if err != nil {
+				return fmt.Errorf("severity_override: %w", err)
+			}
+			sev, err := SeverityFromString(sevName)
+			if err != nil {
+				return fmt.Errorf("severity_override: %w", err)
+			}
+			opts.SeverityOverride[t] = sev
+		}
+	}
+	if o.StrictMode != nil {
+		opts.StrictMode = *o.StrictMode
+	}
+	if o.TreatWarningsAsErrors != nil {
+		opts.TreatWarningsAsErrors = *o.TreatWarningsAsErrors
+	}
+	if o.EnableExperimentalChecks != nil {
+		opts.EnableExperimentalChecks = *o.EnableExperimentalChecks
+	}
+	if o.DenyList != nil {
+		opts.DenyList = o.DenyList
+	}
+	if o.DenyListFile != nil {
+		opts.DenyListFile = *o.DenyListFile
+	}
+	if o.AllowUnsafePatterns != nil {
+		opts.AllowUnsafePatterns = o.AllowUnsafePatterns
+	}
+	if o.AllowUnsafe != nil {
+		opts.AllowUnsafe = *o.AllowUnsafe
+	}
+	return nil
+}
+
+// severityOverrideNames returns the serialized form of a SeverityOverride
+// map, keyed and valued by name.
+func severityOverrideNames(overrides map[IssueType]Severity) map[string]string {
+	if overrides == nil {
+		return nil
+	}
+	names := make(map[string]string, len(overrides))
+	for t, sev := range overrides {
+		names[t.String()] = sev.String()
+	}
+	return names
+}
+
+func parseValidationMode(s string) (ValidationMode, error) {
+	for _, mode := range []ValidationMode{Fast, Balanced, Thorough} {
+		if strings.EqualFold(s, mode.String()) {
+			return mode, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid mode %q (expected fast|balanced|thorough)", s)
+}
+
+func parseTimeoutBehavior(s string) (TimeoutBehavior, error) {
+	for _, b := range []TimeoutBehavior{TimeoutError, TimeoutReturnPartial, TimeoutMarkUnsafe} {
+		if strings.EqualFold(s, b.String()) {
+			return b, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid timeout behavior %q (expected error|return_partial|mark_unsafe)", s)
+}
+
+func parseDialect(s string) (Dialect, error) {
+	for _, d := range []Dialect{DialectPCRE, DialectRE2, DialectJava, DialectPython, DialectJavaScript, DialectRuby} {
+		if strings.EqualFold(s, d.String()) {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid dialect %q (expected pcre|re2|java|python|javascript|ruby)", s)
+}
+
+// RuleSetFetcher opens the rule set file at location for LoadRuleSet. It
+// should stop and return an error when ctx is done.
+type RuleSetFetcher func(ctx context.Context, location string) (io.ReadCloser, error)
+
+// ruleSetHTTPTimeout bounds how long fetching a rule set over HTTP may take,
+// so that a stalled server cannot hang LoadRuleSet.
+const ruleSetHTTPTimeout = 30 * time.Second
+
+var ruleSetHTTPClient = &http.Client{Timeout: ruleSetHTTPTimeout}
+
+// ruleSetFetchers holds fetchers keyed by URL scheme.
+var ruleSetFetchers sync.Map
+
+func init() {
+	RegisterRuleSetFetcher("file", fetchRuleSetFile)
+	RegisterRuleSetFetcher("http", fetchRuleSetHTTP)
+	RegisterRuleSetFetcher("https", fetchRuleSetHTTP)
+}
+
+// RegisterRuleSetFetcher makes LoadRuleSet use f for locations with the
+// given URL scheme. Local paths, file://, http:// and https:// are supported
+// out of the box; other storage, such as s3://, can be added by registering
+// a fetcher that uses the storage's client library.
+// Registering a scheme again replaces the previous fetcher.
+//
+// Example:
+//
+//	regret.RegisterRuleSetFetcher("s3", func(ctx context.Context, location string) (io.ReadCloser, error) {
+//	    u, _ := url.Parse(location)
+//	    out, err := s3Client.GetObject(ctx, &s3.GetObjectInput{
+//	        Bucket: aws.String(u.Host),
+//	        Key:    aws.String(strings.TrimPrefix(u.Path, "/")),
+//	    })
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    return out.Body, nil
+//	})
+func RegisterRuleSetFetcher(scheme string, f RuleSetFetcher) {
+	if f == nil {
+		panic("regret: RegisterRuleSetFetcher fetcher is nil")
+	}
+	ruleSetFetchers.Store(strings.ToLower(scheme), f)
+}
+
+// LoadRuleSet reads a JSON rule set from a local path or URL.
+// See RegisterRuleSetFetcher for the supported locations. HTTP requests
+// time out after 30 seconds; use LoadRuleSetContext to cancel sooner.
+//
+// Example:
+//
+//	rules, err := regret.LoadRuleSet("https://example.com/regret-rules.json")
+//	if err != nil {
+//	    return err
+//	}
+//	issues, err := rules.Validate(pattern)
+func LoadRuleSet(location string) (*RuleSet, error) {
+	return LoadRuleSetContext(context.Background(), location)
+}
+
+// LoadRuleSetContext is like LoadRuleSet but stops fetching the rule set
+// when ctx is done.
+func LoadRuleSetContext(ctx context.Context, location string) (*RuleSet, error) {
+	scheme := "file"
+	// Single-letter schemes are Windows drive letters
+	if u, err := url.Parse(location); err == nil && len(u.Scheme) > 1 {
+		scheme = strings.ToLower(u.Scheme)
+	}
+
+	f, ok := ruleSetFetchers.Load(scheme)
+	if !ok {
+		return nil, fmt.Errorf("load rule set %s: unsupported scheme %q (see RegisterRuleSetFetcher)", location, scheme)
+	}
+
+	rc, err := f.(RuleSetFetcher)(ctx, location)
+	if err != nil {
+		return nil, fmt.Errorf("load rule set %s: %w", location, err)
+	}
+	defer rc.Close()
+
+	var rules RuleSet
+	if err := json.NewDecoder(rc).Decode(&rules); err != nil {
+		return nil, fmt.Errorf("load rule set %s: %w", location, err)
+	}
+	return &rules, nil
+}
+
+func fetchRuleSetFile(ctx context.Context, location string) (io.ReadCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(location, "file://") {
+		u, err := url.Parse(location)
+		if err != nil {
+			return nil, err
+		}
+		location = u.Path
+	}
+	return os.Open(location)
+}
+
+func fetchRuleSetHTTP(ctx context.Context, location string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := ruleSetHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp.Body, nil
+}
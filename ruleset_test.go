@@ -0,0 +1,195 @@
+package regret
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewRuleSet(t *testing.T) {
+	rules := NewRuleSet("default", nil)
+	if rules.Name != "default" || rules.Options == nil {
+		t.Fatalf("NewRuleSet() = %+v, want named rule set with default options", rules)
+	}
+
+	issues, err := rules.Validate("(a+)+")
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if len(issues) == 0 {
+		t.Error("Validate() found no issues in (a+)+")
+	}
+}
+
+func TestRuleSet_Merge(t *testing.T) {
+	parentOpts := DefaultOptions()
+	parentOpts.Mode = Thorough
+	parentOpts.Timeout = time.Second
+	parentOpts.DenyList = []string{"a*"}
+	parent := &RuleSet{Name: "company", Owner: "security", Options: parentOpts}
+
+	child := NewRuleSet("payments", &Options{MaxNestingDepth: 1, DenyList: []string{"b*"}})
+
+	merged := parent.Merge(child)
+
+	if merged.Name != "payments" || merged.Owner != "security" {
+		t.Errorf("merged metadata = %q/%q, want payments/security", merged.Name, merged.Owner)
+	}
+	opts := merged.Options
+	if opts.Mode != Thorough || opts.Timeout != time.Second {
+		t.Errorf("merged kept Mode=%v Timeout=%v, want parent's thorough/1s", opts.Mode, opts.Timeout)
+	}
+	if opts.MaxNestingDepth != 1 {
+		t.Errorf("merged MaxNestingDepth = %d, want child's 1", opts.MaxNestingDepth)
+	}
+	if strings.Join(opts.DenyList, ",") != "a*,b*" {
+		t.Errorf("merged DenyList = %v, want [a* b*]", opts.DenyList)
+	}
+
+	// A child with full default options changes nothing
+	if got := parent.Merge(NewRuleSet("", nil)).Options; got.Mode != Thorough || got.Timeout != time.Second {
+		t.Errorf("merging defaults changed options: %+v", got)
+	}
+
+	// Merge does not modify either input
+	if len(parentOpts.DenyList) != 1 || parentOpts.MaxNestingDepth != 3 {
+		t.Errorf("Merge() modified parent options: %+v", parentOpts)
+	}
+}
+
+func TestRuleSet_JSONRoundTrip(t *testing.T) {
+	opts := ThoroughOptions()
+	opts.DenyList = []string{"(a+)+"}
+	in := NewRuleSet("payments", opts)
+	in.Description = "Payments policy"
+
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"mode":"thorough"`) || !strings.Contains(string(data), `"timeout":"1s"`) {
+		t.Errorf("json.Marshal() = %s, want readable mode and timeout", data)
+	}
+
+	var out RuleSet
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if out.Name != in.Name || out.Description != in.Description {
+		t.Errorf("round trip metadata = %q/%q", out.Name, out.Description)
+	}
+	got, want := out.Options, in.Options
+	if got.Mode != want.Mode || got.Timeout != want.Timeout || got.TimeoutBehavior != want.TimeoutBehavior ||
+		got.Checks != want.Checks || got.MaxPatternLength != want.MaxPatternLength ||
+		got.StrictMode != want.StrictMode || len(got.DenyList) != 1 {
+		t.Errorf("round trip options = %+v, want %+v", got, want)
+	}
+}
+
+func TestRuleSet_UnmarshalJSON(t *testing.T) {
+	var rules RuleSet
+	if err := json.Unmarshal([]byte(`{"name":"sparse","options":{"max_nesting_depth":2}}`), &rules); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	def := DefaultOptions()
+	if rules.Options.MaxNestingDepth != 2 || rules.Options.Timeout != def.Timeout || rules.Options.Mode != def.Mode {
+		t.Errorf("sparse options = %+v, want defaults with MaxNestingDepth 2", rules.Options)
+	}
+
+	for _, input := range []string{
+		`{"name":"x","options":{"mode":"paranoid"}}`,
+		`{"name":"x","options":{"timeout":"soon"}}`,
+		`{"name":"x","options":{"max_depth":2}}`,
+	} {
+		if err := json.Unmarshal([]byte(input), &rules); err == nil {
+			t.Errorf("json.Unmarshal(%s) expected error", input)
+		}
+	}
+}
+
+func TestLoadRuleSet(t *testing.T) {
+	data := `{"name":"shared","options":{"deny_list":["a+"]}}`
+
+	path := filepath.Join(t.TempDir(), "rules.json")
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rules.json" {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, data)
+	}))
+	defer server.Close()
+
+	for _, location := range []string{path, "file://" + path, server.URL + "/rules.json"} {
+		rules, err := LoadRuleSet(location)
+		if err != nil {
+			t.Errorf("LoadRuleSet(%s) error = %v", location, err)
+			continue
+		}
+		issues, err := rules.Validate("a+")
+		if err != nil || len(issues) == 0 {
+			t.Errorf("LoadRuleSet(%s) rules did not deny a+: %v, %v", location, issues, err)
+		}
+	}
+
+	if _, err := LoadRuleSet(server.URL + "/missing.json"); err == nil {
+		t.Error("LoadRuleSet() expected error for 404")
+	}
+	if _, err := LoadRuleSet("s3://bucket/rules.json"); err == nil {
+		t.Error("LoadRuleSet() expected error for unregistered scheme")
+	}
+}
+
+func TestLoadRuleSetContext_Cancel(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := LoadRuleSetContext(ctx, server.URL+"/rules.json")
+	if err == nil {
+		t.Fatal("LoadRuleSetContext() expected error for a stalled server")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("LoadRuleSetContext() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("LoadRuleSetContext() took %v after its context expired", elapsed)
+	}
+}
+
+func TestRegisterRuleSetFetcher(t *testing.T) {
+	RegisterRuleSetFetcher("mem", func(ctx context.Context, location string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(`{"name":"` + location + `"}`)), nil
+	})
+	defer ruleSetFetchers.Delete("mem")
+
+	rules, err := LoadRuleSet("mem://rules")
+	if err != nil {
+		t.Fatalf("LoadRuleSet() error = %v", err)
+	}
+	if rules.Name != "mem://rules" {
+		t.Errorf("LoadRuleSet() Name = %q, want mem://rules", rules.Name)
+	}
+}
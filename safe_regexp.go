@@ -0,0 +1,203 @@
+package regret
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strconv"
+)
+
+// SafeRegexp is a compiled regular expression that passed ReDoS validation.
+// It embeds *regexp.Regexp, so all matching methods are available directly.
+//
+// SafeRegexp serializes as its pattern string and validates the pattern again
+// when deserialized, so configuration files containing regexes are checked
+// as they are loaded:
+//
+//	type Config struct {
+//	    Filter *regret.SafeRegexp `json:"filter"`
+//	}
+//
+//	var cfg Config
+//	err := json.Unmarshal([]byte(`{"filter": {"pattern": "(a+)+"}}`), &cfg)
+//	// err reports the nested quantifier
+//
+// The complexity analysis done by Compile is kept with the compiled pattern
+// and available from Score, Issues and Explain.
+type SafeRegexp struct {
+	*regexp.Regexp
+
+	// complexity is the analysis of the pattern from Compile, or nil for a
+	// SafeRegexp that was not compiled by this package.
+	complexity *ComplexityScore
+}
+
+// Compile validates a pattern with default options and, if no issues are
+// found, compiles it. Unsafe patterns are rejected with an error describing
+// the most severe issue.
+//
+// Example:
+//
+//	re, err := regret.Compile(userPattern)
+//	if err != nil {
+//	    return err
+//	}
+//	matched := re.MatchString(input)
+func Compile(pattern string) (*SafeRegexp, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPattern, err)
+	}
+
+	// The pattern checked must be the one compiled
+	opts := GetDefaultOptions()
+	opts.PatternEncoding = PatternEncodingRaw
+	report, err := Report(pattern, opts)
+	if err != nil {
+		return nil, err
+	}
+	if worst, ok := report.Issues.Worst(); ok {
+		return nil, fmt.Errorf("unsafe pattern %q: %s", pattern, worst.Message)
+	}
+
+	return &SafeRegexp{Regexp: re, complexity: report.Complexity}, nil
+}
+
+// Score returns the overall complexity score of the pattern, from 0 to 100,
+// as computed by Compile. It returns 0 if r was not created by Compile,
+// MustCompile or unmarshaling.
+//
+// Example:
+//
+//	re := regret.MustCompile(`^[a-z]+$`)
+//	fmt.Println(re.Score())
+func (r *SafeRegexp) Score() int {
+	if r == nil || r.complexity == nil {
+		return 0
+	}
+	return r.complexity.Overall
+}
+
+// Issues returns the issues Compile found in the pattern. Compile rejects
+// patterns with issues, so the result is empty for every SafeRegexp it
+// returns; the method is there so that code handling reports and compiled
+// patterns alike can ask either.
+func (r *SafeRegexp) Issues() []Issue {
+	if r == nil || r.complexity == nil {
+		return nil
+	}
+	return r.complexity.Issues
+}
+
+// Explain returns the explanation of the complexity analysis, as in
+// ComplexityScore.Explanation, or "" if r was not created by Compile,
+// MustCompile or unmarshaling.
+func (r *SafeRegexp) Explain() string {
+	if r == nil || r.complexity == nil {
+		return ""
+	}
+	return r.complexity.Explanation
+}
+
+// MustCompile is like Compile but panics if the pattern is invalid or unsafe.
+// It is intended for patterns known at compile time.
+func MustCompile(pattern string) *SafeRegexp {
+	re, err := Compile(pattern)
+	if err != nil {
+		panic(`regret: Compile(` + quote(pattern) + `): ` + err.Error())
+	}
+	return re
+}
+
+// SafeCompile is a drop-in replacement for regexp.Compile with ReDoS
+// protection: it checks the pattern like IsSafe, with FastOptions or the
+// options set with SetDefaultOptions, and compiles it only if it is safe.
+// Unsafe patterns are rejected with an error wrapping ErrUnsafePattern, as
+// returned by CheckPattern.
+//
+// Example:
+//
+//	re, err := regret.SafeCompile(userPattern)
+//	if errors.Is(err, regret.ErrUnsafePattern) {
+//	    return fmt.Errorf("pattern rejected: %w", err)
+//	}
+func SafeCompile(pattern string) (*regexp.Regexp, error) {
+	// Report syntax errors as ErrInvalidPattern, as regexp.Compile would
+	if _, err := syntax.Parse(pattern, syntax.Perl); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPattern, err)
+	}
+	if err := CheckPattern(pattern); err != nil {
+		return nil, err
+	}
+	return regexp.Compile(pattern)
+}
+
+// SafeMustCompile is like SafeCompile but panics if the pattern is invalid
+// or unsafe, for replacing regexp.MustCompile. The panic value is an error
+// wrapping the one from SafeCompile, so an unsafe pattern panics with
+// ErrUnsafePattern and the reason rather than a syntax error.
+func SafeMustCompile(pattern string) *regexp.Regexp {
+	re, err := SafeCompile(pattern)
+	if err != nil {
+		panic(fmt.Errorf("regret: SafeCompile(%s): %w", quote(pattern), err))
+	}
+	return re
+}
+
+// safeRegexpJSON is the JSON form of a SafeRegexp.
+type safeRegexpJSON struct {
+	Pattern string `json:"pattern"`
+}
+
+// MarshalJSON encodes the pattern as {"pattern": "..."}.
+func (r *SafeRegexp) MarshalJSON() ([]byte, error) {
+	return json.Marshal(safeRegexpJSON{Pattern: r.pattern()})
+}
+
+// UnmarshalJSON decodes {"pattern": "..."} and compiles the pattern with
+// Compile, returning an error if it is invalid or unsafe.
+func (r *SafeRegexp) UnmarshalJSON(data []byte) error {
+	var v safeRegexpJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	return r.compile(v.Pattern)
+}
+
+// MarshalText encodes the pattern string. It implements
+// encoding.TextMarshaler, for use with packages such as flag and encoding/csv.
+func (r *SafeRegexp) MarshalText() ([]byte, error) {
+	return []byte(r.pattern()), nil
+}
+
+// UnmarshalText compiles the pattern with Compile, returning an error if it
+// is invalid or unsafe. It implements encoding.TextUnmarshaler.
+func (r *SafeRegexp) UnmarshalText(text []byte) error {
+	return r.compile(string(text))
+}
+
+func (r *SafeRegexp) pattern() string {
+	if r == nil || r.Regexp == nil {
+		return ""
+	}
+	return r.Regexp.String()
+}
+
+func (r *SafeRegexp) compile(pattern string) error {
+	compiled, err := Compile(pattern)
+	if err != nil {
+		return err
+	}
+	*r = *compiled
+	return nil
+}
+
+// quote is like strconv.Quote but uses backquotes when possible,
+// matching regexp.MustCompile's panic message.
+func quote(s string) string {
+	if strconv.CanBackquote(s) {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
@@ -0,0 +1,151 @@
+package regret
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	re, err := Compile(`^[a-z]+$`)
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	if !re.MatchString("hello") || re.MatchString("Hello") {
+		t.Error("compiled pattern does not match as expected")
+	}
+
+	if _, err := Compile(`(a+)+`); err == nil {
+		t.Error("Compile() expected error for unsafe pattern")
+	}
+
+	if _, err := Compile(`(a+`); !errors.Is(err, ErrInvalidPattern) {
+		t.Errorf("Compile() error = %v, want %v", err, ErrInvalidPattern)
+	}
+}
+
+func TestMustCompile(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("MustCompile() did not panic for unsafe pattern")
+		}
+		if msg, _ := r.(string); !strings.Contains(msg, "(a+)+") {
+			t.Errorf("panic message %q does not mention the pattern", msg)
+		}
+	}()
+
+	MustCompile(`(a+)+`)
+}
+
+func TestSafeCompile(t *testing.T) {
+	re, err := SafeCompile(`^[a-z]+$`)
+	if err != nil {
+		t.Fatalf("SafeCompile() error = %v", err)
+	}
+	if !re.MatchString("hello") {
+		t.Error("compiled pattern does not match as expected")
+	}
+
+	if _, err := SafeCompile(`(a+)+`); !errors.Is(err, ErrUnsafePattern) {
+		t.Errorf("SafeCompile() error = %v, want %v", err, ErrUnsafePattern)
+	}
+	if _, err := SafeCompile(`(a+`); !errors.Is(err, ErrInvalidPattern) {
+		t.Errorf("SafeCompile() error = %v, want %v", err, ErrInvalidPattern)
+	}
+}
+
+func TestSafeMustCompile(t *testing.T) {
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, ErrUnsafePattern) {
+			t.Errorf("SafeMustCompile() panicked with %v, want %v", err, ErrUnsafePattern)
+		}
+	}()
+
+	SafeMustCompile(`(a+)+`)
+}
+
+func TestSafeRegexp_JSON(t *testing.T) {
+	type config struct {
+		Filter *SafeRegexp `json:"filter"`
+	}
+
+	in := config{Filter: MustCompile(`^[a-z]+$`)}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if want := `{"filter":{"pattern":"^[a-z]+$"}}`; string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+
+	var out config
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if out.Filter.String() != `^[a-z]+$` {
+		t.Errorf("round-tripped pattern = %q", out.Filter.String())
+	}
+
+	// Unsafe patterns are rejected during deserialization
+	if err := json.Unmarshal([]byte(`{"filter":{"pattern":"(a+)+"}}`), &out); err == nil {
+		t.Error("json.Unmarshal() expected error for unsafe pattern")
+	}
+}
+
+func TestSafeRegexp_Text(t *testing.T) {
+	var re SafeRegexp
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.TextVar(&re, "filter", MustCompile(`^a$`), "filter pattern")
+
+	if err := fs.Parse([]string{"-filter", `^[0-9]+$`}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !re.MatchString("123") {
+		t.Errorf("flag value %q does not match 123", re.String())
+	}
+
+	text, err := re.MarshalText()
+	if err != nil || string(text) != `^[0-9]+$` {
+		t.Errorf("MarshalText() = %q, %v", text, err)
+	}
+
+	if err := re.UnmarshalText([]byte(`(a*)*`)); err == nil {
+		t.Error("UnmarshalText() expected error for unsafe pattern")
+	}
+}
+
+func TestSafeRegexp_Analysis(t *testing.T) {
+	pattern := `^[a-z]+@[a-z]+\.[a-z]{2,}$`
+	re := MustCompile(pattern)
+
+	want, err := AnalyzeComplexity(pattern)
+	if err != nil {
+		t.Fatalf("AnalyzeComplexity() error = %v", err)
+	}
+	if got := re.Score(); got != want.Overall {
+		t.Errorf("Score() = %d, want %d", got, want.Overall)
+	}
+	if got := re.Explain(); got == "" || got != want.Explanation {
+		t.Errorf("Explain() = %q, want %q", got, want.Explanation)
+	}
+	if issues := re.Issues(); len(issues) != 0 {
+		t.Errorf("Issues() = %v, want none", issues)
+	}
+
+	var decoded SafeRegexp
+	if err := json.Unmarshal([]byte(`{"pattern": "^a+$"}`), &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if decoded.Explain() == "" {
+		t.Error("Explain() is empty after unmarshaling")
+	}
+
+	var zero *SafeRegexp
+	if zero.Score() != 0 || zero.Issues() != nil || zero.Explain() != "" {
+		t.Error("nil SafeRegexp returned analysis results")
+	}
+}
@@ -0,0 +1,375 @@
+package regret
+
+import (
+	"bufio"
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Finding is a regex pattern found in source code, together with the
+// result of validating it.
+type Finding struct {
+	// File is the source file the pattern was found in. ScanReader leaves
+	// it empty; callers scanning files fill it in.
+	File string
+
+	// Line and Column are the 1-based position of the literal that holds
+	// the pattern. Column counts bytes.
+	Line   int
+	Column int
+
+	// Pattern is the pattern as the regex engine sees it, with the
+	// language's string escapes removed.
+	Pattern string
+
+	// Issues contains the detected issues (empty if the pattern is safe).
+	Issues []Issue
+
+	// Err is set if the pattern could not be validated, usually because
+	// it uses syntax that Go's regexp package does not support.
+	Err error
+}
+
+// ScanReader extracts regex patterns from source code and validates each one.
+// It returns a Finding for every pattern found, in source order.
+//
+// The language selects how patterns are recognized:
+//
+//   - "go": string literals passed to regexp.Compile, regexp.MustCompile
+//     and similar functions, found by tokenizing the source with go/scanner.
+//   - "python": string literals passed to re.compile, re.match and the
+//     other functions of the re module, including triple-quoted ones that
+//     span several lines.
+//   - "javascript" (or "js", "typescript", "ts"): regex literals and
+//     string literals passed to new RegExp.
+//   - "java": string literals passed to Pattern.compile and the String
+//     methods matches, replaceAll, replaceFirst and split.
+//
+// Go sources are read in full, since they are tokenized as a whole. Other
+// languages are read line by line with heuristics that look for the calls
+// above, so patterns split across lines or built at runtime are not found.
+//
+// The returned error is only set if reading fails or the language is not
+// supported; validation failures are reported in Finding.Err.
+//
+// Example:
+//
+//	f, err := os.Open("main.go")
+//	if err != nil {
+//	    return err
+//	}
+//	defer f.Close()
+//	findings, err := regret.ScanReader(f, "go", nil)
+//	for _, finding := range findings {
+//	    fmt.Printf("%d:%d: %s (%d issues)\n", finding.Line, finding.Column, finding.Pattern, len(finding.Issues))
+//	}
+func ScanReader(r io.Reader, language string, opts *Options) ([]Finding, error) {
+	var findings []Finding
+	var err error
+
+	switch strings.ToLower(language) {
+	case "go":
+		findings, err = scanGo(r)
+	case "python", "py":
+		findings, err = scanLines(r, pythonExtractors, pythonLongQuotes)
+	case "javascript", "js", "typescript", "ts":
+		findings, err = scanLines(r, javascriptExtractors, nil)
+	case "java":
+		findings, err = scanLines(r, javaExtractors, nil)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedLanguage, language)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	v := NewValidator(opts)
+	for i := range findings {
+		findings[i].Issues, findings[i].Err = v.Validate(findings[i].Pattern)
+	}
+
+	return findings, nil
+}
+
+// goRegexpFuncs are the functions of package regexp that take a pattern
+// as their first argument.
+var goRegexpFuncs = map[string]bool{
+	"Compile":          true,
+	"CompilePOSIX":     true,
+	"MustCompile":      true,
+	"MustCompilePOSIX": true,
+	"Match":            true,
+	"MatchReader":      true,
+	"MatchString":      true,
+}
+
+// scanGo finds string literals passed directly to the regexp package.
+func scanGo(r io.Reader) ([]Finding, error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+
+	var s scanner.Scanner
+	// Syntax errors are ignored: the scan is best effort
+	s.Init(file, src, nil, 0)
+
+	// Match the token sequence: regexp . Func ( "literal"
+	want := []token.Token{token.IDENT, token.PERIOD, token.IDENT, token.LPAREN, token.STRING}
+	matched := 0
+
+	var findings []Finding
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+
+		if tok != want[matched] ||
+			(matched == 0 && lit != "regexp") ||
+			(matched == 2 && !goRegexpFuncs[lit]) {
+			matched = 0
+			if tok == token.IDENT && lit == "regexp" {
+				matched = 1
+			}
+			continue
+		}
+
+		matched++
+		if matched < len(want) {
+			continue
+		}
+		matched = 0
+
+		pattern, err := strconv.Unquote(lit)
+		if err != nil {
+			continue
+		}
+		position := fset.Position(pos)
+		findings = append(findings, Finding{
+			Line:    position.Line,
+			Column:  position.Column,
+			Pattern: pattern,
+		})
+	}
+
+	return findings, nil
+}
+
+// extractor recognizes a pattern literal on a single line of source.
+// The first submatch of re is the literal; unquote turns it into a pattern.
+type extractor struct {
+	re      *regexp.Regexp
+	unquote func(literal string) (string, bool)
+}
+
+// Quoted string bodies, allowing escaped quotes
+const (
+	doubleQuoted = `"(?:\\.|[^"\\])*"`
+	singleQuoted = `'(?:\\.|[^'\\])*'`
+	backQuoted   = "`(?:\\\\.|[^`\\\\])*`"
+
+	// Python's triple-quoted strings, which may span lines
+	tripleDoubleQuoted = `"""(?:\\.|[^\\])*?"""`
+	tripleSingleQuoted = `'''(?:\\.|[^\\])*?'''`
+)
+
+// pythonLongQuotes delimit the Python string literals that may span lines.
+var pythonLongQuotes = []string{`"""`, `'''`}
+
+var pythonExtractors = []extractor{{
+	re:      regexp.MustCompile(`\bre\.(?:compile|match|fullmatch|search|findall|finditer|sub|subn|split)\(\s*((?:[rR]|[bB][rR]?|[rR][bB])?(?:` + tripleDoubleQuoted + `|` + tripleSingleQuoted + `|` + doubleQuoted + `|` + singleQuoted + `))`),
+	unquote: unquotePython,
+}}
+
+var javascriptExtractors = []extractor{{
+	re:      regexp.MustCompile(`\bnew\s+RegExp\(\s*(` + doubleQuoted + `|` + singleQuoted + `|` + backQuoted + `)`),
+	unquote: unquoteString,
+}, {
+	// A slash starts a regex literal where an expression is expected;
+	// elsewhere it is division. The first character rules out comments.
+	re:      regexp.MustCompile(`(?:^|[=(,:\[!&|?{};]|\breturn)\s*(/(?:\\.|\[(?:\\.|[^\]\\\n])*\]|[^/\\\n\[*])(?:\\.|\[(?:\\.|[^\]\\\n])*\]|[^/\\\n\[])*/[dgimsuvy]*)`),
+	unquote: unquoteRegexLiteral,
+}}
+
+var javaExtractors = []extractor{{
+	re:      regexp.MustCompile(`(?:\bPattern\.compile|\.matches|\.replaceAll|\.replaceFirst|\.split)\(\s*(` + doubleQuoted + `)`),
+	unquote: unquoteString,
+}}
+
+// scanLines applies the extractors to each line of the source. A line that
+// opens a string delimited by one of longQuotes without closing it is
+// joined with the lines that follow, up to the one that closes it, so that
+// the extractors see the whole literal.
+func scanLines(r io.Reader, extractors []extractor, longQuotes []string) ([]Finding, error) {
+	var findings []Finding
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		text := sc.Text()
+		first := line
+		for quote := openLongQuote(text, longQuotes); quote != "" && sc.Scan(); {
+			line++
+			next := sc.Text()
+			text += "\n" + next
+			if i := strings.Index(next, quote); i >= 0 {
+				quote = openLongQuote(next[i+len(quote):], longQuotes)
+			}
+		}
+
+		var found []Finding
+		for _, ex := range extractors {
+			for _, m := range ex.re.FindAllStringSubmatchIndex(text, -1) {
+				pattern, ok := ex.unquote(text[m[2]:m[3]])
+				if !ok {
+					continue
+				}
+				// Matches after a joined line count from its start
+				lineStart := strings.LastIndexByte(text[:m[2]], '\n') + 1
+				found = append(found, Finding{
+					Line:    first + strings.Count(text[:m[2]], "\n"),
+					Column:  m[2] - lineStart + 1,
+					Pattern: pattern,
+				})
+			}
+		}
+
+		// Keep source order when several extractors match one line
+		for i := 1; i < len(found); i++ {
+			for j := i; j > 0 && found[j].Column < found[j-1].Column; j-- {
+				found[j], found[j-1] = found[j-1], found[j]
+			}
+		}
+		findings = append(findings, found...)
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return findings, nil
+}
+
+// openLongQuote returns the delimiter of the string in longQuotes that line
+// leaves open, or "" if there is none. Short strings and # comments are
+// skipped, so that quotes inside them do not count.
+func openLongQuote(line string, longQuotes []string) string {
+	if len(longQuotes) == 0 {
+		return ""
+	}
+	for i := 0; i < len(line); i++ {
+		if quote := longQuoteAt(line[i:], longQuotes); quote != "" {
+			end := strings.Index(line[i+len(quote):], quote)
+			if end < 0 {
+				return quote
+			}
+			i += 2*len(quote) + end - 1
+			continue
+		}
+		switch c := line[i]; c {
+		case '#':
+			return ""
+		case '"', '\'':
+			// Skip the short string, allowing escaped quotes
+			for i++; i < len(line) && line[i] != c; i++ {
+				if line[i] == '\\' {
+					i++
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// longQuoteAt returns the delimiter in longQuotes that s starts with, or "".
+func longQuoteAt(s string, longQuotes []string) string {
+	for _, quote := range longQuotes {
+		if strings.HasPrefix(s, quote) {
+			return quote
+		}
+	}
+	return ""
+}
+
+// unquotePython handles raw and regular Python string literals, including
+// triple-quoted ones.
+func unquotePython(literal string) (string, bool) {
+	prefix := strings.ToLower(literal[:strings.IndexAny(literal, `"'`)])
+	quoteLen := 1
+	if longQuoteAt(literal[len(prefix):], pythonLongQuotes) != "" {
+		quoteLen = 3
+	}
+	body := literal[len(prefix)+quoteLen : len(literal)-quoteLen]
+	if strings.Contains(prefix, "r") {
+		return body, true
+	}
+	return unescape(body), true
+}
+
+// unquoteString handles quoted string literals in C-like languages.
+func unquoteString(literal string) (string, bool) {
+	if len(literal) < 2 {
+		return "", false
+	}
+	return unescape(literal[1 : len(literal)-1]), true
+}
+
+// unquoteRegexLiteral turns a JavaScript /pattern/flags literal into a
+// pattern, carrying over the flags Go understands.
+func unquoteRegexLiteral(literal string) (string, bool) {
+	end := strings.LastIndex(literal, "/")
+	if end <= 0 {
+		return "", false
+	}
+	pattern := strings.ReplaceAll(literal[1:end], `\/`, "/")
+
+	var flags string
+	for _, f := range literal[end+1:] {
+		if f == 'i' || f == 'm' || f == 's' {
+			flags += string(f)
+		}
+	}
+	if flags != "" {
+		pattern = "(?" + flags + ")" + pattern
+	}
+	return pattern, true
+}
+
+// unescape removes string escapes that are not meaningful to the regex
+// engine. Other escapes, such as \d, are kept as written.
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case '\\', '"', '\'', '`':
+			b.WriteByte(s[i])
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
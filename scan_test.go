@@ -0,0 +1,163 @@
+package regret
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestScanReader_Go(t *testing.T) {
+	src := `package main
+
+import "regexp"
+
+// regexp.MustCompile("(a+)+") in a comment is ignored
+var (
+	email = regexp.MustCompile("^[a-z]+@[a-z]+\\.com$")
+	evil  = regexp.MustCompile(` + "`(a+)+`" + `)
+)
+
+func f() bool {
+	ok, _ := regexp.MatchString("x*", "xx")
+	return ok && strings.Contains("regexp", "(a+)+")
+}
+`
+
+	findings, err := ScanReader(strings.NewReader(src), "go", nil)
+	if err != nil {
+		t.Fatalf("ScanReader() error = %v", err)
+	}
+
+	want := []struct {
+		line, column int
+		pattern      string
+		safe         bool
+	}{
+		{7, 29, `^[a-z]+@[a-z]+\.com$`, true},
+		{8, 29, `(a+)+`, false},
+		{12, 30, `x*`, true},
+	}
+	if len(findings) != len(want) {
+		t.Fatalf("ScanReader() found %d patterns, want %d: %+v", len(findings), len(want), findings)
+	}
+	for i, w := range want {
+		f := findings[i]
+		if f.Line != w.line || f.Column != w.column || f.Pattern != w.pattern {
+			t.Errorf("finding %d = %d:%d %q, want %d:%d %q", i, f.Line, f.Column, f.Pattern, w.line, w.column, w.pattern)
+		}
+		if safe := f.Err == nil && len(f.Issues) == 0; safe != w.safe {
+			t.Errorf("finding %d (%q) safe = %v, want %v", i, f.Pattern, safe, w.safe)
+		}
+	}
+}
+
+func TestScanReader_Lines(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		src      string
+		patterns []string
+		column   int
+	}{
+		{"python raw", "python", `EVIL = re.compile(r"(a+)+$")`, []string{`(a+)+$`}, 19},
+		{"python escaped", "python", `re.match('\\d+\\.\\d+', s)`, []string{`\d+\.\d+`}, 10},
+		{"javascript literal", "javascript", `const evil = /(a+)+$/i;`, []string{`(?i)(a+)+$`}, 14},
+		{"javascript RegExp", "js", `const re = new RegExp("\\w+@\\w+");`, []string{`\w+@\w+`}, 23},
+		{"javascript division", "javascript", `const half = total / 2 / count;`, nil, 0},
+		{"javascript comment", "javascript", `// (a+)+ is dangerous`, nil, 0},
+		{"java", "java", `Pattern p = Pattern.compile("(a|a)*\\d");`, []string{`(a|a)*\d`}, 29},
+		{"java string method", "java", `if (s.matches("[a-z]+")) {`, []string{`[a-z]+`}, 15},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := ScanReader(strings.NewReader(tt.src), tt.language, nil)
+			if err != nil {
+				t.Fatalf("ScanReader() error = %v", err)
+			}
+			if len(findings) != len(tt.patterns) {
+				t.Fatalf("ScanReader() found %+v, want %q", findings, tt.patterns)
+			}
+			for i, pattern := range tt.patterns {
+				if findings[i].Pattern != pattern {
+					t.Errorf("Pattern = %q, want %q", findings[i].Pattern, pattern)
+				}
+				if findings[i].Line != 1 || findings[i].Column != tt.column {
+					t.Errorf("position = %d:%d, want 1:%d", findings[i].Line, findings[i].Column, tt.column)
+				}
+			}
+		})
+	}
+}
+
+func TestScanReader_LineNumbers(t *testing.T) {
+	src := "import re\n\nSAFE = re.compile(r'^[a-z]+$')\nEVIL = re.compile(r'(a+)+')\n"
+
+	findings, err := ScanReader(strings.NewReader(src), "python", nil)
+	if err != nil {
+		t.Fatalf("ScanReader() error = %v", err)
+	}
+	if len(findings) != 2 {
+		t.Fatalf("ScanReader() found %d patterns, want 2", len(findings))
+	}
+	if findings[0].Line != 3 || len(findings[0].Issues) != 0 {
+		t.Errorf("first finding = %+v, want safe pattern on line 3", findings[0])
+	}
+	if findings[1].Line != 4 || len(findings[1].Issues) == 0 {
+		t.Errorf("second finding = %+v, want unsafe pattern on line 4", findings[1])
+	}
+}
+
+func TestScanReader_PythonTripleQuoted(t *testing.T) {
+	src := `import re
+ONE = re.compile(r"""(a+)+$""")
+TWO = re.compile(r'''^(
+[a-z]+
+)$''', re.X); THREE = re.match('b+', s)
+FOUR = re.search(r'(x+x+)+y', s)  # a ''' in a comment
+FIVE = re.compile("'''")
+`
+
+	findings, err := ScanReader(strings.NewReader(src), "python", nil)
+	if err != nil {
+		t.Fatalf("ScanReader() error = %v", err)
+	}
+
+	want := []struct {
+		line, column int
+		pattern      string
+	}{
+		{2, 18, `(a+)+$`},
+		{3, 18, "^(\n[a-z]+\n)$"},
+		{5, 32, "b+"},
+		{6, 18, "(x+x+)+y"},
+		{7, 19, "'''"},
+	}
+	if len(findings) != len(want) {
+		t.Fatalf("ScanReader() found %+v, want %d patterns", findings, len(want))
+	}
+	for i, w := range want {
+		f := findings[i]
+		if f.Line != w.line || f.Column != w.column || f.Pattern != w.pattern {
+			t.Errorf("finding %d = %d:%d %q, want %d:%d %q", i, f.Line, f.Column, f.Pattern, w.line, w.column, w.pattern)
+		}
+	}
+}
+
+func TestScanReader_InvalidPattern(t *testing.T) {
+	// Lookahead is valid JavaScript but not supported by Go
+	findings, err := ScanReader(strings.NewReader(`const re = /a(?=b)/;`), "javascript", nil)
+	if err != nil {
+		t.Fatalf("ScanReader() error = %v", err)
+	}
+	if len(findings) != 1 || findings[0].Err == nil {
+		t.Errorf("ScanReader() = %+v, want one finding with an error", findings)
+	}
+}
+
+func TestScanReader_UnsupportedLanguage(t *testing.T) {
+	_, err := ScanReader(strings.NewReader(""), "cobol", nil)
+	if !errors.Is(err, ErrUnsupportedLanguage) {
+		t.Errorf("ScanReader() error = %v, want ErrUnsupportedLanguage", err)
+	}
+}
@@ -0,0 +1,126 @@
+package regret
+
+import (
+	"errors"
+	"fmt"
+	"regexp/syntax"
+
+	"github.com/theakshaypant/regret/internal/parser"
+	"github.com/theakshaypant/regret/internal/rewrite"
+)
+
+// ErrNoFix indicates that no automatic rewrite could make the pattern safe.
+var ErrNoFix = errors.New("no automatic fix available")
+
+// Suggest rewrites an unsafe regex pattern into a safer equivalent.
+// Uses default options to verify that the rewritten pattern is safe.
+//
+// The rewrite rules preserve the set of matched strings:
+//   - Nested quantifiers collapse into one: (a+)+ becomes a+, (a*)+ becomes a*
+//   - Adjacent quantifiers on the same expression merge: a*a+ becomes a+
+//
+// Capture groups around collapsed quantifiers are removed, so submatch
+// indices may change. Safe patterns are returned unchanged.
+// Returns ErrNoFix if the rules cannot produce a safe pattern, and for
+// unsafe patterns with backreferences, which the rules do not preserve.
+//
+// Example:
+//
+//	fixed, err := regret.Suggest("(a+)+")
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Println(fixed) // a+
+func Suggest(pattern string) (string, error) {
+	return SuggestWithOptions(pattern, DefaultOptions())
+}
+
+// SuggestWithOptions is like Suggest but verifies the rewritten pattern
+// with custom configuration options.
+func SuggestWithOptions(pattern string, opts *Options) (string, error) {
+	if opts == nil {
+		opts = DefaultOptions()
+	}
+
+	issues, err := ValidateWithOptions(pattern, opts)
+	if err != nil {
+		return "", err
+	}
+	if len(issues) == 0 {
+		return pattern, nil
+	}
+
+	expanded := expandBackreferences(pattern, opts)
+	re, err := parser.NewParserWithFlags(parseFlags(opts)).Parse(expanded)
+	if err != nil {
+		return "", err
+	}
+	if expanded != pattern {
+		return "", fmt.Errorf("%w: patterns with backreferences are not rewritten", ErrNoFix)
+	}
+
+	result := rewrite.Rewrite(re)
+	if !result.Changed() {
+		return "", ErrNoFix
+	}
+
+	fixed := result.Regexp.String()
+	issues, err = ValidateWithOptions(fixed, opts)
+	if err != nil {
+		return "", fmt.Errorf("%w: rewritten pattern %q is invalid: %v", ErrNoFix, fixed, err)
+	}
+	if len(issues) > 0 {
+		return "", fmt.Errorf("%w: rewritten pattern %q is still unsafe", ErrNoFix, fixed)
+	}
+
+	return fixed, nil
+}
+
+// Annotations of ComplexityScore.AlternativeSuggestions that change the
+// strings a pattern matches.
+const (
+	supersetAnnotation = "[SUPERSET] "
+	subsetAnnotation   = "[SUBSET] "
+)
+
+// alternatives returns rewrites of re whose complexity score is below
+// score: the rewrite of Suggest, which keeps the matched strings, or if
+// that does not help, rewrites that widen or narrow them, annotated as such.
+func (a *anlz) alternatives(re *syntax.Regexp, score int) []string {
+	ctx, cancel := analysisContext(a.opts)
+	defer cancel()
+
+	var suggestions []string
+	seen := make(map[string]bool)
+	for _, c := range []struct {
+		result     *rewrite.Result
+		annotation string
+	}{
+		{rewrite.Rewrite(re), ""},
+		{rewrite.Widen(re), supersetAnnotation},
+		{rewrite.Narrow(re), subsetAnnotation},
+	} {
+		if !c.result.Changed() {
+			continue
+		}
+		alt := c.result.Regexp.String()
+		if seen[alt] {
+			continue
+		}
+		seen[alt] = true
+
+		altRe, err := a.parser.Parse(alt)
+		if err != nil {
+			continue
+		}
+		altScore, err := a.impl.AnalyzeContext(ctx, altRe, alt)
+		if err != nil || altScore.Score >= score {
+			continue
+		}
+		suggestions = append(suggestions, c.annotation+alt)
+		if c.annotation == "" {
+			break
+		}
+	}
+	return suggestions
+}
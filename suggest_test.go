@@ -0,0 +1,83 @@
+package regret
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		want    string
+		wantErr error
+	}{
+		{"nested plus", "(a+)+", "a+", nil},
+		{"nested star", "(a*)*", "a*", nil},
+		{"nested class", "([a-z]+)*", "[a-z]*", nil},
+		{"overlapping quantifiers", "a*a+", "a+", nil},
+		{"safe pattern unchanged", "^[a-z]+$", "^[a-z]+$", nil},
+		{"no rule applies", "a*b*", "", ErrNoFix},
+		{"safe backreference unchanged", `^(ab)\1$`, `^(ab)\1$`, nil},
+		{"unsafe backreference", `(a+)+\1`, "", ErrNoFix},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Suggest(tt.pattern)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Suggest(%q) error = %v, want %v", tt.pattern, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Suggest(%q) error = %v", tt.pattern, err)
+			}
+			if got != tt.want {
+				t.Errorf("Suggest(%q) = %q, want %q", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggest_InvalidPattern(t *testing.T) {
+	if _, err := Suggest("(a+"); err == nil {
+		t.Error("Suggest() expected error for invalid pattern")
+	}
+}
+
+func TestAnalyzeComplexity_AlternativeSuggestions(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"(a+)+", []string{"a+"}},
+		{"(a+b)+", []string{"[SUPERSET] [ab]+", "[SUBSET] (a+b)"}},
+		{"^[a-z]+$", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			score, err := AnalyzeComplexityWithOptions(tt.pattern, ThoroughOptions())
+			if err != nil {
+				t.Fatalf("AnalyzeComplexityWithOptions() error = %v", err)
+			}
+			if !reflect.DeepEqual(score.AlternativeSuggestions, tt.want) {
+				t.Errorf("AlternativeSuggestions = %q, want %q", score.AlternativeSuggestions, tt.want)
+			}
+			for _, alt := range score.AlternativeSuggestions {
+				alt = strings.TrimPrefix(strings.TrimPrefix(alt, supersetAnnotation), subsetAnnotation)
+				altScore, err := AnalyzeComplexityWithOptions(alt, ThoroughOptions())
+				if err != nil {
+					t.Fatalf("AnalyzeComplexityWithOptions(%q) error = %v", alt, err)
+				}
+				if altScore.Overall >= score.Overall {
+					t.Errorf("suggestion %q scores %d, not below %d", alt, altScore.Overall, score.Overall)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package regret
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// issueTemplates holds the message templates set with SetIssueTemplate,
+// keyed by issue type.
+var issueTemplates sync.Map
+
+// SetIssueTemplate replaces the message of every issue of type t with the
+// output of a text/template, for example to translate messages or to adopt
+// the wording of a security policy. The template is executed with the
+// Issue, so it can use fields such as {{.Pattern}}, {{.Severity}},
+// {{.Complexity}} and {{.Position.Start}}; {{.Message}} is the built-in
+// message. Setting a template for a type again replaces the previous one.
+// It is safe to call from multiple goroutines.
+//
+// Templates apply to the issues returned by Validate and the functions
+// built on it, after Options.SeverityOverride. If a template fails to
+// execute, the built-in message is kept.
+//
+// Example:
+//
+//	err := regret.SetIssueTemplate(regret.NestedQuantifiers,
+//	    "Cuantificadores anidados en {{.Pattern}} (posición {{.Position.Start}})")
+func SetIssueTemplate(t IssueType, tmpl string) error {
+	parsed, err := template.New(t.String()).Parse(tmpl)
+	if err != nil {
+		return fmt.Errorf("regret: issue template for %v: %w", t, err)
+	}
+	issueTemplates.Store(t, parsed)
+	return nil
+}
+
+// ResetIssueTemplates removes every template set with SetIssueTemplate,
+// restoring the built-in messages.
+func ResetIssueTemplates() {
+	issueTemplates.Range(func(key, _ interface{}) bool {
+		issueTemplates.Delete(key)
+		return true
+	})
+}
+
+// applyIssueTemplates rewrites the message of each issue whose type has a
+// template.
+func applyIssueTemplates(issues []Issue) []Issue {
+	for i := range issues {
+		v, ok := issueTemplates.Load(issues[i].Type)
+		if !ok {
+			continue
+		}
+		var b strings.Builder
+		if err := v.(*template.Template).Execute(&b, issues[i]); err == nil {
+			issues[i].Message = b.String()
+		}
+	}
+	return issues
+}
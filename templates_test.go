@@ -0,0 +1,67 @@
+package regret
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSetIssueTemplate(t *testing.T) {
+	defer ResetIssueTemplates()
+
+	if err := SetIssueTemplate(NestedQuantifiers, "{{.Severity}} anidado en {{.Pattern}} @{{.Position.Start}} ({{.Complexity}})"); err != nil {
+		t.Fatalf("SetIssueTemplate() error = %v", err)
+	}
+
+	issues, err := Validate("x(a+)+")
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	var nested *Issue
+	for i := range issues {
+		if issues[i].Type == NestedQuantifiers {
+			nested = &issues[i]
+		}
+	}
+	if nested == nil {
+		t.Fatalf("expected a nested quantifiers issue, got %v", issues)
+	}
+	want := fmt.Sprintf("critical anidado en (a+)+ @1 (%d)", nested.Complexity)
+	if nested.Message != want {
+		t.Errorf("Message = %q, want %q", nested.Message, want)
+	}
+	for _, issue := range issues {
+		if issue.Type != NestedQuantifiers && issue.Message == want {
+			t.Errorf("template applied to %v issue", issue.Type)
+		}
+	}
+
+	ResetIssueTemplates()
+	issues, _ = Validate("x(a+)+")
+	for _, issue := range issues {
+		if issue.Type == NestedQuantifiers && issue.Message == want {
+			t.Error("template still applied after ResetIssueTemplates")
+		}
+	}
+}
+
+func TestSetIssueTemplate_Invalid(t *testing.T) {
+	defer ResetIssueTemplates()
+
+	if err := SetIssueTemplate(NestedQuantifiers, "{{.Pattern"); err == nil {
+		t.Error("SetIssueTemplate() with a malformed template returned no error")
+	}
+
+	// Templates that fail to execute keep the built-in message
+	if err := SetIssueTemplate(NestedQuantifiers, "{{.NoSuchField}}"); err != nil {
+		t.Fatalf("SetIssueTemplate() error = %v", err)
+	}
+	issues, err := Validate("(a+)+")
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	for _, issue := range issues {
+		if issue.Message == "" {
+			t.Errorf("%v issue has an empty message", issue.Type)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package regret
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/theakshaypant/regret/internal/parser"
+)
+
+// Limits on the inputs GenerateTestCases produces.
+const (
+	maxMatchingTestCases = 10
+	maxTestCaseLength    = 32
+	maxOutsideRunes      = 3
+)
+
+// adversarialPumpSizes are the pump counts of the adversarial inputs
+// GenerateTestCases produces.
+var adversarialPumpSizes = []int{10, 20, 50}
+
+// TestCase is an input for property-based or table-driven tests of code
+// that uses a regex pattern.
+type TestCase struct {
+	// Input is the string to match against the pattern.
+	Input string
+
+	// ShouldMatch reports whether the pattern matches Input, as decided by
+	// regexp.MatchString.
+	ShouldMatch bool
+
+	// IsAdversarial marks inputs built to trigger catastrophic
+	// backtracking in engines that backtrack. Matching them with Go's
+	// regexp is safe.
+	IsAdversarial bool
+}
+
+// GenerateTestCases returns inputs that exercise a pattern: inputs it
+// matches, inputs it does not match, and adversarial inputs that trigger
+// its worst-case behavior in backtracking engines. Adversarial inputs are
+// only generated for patterns that Validate reports issues for.
+//
+// Matching inputs are found by a breadth-first walk of the pattern's NFA,
+// shortest first. Non-matching inputs are built from characters outside
+// the pattern's character classes, and adversarial inputs come from the
+// pump generator, repeated 10, 20 and 50 times. Every input is checked
+// against Go's regexp package, so ShouldMatch is always correct; inputs
+// whose outcome differs from what they were built for are dropped.
+//
+// Example:
+//
+//	cases, err := regret.GenerateTestCases(`^\d{3}-\d{4}$`)
+//	if err != nil {
+//	    return err
+//	}
+//	for _, tc := range cases {
+//	    if isPhoneNumber(tc.Input) != tc.ShouldMatch {
+//	        t.Errorf("isPhoneNumber(%q) = %v", tc.Input, !tc.ShouldMatch)
+//	    }
+//	}
+func GenerateTestCases(pattern string) ([]TestCase, error) {
+	opts := DefaultOptions()
+	matcher, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPattern, err)
+	}
+	re, err := parser.NewParser().Parse(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var cases []TestCase
+	seen := make(map[string]bool)
+	add := func(input string, shouldMatch, adversarial bool) {
+		if seen[input] || matcher.MatchString(input) != shouldMatch {
+			return
+		}
+		seen[input] = true
+		cases = append(cases, TestCase{Input: input, ShouldMatch: shouldMatch, IsAdversarial: adversarial})
+	}
+
+	// Matching inputs from paths through the NFA
+	if nfa, err := parser.BuildNFAWithLimit(re, opts.MaxNFAStates); err == nil {
+		for _, input := range nfa.AcceptedInputs(maxMatchingTestCases, maxTestCaseLength) {
+			add(input, true, false)
+		}
+	}
+
+	// Safe non-matching inputs from characters the pattern never matches
+	add("", false, false)
+	for _, r := range outsideRunes(parser.Charset(re), maxOutsideRunes) {
+		add(string(r), false, false)
+		add(strings.Repeat(string(r), maxTestCaseLength), false, false)
+	}
+
+	// Adversarial non-matching inputs from the pump generator, for
+	// patterns that can backtrack
+	issues, err := ValidateWithOptions(pattern, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(issues) == 0 {
+		return cases, nil
+	}
+	// The pump sizes are adversarialPumpSizes rather than the suggested ones
+	if pump, err := newPumpGenerator(opts).generate(pattern, false); err == nil {
+		for _, n := range adversarialPumpSizes {
+			add(pump.Generate(n), false, true)
+		}
+	}
+
+	return cases, nil
+}
+
+// outsideRunes returns up to n printable ASCII characters that are not in
+// charset, preferring letters and digits.
+func outsideRunes(charset []parser.RuneRange, n int) []rune {
+	in := func(r rune) bool {
+		for _, rr := range charset {
+			if rr.Lo <= r && r <= rr.Hi {
+				return true
+			}
+		}
+		return false
+	}
+
+	var runes []rune
+	for _, want := range []parser.RuneRange{{Lo: 'a', Hi: 'z'}, {Lo: '0', Hi: '9'}, {Lo: 'A', Hi: 'Z'}, {Lo: '!', Hi: '~'}} {
+		for r := want.Lo; r <= want.Hi && len(runes) < n; r++ {
+			if !in(r) && !containsRune(runes, r) {
+				runes = append(runes, r)
+			}
+		}
+	}
+	return runes
+}
+
+func containsRune(runes []rune, r rune) bool {
+	for _, x := range runes {
+		if x == r {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package regret
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+)
+
+func TestGenerateTestCases(t *testing.T) {
+	patterns := []string{`(a+)+b`, `^\d{3}-\d{4}$`, `[a-z]+@[a-z]+\.com`, `a*`, `(x|y)z`}
+
+	for _, pattern := range patterns {
+		t.Run(pattern, func(t *testing.T) {
+			cases, err := GenerateTestCases(pattern)
+			if err != nil {
+				t.Fatalf("GenerateTestCases() error = %v", err)
+			}
+
+			re := regexp.MustCompile(pattern)
+			matching := 0
+			seen := make(map[string]bool)
+			for _, tc := range cases {
+				if seen[tc.Input] {
+					t.Errorf("duplicate input %q", tc.Input)
+				}
+				seen[tc.Input] = true
+				if got := re.MatchString(tc.Input); got != tc.ShouldMatch {
+					t.Errorf("MatchString(%q) = %v, ShouldMatch = %v", tc.Input, got, tc.ShouldMatch)
+				}
+				if tc.IsAdversarial && tc.ShouldMatch {
+					t.Errorf("adversarial input %q matches", tc.Input)
+				}
+				if tc.ShouldMatch {
+					matching++
+				}
+			}
+			if matching == 0 {
+				t.Error("no matching inputs generated")
+			}
+		})
+	}
+}
+
+func TestGenerateTestCases_Adversarial(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    bool
+	}{
+		{`(a+)+b`, true},
+		{`^\d{3}-\d{4}$`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			cases, err := GenerateTestCases(tt.pattern)
+			if err != nil {
+				t.Fatalf("GenerateTestCases() error = %v", err)
+			}
+			got := false
+			for _, tc := range cases {
+				got = got || tc.IsAdversarial
+			}
+			if got != tt.want {
+				t.Errorf("adversarial inputs generated = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateTestCases_NonMatching(t *testing.T) {
+	cases, err := GenerateTestCases(`^[a-z]+$`)
+	if err != nil {
+		t.Fatalf("GenerateTestCases() error = %v", err)
+	}
+	for _, tc := range cases {
+		if !tc.ShouldMatch && !tc.IsAdversarial {
+			return
+		}
+	}
+	t.Error("no safe non-matching inputs generated")
+}
+
+func TestGenerateTestCases_InvalidPattern(t *testing.T) {
+	_, err := GenerateTestCases("(abc")
+	if !errors.Is(err, ErrInvalidPattern) {
+		t.Errorf("GenerateTestCases() error = %v, want ErrInvalidPattern", err)
+	}
+}
@@ -0,0 +1,69 @@
+// Package testutil provides assertions for testing regex safety in unit
+// tests, such as checking that a validation function rejects unsafe
+// patterns or that the patterns of a package stay safe:
+//
+//	func TestPatterns(t *testing.T) {
+//	    testutil.AssertSafe(t, emailPattern)
+//	    testutil.AssertUnsafe(t, `(a+)+`)
+//	    testutil.AssertComplexityBelow(t, emailPattern, 40)
+//	    testutil.AssertNoIssuesAbove(t, emailPattern, regret.Low)
+//	}
+//
+// The assertions report failures with t.Errorf, so a test goes on after a
+// failed assertion, and mark themselves as helpers so that failures point
+// to the caller's line.
+package testutil
+
+import (
+	"testing"
+
+	"github.com/theakshaypant/regret"
+)
+
+// AssertSafe fails the test if regret.IsSafe reports pattern as unsafe,
+// giving the reason from regret.CheckPattern.
+func AssertSafe(t testing.TB, pattern string) {
+	t.Helper()
+	if err := regret.CheckPattern(pattern); err != nil {
+		t.Errorf("pattern %q is not safe: %v", pattern, err)
+	}
+}
+
+// AssertUnsafe fails the test if regret.IsSafe reports pattern as safe.
+func AssertUnsafe(t testing.TB, pattern string) {
+	t.Helper()
+	if regret.IsSafe(pattern) {
+		t.Errorf("pattern %q is safe, want unsafe", pattern)
+	}
+}
+
+// AssertComplexityBelow fails the test unless the overall complexity score
+// of pattern, as computed by regret.AnalyzeComplexity, is below maxScore.
+func AssertComplexityBelow(t testing.TB, pattern string, maxScore int) {
+	t.Helper()
+	score, err := regret.AnalyzeComplexity(pattern)
+	if err != nil {
+		t.Errorf("pattern %q: complexity analysis failed: %v", pattern, err)
+		return
+	}
+	if score.Overall >= maxScore {
+		t.Errorf("pattern %q has complexity score %d, want below %d", pattern, score.Overall, maxScore)
+	}
+}
+
+// AssertNoIssuesAbove fails the test if regret.Validate finds an issue in
+// pattern more severe than maxSeverity, listing every such issue. For
+// example, maxSeverity regret.Low allows Low and Info issues only.
+func AssertNoIssuesAbove(t testing.TB, pattern string, maxSeverity regret.Severity) {
+	t.Helper()
+	issues, err := regret.Validate(pattern)
+	if err != nil {
+		t.Errorf("pattern %q: validation failed: %v", pattern, err)
+		return
+	}
+	for _, issue := range issues {
+		if issue.Severity < maxSeverity {
+			t.Errorf("pattern %q has a %s issue, want at most %s: %s", pattern, issue.Severity, maxSeverity, issue.Message)
+		}
+	}
+}
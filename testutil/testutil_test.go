@@ -0,0 +1,49 @@
+package testutil
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/theakshaypant/regret"
+)
+
+// recorder records the failures of an assertion instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertions(t *testing.T) {
+	tests := []struct {
+		name   string
+		assert func(t testing.TB)
+		fail   bool
+	}{
+		{"safe pattern is safe", func(t testing.TB) { AssertSafe(t, `^[a-z]+$`) }, false},
+		{"unsafe pattern is safe", func(t testing.TB) { AssertSafe(t, `(a+)+`) }, true},
+		{"unsafe pattern is unsafe", func(t testing.TB) { AssertUnsafe(t, `(a+)+`) }, false},
+		{"safe pattern is unsafe", func(t testing.TB) { AssertUnsafe(t, `^[a-z]+$`) }, true},
+		{"simple pattern below", func(t testing.TB) { AssertComplexityBelow(t, `^abc$`, 50) }, false},
+		{"nested quantifier below", func(t testing.TB) { AssertComplexityBelow(t, `(a+)+`, 50) }, true},
+		{"invalid pattern below", func(t testing.TB) { AssertComplexityBelow(t, `(a+`, 50) }, true},
+		{"no issues above low", func(t testing.TB) { AssertNoIssuesAbove(t, `^[a-z]+$`, regret.Low) }, false},
+		{"critical issue above low", func(t testing.TB) { AssertNoIssuesAbove(t, `(a+)+`, regret.Low) }, true},
+		{"critical issue above critical", func(t testing.TB) { AssertNoIssuesAbove(t, `(a+)+`, regret.Critical) }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{TB: t}
+			tt.assert(r)
+			if failed := len(r.errors) > 0; failed != tt.fail {
+				t.Errorf("assertion failed = %v (%q), want %v", failed, r.errors, tt.fail)
+			}
+		})
+	}
+}
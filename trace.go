@@ -0,0 +1,72 @@
+package regret
+
+// DetectionTrace is a log of the decisions the built-in checks made while
+// analyzing a pattern, for understanding why a pattern was or was not
+// flagged.
+type DetectionTrace struct {
+	Pattern string       `json:"pattern"`
+	Mode    string       `json:"mode"`
+	Events  []TraceEvent `json:"events"`
+}
+
+// TraceEvent is one decision of the analysis.
+type TraceEvent struct {
+	// Kind is "phase" when a group of checks starts, "check" when a check
+	// runs or is skipped, "visit" when a check visits an AST node,
+	// "threshold" when a value is compared with a limit and "issue" when
+	// an issue is reported.
+	Kind string `json:"kind"`
+
+	// Check is the check the event belongs to, empty for phases.
+	Check string `json:"check,omitempty"`
+
+	// Node is the visited node or the pattern of the issue, if any.
+	Node string `json:"node,omitempty"`
+
+	// Message describes the decision.
+	Message string `json:"message"`
+}
+
+// TraceDetection runs the built-in checks on pattern and returns a trace
+// of the analysis: each check that ran or was skipped, the AST nodes it
+// visited, the thresholds it compared and the issues it reported. If opts
+// is nil, DefaultOptions() is used.
+//
+// The trace covers the detector only: deny lists, allowed unsafe patterns,
+// plugins and severity overrides are not applied, and issues appear with
+// the severities the checks report. Tracing is slow and meant for
+// debugging false positives; `regret analyze --trace` prints it as JSON.
+//
+// Example:
+//
+//	trace, err := regret.TraceDetection(`\d+\.\d+`, nil)
+//	if err != nil {
+//	    return err
+//	}
+//	for _, event := range trace.Events {
+//	    if event.Kind == "threshold" || event.Kind == "issue" {
+//	        fmt.Printf("%s: %s\n", event.Check, event.Message)
+//	    }
+//	}
+func TraceDetection(pattern string, opts *Options) (*DetectionTrace, error) {
+	if opts == nil {
+		opts = DefaultOptions()
+	}
+
+	impl := newValidator(opts)
+	re, err := impl.parser.Parse(expandBackreferences(pattern, opts))
+	if err != nil {
+		return nil, err
+	}
+
+	_, trace, err := impl.detect.DetectWithTrace(re, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]TraceEvent, len(trace.Events))
+	for i, event := range trace.Events {
+		events[i] = TraceEvent(event)
+	}
+	return &DetectionTrace{Pattern: pattern, Mode: opts.Mode.String(), Events: events}, nil
+}
@@ -0,0 +1,39 @@
+package regret
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestTraceDetection(t *testing.T) {
+	trace, err := TraceDetection(`(a+)+`, nil)
+	if err != nil {
+		t.Fatalf("TraceDetection() error = %v", err)
+	}
+	if trace.Pattern != `(a+)+` || trace.Mode != "balanced" {
+		t.Errorf("TraceDetection() = %s/%s, want (a+)+/balanced", trace.Pattern, trace.Mode)
+	}
+
+	found := false
+	for _, event := range trace.Events {
+		if event.Kind == "issue" && strings.Contains(event.Message, "nested_quantifiers") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("trace does not record the nested quantifier issue: %v", trace.Events)
+	}
+
+	data, err := json.Marshal(trace)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"kind":"threshold"`) {
+		t.Errorf("JSON trace = %s, want threshold events", data)
+	}
+
+	if _, err := TraceDetection(`(a+`, nil); err == nil {
+		t.Error("TraceDetection() expected error for invalid pattern")
+	}
+}
@@ -6,7 +6,13 @@
 // backtracking vulnerabilities.
 package regret
 
-import "time"
+import (
+	"fmt"
+	"regexp/syntax"
+	"slices"
+	"strings"
+	"time"
+)
 
 // ValidationMode controls the depth of analysis performed.
 type ValidationMode int
@@ -39,6 +45,88 @@ func (v ValidationMode) String() string {
 	}
 }
 
+// TimeoutBehavior controls what happens when analysis exceeds Options.Timeout.
+type TimeoutBehavior int
+
+const (
+	// TimeoutError discards the results and returns ErrTimeout.
+	TimeoutError TimeoutBehavior = iota
+
+	// TimeoutReturnPartial returns the issues found before the timeout,
+	// followed by an Info issue noting that the results are partial.
+	TimeoutReturnPartial
+
+	// TimeoutMarkUnsafe returns a single Critical issue reporting the
+	// timeout, so the pattern is treated as unsafe.
+	TimeoutMarkUnsafe
+)
+
+// String returns the string representation of the timeout behavior.
+func (b TimeoutBehavior) String() string {
+	switch b {
+	case TimeoutError:
+		return "error"
+	case TimeoutReturnPartial:
+		return "return_partial"
+	case TimeoutMarkUnsafe:
+		return "mark_unsafe"
+	default:
+		return "unknown"
+	}
+}
+
+// Dialect identifies the regex engine a pattern is written for.
+type Dialect int
+
+const (
+	// DialectPCRE is a Perl-compatible backtracking engine, as used by PHP
+	// and assumed by default.
+	DialectPCRE Dialect = iota
+
+	// DialectRE2 is RE2 or Go's regexp package, which match in linear time.
+	DialectRE2
+
+	// DialectJava is java.util.regex, a backtracking engine.
+	DialectJava
+
+	// DialectPython is Python's re module, a backtracking engine.
+	DialectPython
+
+	// DialectJavaScript is the ECMAScript RegExp engine, backtracking in
+	// all major implementations.
+	DialectJavaScript
+
+	// DialectRuby is Ruby's Onigmo engine, a backtracking engine.
+	DialectRuby
+)
+
+// String returns the string representation of the dialect.
+func (d Dialect) String() string {
+	switch d {
+	case DialectPCRE:
+		return "pcre"
+	case DialectRE2:
+		return "re2"
+	case DialectJava:
+		return "java"
+	case DialectPython:
+		return "python"
+	case DialectJavaScript:
+		return "javascript"
+	case DialectRuby:
+		return "ruby"
+	default:
+		return "unknown"
+	}
+}
+
+// Backtracking reports whether the engine matches by backtracking, which
+// makes it vulnerable to the exponential and polynomial blowups regret
+// detects.
+func (d Dialect) Backtracking() bool {
+	return d != DialectRE2
+}
+
 // CheckFlags is a bitmask of checks to perform during validation.
 type CheckFlags uint32
 
@@ -61,18 +149,40 @@ const (
 	// CheckComplexityScore calculates and validates complexity scores.
 	CheckComplexityScore
 
-	// CheckMemoryUsage estimates memory usage for pattern matching.
+	// CheckMemoryUsage estimates memory usage for pattern matching from
+	// the number of DFA states the pattern needs. See Options.MaxDFAStates.
 	CheckMemoryUsage
 
 	// CheckNFAAmbiguity performs NFA analysis to detect EDA and IDA.
 	CheckNFAAmbiguity
 
-	// CheckPolynomialDegree detects and calculates polynomial backtracking degree.
+	// CheckPolynomialDegree detects and calculates polynomial backtracking
+	// degree: a run of adjacent unbounded quantifiers with overlapping
+	// character classes, like \d*\w*, backtracks in O(n^d) for a run of d
+	// quantifiers. The degree is reported in Details["degree"]. When the
+	// NFA analysis reports the same polynomial ambiguity, the degree is
+	// merged into its issue, with the run length in
+	// Details["adjacent_quantifiers"], instead of being reported twice.
+	// Only checked in Balanced and Thorough mode.
 	CheckPolynomialDegree
 
 	// CheckContextAwareness analyzes pattern context and ordering for safety.
+	// Critical issues are downgraded to High when what follows the dangerous
+	// sub-pattern makes exploitation harder, such as the .* in (a+)+.*,
+	// which always matches, and are kept when the sub-pattern is nested in
+	// another quantifier. Issue.Details["context"] records the context.
 	CheckContextAwareness
 
+	// CheckCustomPlugins runs checks registered with RegisterPlugin.
+	CheckCustomPlugins
+
+	// CheckUnicodeAmbiguity detects literals and character classes like
+	// [àáâ]+ that match characters with canonical decompositions. They only
+	// match input in NFC: the same text in NFD spells à as a followed by a
+	// combining grave accent, which does not match. Reported as Low
+	// UnicodeAmbiguity issues. It is not part of CheckDefault.
+	CheckUnicodeAmbiguity
+
 	// CheckAll enables all available checks.
 	CheckAll CheckFlags = ^CheckFlags(0)
 
@@ -90,12 +200,48 @@ type Options struct {
 	Mode ValidationMode
 
 	// Timeout sets the maximum time for analysis.
-	// Analysis will return partial results if timeout is exceeded.
-	// Default: 100ms for Balanced, 1s for Thorough
+	// TimeoutBehavior controls what happens when it is exceeded.
+	// Default: 100ms for Balanced, 1s for Thorough, set to 0 for no limit
 	Timeout time.Duration
 
-	// Checks specifies which checks to perform (bitmask).
-	// Default: CheckDefault
+	// TimeoutBehavior controls what happens when analysis exceeds Timeout.
+	// Default: TimeoutReturnPartial
+	TimeoutBehavior TimeoutBehavior
+
+	// Dialect is the regex engine the pattern is written for, so that
+	// checks depending on engine features can take it into account.
+	// Analysis always assumes the worst case of a backtracking engine.
+	// Patterns for dialects other than DialectRE2 may use backreferences
+	// like \1, which are analyzed as copies of the group they refer to.
+	// Default: DialectPCRE
+	Dialect Dialect
+
+	// ParseFlags are the regexp/syntax flags the pattern is parsed with.
+	// They should match how the pattern will be compiled, since they change
+	// its danger: syntax.FoldCase widens every character, so branches and
+	// quantifiers that were disjoint may overlap, and syntax.ClassNL lets
+	// negated classes match newlines. syntax.POSIX is 0, so combine it
+	// with another flag, such as syntax.OneLine, to parse POSIX syntax.
+	// Default: syntax.Perl, also used when ParseFlags is 0
+	ParseFlags syntax.Flags
+
+	// PatternEncoding is how patterns passed to Validate and Report are
+	// encoded: PatternEncodingRaw, PatternEncodingBase64 or
+	// PatternEncodingURL. Patterns are decoded before any other step, so an
+	// API that decodes patterns before matching them cannot be bypassed by
+	// sending an encoded dangerous pattern. Issues carry the decoded
+	// pattern, and errors about it also quote the encoded form. Patterns
+	// that cannot be decoded return an error wrapping ErrInvalidPattern.
+	// Compile, SafeCompile, IsSafe and CheckPattern ignore it even when it
+	// is set with SetDefaultOptions, since they check the pattern they are
+	// given for compiling.
+	// Default: "" (PatternEncodingRaw)
+	PatternEncoding string
+
+	// Checks specifies which checks to perform (bitmask). Checks whose flag
+	// is not set are skipped entirely. Limits such as MaxPatternLength,
+	// MaxQuantifierRange and MaxRepetitionCount are always enforced.
+	// Default: CheckDefault, also used when Checks is 0
 	Checks CheckFlags
 
 	// MaxComplexityScore is the maximum acceptable complexity score (0-100).
@@ -103,6 +249,14 @@ type Options struct {
 	// Default: 70
 	MaxComplexityScore int
 
+	// EnableSafetyMargin lowers the complexity score threshold by a
+	// fraction of MaxComplexityScore (0.0-1.0), so that patterns scoring
+	// just below it are not considered safe. The effective threshold is
+	// MaxComplexityScore * (1 - EnableSafetyMargin), rounded to the nearest
+	// integer: a margin of 0.2 turns a threshold of 70 into 56.
+	// Default: 0.0
+	EnableSafetyMargin float64
+
 	// MaxPatternLength is the maximum allowed pattern length.
 	// Very long patterns can slow down analysis.
 	// Default: 1000, set to 0 for no limit
@@ -116,10 +270,114 @@ type Options struct {
 	// Default: 20
 	MaxQuantifiers int
 
+	// MaxQuantifierRange is the smallest spread (Max - Min) flagged in a
+	// bounded repetition like a{n,m}. Ranges this wide are flagged because
+	// they cause quadratic matching when followed by an overlapping
+	// quantifier. Go's parser rejects repetition counts above 1000, so this
+	// and MaxRepetitionCount default to the largest value Go accepts.
+	// Default: 1000, set to 0 to disable the check
+	MaxQuantifierRange int
+
+	// MaxRepetitionCount is the smallest count flagged in a repetition like
+	// a{n} or a{n,m}. Counts this large are flagged with a Medium
+	// UnboundedRepetition issue, since a{0,1000} behaves like a* and may
+	// hide that unbounded repetition was intended.
+	// Default: 1000, set to 0 to disable the check
+	MaxRepetitionCount int
+
+	// MaxAlternationBranches is the maximum number of branches allowed in a
+	// single alternation like (foo|bar|baz). Larger alternations are flagged
+	// with a Medium AmbiguousPattern issue. Branches are counted as
+	// written, so (a|b|c) counts as three even though Go merges it into
+	// the class [a-c], and (get|post|put) as three.
+	// Default: 20, set to 0 to disable the check
+	MaxAlternationBranches int
+
+	// MaxNFAStates bounds the size of the NFA built for analysis. Patterns
+	// whose NFA would be larger are not analyzed further and are reported
+	// with a Medium ComplexityThresholdExceeded issue instead.
+	// Default: 10000, set to 0 for no limit
+	MaxNFAStates int
+
+	// MaxDFAStates is the number of DFA states a pattern may need before it
+	// is flagged with a Low ComplexityThresholdExceeded issue. Engines that
+	// build DFAs, such as RE2, use memory in proportion to it. Only checked
+	// when Checks includes CheckMemoryUsage.
+	// Default: 1000, set to 0 to disable the check
+	MaxDFAStates int
+
+	// SeverityOverride changes the severity of issues by type before they
+	// are returned, for example raising PolynomialBacktracking to Critical
+	// for services that process large inputs, or lowering a type to Info to
+	// keep reporting it without failing on it. It applies to issues found
+	// by analysis and by plugins, not to deny list or timeout issues, and
+	// does not change complexity scores.
+	// Default: nil
+	SeverityOverride map[IssueType]Severity
+
 	// StrictMode treats warnings as errors.
 	// Default: false
+	//
+	// Deprecated: StrictMode does not change the returned issues. Use
+	// TreatWarningsAsErrors to promote warnings to errors.
 	StrictMode bool
 
+	// TreatWarningsAsErrors promotes Low and Info issues to High in the
+	// returned issues, after SeverityOverride, so that callers failing on
+	// High issues also fail on warnings. Complexity scores and the
+	// thresholds of the checks are not changed.
+	// Default: false
+	TreatWarningsAsErrors bool
+
+	// MaxIssues is the maximum number of issues returned for a pattern.
+	// When more are found, the most severe are kept and a final Info
+	// ComplexityThresholdExceeded issue notes that analysis stopped at the
+	// limit. Critical issues are never dropped, so a pattern with more
+	// Critical issues than MaxIssues returns all of them.
+	// Default: 50, set to 0 for no limit
+	MaxIssues int
+
+	// EnableExperimentalChecks runs detection algorithms that are still
+	// being evaluated, such as the product-automaton witness for
+	// exponential ambiguity, after the checks of the selected Mode. They
+	// report Info issues until they graduate to the regular checks.
+	// Combined with Mode Thorough this gives the most complete analysis.
+	// Default: false
+	EnableExperimentalChecks bool
+
+	// DenyList contains patterns that are always rejected with a Critical
+	// ContextuallyDangerous issue, regardless of analysis. Patterns are
+	// compared exactly and checked before any analysis.
+	// Default: nil
+	DenyList []string
+
+	// DenyListFile is a file of newline-separated patterns to add to the
+	// deny list. Blank lines and lines starting with # are skipped.
+	// A Validator reads the file once, on first use.
+	// Default: ""
+	DenyListFile string
+
+	// AllowUnsafePatterns contains legacy patterns that are let through
+	// while they are being fixed. Patterns are compared exactly, like
+	// DenyList, and are not analyzed; each gets a single Info
+	// ContextuallyDangerous issue instead, so that the debt stays visible.
+	// Deny list matches take precedence.
+	// Default: nil
+	AllowUnsafePatterns []string
+
+	// Concurrency is the maximum number of patterns ValidateMany and
+	// ValidateManyStream validate at once.
+	// Default: 0, meaning runtime.GOMAXPROCS(0)
+	Concurrency int
+
+	// CacheSize is the number of Validate results a Validator keeps, keyed
+	// by pattern, so that services which see the same patterns again do not
+	// analyze them again. The least recently used result is evicted first.
+	// Results cut short by Timeout are not cached. Report does not use the
+	// cache.
+	// Default: 0, no cache
+	CacheSize int
+
 	// AllowUnsafe skips validation (passthrough mode).
 	// Use with caution, primarily for testing.
 	// Default: false
@@ -129,48 +387,121 @@ type Options struct {
 // DefaultOptions returns the recommended default configuration.
 func DefaultOptions() *Options {
 	return &Options{
-		Mode:               Balanced,
-		Timeout:            100 * time.Millisecond,
-		Checks:             CheckDefault,
-		MaxComplexityScore: 70,
-		MaxPatternLength:   1000,
-		MaxNestingDepth:    3,
-		MaxQuantifiers:     20,
-		StrictMode:         false,
-		AllowUnsafe:        false,
+		Mode:                   Balanced,
+		Timeout:                100 * time.Millisecond,
+		TimeoutBehavior:        TimeoutReturnPartial,
+		ParseFlags:             syntax.Perl,
+		Checks:                 CheckDefault,
+		MaxComplexityScore:     70,
+		MaxPatternLength:       1000,
+		MaxNestingDepth:        3,
+		MaxQuantifiers:         20,
+		MaxQuantifierRange:     1000,
+		MaxRepetitionCount:     1000,
+		MaxAlternationBranches: 20,
+		MaxNFAStates:           10000,
+		MaxDFAStates:           1000,
+		MaxIssues:              50,
+		StrictMode:             false,
+		TreatWarningsAsErrors:  false,
+		AllowUnsafe:            false,
 	}
 }
 
 // FastOptions returns options optimized for speed.
 func FastOptions() *Options {
 	return &Options{
-		Mode:               Fast,
-		Timeout:            10 * time.Millisecond,
-		Checks:             CheckNestedQuantifiers | CheckCatastrophicBacktrack,
-		MaxComplexityScore: 70,
-		MaxPatternLength:   1000,
-		MaxNestingDepth:    3,
-		MaxQuantifiers:     20,
-		StrictMode:         false,
-		AllowUnsafe:        false,
+		Mode:                   Fast,
+		Timeout:                10 * time.Millisecond,
+		TimeoutBehavior:        TimeoutReturnPartial,
+		ParseFlags:             syntax.Perl,
+		Checks:                 CheckNestedQuantifiers | CheckCatastrophicBacktrack,
+		MaxComplexityScore:     70,
+		MaxPatternLength:       1000,
+		MaxNestingDepth:        3,
+		MaxQuantifiers:         20,
+		MaxQuantifierRange:     1000,
+		MaxRepetitionCount:     1000,
+		MaxAlternationBranches: 20,
+		MaxNFAStates:           10000,
+		MaxDFAStates:           1000,
+		MaxIssues:              50,
+		StrictMode:             false,
+		TreatWarningsAsErrors:  false,
+		AllowUnsafe:            false,
 	}
 }
 
 // ThoroughOptions returns options for comprehensive analysis.
 func ThoroughOptions() *Options {
 	return &Options{
-		Mode:               Thorough,
-		Timeout:            1 * time.Second,
-		Checks:             CheckAll,
-		MaxComplexityScore: 70,
-		MaxPatternLength:   2000,
-		MaxNestingDepth:    5,
-		MaxQuantifiers:     50,
-		StrictMode:         true,
-		AllowUnsafe:        false,
+		Mode:                   Thorough,
+		Timeout:                1 * time.Second,
+		TimeoutBehavior:        TimeoutReturnPartial,
+		ParseFlags:             syntax.Perl,
+		Checks:                 CheckAll,
+		MaxComplexityScore:     70,
+		MaxPatternLength:       2000,
+		MaxNestingDepth:        5,
+		MaxQuantifiers:         50,
+		MaxQuantifierRange:     1000,
+		MaxRepetitionCount:     1000,
+		MaxAlternationBranches: 20,
+		MaxNFAStates:           10000,
+		MaxDFAStates:           1000,
+		MaxIssues:              50,
+		StrictMode:             true,
+		TreatWarningsAsErrors:  false,
+		AllowUnsafe:            false,
 	}
 }
 
+// Merge returns a copy of o with every field of overrides that is neither
+// zero nor equal to its DefaultOptions value applied on top, for layering
+// configuration such as a config file, environment variables and
+// per-request options. A zero field, like Timeout: 0, keeps the value of o.
+// Deny lists and allowed unsafe patterns are combined, and severity
+// overrides are merged with those of overrides winning. A nil overrides
+// returns a copy of o.
+//
+// Example:
+//
+//	base, _ := regret.ParseOptions("regret.yaml")
+//	opts := base.Merge(&regret.Options{Timeout: 2 * time.Second})
+func (o *Options) Merge(overrides *Options) *Options {
+	if overrides == nil {
+		return o.clone()
+	}
+	return mergeOptions(o.clone(), overrides)
+}
+
+// IsDefault reports whether every field of o equals DefaultOptions().
+func (o *Options) IsDefault() bool {
+	return len(DiffOptions(o, DefaultOptions())) == 0
+}
+
+// Validate checks that the options are in range, as ParseOptions does, and
+// that they do not contradict each other: AllowUnsafe skips the validation
+// that StrictMode and TreatWarningsAsErrors make stricter, and a pattern
+// cannot be both denied and allowed. Errors wrap ErrInvalidOptions.
+func (o *Options) Validate() error {
+	if err := validateOptions(o); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidOptions, err)
+	}
+	if o.AllowUnsafe && o.StrictMode {
+		return fmt.Errorf("%w: allow_unsafe contradicts strict_mode", ErrInvalidOptions)
+	}
+	if o.AllowUnsafe && o.TreatWarningsAsErrors {
+		return fmt.Errorf("%w: allow_unsafe contradicts treat_warnings_as_errors", ErrInvalidOptions)
+	}
+	for _, pattern := range o.AllowUnsafePatterns {
+		if slices.Contains(o.DenyList, pattern) {
+			return fmt.Errorf("%w: %s is both in deny_list and allow_unsafe_patterns", ErrInvalidOptions, quote(pattern))
+		}
+	}
+	return nil
+}
+
 // Severity represents the severity level of an issue.
 type Severity int
 
@@ -209,6 +540,38 @@ func (s Severity) String() string {
 	}
 }
 
+// SeverityFromString parses the string form of a severity, as returned by
+// Severity.String. Parsing is case-insensitive. Unknown names return
+// ErrUnknownSeverity.
+func SeverityFromString(s string) (Severity, error) {
+	for sev := Critical; sev <= Info; sev++ {
+		if strings.EqualFold(s, sev.String()) {
+			return sev, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q", ErrUnknownSeverity, s)
+}
+
+// MarshalText encodes the severity as its string form. It implements
+// encoding.TextMarshaler.
+func (s Severity) MarshalText() ([]byte, error) {
+	if s < Critical || s > Info {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownSeverity, int(s))
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a severity from its string form. It implements
+// encoding.TextUnmarshaler.
+func (s *Severity) UnmarshalText(text []byte) error {
+	sev, err := SeverityFromString(string(text))
+	if err != nil {
+		return err
+	}
+	*s = sev
+	return nil
+}
+
 // IssueType represents the type of issue detected.
 type IssueType int
 
@@ -239,6 +602,19 @@ const (
 
 	// ContextuallyDangerous indicates pattern is dangerous in current context.
 	ContextuallyDangerous
+
+	// LargeQuantifierRange indicates a bounded repetition like a{0,1000}
+	// whose range reaches Options.MaxQuantifierRange.
+	LargeQuantifierRange
+
+	// BackreferenceAmbiguity indicates a backreference like the \1 in
+	// (.+)\1 to a group that can capture text of many lengths. It is only
+	// reported for dialects with backreferences (see Options.Dialect).
+	BackreferenceAmbiguity
+
+	// UnicodeAmbiguity indicates characters that match differently in NFC
+	// and NFD input, like the à in [àáâ]+. See CheckUnicodeAmbiguity.
+	UnicodeAmbiguity
 )
 
 // String returns the string representation of the issue type.
@@ -262,11 +638,49 @@ func (i IssueType) String() string {
 		return "complexity_threshold_exceeded"
 	case ContextuallyDangerous:
 		return "contextually_dangerous"
+	case LargeQuantifierRange:
+		return "large_quantifier_range"
+	case BackreferenceAmbiguity:
+		return "backreference_ambiguity"
+	case UnicodeAmbiguity:
+		return "unicode_ambiguity"
 	default:
 		return "unknown"
 	}
 }
 
+// IssueTypeFromString parses the string form of an issue type, as returned
+// by IssueType.String. Parsing is case-insensitive. Unknown names return
+// ErrUnknownIssueType.
+func IssueTypeFromString(s string) (IssueType, error) {
+	for t := NestedQuantifiers; t <= UnicodeAmbiguity; t++ {
+		if strings.EqualFold(s, t.String()) {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q", ErrUnknownIssueType, s)
+}
+
+// MarshalText encodes the issue type as its string form. It implements
+// encoding.TextMarshaler.
+func (i IssueType) MarshalText() ([]byte, error) {
+	if i < NestedQuantifiers || i > UnicodeAmbiguity {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownIssueType, int(i))
+	}
+	return []byte(i.String()), nil
+}
+
+// UnmarshalText decodes an issue type from its string form. It implements
+// encoding.TextUnmarshaler.
+func (i *IssueType) UnmarshalText(text []byte) error {
+	t, err := IssueTypeFromString(string(text))
+	if err != nil {
+		return err
+	}
+	*i = t
+	return nil
+}
+
 // Position represents a location in the regex pattern.
 type Position struct {
 	// Start is the starting byte offset in the pattern.
@@ -275,13 +689,43 @@ type Position struct {
 	// End is the ending byte offset in the pattern.
 	End int
 
-	// Line is the line number for multiline patterns (1-indexed).
+	// Line is the line of Start (1-indexed), for patterns that span
+	// several lines. Lines are separated by \n.
 	Line int
 
-	// Column is the column number (1-indexed).
+	// Column is the column of Start in its line (1-indexed), in bytes.
 	Column int
 }
 
+// Snippet returns the part of pattern that the position spans. If the span
+// does not fit in pattern, the whole pattern is returned.
+func (p Position) Snippet(pattern string) string {
+	if p.Start < 0 || p.Start > p.End || p.End > len(pattern) {
+		return pattern
+	}
+	return pattern[p.Start:p.End]
+}
+
+// ContextSnippet returns the span of the position in pattern with up to
+// contextChars characters on each side, marking the span with >>> and <<<,
+// for example "^[a-z]>>>(a+)+<<<$". If the span does not fit in pattern,
+// the whole pattern is returned without markers.
+func (p Position) ContextSnippet(pattern string, contextChars int) string {
+	if p.Start < 0 || p.Start > p.End || p.End > len(pattern) {
+		return pattern
+	}
+
+	before := []rune(pattern[:p.Start])
+	if contextChars < len(before) {
+		before = before[len(before)-max(contextChars, 0):]
+	}
+	after := []rune(pattern[p.End:])
+	if contextChars < len(after) {
+		after = after[:max(contextChars, 0)]
+	}
+	return string(before) + ">>>" + pattern[p.Start:p.End] + "<<<" + string(after)
+}
+
 // Issue represents a detected problem in a regex pattern.
 type Issue struct {
 	// Type is the type of issue detected.
@@ -310,6 +754,17 @@ type Issue struct {
 
 	// Details contains additional technical details about the issue.
 	Details map[string]interface{}
+
+	// References links to research papers, CVE entries and guides that
+	// describe this kind of issue. See References.
+	References []string
+
+	// RelatedPatterns lists up to five known dangerous patterns whose
+	// quantifiers nest like those of Pattern, such as (a*)* and ([a-z]+)+
+	// for (a+)+, to show the class of danger rather than just this
+	// instance. It is empty if Pattern has no unbounded quantifier or no
+	// known pattern has the same structure.
+	RelatedPatterns []string
 }
 
 // Complexity represents time or space complexity classes.
@@ -385,6 +840,19 @@ type ComplexityScore struct {
 	// This means the pattern has polynomially many ways to match input.
 	HasIDA bool
 
+	// ExploitabilityScore rates how easy adversarial input is to build,
+	// from 0 to 1. It is 1/ln(n), capped at 1, where n is the number of
+	// characters accepted by the innermost repeated expression: quantifying
+	// a single character scores 1, while quantifying any Unicode character
+	// approaches 0. It is 0 if the pattern repeats no characters.
+	ExploitabilityScore float64
+
+	// NormalizedScore is Overall divided by ln(len(pattern)+1), so that
+	// short patterns score higher than long ones with the same Overall:
+	// their danger is more concentrated. Use it to decide which of many
+	// patterns to fix first. It is 0 for the empty pattern.
+	NormalizedScore float64
+
 	// PolynomialDegree is the degree of polynomial backtracking.
 	// 2 = quadratic, 3 = cubic, etc. Only set if HasIDA is true.
 	PolynomialDegree int
@@ -392,16 +860,49 @@ type ComplexityScore struct {
 	// Metrics contains detailed metrics about the pattern.
 	Metrics Metrics
 
+	// Breakdown lists the points each analysis step contributed to Overall,
+	// before the score is capped at MaxComplexityScore.
+	Breakdown []SubScore
+
 	// WorstCaseInput is an example input that triggers worst-case behavior.
 	WorstCaseInput string
 
 	// PumpPattern contains the pump components for generating adversarial inputs.
 	PumpPattern []string
 
+	// PumpSizes are suggested numbers of repetitions of the pump
+	// components, as in PumpPattern.Sizes.
+	PumpSizes []int
+
 	// Explanation is a human-readable explanation of the complexity analysis.
 	Explanation string
 
-	// Safe indicates whether the pattern is considered safe based on the analysis.
+	// AlternativeSuggestions lists rewrites of an unsafe pattern that have a
+	// lower Overall score: the rewrite of Suggest, which matches the same
+	// strings, or if there is none, rewrites that change the matched
+	// strings. Those that match more strings, such as [0-9a-z]* for
+	// ([a-z]+[0-9]?)*, start with "[SUPERSET] "; those that match fewer,
+	// such as ([a-z]+[0-9]?)? for the same pattern, start with "[SUBSET] ".
+	// Empty for safe patterns.
+	AlternativeSuggestions []string
+
+	// Issues lists the issues detected in the pattern, as returned by
+	// ValidateWithOptions with the options the analysis used.
+	Issues []Issue
+
+	// Partial indicates that analysis was cut short by Options.Timeout, with
+	// TimeoutReturnPartial or TimeoutMarkUnsafe. Completeness is the
+	// fraction of the analysis steps that completed, from 0 to 1. Since the
+	// missing steps might have found more, Overall is then raised toward
+	// MaxComplexityScore in proportion to the missing fraction, recorded as
+	// a "partial_analysis" entry in Breakdown, so a pattern whose analysis
+	// barely started is not reported Safe.
+	Partial      bool
+	Completeness float64
+
+	// Safe indicates whether the pattern is considered safe based on the analysis:
+	// it has no EDA or IDA and Overall is below Options.MaxComplexityScore,
+	// lowered by Options.EnableSafetyMargin.
 	Safe bool
 }
 
@@ -415,6 +916,45 @@ type Metrics struct {
 
 	// AlternationCount is the number of alternation operators (|).
 	AlternationCount int
+
+	// MaxPathLength is the number of characters consumed along the longest
+	// path through the pattern's NFA, counting each loop body once.
+	MaxPathLength int
+
+	// BranchCount is the number of choice points in the pattern's NFA.
+	// Each one is a point the matcher may backtrack to.
+	BranchCount int
+
+	// MaxBacktrackDepth is the largest number of choice points on a path
+	// through the pattern's NFA, with loops counted once: the stack depth a
+	// backtracking engine reaches in a single pass through the pattern. It
+	// is not an upper bound, since engines that keep a frame per loop
+	// iteration grow their stack further on longer inputs. Validate reports
+	// a Low issue when it exceeds 1000, since engines that recurse per
+	// choice point may overflow their stack.
+	MaxBacktrackDepth int
+
+	// MinimizedStateCount is the number of states of the minimum DFA for
+	// the pattern, without the dead state: the least memory an engine that
+	// builds a DFA needs. A count far above the size of the pattern means
+	// the pattern has to track many possible matches at once. It is only
+	// computed when Options.Checks is CheckAll, as in ThoroughOptions,
+	// and is 0 otherwise or when the DFA has more than 10000 states.
+	MinimizedStateCount int
+}
+
+// SubScore is the contribution of one analysis step to a complexity score.
+type SubScore struct {
+	// Name identifies the step: "nesting", "quantifiers", "alternations",
+	// "pattern", or "time_complexity" when the score was raised to the
+	// minimum for its complexity class.
+	Name string
+
+	// Score is the number of points the step added.
+	Score int
+
+	// Description summarizes what the step found.
+	Description string
 }
 
 // PumpPattern represents a pattern for generating adversarial inputs.
@@ -439,6 +979,12 @@ type PumpPattern struct {
 
 	// Description explains what this pump pattern tests.
 	Description string
+
+	// Sizes are suggested sizes to pass to Generate. For exponential
+	// patterns they double, 1, 2, 4, ..., 32, so that the match time
+	// visibly doubles from one size to the next; for other patterns they
+	// grow by a fixed step.
+	Sizes []int
 }
 
 // Generate creates an adversarial input of the specified size.
@@ -485,3 +1031,35 @@ func (p *PumpPattern) GenerateSequence(start, end, step int) []string {
 	}
 	return sequence
 }
+
+// GenerateMatrix creates adversarial inputs in which the first two pumps are
+// repeated independently: result[i][j] is Prefix + Pumps[0]*i + Pumps[1]*j
+// + Suffix, for i from 0 to maxI and j from 0 to maxJ. Any further pumps are
+// repeated j times. A degree-2 IDA pattern backtracks O(i*j) times on
+// result[i][j], so the matrix shows how the running time depends on each
+// pump. The diagonal result[n][n] is the input GenerateSequence gives for
+// size n without Interleave.
+func (p *PumpPattern) GenerateMatrix(maxI, maxJ int) [][]string {
+	if maxI < 0 || maxJ < 0 {
+		return nil
+	}
+
+	matrix := make([][]string, maxI+1)
+	for i := range matrix {
+		matrix[i] = make([]string, maxJ+1)
+		for j := range matrix[i] {
+			var b strings.Builder
+			b.WriteString(p.Prefix)
+			for k, pump := range p.Pumps {
+				n := j
+				if k == 0 {
+					n = i
+				}
+				b.WriteString(strings.Repeat(pump, n))
+			}
+			b.WriteString(p.Suffix)
+			matrix[i][j] = b.String()
+		}
+	}
+	return matrix
+}
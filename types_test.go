@@ -1,6 +1,10 @@
 package regret
 
 import (
+	"encoding/json"
+	"errors"
+	"regexp/syntax"
+	"strings"
 	"testing"
 	"time"
 )
@@ -56,6 +60,8 @@ func TestIssueType_String(t *testing.T) {
 		{OverlappingAlternation, "overlapping_alternation"},
 		{ExponentialBacktracking, "exponential_backtracking"},
 		{PolynomialBacktracking, "polynomial_backtracking"},
+		{LargeQuantifierRange, "large_quantifier_range"},
+		{UnicodeAmbiguity, "unicode_ambiguity"},
 		{IssueType(999), "unknown"},
 	}
 
@@ -68,6 +74,94 @@ func TestIssueType_String(t *testing.T) {
 	}
 }
 
+func TestSeverityFromString(t *testing.T) {
+	for sev := Critical; sev <= Info; sev++ {
+		got, err := SeverityFromString(sev.String())
+		if err != nil || got != sev {
+			t.Errorf("SeverityFromString(%q) = %v, %v, want %v", sev.String(), got, err, sev)
+		}
+	}
+
+	if got, err := SeverityFromString("HIGH"); err != nil || got != High {
+		t.Errorf("SeverityFromString(HIGH) = %v, %v, want high", got, err)
+	}
+	if _, err := SeverityFromString("severe"); !errors.Is(err, ErrUnknownSeverity) {
+		t.Errorf("SeverityFromString(severe) error = %v, want ErrUnknownSeverity", err)
+	}
+}
+
+func TestIssueTypeFromString(t *testing.T) {
+	for it := NestedQuantifiers; it <= UnicodeAmbiguity; it++ {
+		got, err := IssueTypeFromString(it.String())
+		if err != nil || got != it {
+			t.Errorf("IssueTypeFromString(%q) = %v, %v, want %v", it.String(), got, err, it)
+		}
+	}
+
+	if _, err := IssueTypeFromString("unknown"); !errors.Is(err, ErrUnknownIssueType) {
+		t.Errorf("IssueTypeFromString(unknown) error = %v, want ErrUnknownIssueType", err)
+	}
+}
+
+func TestIssue_JSONRoundTrip(t *testing.T) {
+	in := Issue{Type: PolynomialBacktracking, Severity: High, Message: "overlapping quantifiers"}
+
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"Type":"polynomial_backtracking"`) ||
+		!strings.Contains(string(data), `"Severity":"high"`) {
+		t.Errorf("json.Marshal() = %s, want type and severity as strings", data)
+	}
+
+	var out Issue
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if out.Type != in.Type || out.Severity != in.Severity || out.Message != in.Message {
+		t.Errorf("round trip = %+v, want %+v", out, in)
+	}
+
+	if err := json.Unmarshal([]byte(`{"Type":"bogus"}`), &out); !errors.Is(err, ErrUnknownIssueType) {
+		t.Errorf("json.Unmarshal() error = %v, want ErrUnknownIssueType", err)
+	}
+	if _, err := json.Marshal(Issue{Severity: Severity(99)}); err == nil {
+		t.Error("json.Marshal() expected error for invalid severity")
+	}
+}
+
+func TestPosition_Snippet(t *testing.T) {
+	pattern := "^abc(x+)+d$"
+	tests := []struct {
+		pos         Position
+		snippet     string
+		context     string
+		contextSize int
+	}{
+		{Position{Start: 4, End: 9}, "(x+)+", "bc>>>(x+)+<<<d$", 2},
+		{Position{Start: 4, End: 9}, "(x+)+", "^abc>>>(x+)+<<<d$", 10},
+		{Position{Start: 4, End: 9}, "(x+)+", ">>>(x+)+<<<", 0},
+		{Position{Start: 0, End: len(pattern)}, pattern, ">>>" + pattern + "<<<", 5},
+		{Position{Start: 4, End: 99}, pattern, pattern, 5},
+		{Position{Start: 5, End: 4}, pattern, pattern, 5},
+	}
+
+	for _, tt := range tests {
+		if got := tt.pos.Snippet(pattern); got != tt.snippet {
+			t.Errorf("%+v.Snippet() = %q, want %q", tt.pos, got, tt.snippet)
+		}
+		if got := tt.pos.ContextSnippet(pattern, tt.contextSize); got != tt.context {
+			t.Errorf("%+v.ContextSnippet(%d) = %q, want %q", tt.pos, tt.contextSize, got, tt.context)
+		}
+	}
+
+	// Context is counted in characters, not bytes
+	if got := (Position{Start: 4, End: 6}).ContextSnippet("ééa+é", 1); got != "é>>>a+<<<é" {
+		t.Errorf("ContextSnippet() = %q, want %q", got, "é>>>a+<<<é")
+	}
+}
+
 func TestComplexity_String(t *testing.T) {
 	tests := []struct {
 		complexity Complexity
@@ -107,6 +201,27 @@ func TestDefaultOptions(t *testing.T) {
 	if opts.MaxComplexityScore != 70 {
 		t.Errorf("DefaultOptions().MaxComplexityScore = %v, want 70", opts.MaxComplexityScore)
 	}
+	if opts.MaxQuantifierRange != 1000 {
+		t.Errorf("DefaultOptions().MaxQuantifierRange = %v, want 1000", opts.MaxQuantifierRange)
+	}
+	if opts.MaxRepetitionCount != 1000 {
+		t.Errorf("DefaultOptions().MaxRepetitionCount = %v, want 1000", opts.MaxRepetitionCount)
+	}
+	if opts.TimeoutBehavior != TimeoutReturnPartial {
+		t.Errorf("DefaultOptions().TimeoutBehavior = %v, want %v", opts.TimeoutBehavior, TimeoutReturnPartial)
+	}
+	if opts.MaxAlternationBranches != 20 {
+		t.Errorf("DefaultOptions().MaxAlternationBranches = %v, want 20", opts.MaxAlternationBranches)
+	}
+	if opts.MaxNFAStates != 10000 {
+		t.Errorf("DefaultOptions().MaxNFAStates = %v, want 10000", opts.MaxNFAStates)
+	}
+	if opts.ParseFlags != syntax.Perl {
+		t.Errorf("DefaultOptions().ParseFlags = %v, want syntax.Perl", opts.ParseFlags)
+	}
+	if opts.MaxIssues != 50 {
+		t.Errorf("DefaultOptions().MaxIssues = %v, want 50", opts.MaxIssues)
+	}
 }
 
 func TestFastOptions(t *testing.T) {
@@ -134,6 +249,81 @@ func TestThoroughOptions(t *testing.T) {
 	}
 }
 
+func TestOptions_Merge(t *testing.T) {
+	base := ThoroughOptions()
+	base.DenyList = []string{"(a+)+"}
+
+	merged := base.Merge(&Options{Timeout: 2 * time.Second, MaxIssues: 10, DenyList: []string{"(b+)+"}})
+	if merged.Timeout != 2*time.Second || merged.MaxIssues != 10 {
+		t.Errorf("Merge() Timeout = %v, MaxIssues = %d, want the overrides", merged.Timeout, merged.MaxIssues)
+	}
+	if merged.Mode != Thorough || merged.MaxPatternLength != 2000 {
+		t.Errorf("Merge() Mode = %v, MaxPatternLength = %d, want the base values", merged.Mode, merged.MaxPatternLength)
+	}
+	if len(merged.DenyList) != 2 {
+		t.Errorf("Merge() DenyList = %q, want both lists", merged.DenyList)
+	}
+	if base.Timeout != time.Second || len(base.DenyList) != 1 {
+		t.Error("Merge() modified the base options")
+	}
+
+	// Zero values keep the base value
+	if merged := base.Merge(&Options{}); merged.Timeout != time.Second || merged.MaxNestingDepth != 5 {
+		t.Errorf("Merge(zero) = %+v, want the base values", merged)
+	}
+	if merged := base.Merge(nil); merged == base || merged.Mode != Thorough {
+		t.Errorf("Merge(nil) = %+v, want a copy of the base", merged)
+	}
+}
+
+func TestOptions_IsDefault(t *testing.T) {
+	if !DefaultOptions().IsDefault() {
+		t.Error("DefaultOptions().IsDefault() = false")
+	}
+	if ThoroughOptions().IsDefault() {
+		t.Error("ThoroughOptions().IsDefault() = true")
+	}
+	opts := DefaultOptions()
+	opts.DenyList = []string{"(a+)+"}
+	if opts.IsDefault() {
+		t.Error("IsDefault() = true with a deny list")
+	}
+}
+
+func TestOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Options)
+		want   string // substring of the error, empty for valid options
+	}{
+		{"defaults", func(*Options) {}, ""},
+		{"out of range", func(o *Options) { o.MaxComplexityScore = 200 }, "max_complexity_score"},
+		{"allow unsafe and strict", func(o *Options) { o.AllowUnsafe, o.StrictMode = true, true }, "strict_mode"},
+		{"allow unsafe and warnings as errors", func(o *Options) { o.AllowUnsafe, o.TreatWarningsAsErrors = true, true }, "treat_warnings_as_errors"},
+		{"denied and allowed", func(o *Options) {
+			o.DenyList = []string{"(a+)+"}
+			o.AllowUnsafePatterns = []string{"(a+)+"}
+		}, "both in deny_list and allow_unsafe_patterns"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			tt.modify(opts)
+			err := opts.Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidOptions) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() error = %v, want ErrInvalidOptions mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
+
 func TestPumpPattern_Generate(t *testing.T) {
 	tests := []struct {
 		name string
@@ -216,6 +406,42 @@ func TestPumpPattern_GenerateSequence(t *testing.T) {
 	}
 }
 
+func TestPumpPattern_GenerateMatrix(t *testing.T) {
+	pump := PumpPattern{
+		Prefix: "<",
+		Pumps:  []string{"a", "b"},
+		Suffix: "!",
+	}
+
+	matrix := pump.GenerateMatrix(2, 3)
+	if len(matrix) != 3 {
+		t.Fatalf("GenerateMatrix() returned %d rows, want 3", len(matrix))
+	}
+	for i, row := range matrix {
+		if len(row) != 4 {
+			t.Fatalf("row %d has %d items, want 4", i, len(row))
+		}
+		for j, got := range row {
+			want := "<" + strings.Repeat("a", i) + strings.Repeat("b", j) + "!"
+			if got != want {
+				t.Errorf("GenerateMatrix()[%d][%d] = %q, want %q", i, j, got, want)
+			}
+		}
+	}
+
+	// The diagonal matches GenerateSequence
+	sequence := pump.GenerateSequence(1, 2, 1)
+	for n := 1; n <= 2; n++ {
+		if matrix[n][n] != sequence[n-1] {
+			t.Errorf("GenerateMatrix()[%d][%d] = %q, want %q", n, n, matrix[n][n], sequence[n-1])
+		}
+	}
+
+	if got := pump.GenerateMatrix(-1, 2); got != nil {
+		t.Errorf("GenerateMatrix(-1, 2) = %v, want nil", got)
+	}
+}
+
 func TestCheckFlags(t *testing.T) {
 	// Test that CheckAll includes all flags
 	if CheckAll&CheckNestedQuantifiers == 0 {
@@ -267,8 +493,96 @@ func TestFullVersion(t *testing.T) {
 	if version == "" {
 		t.Error("FullVersion() returned empty string")
 	}
+	if version != CurrentVersion.String() {
+		t.Errorf("FullVersion() = %v, want %v", version, CurrentVersion.String())
+	}
 	// Should include prerelease suffix
-	if VersionPrerelease != "" && version == Version {
+	if CurrentVersion.Prerelease != "" && !strings.HasSuffix(version, "-"+CurrentVersion.Prerelease) {
 		t.Errorf("FullVersion() = %v, expected to include prerelease suffix", version)
 	}
 }
+
+func TestVersion_String(t *testing.T) {
+	tests := []struct {
+		version Version
+		want    string
+	}{
+		{Version{Major: 1, Minor: 2, Patch: 3}, "1.2.3"},
+		{Version{Major: 0, Minor: 1, Patch: 0, Prerelease: "alpha"}, "0.1.0-alpha"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.version.String(); got != tt.want {
+			t.Errorf("String() = %v, want %v", got, tt.want)
+		}
+	}
+}
+
+func TestVersion_CompareTo(t *testing.T) {
+	// Each version has lower precedence than the next
+	ordered := []Version{
+		{Major: 0, Minor: 9, Patch: 9},
+		{Major: 1, Minor: 0, Patch: 0, Prerelease: "alpha"},
+		{Major: 1, Minor: 0, Patch: 0, Prerelease: "alpha.1"},
+		{Major: 1, Minor: 0, Patch: 0, Prerelease: "alpha.2"},
+		{Major: 1, Minor: 0, Patch: 0, Prerelease: "alpha.10"},
+		{Major: 1, Minor: 0, Patch: 0, Prerelease: "alpha.beta"},
+		{Major: 1, Minor: 0, Patch: 0, Prerelease: "rc.1"},
+		{Major: 1, Minor: 0, Patch: 0},
+		{Major: 1, Minor: 0, Patch: 1},
+		{Major: 1, Minor: 1, Patch: 0},
+		{Major: 2, Minor: 0, Patch: 0},
+	}
+
+	for i := range ordered {
+		for j := range ordered {
+			want := compareInts(i, j)
+			if got := ordered[i].CompareTo(ordered[j]); got != want {
+				t.Errorf("%v.CompareTo(%v) = %d, want %d", ordered[i], ordered[j], got, want)
+			}
+		}
+	}
+}
+
+func TestVersion_IsAtLeast(t *testing.T) {
+	v := Version{Major: 1, Minor: 2, Patch: 3}
+	if !v.IsAtLeast(1, 2, 3) || !v.IsAtLeast(1, 1, 9) || v.IsAtLeast(1, 3, 0) {
+		t.Errorf("IsAtLeast() gave unexpected results for %v", v)
+	}
+
+	pre := Version{Major: 0, Minor: 2, Patch: 0, Prerelease: "alpha"}
+	if pre.IsAtLeast(0, 2, 0) {
+		t.Errorf("%v.IsAtLeast(0, 2, 0) = true, want false", pre)
+	}
+	if !pre.IsAtLeast(0, 1, 9) {
+		t.Errorf("%v.IsAtLeast(0, 1, 9) = false, want true", pre)
+	}
+}
+
+func TestReferences(t *testing.T) {
+	refs := References(ExponentialBacktracking)
+	if len(refs) == 0 {
+		t.Fatal("References(ExponentialBacktracking) is empty")
+	}
+	for _, ref := range refs {
+		if !strings.HasPrefix(ref, "https://") {
+			t.Errorf("reference %q is not an https link", ref)
+		}
+	}
+
+	// The result is a copy
+	refs[0] = "changed"
+	if References(ExponentialBacktracking)[0] == "changed" {
+		t.Error("References() returned the shared slice")
+	}
+
+	issues, err := Validate("(a+)+")
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	for _, issue := range issues {
+		if len(issue.References) == 0 {
+			t.Errorf("issue %s has no references", issue.Type)
+		}
+	}
+}
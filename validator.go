@@ -1,8 +1,16 @@
 package regret
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"math"
+	"regexp/syntax"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/theakshaypant/regret/internal/analyzer"
 	"github.com/theakshaypant/regret/internal/detector"
@@ -22,12 +30,35 @@ var (
 
 	// ErrUnsupportedFeature indicates the pattern uses unsupported regex features.
 	ErrUnsupportedFeature = errors.New("unsupported regex feature")
+
+	// ErrUnsupportedLanguage indicates ScanReader or ValidateForLanguage does
+	// not know the source language.
+	ErrUnsupportedLanguage = errors.New("unsupported source language")
+
+	// ErrUnknownIssueType indicates a string does not name an IssueType.
+	ErrUnknownIssueType = errors.New("unknown issue type")
+
+	// ErrUnknownSeverity indicates a string does not name a Severity.
+	ErrUnknownSeverity = errors.New("unknown severity")
+
+	// ErrNotEquivalent indicates IsEquivalent found a string matched by
+	// only one of two patterns. The error message quotes the string.
+	ErrNotEquivalent = errors.New("patterns are not equivalent")
+
+	// ErrUnsafePattern indicates CheckPattern found issues in a pattern.
+	// The error message includes the most severe issue's message.
+	ErrUnsafePattern = errors.New("unsafe regex pattern")
+
+	// ErrInvalidOptions indicates Options.Validate found an option out of
+	// range or two options that contradict each other.
+	ErrInvalidOptions = errors.New("invalid options")
 )
 
 // IsSafe performs a quick safety check on a regex pattern using strict default settings.
 // Returns true if the pattern is safe to use, false otherwise.
 //
-// This function uses Fast mode with CheckDefault flags and is optimized for performance.
+// This function uses Fast mode with CheckDefault flags and is optimized for performance,
+// or the options set with SetDefaultOptions if any.
 // For detailed information about issues, use Validate() instead.
 //
 // Example:
@@ -36,17 +67,46 @@ var (
 //	    return errors.New("unsafe regex pattern")
 //	}
 func IsSafe(pattern string) bool {
-	opts := FastOptions()
-	opts.StrictMode = true
+	return CheckPattern(pattern) == nil
+}
+
+// CheckPattern is like IsSafe but says why a pattern is not safe: it
+// returns an error wrapping ErrUnsafePattern with the message of the most
+// severe issue, or the error that kept the pattern from being validated.
+// It returns nil exactly when IsSafe returns true. A pattern whose analysis
+// times out is not safe, even with TimeoutReturnPartial.
+//
+// Example:
+//
+//	if err := regret.CheckPattern(filter); err != nil {
+//	    return fmt.Errorf("invalid filter: %w", err)
+//	}
+//	// invalid filter: unsafe regex pattern: Nested quantifiers detected: (a+)+
+func CheckPattern(pattern string) error {
+	opts, ok := customDefaultOptions()
+	if !ok {
+		opts = FastOptions()
+	}
+	opts.TreatWarningsAsErrors = true
+	// A pattern is only safe if its analysis finished
+	if opts.TimeoutBehavior == TimeoutReturnPartial {
+		opts.TimeoutBehavior = TimeoutMarkUnsafe
+	}
+	// SafeCompile compiles the pattern it checks here
+	opts.PatternEncoding = PatternEncodingRaw
 	issues, err := ValidateWithOptions(pattern, opts)
 	if err != nil {
-		return false
+		return err
 	}
-	return len(issues) == 0
+	if worst, ok := IssueSet(issues).Worst(); ok {
+		return fmt.Errorf("%w: %s", ErrUnsafePattern, worst.Message)
+	}
+	return nil
 }
 
 // Validate analyzes a regex pattern and returns all detected issues.
-// Uses default options (Balanced mode with CheckDefault flags).
+// Uses default options (Balanced mode with CheckDefault flags), or the
+// options set with SetDefaultOptions if any.
 //
 // Returns a slice of issues (which may be empty) and an error if the pattern
 // cannot be analyzed (e.g., syntax errors, timeout).
@@ -61,7 +121,7 @@ func IsSafe(pattern string) bool {
 //	    fmt.Printf("Issue: %s at position %d\n", issue.Message, issue.Position.Start)
 //	}
 func Validate(pattern string) ([]Issue, error) {
-	return ValidateWithOptions(pattern, DefaultOptions())
+	return ValidateWithOptions(pattern, GetDefaultOptions())
 }
 
 // ValidateWithOptions analyzes a regex pattern with custom configuration options.
@@ -83,26 +143,222 @@ func Validate(pattern string) ([]Issue, error) {
 //	}
 //	issues, err := regret.ValidateWithOptions(pattern, opts)
 func ValidateWithOptions(pattern string, opts *Options) ([]Issue, error) {
+	return NewValidator(opts).Validate(pattern)
+}
+
+// ValidateForLanguage analyzes a regex pattern with options suited to the
+// standard regex engine of a programming language: "go", "java", "python",
+// "javascript" (or "js"), "php" or "ruby". The language picks the Dialect;
+// languages whose engine backtracks, which is all of them except Go, also
+// get StrictMode. Other options are the DefaultOptions.
+//
+// It returns an error wrapping ErrUnsupportedLanguage for other languages.
+//
+// Example:
+//
+//	issues, err := regret.ValidateForLanguage(`^(\w+\.)*\w+$`, "java")
+func ValidateForLanguage(pattern, sourceLanguage string) ([]Issue, error) {
+	opts, err := languageOptions(sourceLanguage)
+	if err != nil {
+		return nil, err
+	}
+	return ValidateWithOptions(pattern, opts)
+}
+
+// languageDialects maps language names to the dialect of their standard
+// regex engine.
+var languageDialects = map[string]Dialect{
+	"go":         DialectRE2,
+	"java":       DialectJava,
+	"python":     DialectPython,
+	"py":         DialectPython,
+	"javascript": DialectJavaScript,
+	"js":         DialectJavaScript,
+	"php":        DialectPCRE,
+	"ruby":       DialectRuby,
+}
+
+// languageOptions returns the options ValidateForLanguage uses for a language.
+func languageOptions(language string) (*Options, error) {
+	dialect, ok := languageDialects[strings.ToLower(language)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedLanguage, language)
+	}
+
+	opts := DefaultOptions()
+	opts.Dialect = dialect
+	opts.StrictMode = dialect.Backtracking()
+	return opts, nil
+}
+
+// Validator validates patterns with a fixed set of options.
+// Create one with NewValidator and reuse it; it is safe for concurrent use.
+type Validator struct {
+	opts  *Options
+	pool  sync.Pool    // of *validator
+	cache *resultCache // nil if Options.CacheSize is 0
+
+	denyOnce sync.Once
+	denied   denyList
+	denyErr  error
+}
+
+// NewValidator creates a reusable validator with the given options.
+// A nil opts uses DefaultOptions. The options must not be modified
+// after the validator is created.
+//
+// Example:
+//
+//	v := regret.NewValidator(regret.FastOptions())
+//	issues, err := v.Validate(userPattern)
+func NewValidator(opts *Options) *Validator {
 	if opts == nil {
 		opts = DefaultOptions()
 	}
 
-	// Handle passthrough mode
-	if opts.AllowUnsafe {
-		return []Issue{}, nil
+	v := &Validator{opts: opts}
+	if opts.CacheSize > 0 {
+		v.cache = newResultCache(opts.CacheSize)
+	}
+	v.pool.New = func() interface{} {
+		return newValidator(opts)
+	}
+	return v
+}
+
+// NewTimeoutValidator creates a reusable validator with DefaultOptions and
+// the given analysis timeout, the most common customization.
+//
+// Example:
+//
+//	v := regret.NewTimeoutValidator(50 * time.Millisecond)
+//	if !v.IsSafe(userPattern) {
+//	    return errors.New("unsafe regex pattern")
+//	}
+func NewTimeoutValidator(d time.Duration) *Validator {
+	opts := DefaultOptions()
+	opts.Timeout = d
+	return NewValidator(opts)
+}
+
+// Options returns the options the validator was created with.
+func (v *Validator) Options() *Options {
+	return v.opts
+}
+
+// Validate analyzes a regex pattern and returns all detected issues.
+// It behaves like ValidateWithOptions with the validator's options.
+func (v *Validator) Validate(pattern string) ([]Issue, error) {
+	decoded, err := decodePattern(pattern, v.opts)
+	if err != nil {
+		return nil, err
+	}
+	issues, err := v.validate(decoded)
+	return issues, encodedError(err, pattern, decoded)
+}
+
+// validate is Validate for a decoded pattern.
+func (v *Validator) validate(pattern string) ([]Issue, error) {
+	if issues, done, err := v.screen(pattern); done {
+		return issues, err
 	}
 
 	// Check pattern length
-	if opts.MaxPatternLength > 0 && len(pattern) > opts.MaxPatternLength {
-		return nil, fmt.Errorf("%w: %d > %d", ErrPatternTooLong, len(pattern), opts.MaxPatternLength)
+	if v.opts.MaxPatternLength > 0 && len(pattern) > v.opts.MaxPatternLength {
+		return nil, fmt.Errorf("%w: %d > %d", ErrPatternTooLong, len(pattern), v.opts.MaxPatternLength)
+	}
+
+	if v.cache != nil {
+		if issues, ok := v.cache.get(pattern); ok {
+			return issues, nil
+		}
 	}
 
-	// Create validator
-	v := newValidator(opts)
-	return v.validate(pattern)
+	impl := v.pool.Get().(*validator)
+	defer v.pool.Put(impl)
+	issues, err := impl.validate(pattern)
+	issues = finishIssues(issues, pattern)
+	if v.cache != nil && err == nil && !impl.timedOut {
+		v.cache.add(pattern, issues)
+	}
+	return issues, err
+}
+
+// CacheLen returns the number of results in the validator's cache, which
+// holds up to Options.CacheSize results. It is 0 without a cache.
+func (v *Validator) CacheLen() int {
+	if v.cache == nil {
+		return 0
+	}
+	return v.cache.len()
+}
+
+// IsSafe reports whether pattern is valid and Validate finds no issues in
+// it with the validator's options. A pattern whose analysis times out is
+// not safe, whatever the validator's TimeoutBehavior.
+func (v *Validator) IsSafe(pattern string) bool {
+	issues, err := v.Validate(pattern)
+	return err == nil && len(issues) == 0
+}
+
+// screen applies the policies that decide the result of Validate before
+// any analysis. done is false if the pattern needs to be analyzed.
+func (v *Validator) screen(pattern string) (issues []Issue, done bool, err error) {
+	// Policy: denied patterns are rejected before any analysis,
+	// even in passthrough mode
+	denied, err := v.denyList()
+	if err != nil {
+		return nil, true, err
+	}
+	if issues := denied.check(pattern); issues != nil {
+		return finishIssues(issues, pattern), true, nil
+	}
+
+	// Policy: allowed unsafe patterns are let through with a reminder
+	if slices.Contains(v.opts.AllowUnsafePatterns, pattern) {
+		return finishIssues(allowedUnsafe(pattern), pattern), true, nil
+	}
+
+	// Handle passthrough mode
+	if v.opts.AllowUnsafe {
+		return []Issue{}, true, nil
+	}
+
+	return nil, false, nil
+}
+
+// denyList returns the validator's deny list, reading Options.DenyListFile
+// on first use.
+func (v *Validator) denyList() (denyList, error) {
+	v.denyOnce.Do(func() {
+		v.denied, v.denyErr = loadDenyList(v.opts)
+	})
+	return v.denied, v.denyErr
+}
+
+// validateParsed is like Validate for a pattern already parsed into re, so
+// that Report can share the AST with the complexity analysis. The caller
+// applies screen first.
+func (v *Validator) validateParsed(re *syntax.Regexp, pattern string) ([]Issue, error) {
+	impl := v.pool.Get().(*validator)
+	defer v.pool.Put(impl)
+	issues, err := impl.detectParsed(re, pattern)
+	return finishIssues(issues, pattern), err
+}
+
+// AnalyzeComplexity performs detailed complexity analysis on a regex pattern
+// using the validator's options instead of ThoroughOptions. The issues
+// Validate reports are included in ComplexityScore.Issues.
+func (v *Validator) AnalyzeComplexity(pattern string) (*ComplexityScore, error) {
+	report, err := v.Report(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return report.Complexity, nil
 }
 
-// AnalyzeComplexity performs detailed complexity analysis on a regex pattern.
+// AnalyzeComplexityWithOptions performs detailed complexity analysis on a
+// regex pattern.
 //
 // This function provides comprehensive information including:
 //   - Complexity score (0-100)
@@ -111,12 +367,17 @@ func ValidateWithOptions(pattern string, opts *Options) ([]Issue, error) {
 //   - Polynomial degree (if applicable)
 //   - Detailed metrics
 //   - Adversarial input examples
+//   - The issues ValidateWithOptions reports with the same options, so
+//     callers do not need a separate Validate call
 //
-// Uses Thorough mode for complete analysis.
+// Patterns longer than opts.MaxPatternLength are rejected with
+// ErrPatternTooLong before they are parsed. If opts is nil,
+// DefaultOptions() is used; ThoroughOptions() gives the most complete
+// analysis.
 //
 // Example:
 //
-//	score, err := regret.AnalyzeComplexity("(a+)+")
+//	score, err := regret.AnalyzeComplexityWithOptions("(a+)+", regret.ThoroughOptions())
 //	if err != nil {
 //	    return err
 //	}
@@ -125,105 +386,273 @@ func ValidateWithOptions(pattern string, opts *Options) ([]Issue, error) {
 //	    fmt.Printf("Exponential backtracking detected!\n")
 //	    fmt.Printf("Worst-case input: %s\n", score.WorstCaseInput)
 //	}
-func AnalyzeComplexity(pattern string) (*ComplexityScore, error) {
-	opts := ThoroughOptions()
-	opts.Checks = CheckAll
+func AnalyzeComplexityWithOptions(pattern string, opts *Options) (*ComplexityScore, error) {
+	return NewValidator(opts).AnalyzeComplexity(pattern)
+}
 
-	// Create analyzer
-	a := newAnalyzer(opts)
-	return a.analyze(pattern)
+// AnalyzeComplexity performs detailed complexity analysis on a regex pattern
+// with ThoroughOptions.
+//
+// Deprecated: Use AnalyzeComplexityWithOptions, which takes the options,
+// including the MaxPatternLength limit, to analyze with.
+func AnalyzeComplexity(pattern string) (*ComplexityScore, error) {
+	return AnalyzeComplexityWithOptions(pattern, ThoroughOptions())
 }
 
 // validator is the internal validator implementation.
 type validator struct {
-	opts   *Options
-	parser *parser.Parser
-	detect *detector.Detector
+	opts     *Options
+	parser   *parser.Parser
+	detect   *detector.Detector
+	timedOut bool // The last detectParsed call was cut short by the timeout
 }
 
 func newValidator(opts *Options) *validator {
+	checks := opts.Checks
+	if checks == 0 {
+		checks = CheckDefault
+	}
+
 	// Convert public options to internal detector options
 	detectorOpts := &detector.Options{
-		Mode:   detector.ValidationMode(opts.Mode),
-		Checks: uint32(opts.Checks),
+		Mode:                   detector.ValidationMode(opts.Mode),
+		Checks:                 uint32(checks),
+		MaxQuantifierRange:     opts.MaxQuantifierRange,
+		MaxRepetitionCount:     opts.MaxRepetitionCount,
+		MaxAlternationBranches: opts.MaxAlternationBranches,
+		MaxNFAStates:           opts.MaxNFAStates,
+		MaxDFAStates:           opts.MaxDFAStates,
+		Timeout:                opts.Timeout,
+		Backreferences:         opts.Dialect != DialectRE2,
+		ParseFlags:             parseFlags(opts),
+
+		EnableExperimentalChecks: opts.EnableExperimentalChecks,
 	}
 
 	return &validator{
 		opts:   opts,
-		parser: parser.NewParser(),
+		parser: parser.NewParserWithFlags(parseFlags(opts)),
 		detect: detector.NewDetector(detectorOpts),
 	}
 }
 
 func (v *validator) validate(pattern string) ([]Issue, error) {
 	// Parse the pattern
-	re, err := v.parser.Parse(pattern)
+	re, err := v.parser.Parse(expandBackreferences(pattern, v.opts))
 	if err != nil {
 		return nil, err
 	}
 
+	return v.detectParsed(re, pattern)
+}
+
+// detectParsed runs the checks on a pattern already parsed into re.
+func (v *validator) detectParsed(re *syntax.Regexp, pattern string) ([]Issue, error) {
 	// Run detection based on mode
-	internalIssues, err := v.detect.Detect(re, pattern)
+	ctx, cancel := analysisContext(v.opts)
+	defer cancel()
+
+	internalIssues, err := v.detect.DetectContext(ctx, re, pattern)
+	v.timedOut = errors.Is(err, context.DeadlineExceeded)
+	if v.timedOut {
+		partial := promoteWarnings(convertIssues(internalIssues, v.opts.SeverityOverride), v.opts)
+		return timedOut(v.opts, limitIssues(partial, v.opts, pattern), pattern)
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	// Convert internal issues to public issues
-	return convertIssues(internalIssues), nil
+	issues := convertIssues(internalIssues, v.opts.SeverityOverride)
+
+	// Run user-registered checks
+	if v.opts.Checks&CheckCustomPlugins != 0 {
+		issues = append(issues, overrideSeverities(runPlugins(re, pattern), v.opts.SeverityOverride)...)
+	}
+
+	return limitIssues(promoteWarnings(issues, v.opts), v.opts, pattern), nil
+}
+
+// parseFlags returns the flags to parse patterns with, which are
+// opts.ParseFlags or syntax.Perl if they are not set.
+func parseFlags(opts *Options) syntax.Flags {
+	if opts.ParseFlags == 0 {
+		return syntax.Perl
+	}
+	return opts.ParseFlags
+}
+
+// expandBackreferences returns pattern with its backreferences replaced by
+// copies of the groups they refer to, so that Go's syntax can parse it, for
+// dialects that support backreferences. RE2 patterns are returned as is.
+func expandBackreferences(pattern string, opts *Options) string {
+	if opts.Dialect == DialectRE2 {
+		return pattern
+	}
+	return parser.ExpandBackreferences(pattern)
+}
+
+// analysisContext returns a context that expires after opts.Timeout,
+// or never if no timeout is set.
+func analysisContext(opts *Options) (context.Context, context.CancelFunc) {
+	if opts.Timeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), opts.Timeout)
+}
+
+// timedOut applies opts.TimeoutBehavior to the issues found before a timeout.
+// Partial results end with an Info issue saying so, which keeps a pattern
+// whose analysis timed out before finding anything from looking safe.
+func timedOut(opts *Options, partial []Issue, pattern string) ([]Issue, error) {
+	switch opts.TimeoutBehavior {
+	case TimeoutReturnPartial:
+		return append(partial, Issue{
+			Type:       ComplexityThresholdExceeded,
+			Severity:   Info,
+			Position:   Position{Start: 0, End: len(pattern)},
+			Pattern:    pattern,
+			Message:    "analysis timed out, results are partial",
+			Suggestion: "Increase Options.Timeout for complete results",
+			Details:    map[string]interface{}{"timeout": opts.Timeout.String()},
+			References: References(ComplexityThresholdExceeded),
+		}), nil
+	case TimeoutMarkUnsafe:
+		return []Issue{{
+			Type:       ComplexityThresholdExceeded,
+			Severity:   Critical,
+			Position:   Position{Start: 0, End: len(pattern)},
+			Pattern:    pattern,
+			Message:    "analysis timed out, treating as unsafe",
+			Suggestion: "Simplify the pattern or increase Options.Timeout",
+			Details:    make(map[string]interface{}),
+			References: References(ComplexityThresholdExceeded),
+		}}, nil
+	default:
+		return nil, fmt.Errorf("%w after %v", ErrTimeout, opts.Timeout)
+	}
 }
 
-// convertIssues converts internal detector issues to public API issues.
-func convertIssues(internal []detector.Issue) []Issue {
+// convertIssues converts internal detector issues to public API issues and
+// applies Options.SeverityOverride.
+func convertIssues(internal []detector.Issue, overrides map[IssueType]Severity) []Issue {
 	issues := make([]Issue, len(internal))
 	for i, iss := range internal {
 		issues[i] = convertIssue(iss)
 	}
+	return overrideSeverities(issues, overrides)
+}
+
+// overrideSeverities sets the severity of each issue whose type is in
+// overrides.
+func overrideSeverities(issues []Issue, overrides map[IssueType]Severity) []Issue {
+	for i := range issues {
+		if sev, ok := overrides[issues[i].Type]; ok {
+			issues[i].Severity = sev
+		}
+	}
+	return issues
+}
+
+// promoteWarnings raises Low and Info issues to High if
+// opts.TreatWarningsAsErrors is set.
+func promoteWarnings(issues []Issue, opts *Options) []Issue {
+	if !opts.TreatWarningsAsErrors {
+		return issues
+	}
+	for i := range issues {
+		if issues[i].Severity == Low || issues[i].Severity == Info {
+			issues[i].Severity = High
+		}
+	}
 	return issues
 }
 
-// convertIssue converts a single internal detector issue to public API issue.
+// limitIssues keeps the opts.MaxIssues most severe issues, in their
+// original order among equal severities, and appends an Info issue noting
+// that the rest were dropped. Critical issues are never dropped, so more
+// than opts.MaxIssues issues are kept if there are more Critical ones.
+func limitIssues(issues []Issue, opts *Options, pattern string) []Issue {
+	if opts.MaxIssues <= 0 {
+		return issues
+	}
+	critical := 0
+	for _, issue := range issues {
+		if issue.Severity == Critical {
+			critical++
+		}
+	}
+	limit := max(opts.MaxIssues, critical)
+	if len(issues) <= limit {
+		return issues
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Severity < issues[j].Severity
+	})
+	return append(issues[:limit:limit], Issue{
+		Type:       ComplexityThresholdExceeded,
+		Severity:   Info,
+		Position:   Position{Start: 0, End: len(pattern)},
+		Pattern:    pattern,
+		Message:    fmt.Sprintf("analysis stopped at %d issues limit, %d more not reported", opts.MaxIssues, len(issues)-limit),
+		Suggestion: "Simplify the pattern or increase Options.MaxIssues",
+		Details:    map[string]interface{}{"max_issues": opts.MaxIssues, "total_issues": len(issues)},
+		References: References(ComplexityThresholdExceeded),
+	})
+}
+
+// finishIssues completes the issues Validate returns for pattern, whether
+// they come from the detector, a plugin or a policy: it sets the line and
+// column of the start of each issue, then applies the issue templates,
+// which may refer to them.
+func finishIssues(issues []Issue, pattern string) []Issue {
+	for i := range issues {
+		pos := &issues[i].Position
+		pos.Line, pos.Column = parser.ComputeLineColumn(pattern, pos.Start)
+	}
+	return applyIssueTemplates(issues)
+}
+
+// convertIssue converts a single internal detector issue to public API
+// issue. finishIssues sets its line and column.
 func convertIssue(iss detector.Issue) Issue {
+	issueType := issueTypeFromString(iss.Type)
+	details := make(map[string]interface{}, len(iss.Details))
+	for k, v := range iss.Details {
+		details[k] = v
+	}
 	return Issue{
-		Type:       issueTypeFromString(iss.Type),
-		Severity:   severityFromString(iss.Severity),
-		Position:   Position{Start: iss.Position.Start, End: iss.Position.End, Line: iss.Position.Line, Column: iss.Position.Column},
-		Pattern:    iss.Pattern,
-		Message:    iss.Message,
-		Example:    iss.Example,
-		Suggestion: iss.Suggestion,
-		Complexity: iss.Complexity,
-		Details:    make(map[string]interface{}),
+		Type:            issueType,
+		Severity:        severityFromString(iss.Severity),
+		Position:        Position{Start: iss.Position.Start, End: iss.Position.End},
+		Pattern:         iss.Pattern,
+		Message:         iss.Message,
+		Example:         iss.Example,
+		Suggestion:      iss.Suggestion,
+		Complexity:      iss.Complexity,
+		Details:         details,
+		References:      References(issueType),
+		RelatedPatterns: relatedPatterns(iss.Pattern),
 	}
 }
 
+// issueTypeFromString maps internal detector issue types to public ones.
+// Detector types without a public counterpart become AmbiguousPattern.
 func issueTypeFromString(s string) IssueType {
-	switch s {
-	case "nested_quantifiers":
-		return NestedQuantifiers
-	case "overlapping_alternation":
-		return OverlappingAlternation
-	case "exponential_backtracking":
-		return ExponentialBacktracking
-	case "polynomial_backtracking":
-		return PolynomialBacktracking
-	default:
-		return AmbiguousPattern
+	if t, err := IssueTypeFromString(s); err == nil {
+		return t
 	}
+	return AmbiguousPattern
 }
 
+// severityFromString maps internal detector severities to public ones,
+// defaulting to Info.
 func severityFromString(s string) Severity {
-	switch s {
-	case "critical":
-		return Critical
-	case "high":
-		return High
-	case "medium":
-		return Medium
-	case "low":
-		return Low
-	default:
-		return Info
+	if sev, err := SeverityFromString(s); err == nil {
+		return sev
 	}
+	return Info
 }
 
 // anlz wraps the internal analyzer.
@@ -237,25 +666,29 @@ func newAnalyzer(opts *Options) *anlz {
 	analyzerOpts := &analyzer.Options{
 		Timeout:            opts.Timeout,
 		MaxComplexityScore: opts.MaxComplexityScore,
+		MaxNFAStates:       opts.MaxNFAStates,
+		MinimizeDFA:        opts.Checks == CheckAll,
 	}
 
 	return &anlz{
 		opts:   opts,
 		impl:   analyzer.NewAnalyzer(analyzerOpts),
-		parser: parser.NewParser(),
+		parser: parser.NewParserWithFlags(parseFlags(opts)),
 	}
 }
 
-func (a *anlz) analyze(pattern string) (*ComplexityScore, error) {
-	// Parse pattern
-	re, err := a.parser.Parse(pattern)
-	if err != nil {
-		return nil, err
-	}
-
+// analyze analyzes a pattern already parsed into re.
+func (a *anlz) analyze(re *syntax.Regexp, pattern string) (*ComplexityScore, error) {
 	// Analyze complexity
-	result, err := a.impl.Analyze(re, pattern)
-	if err != nil {
+	ctx, cancel := analysisContext(a.opts)
+	defer cancel()
+
+	result, err := a.impl.AnalyzeContext(ctx, re, pattern)
+	timeout := errors.Is(err, context.DeadlineExceeded)
+	if timeout && a.opts.TimeoutBehavior == TimeoutError {
+		return nil, fmt.Errorf("%w after %v", ErrTimeout, a.opts.Timeout)
+	}
+	if err != nil && !timeout {
 		return nil, err
 	}
 
@@ -264,14 +697,16 @@ func (a *anlz) analyze(pattern string) (*ComplexityScore, error) {
 
 	// Generate pump pattern for adversarial testing
 	var pumpComponents []string
+	var pumpSizes []int
 	var worstCaseInput string
 
 	// Only generate pump pattern if the pattern is potentially unsafe
 	if result.Score >= 50 {
 		pumpGen := newPumpGenerator(a.opts)
-		pump, err := pumpGen.generate(pattern)
+		pump, err := pumpGen.generate(pattern, result.TimeClass == "exponential")
 		if err == nil && pump != nil {
 			pumpComponents = pump.Pumps
+			pumpSizes = pump.Sizes
 			// Generate a worst-case input with moderate pump size
 			// Use first pump size if available, otherwise default to 20
 			pumpSize := 20
@@ -282,30 +717,105 @@ func (a *anlz) analyze(pattern string) (*ComplexityScore, error) {
 		// Silently ignore pump generation errors - it's supplementary information
 	}
 
-	return &ComplexityScore{
-		Overall:          result.Score,
-		TimeComplexity:   complexity,
-		SpaceComplexity:  Linear, // TODO: implement space complexity analysis
-		HasEDA:           result.TimeClass == "exponential",
-		HasIDA:           result.TimeClass == "polynomial",
-		PolynomialDegree: result.Degree,
+	score := &ComplexityScore{
+		Overall:             result.Score,
+		TimeComplexity:      complexity,
+		SpaceComplexity:     Linear, // TODO: implement space complexity analysis
+		HasEDA:              result.TimeClass == "exponential",
+		HasIDA:              result.TimeClass == "polynomial",
+		PolynomialDegree:    result.Degree,
+		ExploitabilityScore: exploitabilityScore(re),
+		NormalizedScore:     normalizedScore(result.Score, pattern),
 		Metrics: Metrics{
-			NestingDepth:     getMetricInt(result.Metrics, "nesting_depth"),
-			QuantifierCount:  getMetricInt(result.Metrics, "quantifier_count"),
-			AlternationCount: getMetricInt(result.Metrics, "alternations"),
+			NestingDepth:        getMetricInt(result.Metrics, "nesting_depth"),
+			QuantifierCount:     getMetricInt(result.Metrics, "quantifier_count"),
+			AlternationCount:    getMetricInt(result.Metrics, "alternations"),
+			MaxPathLength:       getMetricInt(result.Metrics, "max_path_length"),
+			BranchCount:         getMetricInt(result.Metrics, "branch_count"),
+			MaxBacktrackDepth:   getMetricInt(result.Metrics, "max_backtrack_depth"),
+			MinimizedStateCount: getMetricInt(result.Metrics, "minimized_state_count"),
 		},
+		Breakdown:      convertBreakdown(result.Breakdown),
 		WorstCaseInput: worstCaseInput,
 		PumpPattern:    pumpComponents,
+		PumpSizes:      pumpSizes,
 		Explanation:    result.Description,
-		Safe:           result.Score < 50,
-	}, nil
+		Partial:        result.Partial,
+		Completeness:   result.Completeness,
+	}
+	score.Safe = !score.HasEDA && !score.HasIDA && score.Overall < a.maxComplexityScore()
+	if !score.Safe {
+		score.AlternativeSuggestions = a.alternatives(re, result.Score)
+	}
+
+	if timeout && a.opts.TimeoutBehavior == TimeoutMarkUnsafe {
+		score.Safe = false
+		score.Explanation = "analysis timed out, treating as unsafe"
+	}
+
+	return score, nil
 }
 
-// pumpGen wraps the internal pump generator.
+// exploitabilityScore computes ComplexityScore.ExploitabilityScore.
+func exploitabilityScore(re *syntax.Regexp) float64 {
+	body := parser.InnermostQuantifiedBody(re)
+	if body == nil {
+		return 0
+	}
+	switch size := parser.CharsetSize(body); size {
+	case 0:
+		return 0
+	case 1:
+		return 1
+	default:
+		return math.Min(1, 1/math.Log(float64(size)))
+	}
+}
+
+// normalizedScore computes ComplexityScore.NormalizedScore.
+func normalizedScore(overall int, pattern string) float64 {
+	if pattern == "" {
+		return 0
+	}
+	return float64(overall) / math.Log(float64(len(pattern)+1))
+}
+
+// maxComplexityScore returns the score threshold for ComplexityScore.Safe,
+// using the default when the options leave it unset and lowered by
+// Options.EnableSafetyMargin.
+func (a *anlz) maxComplexityScore() int {
+	limit := a.opts.MaxComplexityScore
+	if limit <= 0 {
+		limit = DefaultOptions().MaxComplexityScore
+	}
+	return effectiveThreshold(limit, a.opts.EnableSafetyMargin)
+}
+
+// effectiveThreshold returns limit lowered by the fraction margin, rounded
+// to the nearest integer.
+func effectiveThreshold(limit int, margin float64) int {
+	if margin <= 0 {
+		return limit
+	}
+	return int(math.Round(float64(limit) * (1 - margin)))
+}
+
+func convertBreakdown(subs []analyzer.SubScore) []SubScore {
+	breakdown := make([]SubScore, len(subs))
+	for i, sub := range subs {
+		breakdown[i] = SubScore{Name: sub.Name, Score: sub.Score, Description: sub.Description}
+	}
+	return breakdown
+}
+
+// pumpGen wraps the internal pump generators: one suggesting sizes that
+// grow by a fixed step, and one suggesting doubling sizes for exponential
+// patterns.
 type pumpGen struct {
-	opts   *Options
-	impl   *pump.Generator
-	parser *parser.Parser
+	opts      *Options
+	impl      *pump.Generator
+	geometric *pump.Generator
+	parser    *parser.Parser
 }
 
 func newPumpGenerator(opts *Options) *pumpGen {
@@ -314,15 +824,21 @@ func newPumpGenerator(opts *Options) *pumpGen {
 		MaxPumpSize:    100,
 		IncludeFailure: true,
 	}
+	geometricOpts := *pumpOpts
+	geometricOpts.UseGeometricGrowth = true
 
 	return &pumpGen{
-		opts:   opts,
-		impl:   pump.NewGenerator(pumpOpts),
-		parser: parser.NewParser(),
+		opts:      opts,
+		impl:      pump.NewGenerator(pumpOpts),
+		geometric: pump.NewGenerator(&geometricOpts),
+		parser:    parser.NewParserWithFlags(parseFlags(opts)),
 	}
 }
 
-func (g *pumpGen) generate(pattern string) (*PumpPattern, error) {
+// generate returns the most relevant pump pattern for pattern. Its sizes
+// double if exponential is set, to show the exponential growth of the
+// match time.
+func (g *pumpGen) generate(pattern string, exponential bool) (*PumpPattern, error) {
 	// Parse pattern
 	re, err := g.parser.Parse(pattern)
 	if err != nil {
@@ -330,7 +846,11 @@ func (g *pumpGen) generate(pattern string) (*PumpPattern, error) {
 	}
 
 	// Generate pump patterns
-	results, err := g.impl.Generate(re, pattern)
+	impl := g.impl
+	if exponential {
+		impl = g.geometric
+	}
+	results, err := impl.Generate(re, pattern)
 	if err != nil {
 		return nil, err
 	}
@@ -351,6 +871,7 @@ func (g *pumpGen) generate(pattern string) (*PumpPattern, error) {
 		Suffix:      result.FailSuffix,
 		Interleave:  false,
 		Description: result.Description,
+		Sizes:       result.Sizes,
 	}, nil
 }
 
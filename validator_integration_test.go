@@ -1,7 +1,15 @@
 package regret
 
 import (
+	"errors"
+	"fmt"
+	"regexp/syntax"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/theakshaypant/regret/internal/parser"
 )
 
 // Integration tests to verify the public API works with internal detector
@@ -77,6 +85,7 @@ func TestIsSafe_RealWorldPatterns(t *testing.T) {
 		{"email simple", `^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`, true},
 		{"url simple", `^https?://[a-z0-9.-]+\.[a-z]{2,}/[a-z0-9-]+$`, true}, // Simplified to avoid false positive
 		{"phone number", `^\d{3}-\d{3}-\d{4}$`, true},
+		{"sha512 hex digest", `^[0-9a-f]{128}$`, true},
 		{"evil nested", `(a+)+b`, false},
 		{"evil overlapping", `a*a*a*`, false},
 		{"greedy dots", `.*.*.`, false},
@@ -99,6 +108,29 @@ func TestIsSafe_RealWorldPatterns(t *testing.T) {
 	}
 }
 
+func TestCheckPattern(t *testing.T) {
+	if err := CheckPattern(`^\d{3}-\d{4}$`); err != nil {
+		t.Errorf("CheckPattern(safe) = %v, want nil", err)
+	}
+
+	err := CheckPattern("(a+)+b")
+	if !errors.Is(err, ErrUnsafePattern) {
+		t.Fatalf("CheckPattern((a+)+b) = %v, want ErrUnsafePattern", err)
+	}
+	issues, _ := ValidateWithOptions("(a+)+b", FastOptions())
+	worst, _ := IssueSet(issues).Worst()
+	if !strings.Contains(err.Error(), worst.Message) {
+		t.Errorf("CheckPattern() error = %q, want it to contain %q", err, worst.Message)
+	}
+	if wrapped := fmt.Errorf("invalid filter: %w", err); !errors.Is(wrapped, ErrUnsafePattern) {
+		t.Errorf("wrapped error %v does not match ErrUnsafePattern", wrapped)
+	}
+
+	if err := CheckPattern("(abc"); err == nil || errors.Is(err, ErrUnsafePattern) {
+		t.Errorf("CheckPattern(invalid) = %v, want a parse error", err)
+	}
+}
+
 func TestValidate_IssueDetails(t *testing.T) {
 	pattern := "(a+)+"
 
@@ -162,6 +194,336 @@ func TestValidate_ValidationModes(t *testing.T) {
 	}
 }
 
+func TestValidate_MaxQuantifierRange(t *testing.T) {
+	// The default limit is Go's own repetition limit of 1000
+	opts := DefaultOptions()
+	opts.MaxIssues = 0 // \d{0,1000} expands into far more issues than the default limit
+	opts.Timeout = time.Minute
+
+	issues, err := ValidateWithOptions(`^\d{0,1000}$`, opts)
+	if err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v", err)
+	}
+
+	found := false
+	for _, issue := range issues {
+		if issue.Type == LargeQuantifierRange {
+			found = true
+			if issue.Severity != Medium {
+				t.Errorf("LargeQuantifierRange severity = %v, want %v", issue.Severity, Medium)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("expected LargeQuantifierRange issue, got %v", issues)
+	}
+
+	issues, err = ValidateWithOptions(`^\d{1,1000}$`, opts)
+	if err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v", err)
+	}
+	for _, issue := range issues {
+		if issue.Type == LargeQuantifierRange {
+			t.Errorf("unexpected LargeQuantifierRange issue within the default limit: %v", issue)
+		}
+	}
+}
+
+func TestValidate_TreatWarningsAsErrors(t *testing.T) {
+	tests := []struct {
+		override Severity
+		promote  bool
+		want     Severity
+	}{
+		{Info, false, Info},
+		{Info, true, High},
+		{Low, true, High},
+		{Medium, true, Medium},
+		{Critical, true, Critical},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v/%v", tt.override, tt.promote), func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.SeverityOverride = map[IssueType]Severity{NestedQuantifiers: tt.override}
+			opts.TreatWarningsAsErrors = tt.promote
+
+			issues, err := ValidateWithOptions("(a+)+", opts)
+			if err != nil {
+				t.Fatalf("ValidateWithOptions() error = %v", err)
+			}
+			found := false
+			for _, issue := range issues {
+				if issue.Type == NestedQuantifiers {
+					found = true
+					if issue.Severity != tt.want {
+						t.Errorf("Severity = %v, want %v", issue.Severity, tt.want)
+					}
+				}
+			}
+			if !found {
+				t.Fatal("no NestedQuantifiers issue reported")
+			}
+		})
+	}
+}
+
+func TestValidate_ParseFlags(t *testing.T) {
+	hasType := func(issues []Issue, typ IssueType) bool {
+		for _, issue := range issues {
+			if issue.Type == typ {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Case folding makes the branches of the alternation overlap
+	pattern := "^(?:[a-z]+|[A-Z]+)+$"
+	opts := DefaultOptions()
+	issues, err := ValidateWithOptions(pattern, opts)
+	if err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v", err)
+	}
+	if hasType(issues, OverlappingAlternation) {
+		t.Errorf("unexpected OverlappingAlternation issue without FoldCase")
+	}
+
+	opts.ParseFlags = syntax.Perl | syntax.FoldCase
+	issues, err = ValidateWithOptions(pattern, opts)
+	if err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v", err)
+	}
+	if !hasType(issues, OverlappingAlternation) {
+		t.Errorf("expected OverlappingAlternation issue with FoldCase, got %v", issues)
+	}
+
+	opts.ParseFlags = syntax.POSIX | syntax.OneLine
+	if _, err := ValidateWithOptions(`\d+`, opts); err == nil {
+		t.Error("ValidateWithOptions() expected error for a Perl class in POSIX syntax")
+	}
+}
+
+func TestValidate_MaxIssues(t *testing.T) {
+	pattern := "(a+)+(b+)+(c+)+"
+	opts := DefaultOptions()
+	opts.SeverityOverride = map[IssueType]Severity{NestedQuantifiers: Low}
+
+	opts.MaxIssues = 0
+	all, err := ValidateWithOptions(pattern, opts)
+	if err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v", err)
+	}
+	if len(all) <= 2 {
+		t.Fatalf("ValidateWithOptions() = %d issues, want more than 2", len(all))
+	}
+
+	opts.MaxIssues = 2
+	issues, err := ValidateWithOptions(pattern, opts)
+	if err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v", err)
+	}
+	if len(issues) != 3 {
+		t.Fatalf("ValidateWithOptions() = %d issues, want 2 and a limit notice", len(issues))
+	}
+	if issues[0].Severity != Critical || issues[1].Severity != High {
+		t.Errorf("kept severities = %v, %v, want the most severe issues", issues[0].Severity, issues[1].Severity)
+	}
+	last := issues[2]
+	if last.Type != ComplexityThresholdExceeded || last.Severity != Info ||
+		!strings.Contains(last.Message, "stopped at 2 issues limit") {
+		t.Errorf("last issue = %+v, want an Info limit notice", last)
+	}
+
+	// Critical issues are kept even beyond the limit
+	opts.SeverityOverride = nil
+	opts.MaxIssues = 0
+	all, err = ValidateWithOptions(pattern, opts)
+	if err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v", err)
+	}
+	critical := 0
+	for _, issue := range all {
+		if issue.Severity == Critical {
+			critical++
+		}
+	}
+	if critical < 2 || critical == len(all) {
+		t.Fatalf("ValidateWithOptions() = %v, want several Critical issues and others", all)
+	}
+	opts.MaxIssues = 1
+	issues, err = ValidateWithOptions(pattern, opts)
+	if err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v", err)
+	}
+	if len(issues) != critical+1 {
+		t.Errorf("ValidateWithOptions() = %d issues, want the %d Critical ones and a limit notice", len(issues), critical)
+	}
+	for _, issue := range issues[:len(issues)-1] {
+		if issue.Severity != Critical {
+			t.Errorf("kept %v issue %s, want only Critical ones", issue.Severity, issue.Type)
+		}
+	}
+}
+
+func TestValidate_LineColumn(t *testing.T) {
+	issues, err := Validate("^abc\n(a+)+$")
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	var nested *Issue
+	for i := range issues {
+		if issues[i].Type == NestedQuantifiers {
+			nested = &issues[i]
+		}
+	}
+	if nested == nil {
+		t.Fatalf("Validate() = %+v, want a NestedQuantifiers issue", issues)
+	}
+	if nested.Position.Start != 5 || nested.Position.Line != 2 || nested.Position.Column != 1 {
+		t.Errorf("Position = %+v, want (a+)+ at line 2, column 1", nested.Position)
+	}
+}
+
+func TestValidate_LineColumnOfEveryIssue(t *testing.T) {
+	RegisterPlugin(cardNumberPlugin{})
+	defer UnregisterPlugin(cardNumberPlugin{}.Name())
+
+	pattern := "^card:\n\\d{16}(a+)+$"
+	tests := []struct {
+		name string
+		opts func(*Options)
+	}{
+		{"detector and plugin", func(o *Options) { o.Checks = CheckAll }},
+		{"deny list", func(o *Options) { o.DenyList = []string{pattern} }},
+		{"allowed unsafe", func(o *Options) { o.AllowUnsafePatterns = []string{pattern} }},
+		{"issue limit", func(o *Options) { o.MaxIssues = 1; o.Checks = CheckAll }},
+		{"timeout", func(o *Options) { o.Timeout = time.Nanosecond }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			tt.opts(opts)
+			issues, err := ValidateWithOptions(pattern, opts)
+			if err != nil {
+				t.Fatalf("ValidateWithOptions() error = %v", err)
+			}
+			if len(issues) == 0 {
+				t.Fatal("ValidateWithOptions() = no issues")
+			}
+			for _, issue := range issues {
+				line, column := parser.ComputeLineColumn(pattern, issue.Position.Start)
+				if issue.Position.Line != line || issue.Position.Column != column {
+					t.Errorf("%s issue %q at %+v, want line %d, column %d", issue.Type, issue.Message, issue.Position, line, column)
+				}
+			}
+		})
+	}
+}
+
+func TestValidate_MaxRepetitionCount(t *testing.T) {
+	// The default limit is Go's own repetition limit of 1000
+	opts := DefaultOptions()
+	opts.MaxIssues = 0 // a{1000} expands into far more issues than the default limit
+	// The expanded repetitions make the other checks slow; keep them out
+	// of the timeout so that the result does not depend on the load
+	opts.Timeout = time.Minute
+
+	tests := []struct {
+		pattern    string
+		want       bool
+		suggestion string // Expected in the issue's suggestion
+	}{
+		{`^a{1000}$`, true, "Lower the count"},
+		{`^a{0,1000}$`, true, "* or +"},
+		{`^a{1000,}$`, true, "* or +"},
+		{`^a{0,999}$`, false, ""},
+		{`^[0-9a-f]{128}$`, false, ""},
+		{`^a+$`, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			issues, err := ValidateWithOptions(tt.pattern, opts)
+			if err != nil {
+				t.Fatalf("ValidateWithOptions() error = %v", err)
+			}
+
+			found := false
+			for _, issue := range issues {
+				if issue.Type == UnboundedRepetition && issue.Severity == Medium {
+					found = true
+					if !strings.Contains(issue.Suggestion, tt.suggestion) {
+						t.Errorf("Suggestion = %q, want it to contain %q", issue.Suggestion, tt.suggestion)
+					}
+				}
+			}
+			if found != tt.want {
+				t.Errorf("found Medium UnboundedRepetition = %v, want %v: %v", found, tt.want, issues)
+			}
+		})
+	}
+}
+
+func TestValidator_Concurrent(t *testing.T) {
+	v := NewValidator(nil)
+	patterns := []string{"(a+)+", "^[a-z]+$", "a*a*", `^\d{3}-\d{4}$`}
+
+	want := make([]int, len(patterns))
+	for i, p := range patterns {
+		issues, err := ValidateWithOptions(p, DefaultOptions())
+		if err != nil {
+			t.Fatalf("ValidateWithOptions(%q) error = %v", p, err)
+		}
+		want[i] = len(issues)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				p := patterns[i%len(patterns)]
+				issues, err := v.Validate(p)
+				if err != nil {
+					t.Errorf("Validate(%q) error = %v", p, err)
+					return
+				}
+				if len(issues) != want[i%len(patterns)] {
+					t.Errorf("Validate(%q) returned %d issues, want %d", p, len(issues), want[i%len(patterns)])
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestNewTimeoutValidator(t *testing.T) {
+	v := NewTimeoutValidator(250 * time.Millisecond)
+	if v.Options().Timeout != 250*time.Millisecond {
+		t.Errorf("Timeout = %v, want 250ms", v.Options().Timeout)
+	}
+	if v.Options().Mode != DefaultOptions().Mode {
+		t.Errorf("Mode = %v, want the default", v.Options().Mode)
+	}
+
+	tests := []struct {
+		pattern string
+		want    bool
+	}{
+		{"^[a-z]+$", true},
+		{"(a+)+", false},
+		{"(a+", false},
+	}
+	for _, tt := range tests {
+		if got := v.IsSafe(tt.pattern); got != tt.want {
+			t.Errorf("IsSafe(%q) = %v, want %v", tt.pattern, got, tt.want)
+		}
+	}
+}
+
 func BenchmarkIsSafe_Safe(b *testing.B) {
 	pattern := "^[a-z]+$"
 
@@ -188,3 +550,327 @@ func BenchmarkValidate_ComplexPattern(b *testing.B) {
 		_, _ = Validate(pattern)
 	}
 }
+
+func TestValidate_MaxNFAStates(t *testing.T) {
+	opts := DefaultOptions()
+	opts.MaxNFAStates = 100
+
+	issues, err := ValidateWithOptions(`^(a|b|c|d){100}$`, opts)
+	if err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v", err)
+	}
+
+	found := false
+	for _, issue := range issues {
+		if issue.Type == ComplexityThresholdExceeded {
+			found = true
+			if issue.Severity != Medium {
+				t.Errorf("ComplexityThresholdExceeded severity = %v, want %v", issue.Severity, Medium)
+			}
+			if issue.Message != "NFA too large for analysis" {
+				t.Errorf("Message = %q", issue.Message)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("expected ComplexityThresholdExceeded issue, got %v", issues)
+	}
+
+	// The default limit leaves room for this pattern
+	issues, err = Validate(`^(a|b|c|d){100}$`)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	for _, issue := range issues {
+		if issue.Type == ComplexityThresholdExceeded {
+			t.Errorf("unexpected ComplexityThresholdExceeded issue with default options: %v", issue)
+		}
+	}
+}
+
+func TestTimedOut(t *testing.T) {
+	partial := []Issue{{Type: NestedQuantifiers, Severity: Critical, Message: "partial"}}
+
+	tests := []struct {
+		behavior   TimeoutBehavior
+		wantErr    bool
+		wantIssues []string
+	}{
+		{TimeoutError, true, nil},
+		{TimeoutReturnPartial, false, []string{"partial", "analysis timed out, results are partial"}},
+		{TimeoutMarkUnsafe, false, []string{"analysis timed out, treating as unsafe"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.behavior.String(), func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.TimeoutBehavior = tt.behavior
+
+			issues, err := timedOut(opts, partial, "(a+)+")
+			if tt.wantErr {
+				if !errors.Is(err, ErrTimeout) {
+					t.Errorf("timedOut() error = %v, want %v", err, ErrTimeout)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("timedOut() error = %v", err)
+			}
+			if len(issues) != len(tt.wantIssues) {
+				t.Fatalf("timedOut() = %v, want issues %q", issues, tt.wantIssues)
+			}
+			for i, want := range tt.wantIssues {
+				if issues[i].Message != want {
+					t.Errorf("issue %d = %q, want %q", i, issues[i].Message, want)
+				}
+			}
+			if issues[0].Severity != Critical {
+				t.Errorf("Severity = %v, want %v", issues[0].Severity, Critical)
+			}
+		})
+	}
+}
+
+func TestValidate_TimeoutExceeded(t *testing.T) {
+	// Hundreds of overlapping quantifiers take far longer than 10ms to analyze
+	pattern := strings.Repeat(`[a-z]*[a-z0-9]*`, 120)
+	opts := ThoroughOptions()
+	opts.Timeout = 10 * time.Millisecond
+	opts.MaxPatternLength = 0
+
+	issues, err := ValidateWithOptions(pattern, opts)
+	if err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v, want the partial issues of ThoroughOptions", err)
+	}
+	if len(issues) == 0 {
+		t.Error("ValidateWithOptions() = no issues, want those found before the timeout")
+	}
+
+	opts.TimeoutBehavior = TimeoutError
+	start := time.Now()
+	if _, err := ValidateWithOptions(pattern, opts); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("ValidateWithOptions() error = %v, want %v", err, ErrTimeout)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("ValidateWithOptions() took %v with a 10ms timeout", elapsed)
+	}
+}
+
+func TestValidate_TimeoutBeforeAnyIssue(t *testing.T) {
+	defer ResetDefaultOptions()
+
+	opts := FastOptions()
+	opts.Timeout = time.Nanosecond
+	issues, err := ValidateWithOptions("(a+)+$", opts)
+	if err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v", err)
+	}
+	if len(issues) == 0 || issues[len(issues)-1].Message != "analysis timed out, results are partial" {
+		t.Errorf("ValidateWithOptions() = %v, want a note that the results are partial", issues)
+	}
+
+	if NewTimeoutValidator(time.Nanosecond).IsSafe("(a+)+$") {
+		t.Error("Validator.IsSafe() = true for a pattern whose analysis timed out")
+	}
+
+	SetDefaultOptions(opts)
+	if err := CheckPattern("(a+)+$"); !errors.Is(err, ErrUnsafePattern) {
+		t.Errorf("CheckPattern() error = %v, want %v", err, ErrUnsafePattern)
+	}
+	if IsSafe("^[a-z]+$") {
+		t.Error("IsSafe() = true for a pattern whose analysis timed out")
+	}
+}
+
+func TestValidate_NoTimeoutWithinLimit(t *testing.T) {
+	opts := DefaultOptions()
+	opts.TimeoutBehavior = TimeoutError
+
+	issues, err := ValidateWithOptions("(a+)+", opts)
+	if err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v", err)
+	}
+	if len(issues) == 0 {
+		t.Error("expected issues for (a+)+")
+	}
+}
+
+func TestValidate_Checks(t *testing.T) {
+	pattern := `^id:(?:(a)|(ab))+$`
+
+	opts := DefaultOptions()
+	opts.Checks = CheckNestedQuantifiers
+	issues, err := ValidateWithOptions(pattern, opts)
+	if err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("Checks = CheckNestedQuantifiers reported %v, want none", issues)
+	}
+
+	// Zero Checks means CheckDefault
+	issues, err = ValidateWithOptions(pattern, &Options{Mode: Balanced})
+	if err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v", err)
+	}
+	if len(issues) == 0 {
+		t.Error("Checks = 0 reported no issues, want CheckDefault behavior")
+	}
+}
+
+func TestValidate_ExperimentalChecks(t *testing.T) {
+	pattern := `^(?:\d|\d\d)+$`
+
+	count := func(issues []Issue) int {
+		n := 0
+		for _, issue := range issues {
+			if strings.HasPrefix(issue.Message, "Experimental:") {
+				n++
+				if issue.Severity != Info {
+					t.Errorf("experimental issue severity = %v, want Info", issue.Severity)
+				}
+			}
+		}
+		return n
+	}
+
+	opts := ThoroughOptions()
+	issues, err := ValidateWithOptions(pattern, opts)
+	if err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v", err)
+	}
+	if count(issues) != 0 {
+		t.Errorf("experimental checks ran without EnableExperimentalChecks: %v", issues)
+	}
+
+	opts.EnableExperimentalChecks = true
+	issues, err = ValidateWithOptions(pattern, opts)
+	if err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v", err)
+	}
+	if n := count(issues); n != 1 {
+		t.Errorf("EnableExperimentalChecks reported %d experimental issues, want 1: %v", n, issues)
+	}
+}
+
+func TestValidateForLanguage(t *testing.T) {
+	tests := []struct {
+		language    string
+		wantDialect Dialect
+		wantStrict  bool
+	}{
+		{"go", DialectRE2, false},
+		{"java", DialectJava, true},
+		{"Python", DialectPython, true},
+		{"js", DialectJavaScript, true},
+		{"php", DialectPCRE, true},
+		{"ruby", DialectRuby, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.language, func(t *testing.T) {
+			opts, err := languageOptions(tt.language)
+			if err != nil {
+				t.Fatalf("languageOptions(%q) error = %v", tt.language, err)
+			}
+			if opts.Dialect != tt.wantDialect || opts.StrictMode != tt.wantStrict {
+				t.Errorf("languageOptions(%q) = dialect %v, strict %v; want %v, %v",
+					tt.language, opts.Dialect, opts.StrictMode, tt.wantDialect, tt.wantStrict)
+			}
+
+			issues, err := ValidateForLanguage("(a+)+", tt.language)
+			if err != nil {
+				t.Fatalf("ValidateForLanguage() error = %v", err)
+			}
+			if len(issues) == 0 {
+				t.Error("ValidateForLanguage((a+)+) reported no issues")
+			}
+		})
+	}
+
+	if _, err := ValidateForLanguage("a+", "cobol"); !errors.Is(err, ErrUnsupportedLanguage) {
+		t.Errorf("ValidateForLanguage() with unknown language error = %v, want ErrUnsupportedLanguage", err)
+	}
+}
+
+func TestValidate_BackreferenceAmbiguity(t *testing.T) {
+	issues, err := ValidateForLanguage(`^(.+)\1+$`, "python")
+	if err != nil {
+		t.Fatalf("ValidateForLanguage() error = %v", err)
+	}
+	found := false
+	for _, issue := range issues {
+		if issue.Type == BackreferenceAmbiguity {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a %v issue, got %v", BackreferenceAmbiguity, issues)
+	}
+
+	// Go's regexp has no backreferences
+	if _, err := ValidateForLanguage(`^(.+)\1+$`, "go"); err == nil {
+		t.Error("ValidateForLanguage() for go returned no error")
+	}
+}
+
+func TestValidate_MemoryUsage(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Checks = CheckDefault | CheckMemoryUsage
+	// The memory checks run last, so keep them out of the timeout
+	opts.Timeout = time.Minute
+
+	issues, err := ValidateWithOptions("[ab]*a[ab]{12}", opts)
+	if err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v", err)
+	}
+	for _, issue := range issues {
+		if n, ok := issue.Details["estimated_dfa_states"].(int); ok {
+			if issue.Type != ComplexityThresholdExceeded || issue.Severity != Low || n <= opts.MaxDFAStates {
+				t.Errorf("memory issue = %+v, want Low ComplexityThresholdExceeded over %d states", issue, opts.MaxDFAStates)
+			}
+			return
+		}
+	}
+	t.Errorf("ValidateWithOptions() = %v, want an issue with estimated_dfa_states", issues)
+}
+
+func TestValidate_SeverityOverride(t *testing.T) {
+	pattern := "(a+)+"
+
+	baseline, err := ValidateWithOptions(pattern, DefaultOptions())
+	if err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v", err)
+	}
+
+	opts := DefaultOptions()
+	opts.SeverityOverride = map[IssueType]Severity{
+		NestedQuantifiers:       Info,
+		ExponentialBacktracking: Low,
+	}
+	issues, err := ValidateWithOptions(pattern, opts)
+	if err != nil {
+		t.Fatalf("ValidateWithOptions() error = %v", err)
+	}
+	if len(issues) != len(baseline) {
+		t.Fatalf("SeverityOverride changed the issue count: %d, want %d", len(issues), len(baseline))
+	}
+	for _, issue := range issues {
+		if want, ok := opts.SeverityOverride[issue.Type]; ok && issue.Severity != want {
+			t.Errorf("%s severity = %v, want %v", issue.Type, issue.Severity, want)
+		}
+	}
+
+	// Scores are unaffected
+	before, err := NewValidator(DefaultOptions()).AnalyzeComplexity(pattern)
+	if err != nil {
+		t.Fatalf("AnalyzeComplexity() error = %v", err)
+	}
+	after, err := NewValidator(opts).AnalyzeComplexity(pattern)
+	if err != nil {
+		t.Fatalf("AnalyzeComplexity() error = %v", err)
+	}
+	if before.Overall != after.Overall {
+		t.Errorf("SeverityOverride changed the score: %d, want %d", after.Overall, before.Overall)
+	}
+}
@@ -1,27 +1,96 @@
 package regret
 
-// Version information for the regret library.
-const (
-	// Version is the current version of the library.
-	Version = "0.1.0"
-
-	// VersionMajor is the major version number.
-	VersionMajor = 0
-
-	// VersionMinor is the minor version number.
-	VersionMinor = 1
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
-	// VersionPatch is the patch version number.
-	VersionPatch = 0
+// Version is a semantic version number.
+type Version struct {
+	Major      int    `json:"major"`
+	Minor      int    `json:"minor"`
+	Patch      int    `json:"patch"`
+	Prerelease string `json:"prerelease,omitempty"` // e.g. "alpha", "rc.1"
+}
 
-	// VersionPrerelease indicates this is a pre-release version.
-	VersionPrerelease = "alpha"
-)
+// CurrentVersion is the version of the library.
+var CurrentVersion = Version{Major: 0, Minor: 1, Patch: 0, Prerelease: "alpha"}
 
 // FullVersion returns the full version string including pre-release suffix.
+// It is shorthand for CurrentVersion.String().
 func FullVersion() string {
-	if VersionPrerelease != "" {
-		return Version + "-" + VersionPrerelease
+	return CurrentVersion.String()
+}
+
+// String returns the version as MAJOR.MINOR.PATCH[-PRERELEASE].
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+// CompareTo returns -1, 0 or 1 if v has lower, equal or higher precedence
+// than other, following Semantic Versioning: a pre-release has lower
+// precedence than the release it precedes (1.0.0-alpha < 1.0.0), and
+// pre-release identifiers are compared field by field, numerically where
+// both are numbers (alpha.2 < alpha.10).
+func (v Version) CompareTo(other Version) int {
+	for _, c := range [][2]int{{v.Major, other.Major}, {v.Minor, other.Minor}, {v.Patch, other.Patch}} {
+		if c[0] != c[1] {
+			return compareInts(c[0], c[1])
+		}
+	}
+
+	switch {
+	case v.Prerelease == other.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case other.Prerelease == "":
+		return -1
+	}
+
+	a, b := strings.Split(v.Prerelease, "."), strings.Split(other.Prerelease, ".")
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := comparePrereleaseField(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(a), len(b))
+}
+
+// IsAtLeast reports whether v has at least the precedence of
+// MAJOR.MINOR.PATCH. Pre-releases precede their release, so
+// 0.2.0-alpha is not at least 0.2.0.
+func (v Version) IsAtLeast(major, minor, patch int) bool {
+	return v.CompareTo(Version{Major: major, Minor: minor, Patch: patch}) >= 0
+}
+
+// comparePrereleaseField compares pre-release identifiers: numeric
+// identifiers compare numerically and sort before alphanumeric ones.
+func comparePrereleaseField(a, b string) int {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		return compareInts(na, nb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
 	}
-	return Version
+	return 0
 }
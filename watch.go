@@ -0,0 +1,215 @@
+package regret
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// FileValidationEvent reports the validation result of one pattern
+// read from a watched file.
+type FileValidationEvent struct {
+	// Path is the file the pattern was read from.
+	Path string
+
+	// Line is the 1-based line number of the pattern in the file.
+	Line int
+
+	// Pattern is the validated pattern.
+	Pattern string
+
+	// Issues contains the detected issues (empty if the pattern is safe).
+	Issues []Issue
+
+	// Err is set if the pattern could not be validated, or if the file
+	// could not be read (in which case Pattern is empty).
+	Err error
+
+	// Timestamp is when the validation ran.
+	Timestamp time.Time
+}
+
+// Watcher re-validates pattern files as they change.
+// Call Close to stop watching.
+type Watcher struct {
+	fs        *fsnotify.Watcher
+	validator *Validator
+	results   chan<- FileValidationEvent
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+// WatchFile watches a file containing newline-separated patterns and sends
+// a FileValidationEvent for every pattern on results: once when watching
+// starts, and again each time the file is written or replaced.
+// Blank lines and lines starting with # are skipped.
+//
+// Events are sent from a background goroutine; a slow receiver delays
+// further validation but never drops events. The results channel is not
+// closed by the watcher.
+//
+// Example:
+//
+//	events := make(chan regret.FileValidationEvent)
+//	w, err := regret.WatchFile("patterns.txt", nil, events)
+//	if err != nil {
+//	    return err
+//	}
+//	defer w.Close()
+//	for event := range events {
+//	    fmt.Println(event.Pattern, len(event.Issues))
+//	}
+func WatchFile(path string, opts *Options, results chan<- FileValidationEvent) (*Watcher, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%s is a directory, use WatchDir", path)
+	}
+
+	path = filepath.Clean(path)
+
+	// Watch the parent directory so that editors which save by replacing
+	// the file (write to temp, then rename) are picked up too
+	return newWatcher(filepath.Dir(path), []string{path}, func(name string) bool {
+		return filepath.Clean(name) == path
+	}, opts, results)
+}
+
+// WatchDir is like WatchFile but watches every regular file directly
+// inside dir. Subdirectories are not watched.
+func WatchDir(dir string, opts *Options, results chan<- FileValidationEvent) (*Watcher, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+
+	return newWatcher(dir, files, isRegularFile, opts, results)
+}
+
+func newWatcher(dir string, initial []string, match func(string) bool, opts *Options, results chan<- FileValidationEvent) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := fsw.Add(dir); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+
+	w := &Watcher{
+		fs:        fsw,
+		validator: NewValidator(opts),
+		results:   results,
+		done:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}
+
+	go w.run(initial, match)
+
+	return w, nil
+}
+
+// Close stops watching and waits for the background goroutine to exit.
+func (w *Watcher) Close() error {
+	var err error
+	w.closeOnce.Do(func() {
+		close(w.done)
+		err = w.fs.Close()
+		<-w.stopped
+	})
+	return err
+}
+
+func (w *Watcher) run(initial []string, match func(string) bool) {
+	defer close(w.stopped)
+
+	for _, path := range initial {
+		if !w.validateFile(path) {
+			return
+		}
+	}
+
+	for {
+		select {
+		case <-w.done:
+			return
+
+		case event, ok := <-w.fs.Events:
+			if !ok {
+				return
+			}
+			if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) {
+				continue
+			}
+			if !match(event.Name) {
+				continue
+			}
+			if !w.validateFile(event.Name) {
+				return
+			}
+
+		case err, ok := <-w.fs.Errors:
+			if !ok {
+				return
+			}
+			if !w.send(FileValidationEvent{Err: err, Timestamp: time.Now()}) {
+				return
+			}
+		}
+	}
+}
+
+// validateFile validates every pattern in the file and sends the results.
+// Returns false if the watcher was closed while sending.
+func (w *Watcher) validateFile(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return w.send(FileValidationEvent{Path: path, Err: err, Timestamp: time.Now()})
+	}
+
+	for _, line := range readPatternLines(data) {
+		issues, err := w.validator.Validate(line.pattern)
+		event := FileValidationEvent{
+			Path:      path,
+			Line:      line.line,
+			Pattern:   line.pattern,
+			Issues:    issues,
+			Err:       err,
+			Timestamp: time.Now(),
+		}
+		if !w.send(event) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// send delivers an event unless the watcher is closed first.
+func (w *Watcher) send(event FileValidationEvent) bool {
+	select {
+	case w.results <- event:
+		return true
+	case <-w.done:
+		return false
+	}
+}
+
+func isRegularFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
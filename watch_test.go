@@ -0,0 +1,117 @@
+package regret
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func receiveEvent(t *testing.T, events <-chan FileValidationEvent) FileValidationEvent {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for validation event")
+		return FileValidationEvent{}
+	}
+}
+
+func TestWatchFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "patterns.txt")
+	if err := os.WriteFile(path, []byte("# comment\n(a+)+\n\n^[a-z]+$\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	events := make(chan FileValidationEvent)
+	w, err := WatchFile(path, nil, events)
+	if err != nil {
+		t.Fatalf("WatchFile() error = %v", err)
+	}
+	defer w.Close()
+
+	// Initial validation of the existing content
+	unsafe := receiveEvent(t, events)
+	if unsafe.Pattern != "(a+)+" || unsafe.Line != 2 || len(unsafe.Issues) == 0 {
+		t.Errorf("first event = %+v, want unsafe (a+)+ on line 2", unsafe)
+	}
+	safe := receiveEvent(t, events)
+	if safe.Pattern != "^[a-z]+$" || safe.Line != 4 || len(safe.Issues) != 0 {
+		t.Errorf("second event = %+v, want safe ^[a-z]+$ on line 4", safe)
+	}
+	if safe.Path != path || safe.Timestamp.IsZero() {
+		t.Errorf("event Path = %q, Timestamp = %v", safe.Path, safe.Timestamp)
+	}
+
+	// Changing the file triggers a new validation
+	if err := os.WriteFile(path, []byte("(x*)*\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for {
+		event := receiveEvent(t, events)
+		if event.Pattern == "(x*)*" {
+			if len(event.Issues) == 0 {
+				t.Error("expected issues for (x*)* after file change")
+			}
+			break
+		}
+	}
+}
+
+func TestWatchDir(t *testing.T) {
+	dir := t.TempDir()
+
+	events := make(chan FileValidationEvent)
+	w, err := WatchDir(dir, nil, events)
+	if err != nil {
+		t.Fatalf("WatchDir() error = %v", err)
+	}
+	defer w.Close()
+
+	path := filepath.Join(dir, "new.txt")
+	if err := os.WriteFile(path, []byte("(a|a)*b\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	event := receiveEvent(t, events)
+	if event.Path != path || event.Pattern != "(a|a)*b" {
+		t.Errorf("event = %+v, want pattern from %s", event, path)
+	}
+}
+
+func TestWatchFile_Errors(t *testing.T) {
+	events := make(chan FileValidationEvent)
+
+	if _, err := WatchFile(filepath.Join(t.TempDir(), "missing.txt"), nil, events); err == nil {
+		t.Error("WatchFile() expected error for missing file")
+	}
+	if _, err := WatchFile(t.TempDir(), nil, events); err == nil {
+		t.Error("WatchFile() expected error for directory")
+	}
+}
+
+func TestWatcher_CloseUnblocksSend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "patterns.txt")
+	if err := os.WriteFile(path, []byte("a+\nb+\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nobody receives: Close must not hang on the pending send
+	w, err := WatchFile(path, nil, make(chan FileValidationEvent))
+	if err != nil {
+		t.Fatalf("WatchFile() error = %v", err)
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		w.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close() did not return")
+	}
+}